@@ -0,0 +1,88 @@
+package controllers
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+
+	domain "task_manager/Domain"
+	usecases "task_manager/Usecases"
+
+	"github.com/gin-gonic/gin"
+)
+
+// multipartOverhead is how much larger than the file an upload request may be,
+// for the multipart boundaries, headers and form fields around it.
+const multipartOverhead = 64 << 10
+
+// ListAttachments handles GET /tasks/:id/attachments
+func (c *Controller) ListAttachments(ctx *gin.Context) {
+	attachments, err := c.attachmentUsecases.ListAttachments(ctx.Request.Context(), currentActor(ctx), ctx.Param("id"))
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": attachments})
+}
+
+// UploadAttachment handles POST /tasks/:id/attachments with a multipart
+// form holding the file in "file" and optionally its checksum in "sha256".
+func (c *Controller) UploadAttachment(ctx *gin.Context) {
+	maxSize := c.attachmentUsecases.Config().MaxSize
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, maxSize+multipartOverhead)
+	file, header, err := ctx.Request.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			ctx.Error(domain.NewTooLargeError(fmt.Sprintf("attachments must be at most %d bytes", maxSize)))
+			return
+		}
+		ctx.Error(domain.NewValidationError("invalid request", domain.FieldError{Field: "file", Message: "is required"}))
+		return
+	}
+	defer file.Close()
+
+	attachment, err := c.attachmentUsecases.UploadAttachment(ctx.Request.Context(), currentActor(ctx), ctx.Param("id"), usecases.AttachmentUpload{
+		Filename: header.Filename,
+		Content:  file,
+		SHA256:   ctx.Request.FormValue("sha256"),
+	})
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, gin.H{"data": attachment})
+}
+
+// DownloadAttachment handles GET /tasks/:id/attachments/:attachment_id
+func (c *Controller) DownloadAttachment(ctx *gin.Context) {
+	attachment, content, err := c.attachmentUsecases.OpenAttachment(ctx.Request.Context(), currentActor(ctx), ctx.Param("id"), ctx.Param("attachment_id"))
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	defer content.Close()
+
+	// Served as a download that browsers must not reinterpret, whatever its type
+	headers := map[string]string{
+		"Content-Disposition":    mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Filename}),
+		"X-Content-Type-Options": "nosniff",
+		"ETag":                   `"` + attachment.SHA256 + `"`,
+	}
+	if sum, err := hex.DecodeString(attachment.SHA256); err == nil {
+		headers["Content-Digest"] = "sha-256=:" + base64.StdEncoding.EncodeToString(sum) + ":"
+	}
+	ctx.DataFromReader(http.StatusOK, attachment.Size, attachment.ContentType, content, headers)
+}
+
+// DeleteAttachment handles DELETE /tasks/:id/attachments/:attachment_id
+func (c *Controller) DeleteAttachment(ctx *gin.Context) {
+	if err := c.attachmentUsecases.DeleteAttachment(ctx.Request.Context(), currentActor(ctx), ctx.Param("id"), ctx.Param("attachment_id")); err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
@@ -0,0 +1,78 @@
+package controllers
+
+import (
+	"net/http"
+
+	domain "task_manager/Domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+// commentInput is the request body of posting or editing a comment.
+type commentInput struct {
+	Body string `json:"body" binding:"required"`
+}
+
+// ListComments handles GET /tasks/:id/comments
+func (c *Controller) ListComments(ctx *gin.Context) {
+	var input struct {
+		Page     int `form:"page"`
+		PageSize int `form:"page_size"`
+	}
+	if err := ctx.ShouldBindQuery(&input); err != nil {
+		ctx.Error(bindingError(err))
+		return
+	}
+
+	page, err := c.commentUsecases.ListComments(ctx.Request.Context(), currentActor(ctx), domain.CommentQuery{
+		TaskID:   ctx.Param("id"),
+		Page:     input.Page,
+		PageSize: input.PageSize,
+	})
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, page)
+}
+
+// AddComment handles POST /tasks/:id/comments
+func (c *Controller) AddComment(ctx *gin.Context) {
+	var input commentInput
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		ctx.Error(bindingError(err))
+		return
+	}
+
+	comment, err := c.commentUsecases.AddComment(ctx.Request.Context(), currentActor(ctx), ctx.Param("id"), input.Body)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, gin.H{"data": comment})
+}
+
+// EditComment handles PATCH /tasks/:id/comments/:comment_id
+func (c *Controller) EditComment(ctx *gin.Context) {
+	var input commentInput
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		ctx.Error(bindingError(err))
+		return
+	}
+
+	comment, err := c.commentUsecases.EditComment(ctx.Request.Context(), currentActor(ctx), ctx.Param("id"), ctx.Param("comment_id"), input.Body)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": comment})
+}
+
+// DeleteComment handles DELETE /tasks/:id/comments/:comment_id
+func (c *Controller) DeleteComment(ctx *gin.Context) {
+	if err := c.commentUsecases.DeleteComment(ctx.Request.Context(), currentActor(ctx), ctx.Param("id"), ctx.Param("comment_id")); err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
@@ -1,119 +1,525 @@
 package controllers
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
+	"unicode"
 
+	domain "task_manager/Domain"
 	infrastructure "task_manager/Infrastructure"
+	repositories "task_manager/Repositories"
 	usecases "task_manager/Usecases"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
 )
 
 // Controller handles HTTP requests and responses.
 type Controller struct {
-	taskUsecases *usecases.TaskUsecases
-	userUsecases *usecases.UserUsecases
-	jwtService   *infrastructure.JWTService
+	taskUsecases       *usecases.TaskUsecases
+	userUsecases       *usecases.UserUsecases
+	roleUsecases       *usecases.RoleUsecases
+	auditUsecases      *usecases.AuditUsecases
+	webhookUsecases    *usecases.WebhookUsecases
+	commentUsecases    *usecases.CommentUsecases
+	attachmentUsecases *usecases.AttachmentUsecases
+	projectUsecases    *usecases.ProjectUsecases
+	tokenService       *infrastructure.TokenService
 }
 
 // NewController creates a new controller.
-func NewController(taskUsecases *usecases.TaskUsecases, userUsecases *usecases.UserUsecases, jwtService *infrastructure.JWTService) *Controller {
+func NewController(taskUsecases *usecases.TaskUsecases, userUsecases *usecases.UserUsecases, roleUsecases *usecases.RoleUsecases, auditUsecases *usecases.AuditUsecases, webhookUsecases *usecases.WebhookUsecases, commentUsecases *usecases.CommentUsecases, attachmentUsecases *usecases.AttachmentUsecases, projectUsecases *usecases.ProjectUsecases, tokenService *infrastructure.TokenService) *Controller {
 	return &Controller{
-		taskUsecases: taskUsecases,
-		userUsecases: userUsecases,
-		jwtService:   jwtService,
+		taskUsecases:       taskUsecases,
+		userUsecases:       userUsecases,
+		roleUsecases:       roleUsecases,
+		auditUsecases:      auditUsecases,
+		webhookUsecases:    webhookUsecases,
+		commentUsecases:    commentUsecases,
+		attachmentUsecases: attachmentUsecases,
+		projectUsecases:    projectUsecases,
+		tokenService:       tokenService,
 	}
 }
 
+// currentActor builds the actor from the claims set by AuthRequired and
+// the project set by ProjectScope.
+func currentActor(ctx *gin.Context) domain.Actor {
+	return domain.Actor{
+		UserID:      ctx.GetString("user_id"),
+		Role:        ctx.GetString("role"),
+		Permissions: ctx.GetStringSlice("permissions"),
+		ProjectID:   ctx.GetString("project_id"),
+	}
+}
+
+// bindingError turns a request binding failure into a validation error,
+// naming each invalid field the way clients spell it.
+func bindingError(err error) error {
+	var invalid validator.ValidationErrors
+	if !errors.As(err, &invalid) {
+		return domain.NewValidationError("invalid request: " + err.Error())
+	}
+	fields := make([]domain.FieldError, 0, len(invalid))
+	for _, fe := range invalid {
+		fields = append(fields, domain.FieldError{Field: snakeCase(fe.Field()), Message: validationMessage(fe)})
+	}
+	return domain.NewValidationError("invalid request", fields...)
+}
+
+func validationMessage(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "oneof":
+		return "must be one of: " + fe.Param()
+	}
+	return "failed the " + fe.Tag() + " check"
+}
+
+// snakeCase converts a Go field name such as DueDate to due_date.
+func snakeCase(name string) string {
+	var b strings.Builder
+	for i, r := range name {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// setETag exposes the task's version as a strong ETag.
+func setETag(ctx *gin.Context, task domain.Task) {
+	ctx.Header("ETag", `"`+strconv.FormatInt(task.Version, 10)+`"`)
+}
+
+// ifMatchVersion returns the version named by the If-Match header, or 0
+// when the header is absent or "*". ETags this server never issued can
+// never match, so they are reported as a failed precondition.
+func ifMatchVersion(ctx *gin.Context) (int64, error) {
+	header := strings.TrimSpace(ctx.GetHeader("If-Match"))
+	if header == "" || header == "*" {
+		return 0, nil
+	}
+	version, err := strconv.ParseInt(strings.Trim(header, `"`), 10, 64)
+	if err != nil || version <= 0 || !strings.HasPrefix(header, `"`) {
+		return 0, repositories.ErrVersionConflict
+	}
+	return version, nil
+}
+
 // Task Handlers
 
 // ListTasks handles GET /tasks
 func (c *Controller) ListTasks(ctx *gin.Context) {
-	tasks, err := c.taskUsecases.GetAllTasks()
+	query, ok := bindTaskQuery(ctx)
+	if !ok {
+		return
+	}
+	if err := query.Normalize(); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	page, err := c.taskUsecases.ListTasks(ctx.Request.Context(), currentActor(ctx), query)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, page)
+}
+
+// SearchTasks handles GET /tasks/search
+func (c *Controller) SearchTasks(ctx *gin.Context) {
+	var input struct {
+		Text     string `form:"q"`
+		Page     int    `form:"page"`
+		PageSize int    `form:"page_size"`
+	}
+	if err := ctx.ShouldBindQuery(&input); err != nil {
+		ctx.Error(bindingError(err))
+		return
+	}
+
+	search := domain.TaskSearch{Text: input.Text, Page: input.Page, PageSize: input.PageSize}
+	page, err := c.taskUsecases.SearchTasks(ctx.Request.Context(), currentActor(ctx), search)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve tasks"})
+		ctx.Error(err)
 		return
 	}
-	ctx.JSON(http.StatusOK, gin.H{"data": tasks})
+	ctx.JSON(http.StatusOK, page)
+}
+
+// bindTaskQuery reads the task list filters from the query string.
+// On failure it reports the error and returns false.
+func bindTaskQuery(ctx *gin.Context) (domain.TaskQuery, bool) {
+	var input struct {
+		OwnerID   string    `form:"owner_id"`
+		Status    string    `form:"status"`
+		DueAfter  time.Time `form:"due_after" time_format:"2006-01-02T15:04:05Z07:00"`
+		DueBefore time.Time `form:"due_before" time_format:"2006-01-02T15:04:05Z07:00"`
+		Search    string    `form:"q"`
+		Overdue   bool      `form:"overdue"`
+		Sort      string    `form:"sort"`
+		Order     string    `form:"order" binding:"omitempty,oneof=asc desc"`
+		Page      int       `form:"page"`
+		PageSize  int       `form:"page_size"`
+	}
+	if err := ctx.ShouldBindQuery(&input); err != nil {
+		ctx.Error(bindingError(err))
+		return domain.TaskQuery{}, false
+	}
+	return domain.TaskQuery{
+		OwnerID:   input.OwnerID,
+		Status:    input.Status,
+		DueAfter:  input.DueAfter,
+		DueBefore: input.DueBefore,
+		Search:    input.Search,
+		Overdue:   input.Overdue,
+		SortBy:    input.Sort,
+		SortDesc:  input.Order == "desc",
+		Page:      input.Page,
+		PageSize:  input.PageSize,
+	}, true
 }
 
 // GetTask handles GET /tasks/:id
 func (c *Controller) GetTask(ctx *gin.Context) {
 	id := ctx.Param("id")
-	task, err := c.taskUsecases.GetTaskByID(id)
+	task, err := c.taskUsecases.GetTaskByID(ctx.Request.Context(), currentActor(ctx), id)
 	if err != nil {
-		if err.Error() == "task not found" {
-			ctx.JSON(http.StatusNotFound, gin.H{"error": "task not found"})
-			return
-		}
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve task"})
+		ctx.Error(err)
 		return
 	}
+	setETag(ctx, task)
 	ctx.JSON(http.StatusOK, gin.H{"data": task})
 }
 
+// taskInput is the request body of task creation and updates.
+type taskInput struct {
+	Title       string    `json:"title" binding:"required"`
+	Description string    `json:"description"`
+	DueDate     time.Time `json:"due_date"`
+	Status      string    `json:"status"`
+	Priority    string    `json:"priority"`
+	Tags        []string  `json:"tags"`
+	AssigneeIDs []string  `json:"assignee_ids"`
+	ParentID    string    `json:"parent_id"`
+	// Recurrence is null for one-off tasks
+	Recurrence *domain.Recurrence `json:"recurrence"`
+}
+
+func newTaskInput(t domain.Task) taskInput {
+	return taskInput{
+		Title:       t.Title,
+		Description: t.Description,
+		DueDate:     t.DueDate,
+		Status:      t.Status,
+		Priority:    t.Priority,
+		Tags:        t.Tags,
+		AssigneeIDs: t.AssigneeIDs,
+		ParentID:    t.ParentID,
+		Recurrence:  t.Recurrence,
+	}
+}
+
+func (in taskInput) toUsecase() usecases.TaskInput {
+	return usecases.TaskInput{
+		Title:       in.Title,
+		Description: in.Description,
+		DueDate:     in.DueDate,
+		Status:      in.Status,
+		Priority:    in.Priority,
+		Tags:        in.Tags,
+		AssigneeIDs: in.AssigneeIDs,
+		ParentID:    in.ParentID,
+		Recurrence:  in.Recurrence,
+	}
+}
+
 // CreateTask handles POST /tasks
 func (c *Controller) CreateTask(ctx *gin.Context) {
-	var input struct {
-		Title       string    `json:"title" binding:"required"`
-		Description string    `json:"description"`
-		DueDate     time.Time `json:"due_date"`
-		Status      string    `json:"status"`
-	}
+	var input taskInput
 	if err := ctx.ShouldBindJSON(&input); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		ctx.Error(bindingError(err))
 		return
 	}
 
-	task, err := c.taskUsecases.CreateTask(input.Title, input.Description, input.DueDate, input.Status)
+	task, err := c.taskUsecases.CreateTask(ctx.Request.Context(), currentActor(ctx), input.toUsecase())
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		ctx.Error(err)
 		return
 	}
+	setETag(ctx, task)
 	ctx.JSON(http.StatusCreated, gin.H{"data": task})
 }
 
 // UpdateTask handles PUT /tasks/:id
 func (c *Controller) UpdateTask(ctx *gin.Context) {
 	id := ctx.Param("id")
-	var input struct {
-		Title       string    `json:"title" binding:"required"`
-		Description string    `json:"description"`
-		DueDate     time.Time `json:"due_date"`
-		Status      string    `json:"status"`
+	version, err := ifMatchVersion(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
 	}
+	var input taskInput
 	if err := ctx.ShouldBindJSON(&input); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		ctx.Error(bindingError(err))
 		return
 	}
 
-	task, err := c.taskUsecases.UpdateTask(id, input.Title, input.Description, input.DueDate, input.Status)
+	update := input.toUsecase()
+	update.Version = version
+	task, err := c.taskUsecases.UpdateTask(ctx.Request.Context(), currentActor(ctx), id, update)
 	if err != nil {
-		if err.Error() == "task not found" {
-			ctx.JSON(http.StatusNotFound, gin.H{"error": "task not found"})
-			return
-		}
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		ctx.Error(err)
 		return
 	}
+	setETag(ctx, task)
 	ctx.JSON(http.StatusOK, gin.H{"data": task})
 }
 
+// PatchTask handles PATCH /tasks/:id with a JSON Merge Patch body.
+// Only the fields present in the patch change.
+func (c *Controller) PatchTask(ctx *gin.Context) {
+	id := ctx.Param("id")
+	version, err := ifMatchVersion(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	patch, err := ctx.GetRawData()
+	if err != nil {
+		ctx.Error(bindingError(err))
+		return
+	}
+
+	actor := currentActor(ctx)
+	existing, err := c.taskUsecases.GetTaskByID(ctx.Request.Context(), actor, id)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	if version != 0 && version != existing.Version {
+		ctx.Error(repositories.ErrVersionConflict)
+		return
+	}
+
+	current, err := json.Marshal(newTaskInput(existing))
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	var input taskInput
+	if err := applyMergePatch(current, patch, &input); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	// Update against the version the patch was applied to, so concurrent writes are not lost
+	update := input.toUsecase()
+	update.Version = existing.Version
+	task, err := c.taskUsecases.UpdateTask(ctx.Request.Context(), actor, id, update)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	setETag(ctx, task)
+	ctx.JSON(http.StatusOK, gin.H{"data": task})
+}
+
+// bulkRequest is the request body of POST /tasks/bulk.
+type bulkRequest struct {
+	Atomic     bool                   `json:"atomic"`
+	Operations []bulkOperationRequest `json:"operations" binding:"required"`
+}
+
+// bulkOperationRequest is one operation of a bulk request. Creates take a
+// task like POST /tasks, and updates a JSON Merge Patch like PATCH /tasks/:id.
+type bulkOperationRequest struct {
+	Op      string          `json:"op"`
+	ID      string          `json:"id"`
+	Version int64           `json:"version"`
+	Task    json.RawMessage `json:"task"`
+}
+
+// bulkResult is the outcome of one operation, with the status and body the
+// single-task endpoint would have responded with.
+type bulkResult struct {
+	Status int                       `json:"status"`
+	Data   *domain.Task              `json:"data,omitempty"`
+	Error  *infrastructure.ErrorBody `json:"error,omitempty"`
+}
+
+// bulkStatuses are the statuses of successful bulk operations.
+var bulkStatuses = map[string]int{
+	usecases.BulkCreate: http.StatusCreated,
+	usecases.BulkUpdate: http.StatusOK,
+	usecases.BulkDelete: http.StatusNoContent,
+}
+
+// BulkTasks handles POST /tasks/bulk. It responds with one result per
+// operation, with status 200 if every operation succeeded and 207 otherwise.
+func (c *Controller) BulkTasks(ctx *gin.Context) {
+	var req bulkRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.Error(bindingError(err))
+		return
+	}
+
+	actor := currentActor(ctx)
+	ops := make([]usecases.BulkOperation, len(req.Operations))
+	for i, op := range req.Operations {
+		ops[i] = c.bulkOperation(ctx.Request.Context(), actor, op)
+	}
+	results, err := c.taskUsecases.BulkTasks(ctx.Request.Context(), actor, ops, req.Atomic)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	status := http.StatusOK
+	response := make([]bulkResult, len(results))
+	for i, result := range results {
+		if result.Err != nil {
+			code, body := infrastructure.DescribeError(result.Err)
+			if code == http.StatusInternalServerError {
+				log.Printf("request %s: bulk operation %d failed: %v", ctx.GetString("request_id"), i, result.Err)
+			}
+			body.RequestID = ctx.GetString("request_id")
+			response[i] = bulkResult{Status: code, Error: &body}
+			status = http.StatusMultiStatus
+			continue
+		}
+		response[i].Status = bulkStatuses[ops[i].Op]
+		if ops[i].Op != usecases.BulkDelete {
+			task := result.Task
+			response[i].Data = &task
+		}
+	}
+	ctx.JSON(status, gin.H{"data": response})
+}
+
+// bulkOperation decodes one operation of a bulk request. Failures are
+// attached to the operation so that they are reported in its result.
+func (c *Controller) bulkOperation(ctx context.Context, actor domain.Actor, req bulkOperationRequest) usecases.BulkOperation {
+	op := usecases.BulkOperation{Op: req.Op, ID: req.ID}
+	switch req.Op {
+	case usecases.BulkCreate:
+		var input taskInput
+		if err := json.Unmarshal(req.Task, &input); err != nil {
+			op.Err = domain.NewValidationError("invalid task: " + err.Error())
+			return op
+		}
+		if err := binding.Validator.ValidateStruct(&input); err != nil {
+			op.Err = bindingError(err)
+			return op
+		}
+		op.Input = input.toUsecase()
+
+	case usecases.BulkUpdate:
+		existing, err := c.taskUsecases.GetTaskByID(ctx, actor, req.ID)
+		if err != nil {
+			op.Err = err
+			return op
+		}
+		if req.Version != 0 && req.Version != existing.Version {
+			op.Err = repositories.ErrVersionConflict
+			return op
+		}
+		current, err := json.Marshal(newTaskInput(existing))
+		if err != nil {
+			op.Err = err
+			return op
+		}
+		var input taskInput
+		if err := applyMergePatch(current, req.Task, &input); err != nil {
+			op.Err = err
+			return op
+		}
+		op.Input = input.toUsecase()
+		op.Input.Version = existing.Version
+
+	case usecases.BulkDelete:
+		op.Input.Version = req.Version
+	}
+	return op
+}
+
 // DeleteTask handles DELETE /tasks/:id
 func (c *Controller) DeleteTask(ctx *gin.Context) {
 	id := ctx.Param("id")
-	if err := c.taskUsecases.DeleteTask(id); err != nil {
-		if err.Error() == "task not found" {
-			ctx.JSON(http.StatusNotFound, gin.H{"error": "task not found"})
-			return
-		}
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete task"})
+	if err := c.taskUsecases.DeleteTask(ctx.Request.Context(), currentActor(ctx), id); err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// ListTrash handles GET /trash
+func (c *Controller) ListTrash(ctx *gin.Context) {
+	query, ok := bindTaskQuery(ctx)
+	if !ok {
+		return
+	}
+	page, err := c.taskUsecases.ListTrash(ctx.Request.Context(), currentActor(ctx), query)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, page)
+}
+
+// RestoreTask handles POST /trash/:id/restore
+func (c *Controller) RestoreTask(ctx *gin.Context) {
+	task, err := c.taskUsecases.RestoreTask(ctx.Request.Context(), currentActor(ctx), ctx.Param("id"))
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	setETag(ctx, task)
+	ctx.JSON(http.StatusOK, gin.H{"data": task})
+}
+
+// PurgeTask handles DELETE /trash/:id
+func (c *Controller) PurgeTask(ctx *gin.Context) {
+	if err := c.taskUsecases.PurgeTask(ctx.Request.Context(), currentActor(ctx), ctx.Param("id")); err != nil {
+		ctx.Error(err)
 		return
 	}
 	ctx.Status(http.StatusNoContent)
 }
 
+// TaskHistory handles GET /tasks/:id/history
+func (c *Controller) TaskHistory(ctx *gin.Context) {
+	query, ok := bindAuditQuery(ctx)
+	if !ok {
+		return
+	}
+	page, err := c.taskUsecases.TaskHistory(ctx.Request.Context(), currentActor(ctx), ctx.Param("id"), query)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, page)
+}
+
+// GetWorkflow handles GET /workflow
+func (c *Controller) GetWorkflow(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, gin.H{"data": c.taskUsecases.Workflow()})
+}
+
 // User/Auth Handlers
 
 // Register handles POST /register
@@ -123,13 +529,13 @@ func (c *Controller) Register(ctx *gin.Context) {
 		Password string `json:"password" binding:"required"`
 	}
 	if err := ctx.ShouldBindJSON(&input); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		ctx.Error(bindingError(err))
 		return
 	}
 
-	user, err := c.userUsecases.RegisterUser(input.Username, input.Password)
+	user, err := c.userUsecases.RegisterUser(ctx.Request.Context(), input.Username, input.Password)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		ctx.Error(err)
 		return
 	}
 	ctx.JSON(http.StatusCreated, gin.H{"data": user})
@@ -142,35 +548,271 @@ func (c *Controller) Login(ctx *gin.Context) {
 		Password string `json:"password" binding:"required"`
 	}
 	if err := ctx.ShouldBindJSON(&input); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		ctx.Error(bindingError(err))
 		return
 	}
 
-	user, err := c.userUsecases.LoginUser(input.Username, input.Password)
+	user, err := c.userUsecases.LoginUser(ctx.Request.Context(), input.Username, input.Password, ctx.ClientIP())
 	if err != nil {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "invalid credentials"})
+		ctx.Error(err)
 		return
 	}
 
-	token, err := c.jwtService.GenerateToken(user)
+	tokens, err := c.tokenService.Issue(ctx.Request.Context(), user)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to generate token"})
+		ctx.Error(err)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, gin.H{"token": token})
+	ctx.JSON(http.StatusOK, tokens)
+}
+
+// Refresh handles POST /refresh
+func (c *Controller) Refresh(ctx *gin.Context) {
+	var input struct {
+		RefreshToken string `json:"refresh_token" binding:"required"`
+	}
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		ctx.Error(bindingError(err))
+		return
+	}
+
+	tokens, err := c.tokenService.Refresh(ctx.Request.Context(), input.RefreshToken)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, tokens)
+}
+
+// Logout handles POST /logout
+func (c *Controller) Logout(ctx *gin.Context) {
+	var input struct {
+		RefreshToken string `json:"refresh_token"`
+	}
+	if err := ctx.ShouldBindJSON(&input); err != nil && ctx.Request.ContentLength > 0 {
+		ctx.Error(bindingError(err))
+		return
+	}
+
+	if err := c.tokenService.Logout(ctx.Request.Context(), ctx.GetString("user_id"), ctx.GetString("jti"), input.RefreshToken); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// JWKS handles GET /.well-known/jwks.json
+func (c *Controller) JWKS(ctx *gin.Context) {
+	ctx.Header("Cache-Control", "public, max-age=300")
+	ctx.JSON(http.StatusOK, c.tokenService.JWKS())
 }
 
 // Promote handles POST /promote/:id
 func (c *Controller) Promote(ctx *gin.Context) {
 	id := ctx.Param("id")
-	if err := c.userUsecases.PromoteUser(id); err != nil {
-		if err.Error() == "user not found" {
-			ctx.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
-			return
-		}
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to promote user"})
+	if err := c.userUsecases.PromoteUser(ctx.Request.Context(), currentActor(ctx), id); err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// Demote handles POST /demote/:id
+func (c *Controller) Demote(ctx *gin.Context) {
+	id := ctx.Param("id")
+	if err := c.userUsecases.DemoteUser(ctx.Request.Context(), currentActor(ctx), id); err != nil {
+		ctx.Error(err)
 		return
 	}
 	ctx.Status(http.StatusNoContent)
 }
+
+// AssignRole handles PUT /users/:id/role
+func (c *Controller) AssignRole(ctx *gin.Context) {
+	id := ctx.Param("id")
+	var input struct {
+		Role string `json:"role" binding:"required"`
+	}
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		ctx.Error(bindingError(err))
+		return
+	}
+
+	if err := c.roleUsecases.AssignRole(ctx.Request.Context(), currentActor(ctx), id, input.Role); err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// Audit Handlers
+
+// ListAudit handles GET /audit
+func (c *Controller) ListAudit(ctx *gin.Context) {
+	query, ok := bindAuditQuery(ctx)
+	if !ok {
+		return
+	}
+	page, err := c.auditUsecases.ListAudit(ctx.Request.Context(), query)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, page)
+}
+
+// bindAuditQuery reads the audit log filters from the query string.
+// On failure it reports the error and returns false.
+func bindAuditQuery(ctx *gin.Context) (domain.AuditQuery, bool) {
+	var input struct {
+		ActorID    string    `form:"actor_id"`
+		Action     string    `form:"action"`
+		TargetType string    `form:"target_type"`
+		TargetID   string    `form:"target_id"`
+		Since      time.Time `form:"since" time_format:"2006-01-02T15:04:05Z07:00"`
+		Until      time.Time `form:"until" time_format:"2006-01-02T15:04:05Z07:00"`
+		Page       int       `form:"page"`
+		PageSize   int       `form:"page_size"`
+	}
+	if err := ctx.ShouldBindQuery(&input); err != nil {
+		ctx.Error(bindingError(err))
+		return domain.AuditQuery{}, false
+	}
+	return domain.AuditQuery{
+		ActorID:    input.ActorID,
+		Action:     input.Action,
+		TargetType: input.TargetType,
+		TargetID:   input.TargetID,
+		Since:      input.Since,
+		Until:      input.Until,
+		Page:       input.Page,
+		PageSize:   input.PageSize,
+	}, true
+}
+
+// Role Handlers
+
+// ListRoles handles GET /roles
+func (c *Controller) ListRoles(ctx *gin.Context) {
+	roles, err := c.roleUsecases.ListRoles(ctx.Request.Context())
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": roles, "permissions": domain.AllPermissions})
+}
+
+// SaveRole handles PUT /roles/:name
+func (c *Controller) SaveRole(ctx *gin.Context) {
+	var input struct {
+		Permissions []string `json:"permissions"`
+	}
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		ctx.Error(bindingError(err))
+		return
+	}
+
+	role, err := c.roleUsecases.SaveRole(ctx.Request.Context(), domain.Role{Name: ctx.Param("name"), Permissions: input.Permissions})
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": role})
+}
+
+// DeleteRole handles DELETE /roles/:name
+func (c *Controller) DeleteRole(ctx *gin.Context) {
+	if err := c.roleUsecases.DeleteRole(ctx.Request.Context(), ctx.Param("name")); err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// Webhook Handlers
+
+// CreateWebhook handles POST /webhooks
+func (c *Controller) CreateWebhook(ctx *gin.Context) {
+	var input struct {
+		URL    string   `json:"url" binding:"required"`
+		Events []string `json:"events" binding:"required"`
+	}
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		ctx.Error(bindingError(err))
+		return
+	}
+
+	hook, err := c.webhookUsecases.CreateWebhook(ctx.Request.Context(), currentActor(ctx), input.URL, input.Events)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, gin.H{"data": hook})
+}
+
+// ListWebhooks handles GET /webhooks
+func (c *Controller) ListWebhooks(ctx *gin.Context) {
+	webhooks, err := c.webhookUsecases.ListWebhooks(ctx.Request.Context())
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": webhooks, "events": domain.WebhookEvents})
+}
+
+// GetWebhook handles GET /webhooks/:id
+func (c *Controller) GetWebhook(ctx *gin.Context) {
+	hook, err := c.webhookUsecases.GetWebhook(ctx.Request.Context(), ctx.Param("id"))
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": hook})
+}
+
+// DeleteWebhook handles DELETE /webhooks/:id
+func (c *Controller) DeleteWebhook(ctx *gin.Context) {
+	if err := c.webhookUsecases.DeleteWebhook(ctx.Request.Context(), currentActor(ctx), ctx.Param("id")); err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// ListDeliveries handles GET /webhooks/:id/deliveries
+func (c *Controller) ListDeliveries(ctx *gin.Context) {
+	var input struct {
+		Status   string `form:"status"`
+		Page     int    `form:"page"`
+		PageSize int    `form:"page_size"`
+	}
+	if err := ctx.ShouldBindQuery(&input); err != nil {
+		ctx.Error(bindingError(err))
+		return
+	}
+
+	page, err := c.webhookUsecases.ListDeliveries(ctx.Request.Context(), domain.DeliveryQuery{
+		WebhookID: ctx.Param("id"),
+		Status:    input.Status,
+		Page:      input.Page,
+		PageSize:  input.PageSize,
+	})
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, page)
+}
+
+// Redeliver handles POST /webhooks/:id/deliveries/:delivery_id/redeliver
+func (c *Controller) Redeliver(ctx *gin.Context) {
+	delivery, err := c.webhookUsecases.Redeliver(ctx.Request.Context(), ctx.Param("id"), ctx.Param("delivery_id"))
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusAccepted, gin.H{"data": delivery})
+}
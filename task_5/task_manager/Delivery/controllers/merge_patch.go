@@ -0,0 +1,56 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+
+	domain "task_manager/Domain"
+)
+
+// applyMergePatch applies a JSON Merge Patch (RFC 7386) to the JSON object
+// doc and decodes the result into out. Fields out does not have are rejected.
+func applyMergePatch(doc, patch []byte, out interface{}) error {
+	var patchValue interface{}
+	if err := json.Unmarshal(patch, &patchValue); err != nil {
+		return domain.NewValidationError("invalid patch: " + err.Error())
+	}
+	if _, ok := patchValue.(map[string]interface{}); !ok {
+		return domain.NewValidationError("invalid patch: must be a JSON object")
+	}
+	var docValue interface{}
+	if err := json.Unmarshal(doc, &docValue); err != nil {
+		return err
+	}
+
+	merged, err := json.Marshal(mergePatch(docValue, patchValue))
+	if err != nil {
+		return err
+	}
+	dec := json.NewDecoder(bytes.NewReader(merged))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(out); err != nil {
+		return domain.NewValidationError("invalid patch: " + err.Error())
+	}
+	return nil
+}
+
+// mergePatch merges patch into target: null removes a member, objects are
+// merged recursively and any other value replaces the target.
+func mergePatch(target, patch interface{}) interface{} {
+	patchObject, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+	targetObject, ok := target.(map[string]interface{})
+	if !ok {
+		targetObject = map[string]interface{}{}
+	}
+	for key, value := range patchObject {
+		if value == nil {
+			delete(targetObject, key)
+		} else {
+			targetObject[key] = mergePatch(targetObject[key], value)
+		}
+	}
+	return targetObject
+}
@@ -0,0 +1,101 @@
+package controllers
+
+import (
+	"net/http"
+
+	domain "task_manager/Domain"
+	usecases "task_manager/Usecases"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListProjects handles GET /projects
+func (c *Controller) ListProjects(ctx *gin.Context) {
+	projects, err := c.projectUsecases.ListProjects(ctx.Request.Context(), currentActor(ctx))
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": projects})
+}
+
+// CreateProject handles POST /projects
+func (c *Controller) CreateProject(ctx *gin.Context) {
+	var input struct {
+		Name        string                 `json:"name" binding:"required"`
+		Description string                 `json:"description"`
+		Members     []domain.ProjectMember `json:"members"`
+	}
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		ctx.Error(bindingError(err))
+		return
+	}
+
+	project, err := c.projectUsecases.CreateProject(ctx.Request.Context(), currentActor(ctx), usecases.ProjectInput{
+		Name:        input.Name,
+		Description: input.Description,
+		Members:     input.Members,
+	})
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, gin.H{"data": project})
+}
+
+// GetProject handles GET /projects/:pid
+func (c *Controller) GetProject(ctx *gin.Context) {
+	project, err := c.projectUsecases.GetProject(ctx.Request.Context(), currentActor(ctx), ctx.Param("pid"))
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": project})
+}
+
+// ArchiveProject handles POST /projects/:pid/archive
+func (c *Controller) ArchiveProject(ctx *gin.Context) {
+	project, err := c.projectUsecases.ArchiveProject(ctx.Request.Context(), currentActor(ctx), ctx.Param("pid"))
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": project})
+}
+
+// UnarchiveProject handles POST /projects/:pid/unarchive
+func (c *Controller) UnarchiveProject(ctx *gin.Context) {
+	project, err := c.projectUsecases.UnarchiveProject(ctx.Request.Context(), currentActor(ctx), ctx.Param("pid"))
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": project})
+}
+
+// SetProjectMember handles PUT /projects/:pid/members/:user_id
+func (c *Controller) SetProjectMember(ctx *gin.Context) {
+	var input struct {
+		Role string `json:"role" binding:"required"`
+	}
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		ctx.Error(bindingError(err))
+		return
+	}
+
+	project, err := c.projectUsecases.SetMember(ctx.Request.Context(), currentActor(ctx), ctx.Param("pid"), ctx.Param("user_id"), input.Role)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": project})
+}
+
+// RemoveProjectMember handles DELETE /projects/:pid/members/:user_id
+func (c *Controller) RemoveProjectMember(ctx *gin.Context) {
+	if err := c.projectUsecases.RemoveMember(ctx.Request.Context(), currentActor(ctx), ctx.Param("pid"), ctx.Param("user_id")); err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
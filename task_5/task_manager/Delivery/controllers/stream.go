@@ -0,0 +1,147 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	domain "task_manager/Domain"
+	usecases "task_manager/Usecases"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/net/websocket"
+)
+
+// streamKeepAlive is how long a stream may stay silent before a keep-alive
+// is sent, so that proxies do not close idle connections.
+var streamKeepAlive = 30 * time.Second
+
+// wsPing sends WebSocket ping frames. Going through a codec gives each ping
+// its own frame writer, so the connection's PayloadType stays untouched.
+var wsPing = websocket.Codec{Marshal: func(interface{}) ([]byte, byte, error) {
+	return nil, websocket.PingFrame, nil
+}}
+
+// StreamTasks handles GET /tasks/stream
+// WebSocket upgrade requests get a WebSocket stream, every other request Server-Sent Events.
+func (c *Controller) StreamTasks(ctx *gin.Context) {
+	lastEventID, err := lastEventID(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	stream := c.taskUsecases.StreamTasks(currentActor(ctx), lastEventID)
+	defer stream.Close()
+
+	if strings.EqualFold(ctx.GetHeader("Upgrade"), "websocket") {
+		streamWebSocket(ctx, stream)
+		return
+	}
+	streamSSE(ctx, stream)
+}
+
+// lastEventID reads the ID to resume after from the Last-Event-ID header
+// sent by reconnecting SSE clients, or the last_event_id query parameter.
+func lastEventID(ctx *gin.Context) (int64, error) {
+	value := ctx.GetHeader("Last-Event-ID")
+	if value == "" {
+		value = ctx.Query("last_event_id")
+	}
+	if value == "" {
+		return 0, nil
+	}
+	id, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || id < 0 {
+		return 0, domain.NewValidationError("invalid last event ID",
+			domain.FieldError{Field: "last_event_id", Message: "must be a non-negative integer"})
+	}
+	return id, nil
+}
+
+func streamSSE(ctx *gin.Context, stream *usecases.TaskStream) {
+	header := ctx.Writer.Header()
+	header.Set("Content-Type", "text/event-stream")
+	header.Set("Cache-Control", "no-cache")
+	header.Set("X-Accel-Buffering", "no")
+	ctx.Status(http.StatusOK)
+
+	w := ctx.Writer
+	send := func(event domain.TaskEvent) error {
+		data, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+		if event.ID != 0 {
+			fmt.Fprintf(w, "id: %d\n", event.ID)
+		}
+		if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, data); err != nil {
+			return err
+		}
+		w.Flush()
+		return nil
+	}
+	keepAlive := func() error {
+		if _, err := io.WriteString(w, ": keep-alive\n\n"); err != nil {
+			return err
+		}
+		w.Flush()
+		return nil
+	}
+
+	w.WriteHeaderNow()
+	w.Flush()
+	pumpEvents(ctx.Request.Context(), stream, send, keepAlive)
+}
+
+func streamWebSocket(ctx *gin.Context, stream *usecases.TaskStream) {
+	// Clients authenticate with a bearer token rather than cookies, so
+	// cross-origin connections cannot ride on a user's session
+	server := websocket.Server{Handler: func(ws *websocket.Conn) {
+		streamCtx, cancel := context.WithCancel(ctx.Request.Context())
+		defer cancel()
+		// Clients send nothing; reading only notices when they go away
+		go func() {
+			io.Copy(io.Discard, ws)
+			cancel()
+		}()
+
+		send := func(event domain.TaskEvent) error {
+			return websocket.JSON.Send(ws, event)
+		}
+		keepAlive := func() error {
+			return wsPing.Send(ws, nil)
+		}
+		pumpEvents(streamCtx, stream, send, keepAlive)
+	}}
+	server.ServeHTTP(ctx.Writer, ctx.Request)
+}
+
+// pumpEvents sends the stream's events until the client goes away or the
+// stream is closed, calling keepAlive whenever no event arrived for a while.
+func pumpEvents(ctx context.Context, stream *usecases.TaskStream, send func(domain.TaskEvent) error, keepAlive func() error) {
+	if stream.Reset() {
+		if err := send(domain.TaskEvent{Type: domain.EventStreamReset, CreatedAt: time.Now().UTC()}); err != nil {
+			return
+		}
+	}
+	for {
+		waitCtx, cancel := context.WithTimeout(ctx, streamKeepAlive)
+		event, err := stream.Next(waitCtx)
+		cancel()
+		switch {
+		case err == nil:
+			err = send(event)
+		case errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil:
+			err = keepAlive()
+		}
+		if err != nil {
+			return
+		}
+	}
+}
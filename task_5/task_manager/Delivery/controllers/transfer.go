@@ -0,0 +1,155 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+
+	domain "task_manager/Domain"
+	infrastructure "task_manager/Infrastructure"
+	usecases "task_manager/Usecases"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxImportSize bounds the body of an import.
+const maxImportSize = 10 << 20
+
+// transferFormat reads the format query parameter, which defaults to CSV.
+func transferFormat(ctx *gin.Context) (string, string, error) {
+	format := ctx.DefaultQuery("format", infrastructure.FormatCSV)
+	contentType, ok := infrastructure.TaskFormatType(format)
+	if !ok {
+		return "", "", domain.NewValidationError("unknown format: "+format,
+			domain.FieldError{Field: "format", Message: "format must be csv, jsonl or ics"})
+	}
+	return format, contentType, nil
+}
+
+// ExportTasks handles GET /tasks/export
+// It streams the tasks that match the list filters, ignoring paging.
+func (c *Controller) ExportTasks(ctx *gin.Context) {
+	format, contentType, err := transferFormat(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	query, ok := bindTaskQuery(ctx)
+	if !ok {
+		return
+	}
+	query.Page, query.PageSize = 0, 0
+
+	ctx.Header("Content-Type", contentType)
+	ctx.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="tasks.%s"`, format))
+	encoder, err := infrastructure.NewTaskEncoder(format, ctx.Writer, c.taskUsecases.Workflow())
+	if err == nil {
+		err = c.taskUsecases.ExportTasks(ctx.Request.Context(), currentActor(ctx), query, encoder.Encode)
+	}
+	if err == nil {
+		err = encoder.Close()
+	}
+	if err == nil {
+		return
+	}
+	if ctx.Writer.Written() {
+		// The status is sent, so the client only sees a truncated file
+		log.Printf("request %s: export failed: %v", ctx.GetString("request_id"), err)
+		return
+	}
+	ctx.Header("Content-Type", "")
+	ctx.Header("Content-Disposition", "")
+	ctx.Error(err)
+}
+
+// importError is why the task on a line of an import was not imported.
+type importError struct {
+	Line  int                      `json:"line"`
+	Error infrastructure.ErrorBody `json:"error"`
+}
+
+// importReport is the response body of POST /tasks/import.
+type importReport struct {
+	DryRun   bool          `json:"dry_run"`
+	Valid    int           `json:"valid"`
+	Imported int           `json:"imported"`
+	Errors   []importError `json:"errors"`
+}
+
+// ImportTasks handles POST /tasks/import
+// The body is the file itself. Nothing is imported unless every task is
+// valid, and with dry_run=true the tasks are only validated.
+func (c *Controller) ImportTasks(ctx *gin.Context) {
+	format, _, err := transferFormat(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	var query struct {
+		DryRun bool `form:"dry_run"`
+	}
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.Error(bindingError(err))
+		return
+	}
+
+	body := http.MaxBytesReader(ctx.Writer, ctx.Request.Body, maxImportSize)
+	decoder, err := infrastructure.NewTaskDecoder(format, body, c.taskUsecases.Workflow())
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	var records []usecases.ImportRecord
+	for {
+		record, err := decoder.Next()
+		if err == io.EOF {
+			break
+		}
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			message := fmt.Sprintf("import must not exceed %d bytes", maxImportSize)
+			ctx.Error(domain.NewValidationError(message, domain.FieldError{Field: "file", Message: message}))
+			return
+		}
+		if err != nil {
+			ctx.Error(err)
+			return
+		}
+		if len(records) == usecases.MaxImportTasks {
+			message := fmt.Sprintf("at most %d tasks can be imported at once", usecases.MaxImportTasks)
+			ctx.Error(domain.NewValidationError(message, domain.FieldError{Field: "file", Message: message}))
+			return
+		}
+		records = append(records, usecases.ImportRecord{
+			Line:  record.Line,
+			Input: newTaskInput(record.Task).toUsecase(),
+			Err:   record.Err,
+		})
+	}
+
+	report, err := c.taskUsecases.ImportTasks(ctx.Request.Context(), currentActor(ctx), records, query.DryRun)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	response := importReport{DryRun: report.DryRun, Valid: report.Valid, Imported: report.Imported, Errors: []importError{}}
+	for _, e := range report.Errors {
+		code, body := infrastructure.DescribeError(e.Err)
+		if code == http.StatusInternalServerError {
+			log.Printf("request %s: import of line %d failed: %v", ctx.GetString("request_id"), e.Line, e.Err)
+		}
+		body.RequestID = ctx.GetString("request_id")
+		response.Errors = append(response.Errors, importError{Line: e.Line, Error: body})
+	}
+
+	status := http.StatusCreated
+	switch {
+	case report.DryRun:
+		status = http.StatusOK
+	case len(report.Errors) > 0:
+		status = http.StatusUnprocessableEntity
+	}
+	ctx.JSON(status, gin.H{"data": response})
+}
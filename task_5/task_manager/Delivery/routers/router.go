@@ -2,6 +2,7 @@ package routers
 
 import (
 	"task_manager/Delivery/controllers"
+	domain "task_manager/Domain"
 	infrastructure "task_manager/Infrastructure"
 	usecases "task_manager/Usecases"
 
@@ -9,26 +10,83 @@ import (
 )
 
 // SetupRouter initializes the Gin router with routes and middleware.
-func SetupRouter(taskUsecases *usecases.TaskUsecases, userUsecases *usecases.UserUsecases, jwtService *infrastructure.JWTService, authMiddleware *infrastructure.AuthMiddleware) *gin.Engine {
+func SetupRouter(taskUsecases *usecases.TaskUsecases, userUsecases *usecases.UserUsecases, roleUsecases *usecases.RoleUsecases, auditUsecases *usecases.AuditUsecases, webhookUsecases *usecases.WebhookUsecases, commentUsecases *usecases.CommentUsecases, attachmentUsecases *usecases.AttachmentUsecases, projectUsecases *usecases.ProjectUsecases, tokenService *infrastructure.TokenService, authMiddleware *infrastructure.AuthMiddleware, rateLimiter *infrastructure.RateLimiter) *gin.Engine {
 	r := gin.Default()
+	r.Use(infrastructure.ErrorHandler())
+	r.NoRoute(func(c *gin.Context) {
+		c.Error(domain.NewNotFoundError("route not found"))
+	})
 
-	ctrl := controllers.NewController(taskUsecases, userUsecases, jwtService)
+	ctrl := controllers.NewController(taskUsecases, userUsecases, roleUsecases, auditUsecases, webhookUsecases, commentUsecases, attachmentUsecases, projectUsecases, tokenService)
 
-	// Public routes
-	r.POST("/register", ctrl.Register)
-	r.POST("/login", ctrl.Login)
+	// Public routes; the credential routes are throttled against brute force
+	r.POST("/register", rateLimiter.Limit("register"), ctrl.Register)
+	r.POST("/login", rateLimiter.Limit("login"), ctrl.Login)
+	r.POST("/refresh", ctrl.Refresh)
+	r.GET("/.well-known/jwks.json", ctrl.JWKS)
 
 	// Protected routes
 	protected := r.Group("/")
 	protected.Use(authMiddleware.AuthRequired())
 	{
-		protected.GET("/tasks", ctrl.ListTasks)
-		protected.GET("/tasks/:id", ctrl.GetTask)
-		protected.POST("/tasks", ctrl.CreateTask)
-		protected.PUT("/tasks/:id", ctrl.UpdateTask)
-		protected.DELETE("/tasks/:id", authMiddleware.AdminRequired(), ctrl.DeleteTask)
-		protected.POST("/promote/:id", authMiddleware.AdminRequired(), ctrl.Promote)
+		protected.POST("/logout", ctrl.Logout)
+		// The task routes without a project work in the default project
+		registerTaskRoutes(protected, ctrl, authMiddleware)
+		protected.GET("/projects", ctrl.ListProjects)
+		protected.POST("/projects", authMiddleware.RequirePermission(domain.PermProjectsManage), ctrl.CreateProject)
+		protected.GET("/projects/:pid", ctrl.GetProject)
+		protected.POST("/projects/:pid/archive", authMiddleware.RequirePermission(domain.PermProjectsManage), ctrl.ArchiveProject)
+		protected.POST("/projects/:pid/unarchive", authMiddleware.RequirePermission(domain.PermProjectsManage), ctrl.UnarchiveProject)
+		// Project managers change the members of their own projects
+		protected.PUT("/projects/:pid/members/:user_id", ctrl.SetProjectMember)
+		protected.DELETE("/projects/:pid/members/:user_id", ctrl.RemoveProjectMember)
+		registerTaskRoutes(protected.Group("/projects/:pid", infrastructure.ProjectScope(projectUsecases)), ctrl, authMiddleware)
+		protected.GET("/workflow", authMiddleware.RequirePermission(domain.PermTasksRead), ctrl.GetWorkflow)
+		protected.POST("/promote/:id", authMiddleware.RequirePermission(domain.PermUsersPromote), ctrl.Promote)
+		protected.POST("/demote/:id", authMiddleware.RequirePermission(domain.PermUsersDemote), ctrl.Demote)
+		protected.PUT("/users/:id/role", authMiddleware.RequirePermission(domain.PermUsersPromote), ctrl.AssignRole)
+		protected.GET("/audit", authMiddleware.RequirePermission(domain.PermAuditRead), ctrl.ListAudit)
+		protected.GET("/roles", authMiddleware.RequirePermission(domain.PermRolesManage), ctrl.ListRoles)
+		protected.PUT("/roles/:name", authMiddleware.RequirePermission(domain.PermRolesManage), ctrl.SaveRole)
+		protected.DELETE("/roles/:name", authMiddleware.RequirePermission(domain.PermRolesManage), ctrl.DeleteRole)
+		protected.POST("/webhooks", authMiddleware.RequirePermission(domain.PermWebhooksManage), ctrl.CreateWebhook)
+		protected.GET("/webhooks", authMiddleware.RequirePermission(domain.PermWebhooksManage), ctrl.ListWebhooks)
+		protected.GET("/webhooks/:id", authMiddleware.RequirePermission(domain.PermWebhooksManage), ctrl.GetWebhook)
+		protected.DELETE("/webhooks/:id", authMiddleware.RequirePermission(domain.PermWebhooksManage), ctrl.DeleteWebhook)
+		protected.GET("/webhooks/:id/deliveries", authMiddleware.RequirePermission(domain.PermWebhooksManage), ctrl.ListDeliveries)
+		protected.POST("/webhooks/:id/deliveries/:delivery_id/redeliver", authMiddleware.RequirePermission(domain.PermWebhooksManage), ctrl.Redeliver)
 	}
 
 	return r
 }
+
+// registerTaskRoutes adds the routes of tasks, their comments and
+// attachments, and the trash to the group.
+func registerTaskRoutes(g *gin.RouterGroup, ctrl *controllers.Controller, authMiddleware *infrastructure.AuthMiddleware) {
+	g.GET("/tasks", authMiddleware.RequirePermission(domain.PermTasksRead), ctrl.ListTasks)
+	g.GET("/tasks/stream", authMiddleware.RequirePermission(domain.PermTasksRead), ctrl.StreamTasks)
+	g.GET("/tasks/export", authMiddleware.RequirePermission(domain.PermTasksRead), ctrl.ExportTasks)
+	g.GET("/tasks/search", authMiddleware.RequirePermission(domain.PermTasksRead), ctrl.SearchTasks)
+	g.GET("/tasks/:id", authMiddleware.RequirePermission(domain.PermTasksRead), ctrl.GetTask)
+	g.POST("/tasks", authMiddleware.RequirePermission(domain.PermTasksCreate), ctrl.CreateTask)
+	g.POST("/tasks/import", authMiddleware.RequirePermission(domain.PermTasksCreate), ctrl.ImportTasks)
+	// Each operation of a bulk request checks its own permission
+	g.POST("/tasks/bulk", ctrl.BulkTasks)
+	g.PUT("/tasks/:id", authMiddleware.RequirePermission(domain.PermTasksUpdate), ctrl.UpdateTask)
+	g.PATCH("/tasks/:id", authMiddleware.RequirePermission(domain.PermTasksUpdate), ctrl.PatchTask)
+	g.DELETE("/tasks/:id", authMiddleware.RequirePermission(domain.PermTasksDelete), ctrl.DeleteTask)
+	g.GET("/tasks/:id/history", authMiddleware.RequirePermission(domain.PermTasksRead), ctrl.TaskHistory)
+	// Anyone who can read a task can discuss it; edits are checked against the author
+	g.GET("/tasks/:id/comments", authMiddleware.RequirePermission(domain.PermTasksRead), ctrl.ListComments)
+	g.POST("/tasks/:id/comments", authMiddleware.RequirePermission(domain.PermTasksRead), ctrl.AddComment)
+	g.PATCH("/tasks/:id/comments/:comment_id", authMiddleware.RequirePermission(domain.PermTasksRead), ctrl.EditComment)
+	g.DELETE("/tasks/:id/comments/:comment_id", authMiddleware.RequirePermission(domain.PermTasksRead), ctrl.DeleteComment)
+	// Attaching files changes a task; reading them is part of reading it
+	g.GET("/tasks/:id/attachments", authMiddleware.RequirePermission(domain.PermTasksRead), ctrl.ListAttachments)
+	g.POST("/tasks/:id/attachments", authMiddleware.RequirePermission(domain.PermTasksUpdate), ctrl.UploadAttachment)
+	g.GET("/tasks/:id/attachments/:attachment_id", authMiddleware.RequirePermission(domain.PermTasksRead), ctrl.DownloadAttachment)
+	g.DELETE("/tasks/:id/attachments/:attachment_id", authMiddleware.RequirePermission(domain.PermTasksUpdate), ctrl.DeleteAttachment)
+	g.GET("/trash", authMiddleware.RequirePermission(domain.PermTasksTrash), ctrl.ListTrash)
+	g.POST("/trash/:id/restore", authMiddleware.RequirePermission(domain.PermTasksTrash), ctrl.RestoreTask)
+	g.DELETE("/trash/:id", authMiddleware.RequirePermission(domain.PermTasksTrash), ctrl.PurgeTask)
+}
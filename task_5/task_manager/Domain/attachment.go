@@ -0,0 +1,52 @@
+package domain
+
+import (
+	"path"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Attachment limits.
+const (
+	MaxAttachmentsPerTask  = 50
+	MaxAttachmentNameBytes = 255
+)
+
+// Attachment is a file attached to a task. Its content lives in a blob
+// store under BlobKey; this is only its metadata.
+type Attachment struct {
+	ID          string `json:"id" bson:"_id,omitempty"`
+	TaskID      string `json:"task_id" bson:"task_id"`
+	UploaderID  string `json:"uploader_id" bson:"uploader_id"`
+	Filename    string `json:"filename" bson:"filename"`
+	ContentType string `json:"content_type" bson:"content_type"`
+	Size        int64  `json:"size" bson:"size"`
+	// SHA256 is the hex-encoded SHA-256 checksum of the content.
+	SHA256    string    `json:"sha256" bson:"sha256"`
+	BlobKey   string    `json:"-" bson:"blob_key"`
+	CreatedAt time.Time `json:"created_at" bson:"created_at"`
+}
+
+// CleanAttachmentName reduces a client supplied file name to its last path
+// element without control characters, so that it is safe to echo back in
+// a Content-Disposition header.
+func CleanAttachmentName(name string) string {
+	name = path.Base(strings.ReplaceAll(name, `\`, "/"))
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) || r == '"' {
+			return -1
+		}
+		return r
+	}, name)
+	name = strings.TrimSpace(name)
+	for len(name) > MaxAttachmentNameBytes {
+		_, size := utf8.DecodeLastRuneInString(name)
+		name = name[:len(name)-size]
+	}
+	if name == "" || name == "." || name == "/" {
+		return "attachment"
+	}
+	return name
+}
@@ -0,0 +1,232 @@
+package domain
+
+import (
+	"bytes"
+	"encoding/json"
+	"time"
+)
+
+// Audited actions.
+const (
+	AuditTaskCreate    = "task.create"
+	AuditTaskUpdate    = "task.update"
+	AuditTaskDelete    = "task.delete"
+	AuditTaskRestore   = "task.restore"
+	AuditTaskPurge     = "task.purge"
+	AuditUserPromote   = "user.promote"
+	AuditUserDemote    = "user.demote"
+	AuditUserSetRole   = "user.set_role"
+	AuditWebhookCreate = "webhook.create"
+	AuditWebhookDelete = "webhook.delete"
+
+	AuditProjectCreate       = "project.create"
+	AuditProjectArchive      = "project.archive"
+	AuditProjectUnarchive    = "project.unarchive"
+	AuditProjectSetMember    = "project.set_member"
+	AuditProjectRemoveMember = "project.remove_member"
+)
+
+// Kinds of audited targets.
+const (
+	AuditTargetTask    = "task"
+	AuditTargetUser    = "user"
+	AuditTargetWebhook = "webhook"
+	AuditTargetProject = "project"
+)
+
+// AuditEntry records who changed what and when. Entries are never modified.
+type AuditEntry struct {
+	ID         string        `json:"id" bson:"_id,omitempty"`
+	ActorID    string        `json:"actor_id" bson:"actor_id"`
+	ActorRole  string        `json:"actor_role" bson:"actor_role"`
+	Action     string        `json:"action" bson:"action"`
+	TargetType string        `json:"target_type" bson:"target_type"`
+	TargetID   string        `json:"target_id" bson:"target_id"`
+	Changes    []FieldChange `json:"changes" bson:"changes"`
+	CreatedAt  time.Time     `json:"created_at" bson:"created_at"`
+}
+
+// FieldChange is the old and new JSON value of one field.
+// Old is omitted for fields that were set, New for fields that were cleared.
+type FieldChange struct {
+	Field string          `json:"field" bson:"field"`
+	Old   json.RawMessage `json:"old,omitempty" bson:"old,omitempty"`
+	New   json.RawMessage `json:"new,omitempty" bson:"new,omitempty"`
+}
+
+// NewAuditEntry records an action of the actor on a target.
+func NewAuditEntry(actor Actor, action, targetType, targetID string, changes []FieldChange) AuditEntry {
+	if changes == nil {
+		changes = []FieldChange{}
+	}
+	return AuditEntry{
+		ActorID:    actor.UserID,
+		ActorRole:  actor.Role,
+		Action:     action,
+		TargetType: targetType,
+		TargetID:   targetID,
+		Changes:    changes,
+		CreatedAt:  time.Now().UTC(),
+	}
+}
+
+// DiffTasks lists the client-visible fields that differ between two
+// versions of a task. Diff against a zero Task to record a creation or deletion.
+func DiffTasks(before, after Task) []FieldChange {
+	var d differ
+	d.add("title", before.Title, after.Title)
+	d.add("description", before.Description, after.Description)
+	d.add("due_date", before.DueDate, after.DueDate)
+	d.add("status", before.Status, after.Status)
+	d.add("priority", before.Priority, after.Priority)
+	d.add("tags", emptyToNil(before.Tags), emptyToNil(after.Tags))
+	d.add("assignee_ids", emptyToNil(before.AssigneeIDs), emptyToNil(after.AssigneeIDs))
+	d.add("parent_id", before.ParentID, after.ParentID)
+	d.add("recurrence", before.Recurrence, after.Recurrence)
+	d.add("owner_id", before.OwnerID, after.OwnerID)
+	d.add("project_id", before.ProjectID, after.ProjectID)
+	return d.changes
+}
+
+// DiffWebhooks lists the fields that differ between two webhooks, leaving
+// out the secret. Diff against a zero Webhook to record a creation or deletion.
+func DiffWebhooks(before, after Webhook) []FieldChange {
+	var d differ
+	d.add("url", before.URL, after.URL)
+	d.add("events", emptyToNil(before.Events), emptyToNil(after.Events))
+	return d.changes
+}
+
+// DiffField records a single field change, or none when the values are equal.
+func DiffField(field string, before, after interface{}) []FieldChange {
+	var d differ
+	d.add(field, before, after)
+	return d.changes
+}
+
+type differ struct {
+	changes []FieldChange
+}
+
+func (d *differ) add(field string, before, after interface{}) {
+	oldValue, newValue := auditValue(before), auditValue(after)
+	if bytes.Equal(oldValue, newValue) {
+		return
+	}
+	d.changes = append(d.changes, FieldChange{Field: field, Old: oldValue, New: newValue})
+}
+
+// auditValue encodes v as JSON, or returns nil for zero values.
+func auditValue(v interface{}) json.RawMessage {
+	switch x := v.(type) {
+	case string:
+		if x == "" {
+			return nil
+		}
+	case time.Time:
+		if x.IsZero() {
+			return nil
+		}
+	case []string:
+		if x == nil {
+			return nil
+		}
+	case *Recurrence:
+		if x == nil {
+			return nil
+		}
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	return data
+}
+
+func emptyToNil(values []string) []string {
+	if len(values) == 0 {
+		return nil
+	}
+	return values
+}
+
+// AuditQuery describes a filtered, paginated audit log listing, newest first.
+type AuditQuery struct {
+	ActorID    string
+	Action     string
+	TargetType string
+	TargetID   string
+	Since      time.Time
+	Until      time.Time
+	Page       int
+	PageSize   int
+}
+
+// Normalize fills in defaults and validates the query.
+func (q *AuditQuery) Normalize() error {
+	if q.Page == 0 {
+		q.Page = 1
+	}
+	if q.Page < 0 {
+		return invalidField("page", "invalid page")
+	}
+	if q.PageSize == 0 {
+		q.PageSize = DefaultPageSize
+	}
+	if q.PageSize < 0 || q.PageSize > MaxPageSize {
+		return invalidField("page_size", "invalid page size")
+	}
+	if !q.Since.IsZero() && !q.Until.IsZero() && q.Until.Before(q.Since) {
+		return invalidField("until", "until must not be before since")
+	}
+	return nil
+}
+
+// Matches reports whether the entry satisfies the query's filters.
+func (q AuditQuery) Matches(e AuditEntry) bool {
+	if q.ActorID != "" && e.ActorID != q.ActorID {
+		return false
+	}
+	if q.Action != "" && e.Action != q.Action {
+		return false
+	}
+	if q.TargetType != "" && e.TargetType != q.TargetType {
+		return false
+	}
+	if q.TargetID != "" && e.TargetID != q.TargetID {
+		return false
+	}
+	if !q.Since.IsZero() && e.CreatedAt.Before(q.Since) {
+		return false
+	}
+	if !q.Until.IsZero() && e.CreatedAt.After(q.Until) {
+		return false
+	}
+	return true
+}
+
+// Skip returns the number of entries before the requested page.
+func (q AuditQuery) Skip() int64 {
+	return int64(q.Page-1) * int64(q.PageSize)
+}
+
+// AuditPage is one page of an audit log listing.
+type AuditPage struct {
+	Entries  []AuditEntry `json:"data"`
+	Total    int64        `json:"total"`
+	Page     int          `json:"page"`
+	PageSize int          `json:"page_size"`
+	NextPage int          `json:"next_page,omitempty"`
+}
+
+// NewAuditPage builds the page for the query, setting NextPage when more entries remain.
+func NewAuditPage(q AuditQuery, entries []AuditEntry, total int64) AuditPage {
+	if entries == nil {
+		entries = []AuditEntry{}
+	}
+	page := AuditPage{Entries: entries, Total: total, Page: q.Page, PageSize: q.PageSize}
+	if q.Skip()+int64(len(entries)) < total {
+		page.NextPage = q.Page + 1
+	}
+	return page
+}
@@ -0,0 +1,112 @@
+package domain
+
+import (
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Comment limits.
+const (
+	MaxCommentLength = 10000 // characters
+	MaxMentions      = 20    // distinct users one comment may mention
+)
+
+// Comment is a message in the discussion of a task.
+type Comment struct {
+	ID       string    `json:"id" bson:"_id,omitempty"`
+	TaskID   string    `json:"task_id" bson:"task_id"`
+	AuthorID string    `json:"author_id" bson:"author_id"`
+	Body     string    `json:"body" bson:"body"`
+	Mentions []Mention `json:"mentions" bson:"mentions"`
+	// EditedAt is when the body was last changed, if ever.
+	EditedAt  *time.Time `json:"edited_at,omitempty" bson:"edited_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at" bson:"created_at"`
+}
+
+// Mention is a user mentioned in a comment with @username.
+type Mention struct {
+	UserID   string `json:"user_id" bson:"user_id"`
+	Username string `json:"username" bson:"username"`
+}
+
+// Normalize trims the body.
+func (c *Comment) Normalize() {
+	c.Body = strings.TrimSpace(c.Body)
+}
+
+// Validate checks that the comment has a body of acceptable length.
+func (c *Comment) Validate() error {
+	if c.Body == "" {
+		return invalidField("body", "body is required")
+	}
+	if utf8.RuneCountInString(c.Body) > MaxCommentLength {
+		return invalidField("body", "body must be at most 10000 characters long")
+	}
+	return nil
+}
+
+// mentionPattern matches @username where the @ does not follow a word
+// character, so that email addresses are not mentions.
+var mentionPattern = regexp.MustCompile(`(?:^|[^\p{L}\p{N}_])@([\p{L}\p{N}_.-]+)`)
+
+// ParseMentions returns the usernames mentioned in the body, in the order
+// they first appear. A trailing period ends the sentence, not the username.
+func ParseMentions(body string) []string {
+	var usernames []string
+	for _, m := range mentionPattern.FindAllStringSubmatch(body, -1) {
+		usernames = append(usernames, strings.TrimRight(m[1], "."))
+	}
+	return uniqueStrings(usernames, func(s string) string { return s })
+}
+
+// CommentQuery describes a paginated listing of a task's comments, oldest first.
+type CommentQuery struct {
+	TaskID   string
+	Page     int
+	PageSize int
+}
+
+// Normalize fills in defaults and validates the query.
+func (q *CommentQuery) Normalize() error {
+	if q.Page == 0 {
+		q.Page = 1
+	}
+	if q.Page < 0 {
+		return invalidField("page", "invalid page")
+	}
+	if q.PageSize == 0 {
+		q.PageSize = DefaultPageSize
+	}
+	if q.PageSize < 0 || q.PageSize > MaxPageSize {
+		return invalidField("page_size", "invalid page size")
+	}
+	return nil
+}
+
+// Skip returns the number of comments before the requested page.
+func (q CommentQuery) Skip() int64 {
+	return int64(q.Page-1) * int64(q.PageSize)
+}
+
+// CommentPage is one page of a comment listing.
+type CommentPage struct {
+	Comments []Comment `json:"data"`
+	Total    int64     `json:"total"`
+	Page     int       `json:"page"`
+	PageSize int       `json:"page_size"`
+	NextPage int       `json:"next_page,omitempty"`
+}
+
+// NewCommentPage builds the page for the query, setting NextPage when more comments remain.
+func NewCommentPage(q CommentQuery, comments []Comment, total int64) CommentPage {
+	if comments == nil {
+		comments = []Comment{}
+	}
+	page := CommentPage{Comments: comments, Total: total, Page: q.Page, PageSize: q.PageSize}
+	if q.Skip()+int64(len(comments)) < total {
+		page.NextPage = q.Page + 1
+	}
+	return page
+}
@@ -1,12 +1,37 @@
 package domain
 
 import (
-	"errors"
+	"strings"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// Statuses of the default workflow.
+const (
+	StatusPending    = "pending"
+	StatusInProgress = "in_progress"
+	StatusCompleted  = "completed"
+)
+
+// Task priorities, from least to most pressing.
+const (
+	PriorityLow    = "low"
+	PriorityMedium = "medium"
+	PriorityHigh   = "high"
+	PriorityUrgent = "urgent"
+)
+
+// DueDateSkew is how far in the past a new task's due date may be,
+// allowing for clock differences between clients and the server.
+const DueDateSkew = time.Minute
+
+// Tag limits.
+const (
+	MaxTags      = 20
+	MaxTagLength = 32
+)
+
 // Task represents a task entity with business rules.
 type Task struct {
 	ID          string    `json:"id" bson:"_id,omitempty"`
@@ -14,40 +39,429 @@ type Task struct {
 	Description string    `json:"description" bson:"description"`
 	DueDate     time.Time `json:"due_date" bson:"due_date"`
 	Status      string    `json:"status" bson:"status"`
+	Priority    string    `json:"priority" bson:"priority"`
+	Tags        []string  `json:"tags" bson:"tags"`
+	AssigneeIDs []string  `json:"assignee_ids" bson:"assignee_ids"`
+	ParentID    string    `json:"parent_id,omitempty" bson:"parent_id,omitempty"` // set on subtasks
+	OwnerID     string    `json:"owner_id" bson:"owner_id"`
+	ProjectID   string    `json:"project_id" bson:"project_id,omitempty"`
+	Version     int64     `json:"version" bson:"version"` // incremented on every update
+
+	// Recurrence, when set, creates the next occurrence once the task is completed.
+	Recurrence *Recurrence `json:"recurrence,omitempty" bson:"recurrence,omitempty"`
+
+	// DeletedAt is set while the task is in the trash.
+	DeletedAt *time.Time `json:"deleted_at,omitempty" bson:"deleted_at,omitempty"`
+
+	// NextStatuses lists the statuses the caller may move the task to.
+	// It is filled in per request and never stored.
+	NextStatuses []string `json:"next_statuses,omitempty" bson:"-"`
+}
+
+// Project returns the ID of the project the task belongs to.
+func (t Task) Project() string {
+	return projectOrDefault(t.ProjectID)
+}
+
+// Normalize fills in the default priority and cleans up tags, assignees and the recurrence rule:
+// tags are trimmed and lowercased, and blank or duplicate entries are dropped.
+// The recurrence takes its time of day from the due date.
+func (t *Task) Normalize() {
+	if t.Priority == "" {
+		t.Priority = PriorityMedium
+	}
+	t.Tags = uniqueStrings(t.Tags, func(tag string) string { return strings.ToLower(strings.TrimSpace(tag)) })
+	t.AssigneeIDs = uniqueStrings(t.AssigneeIDs, strings.TrimSpace)
+	if t.Recurrence != nil {
+		t.Recurrence.Normalize()
+		t.Recurrence.anchor(t.DueDate)
+	}
 }
 
 // Validate checks if the task is valid according to business rules.
+// Which statuses exist is up to the Workflow, and rules that involve other
+// tasks or users are checked by the usecases.
+// Every task needs a due date. New tasks, which have no ID yet, cannot be
+// due in the past; stored tasks keep whatever due date they have.
 func (t *Task) Validate() error {
+	return t.validate(t.ID == "")
+}
+
+// ValidateImported checks a new task restored from an export like Validate,
+// except that it may be due in the past: exports include overdue and
+// completed tasks.
+func (t *Task) ValidateImported() error {
+	return t.validate(false)
+}
+
+func (t *Task) validate(dueInFuture bool) error {
 	if t.Title == "" {
-		return errors.New("title is required")
+		return invalidField("title", "title is required")
+	}
+	if t.DueDate.IsZero() {
+		return invalidField("due_date", "due date is required")
+	}
+	if dueInFuture && t.DueDate.Before(time.Now().Add(-DueDateSkew)) {
+		return invalidField("due_date", "due date must not be in the past")
 	}
-	if t.Status != "pending" && t.Status != "in_progress" && t.Status != "completed" {
-		return errors.New("invalid status")
+	if t.Status == "" {
+		return invalidField("status", "invalid status")
+	}
+	if t.Priority != PriorityLow && t.Priority != PriorityMedium && t.Priority != PriorityHigh && t.Priority != PriorityUrgent {
+		return invalidField("priority", "invalid priority")
+	}
+	if len(t.Tags) > MaxTags {
+		return invalidField("tags", "too many tags")
+	}
+	for _, tag := range t.Tags {
+		if tag == "" || len(tag) > MaxTagLength {
+			return invalidField("tags", "tags must be 1 to 32 characters long")
+		}
+	}
+	for _, id := range t.AssigneeIDs {
+		if id == "" {
+			return invalidField("assignee_ids", "assignee IDs must not be empty")
+		}
+	}
+	if t.ParentID != "" && t.ParentID == t.ID {
+		return invalidField("parent_id", "a task cannot be its own parent")
+	}
+	if t.Recurrence != nil {
+		if t.ParentID != "" {
+			return invalidField("recurrence", "subtasks cannot recur")
+		}
+		return t.Recurrence.Validate()
 	}
 	return nil
 }
 
+// uniqueStrings applies clean to each value and keeps the first of each
+// distinct non-empty result. It always returns a non-nil slice.
+func uniqueStrings(values []string, clean func(string) string) []string {
+	seen := make(map[string]bool, len(values))
+	out := make([]string, 0, len(values))
+	for _, v := range values {
+		v = clean(v)
+		if v == "" || seen[v] {
+			continue
+		}
+		seen[v] = true
+		out = append(out, v)
+	}
+	return out
+}
+
+// Task list defaults and limits.
+const (
+	DefaultPageSize = 20
+	MaxPageSize     = 100
+)
+
+// taskSortFields lists the fields tasks can be sorted by.
+var taskSortFields = map[string]bool{
+	"due_date":   true,
+	"title":      true,
+	"status":     true,
+	"deleted_at": true,
+}
+
+// TaskQuery describes a filtered, sorted and paginated task listing.
+type TaskQuery struct {
+	OwnerID   string
+	ProjectID string
+	Status    string
+	DueAfter  time.Time
+	DueBefore time.Time
+	Search    string
+	// ExcludeStatus leaves out the tasks in this status.
+	ExcludeStatus string
+	SortBy        string
+	SortDesc      bool
+	Page          int
+	PageSize      int
+
+	// Overdue lists the tasks past their due date that are not completed.
+	// The usecases resolve it into DueBefore and ExcludeStatus.
+	Overdue bool
+
+	// Deleted lists the tasks in the trash instead of the live ones.
+	Deleted bool
+}
+
+// Normalize fills in defaults and validates the query.
+func (q *TaskQuery) Normalize() error {
+	if q.SortBy == "" {
+		q.SortBy = "due_date"
+	}
+	if !taskSortFields[q.SortBy] {
+		return invalidField("sort", "invalid sort field")
+	}
+	if q.Page == 0 {
+		q.Page = 1
+	}
+	if q.Page < 0 {
+		return invalidField("page", "invalid page")
+	}
+	if q.PageSize == 0 {
+		q.PageSize = DefaultPageSize
+	}
+	if q.PageSize < 0 || q.PageSize > MaxPageSize {
+		return invalidField("page_size", "invalid page size")
+	}
+	if !q.DueAfter.IsZero() && !q.DueBefore.IsZero() && q.DueBefore.Before(q.DueAfter) {
+		return invalidField("due_before", "due_before must not be before due_after")
+	}
+	return nil
+}
+
+// RestrictToOverdue limits the query to tasks due before now that are not
+// in the completed status.
+func (q *TaskQuery) RestrictToOverdue(now time.Time, completed string) {
+	if q.DueBefore.IsZero() || q.DueBefore.After(now) {
+		q.DueBefore = now
+	}
+	q.ExcludeStatus = completed
+}
+
+// Matches reports whether the task satisfies the query's filters.
+func (q TaskQuery) Matches(t Task) bool {
+	if q.Deleted != (t.DeletedAt != nil) {
+		return false
+	}
+	if q.OwnerID != "" && t.OwnerID != q.OwnerID {
+		return false
+	}
+	if q.ProjectID != "" && t.Project() != q.ProjectID {
+		return false
+	}
+	if q.Status != "" && t.Status != q.Status {
+		return false
+	}
+	if q.ExcludeStatus != "" && t.Status == q.ExcludeStatus {
+		return false
+	}
+	if !q.DueAfter.IsZero() && t.DueDate.Before(q.DueAfter) {
+		return false
+	}
+	if !q.DueBefore.IsZero() && t.DueDate.After(q.DueBefore) {
+		return false
+	}
+	if q.Search != "" && !strings.Contains(strings.ToLower(t.Title), strings.ToLower(q.Search)) {
+		return false
+	}
+	return true
+}
+
+// Skip returns the number of tasks before the requested page.
+func (q TaskQuery) Skip() int64 {
+	return int64(q.Page-1) * int64(q.PageSize)
+}
+
+// TaskPage is one page of a task listing.
+type TaskPage struct {
+	Tasks    []Task `json:"data"`
+	Total    int64  `json:"total"`
+	Page     int    `json:"page"`
+	PageSize int    `json:"page_size"`
+	NextPage int    `json:"next_page,omitempty"`
+}
+
+// NewTaskPage builds the page for the query, setting NextPage when more tasks remain.
+func NewTaskPage(q TaskQuery, tasks []Task, total int64) TaskPage {
+	if tasks == nil {
+		tasks = []Task{}
+	}
+	page := TaskPage{Tasks: tasks, Total: total, Page: q.Page, PageSize: q.PageSize}
+	if q.Skip()+int64(len(tasks)) < total {
+		page.NextPage = q.Page + 1
+	}
+	return page
+}
+
 // User represents a user entity with business rules.
 type User struct {
 	ID           primitive.ObjectID `bson:"_id,omitempty" json:"id"`
 	Username     string             `bson:"username" json:"username"`
-	PasswordHash string             `bson:"-"`
-	Role         string             `bson:"role" json:"role"` // "admin" or "user"
+	PasswordHash string             `bson:"password_hash" json:"-"`
+	Role         string             `bson:"role" json:"role"` // name of a built-in or custom role
 	CreatedAt    time.Time          `bson:"created_at" json:"created_at"`
 }
 
 // Validate checks if the user is valid.
 func (u *User) Validate() error {
 	if u.Username == "" {
-		return errors.New("username is required")
+		return invalidField("username", "username is required")
 	}
-	if u.Role != "admin" && u.Role != "user" {
-		return errors.New("invalid role")
+	if u.Role == "" {
+		return invalidField("role", "invalid role")
 	}
 	return nil
 }
 
 // IsAdmin checks if the user has admin role.
 func (u *User) IsAdmin() bool {
-	return u.Role == "admin"
+	return u.Role == RoleAdmin
+}
+
+// Permissions that can be granted to roles.
+const (
+	PermTasksRead      = "tasks:read"
+	PermTasksCreate    = "tasks:create"
+	PermTasksUpdate    = "tasks:update"
+	PermTasksDelete    = "tasks:delete"
+	PermTasksManage    = "tasks:manage" // act on tasks owned by other users
+	PermUsersPromote   = "users:promote"
+	PermUsersDemote    = "users:demote"
+	PermRolesManage    = "roles:manage"
+	PermAuditRead      = "audit:read"
+	PermTasksTrash     = "tasks:trash" // list, restore and purge deleted tasks
+	PermWebhooksManage = "webhooks:manage"
+	PermCommentsManage = "comments:manage" // edit and delete comments of other users
+	PermProjectsManage = "projects:manage" // create and archive projects and act in any of them
+)
+
+// AllPermissions lists every known permission.
+var AllPermissions = []string{
+	PermTasksRead,
+	PermTasksCreate,
+	PermTasksUpdate,
+	PermTasksDelete,
+	PermTasksManage,
+	PermUsersPromote,
+	PermUsersDemote,
+	PermRolesManage,
+	PermAuditRead,
+	PermTasksTrash,
+	PermWebhooksManage,
+	PermCommentsManage,
+	PermProjectsManage,
+}
+
+// IsValidPermission reports whether p is a known permission.
+func IsValidPermission(p string) bool {
+	for _, known := range AllPermissions {
+		if p == known {
+			return true
+		}
+	}
+	return false
+}
+
+// Built-in role names.
+const (
+	RoleAdmin = "admin"
+	RoleUser  = "user"
+)
+
+// Role is a named collection of permissions.
+type Role struct {
+	Name        string   `json:"name" bson:"_id"`
+	Permissions []string `json:"permissions" bson:"permissions"`
+	BuiltIn     bool     `json:"built_in" bson:"-"`
+}
+
+// builtInRoles are defined in code and cannot be changed at runtime.
+var builtInRoles = map[string]Role{
+	RoleAdmin: {Name: RoleAdmin, Permissions: AllPermissions, BuiltIn: true},
+	RoleUser:  {Name: RoleUser, Permissions: []string{PermTasksRead, PermTasksCreate, PermTasksUpdate}, BuiltIn: true},
+}
+
+// BuiltInRole returns the built-in role with the given name.
+func BuiltInRole(name string) (Role, bool) {
+	r, ok := builtInRoles[name]
+	if ok {
+		r.Permissions = append([]string(nil), r.Permissions...)
+	}
+	return r, ok
+}
+
+// BuiltInRoles returns every built-in role.
+func BuiltInRoles() []Role {
+	admin, _ := BuiltInRole(RoleAdmin)
+	user, _ := BuiltInRole(RoleUser)
+	return []Role{admin, user}
+}
+
+// Validate checks if the role is valid.
+func (r *Role) Validate() error {
+	if r.Name == "" {
+		return invalidField("name", "role name is required")
+	}
+	for _, c := range r.Name {
+		if !(c >= 'a' && c <= 'z' || c >= '0' && c <= '9' || c == '_' || c == '-') {
+			return invalidField("name", "role name may only contain lowercase letters, digits, '_' and '-'")
+		}
+	}
+	for _, p := range r.Permissions {
+		if !IsValidPermission(p) {
+			return invalidField("permissions", "unknown permission: "+p)
+		}
+	}
+	return nil
+}
+
+// Actor identifies the authenticated caller performing an operation.
+// Within a project, Permissions are those of the actor's project role.
+type Actor struct {
+	UserID      string
+	Role        string
+	Permissions []string
+	ProjectID   string // the project the actor works in; empty means the default project
+}
+
+// Project returns the ID of the project the actor works in.
+func (a Actor) Project() string {
+	return projectOrDefault(a.ProjectID)
+}
+
+// Can reports whether the actor has been granted the permission.
+func (a Actor) Can(permission string) bool {
+	for _, p := range a.Permissions {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// SeesAllTasks reports whether the actor sees every task of its project
+// rather than only its own. Every member of a team project does.
+func (a Actor) SeesAllTasks() bool {
+	return a.Can(PermTasksManage) || a.Project() != DefaultProjectID
+}
+
+// CanView reports whether the actor may view the task.
+// Tasks of other projects are out of reach whatever the permissions.
+func (a Actor) CanView(t Task) bool {
+	if t.Project() != a.Project() {
+		return false
+	}
+	return a.SeesAllTasks() || (a.UserID != "" && t.OwnerID == a.UserID)
+}
+
+// CanModify reports whether the actor may modify the task: its owner can,
+// and so can actors with the tasks:manage permission in its project.
+func (a Actor) CanModify(t Task) bool {
+	if t.Project() != a.Project() {
+		return false
+	}
+	return a.Can(PermTasksManage) || (a.UserID != "" && t.OwnerID == a.UserID)
+}
+
+// RefreshToken is a server-side record of an issued refresh token.
+// Tokens issued by rotating the same login share a FamilyID.
+type RefreshToken struct {
+	Hash      string    `bson:"_id"`
+	FamilyID  string    `bson:"family_id"`
+	UserID    string    `bson:"user_id"`
+	Username  string    `bson:"username"`
+	ExpiresAt time.Time `bson:"expires_at"`
+	Used      bool      `bson:"used"`
+	Revoked   bool      `bson:"revoked"`
+	CreatedAt time.Time `bson:"created_at"`
+}
+
+// IsExpired reports whether the refresh token has expired at the given time.
+func (rt *RefreshToken) IsExpired(now time.Time) bool {
+	return !now.Before(rt.ExpiresAt)
 }
@@ -0,0 +1,94 @@
+package domain
+
+import "time"
+
+// ErrorCode is the machine-readable code of a domain error.
+type ErrorCode string
+
+// Domain error codes.
+const (
+	CodeNotFound     ErrorCode = "not_found"
+	CodeConflict     ErrorCode = "conflict"
+	CodeValidation   ErrorCode = "validation_failed"
+	CodeUnauthorized ErrorCode = "unauthorized"
+	CodeForbidden    ErrorCode = "forbidden"
+
+	CodePreconditionFailed ErrorCode = "precondition_failed" // the resource changed since the client read it
+
+	CodeInvalidTransition ErrorCode = "invalid_transition" // status change the workflow does not allow
+
+	CodeTooLarge         ErrorCode = "too_large"              // request body over the size limit
+	CodeUnsupportedMedia ErrorCode = "unsupported_media_type" // content of a type that is not accepted
+
+	CodeRateLimited ErrorCode = "rate_limited" // too many requests; retry after RetryAfter
+)
+
+// FieldError describes why one input field is invalid.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// Error is a typed domain error. The delivery layer picks the response
+// status from its Code, so errors keep their meaning when wrapped.
+type Error struct {
+	Code    ErrorCode
+	Message string
+	Fields  []FieldError // set on validation errors
+	// RetryAfter is how long the client should wait before retrying, if known
+	RetryAfter time.Duration
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// NewNotFoundError reports a missing resource.
+func NewNotFoundError(message string) *Error {
+	return &Error{Code: CodeNotFound, Message: message}
+}
+
+// NewConflictError reports a clash with the current state, such as a duplicate key.
+func NewConflictError(message string) *Error {
+	return &Error{Code: CodeConflict, Message: message}
+}
+
+// NewValidationError reports invalid input, optionally per field.
+func NewValidationError(message string, fields ...FieldError) *Error {
+	return &Error{Code: CodeValidation, Message: message, Fields: fields}
+}
+
+// NewUnauthorizedError reports missing or invalid credentials.
+func NewUnauthorizedError(message string) *Error {
+	return &Error{Code: CodeUnauthorized, Message: message}
+}
+
+// NewForbiddenError reports an action the caller is not allowed to perform.
+func NewForbiddenError(message string) *Error {
+	return &Error{Code: CodeForbidden, Message: message}
+}
+
+// NewPreconditionFailedError reports a write based on a stale version of a resource.
+func NewPreconditionFailedError(message string) *Error {
+	return &Error{Code: CodePreconditionFailed, Message: message}
+}
+
+// NewTooLargeError reports content over a size limit.
+func NewTooLargeError(message string) *Error {
+	return &Error{Code: CodeTooLarge, Message: message}
+}
+
+// NewUnsupportedMediaError reports content of a type that is not accepted.
+func NewUnsupportedMediaError(message string) *Error {
+	return &Error{Code: CodeUnsupportedMedia, Message: message}
+}
+
+// NewRateLimitedError reports a client that must wait retryAfter before trying again.
+func NewRateLimitedError(message string, retryAfter time.Duration) *Error {
+	return &Error{Code: CodeRateLimited, Message: message, RetryAfter: retryAfter}
+}
+
+// invalidField is a validation error for a single field.
+func invalidField(field, message string) *Error {
+	return NewValidationError(message, FieldError{Field: field, Message: message})
+}
@@ -0,0 +1,31 @@
+package domain
+
+import "time"
+
+// Kinds of task events.
+const (
+	EventTaskCreated = "task.created"
+	EventTaskUpdated = "task.updated"
+	EventTaskDeleted = "task.deleted"
+	// EventTaskStatusChanged follows the task.updated event of an update
+	// that changed the task's status.
+	EventTaskStatusChanged = "task.status_changed"
+	// EventTaskDueSoon and EventTaskOverdue are reminders sent once per due
+	// date, before it and once it has passed, while the task is not completed.
+	EventTaskDueSoon = "task.due_soon"
+	EventTaskOverdue = "task.overdue"
+
+	// EventStreamReset tells a resuming subscriber that some events were
+	// missed, so it should reload the tasks it cares about.
+	EventStreamReset = "stream.reset"
+)
+
+// TaskEvent reports a change to a task. IDs increase by one per event.
+type TaskEvent struct {
+	ID   int64  `json:"id,omitempty"`
+	Type string `json:"type"`
+	Task *Task  `json:"task,omitempty"`
+	// PreviousStatus is set on task.status_changed events.
+	PreviousStatus string    `json:"previous_status,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+}
@@ -0,0 +1,154 @@
+package domain
+
+import (
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// DefaultProjectID is the project of the tasks outside any team project,
+// which every user can reach through the /tasks routes with their role's
+// permissions. It is not stored; tasks created before projects existed
+// and tasks without a project ID belong to it.
+const DefaultProjectID = "default"
+
+// Project limits.
+const (
+	MaxProjectNameLength = 100 // characters
+	MaxProjectMembers    = 500
+)
+
+// Project roles, from most to least privileged.
+const (
+	ProjectRoleManager = "manager" // manages the members and the trash
+	ProjectRoleMember  = "member"  // works on the project's tasks
+	ProjectRoleViewer  = "viewer"  // reads the project's tasks
+)
+
+// projectRolePermissions are the permissions a project role grants within
+// its project. Every member sees all of the project's tasks.
+var projectRolePermissions = map[string][]string{
+	ProjectRoleManager: {PermTasksRead, PermTasksCreate, PermTasksUpdate, PermTasksDelete, PermTasksManage, PermTasksTrash, PermCommentsManage},
+	ProjectRoleMember:  {PermTasksRead, PermTasksCreate, PermTasksUpdate, PermTasksDelete, PermTasksManage},
+	ProjectRoleViewer:  {PermTasksRead},
+}
+
+// IsValidProjectRole reports whether role is a known project role.
+func IsValidProjectRole(role string) bool {
+	_, ok := projectRolePermissions[role]
+	return ok
+}
+
+// Project is a team's container of tasks. Only its members can reach its
+// tasks, with the permissions of their project role.
+type Project struct {
+	ID          string          `json:"id" bson:"_id,omitempty"`
+	Name        string          `json:"name" bson:"name"`
+	Description string          `json:"description" bson:"description"`
+	Members     []ProjectMember `json:"members" bson:"members"`
+	// ArchivedAt is set while the project is archived. Its tasks can
+	// still be read but no longer changed.
+	ArchivedAt *time.Time `json:"archived_at,omitempty" bson:"archived_at,omitempty"`
+	CreatedBy  string     `json:"created_by" bson:"created_by"`
+	CreatedAt  time.Time  `json:"created_at" bson:"created_at"`
+}
+
+// ProjectMember is a user's membership of a project.
+type ProjectMember struct {
+	UserID string `json:"user_id" bson:"user_id"`
+	Role   string `json:"role" bson:"role"`
+}
+
+// Normalize trims the name and description.
+func (p *Project) Normalize() {
+	p.Name = strings.TrimSpace(p.Name)
+	p.Description = strings.TrimSpace(p.Description)
+}
+
+// Validate checks the name and the members of the project.
+// A project always keeps at least one manager.
+func (p *Project) Validate() error {
+	if p.Name == "" {
+		return invalidField("name", "name is required")
+	}
+	if utf8.RuneCountInString(p.Name) > MaxProjectNameLength {
+		return invalidField("name", "name must be at most 100 characters long")
+	}
+	if len(p.Members) > MaxProjectMembers {
+		return invalidField("members", "too many members")
+	}
+	seen := make(map[string]bool, len(p.Members))
+	for _, m := range p.Members {
+		if m.UserID == "" || seen[m.UserID] {
+			return invalidField("members", "members must be distinct users")
+		}
+		if !IsValidProjectRole(m.Role) {
+			return invalidField("members", "unknown project role: "+m.Role)
+		}
+		seen[m.UserID] = true
+	}
+	if !p.HasManager() {
+		return invalidField("members", "a project needs at least one manager")
+	}
+	return nil
+}
+
+// HasManager reports whether a member of the project is a manager.
+func (p Project) HasManager() bool {
+	return slices.ContainsFunc(p.Members, func(m ProjectMember) bool { return m.Role == ProjectRoleManager })
+}
+
+// IsArchived reports whether the project is archived.
+func (p Project) IsArchived() bool {
+	return p.ArchivedAt != nil
+}
+
+// MemberRole returns the project role of the user, if they are a member.
+func (p Project) MemberRole(userID string) (string, bool) {
+	for _, m := range p.Members {
+		if m.UserID == userID {
+			return m.Role, true
+		}
+	}
+	return "", false
+}
+
+// SetMember adds the user to the project or changes their role.
+func (p *Project) SetMember(userID, role string) {
+	for i, m := range p.Members {
+		if m.UserID == userID {
+			p.Members[i].Role = role
+			return
+		}
+	}
+	p.Members = append(p.Members, ProjectMember{UserID: userID, Role: role})
+}
+
+// RemoveMember removes the user from the project and reports whether they were a member.
+func (p *Project) RemoveMember(userID string) bool {
+	n := len(p.Members)
+	p.Members = slices.DeleteFunc(p.Members, func(m ProjectMember) bool { return m.UserID == userID })
+	return len(p.Members) < n
+}
+
+// ProjectRolePermissions returns the permissions the project role grants.
+func ProjectRolePermissions(role string) []string {
+	return slices.Clone(projectRolePermissions[role])
+}
+
+// projectOrDefault maps an empty project ID to the default project.
+func projectOrDefault(id string) string {
+	if id == "" {
+		return DefaultProjectID
+	}
+	return id
+}
+
+// ProjectAccess is what an actor may do in a project.
+type ProjectAccess struct {
+	ProjectID   string
+	Permissions []string
+	// ReadOnly is set while the project is archived.
+	ReadOnly bool
+}
@@ -0,0 +1,157 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RateLimit is a token bucket: it holds up to Burst requests and refills
+// at Burst requests per Period. The zero RateLimit does not limit.
+type RateLimit struct {
+	Burst  int
+	Period time.Duration
+}
+
+// ParseRateLimit parses a limit written as "<burst>/<period>", e.g. "5/1m"
+// for five requests a minute. "0" disables the limit.
+func ParseRateLimit(s string) (RateLimit, error) {
+	s = strings.TrimSpace(s)
+	if s == "0" {
+		return RateLimit{}, nil
+	}
+	burst, period, ok := strings.Cut(s, "/")
+	if !ok {
+		return RateLimit{}, fmt.Errorf("invalid rate limit %q: expected burst/period", s)
+	}
+	l := RateLimit{}
+	var err error
+	if l.Burst, err = strconv.Atoi(strings.TrimSpace(burst)); err != nil || l.Burst <= 0 {
+		return RateLimit{}, fmt.Errorf("invalid rate limit %q: burst must be a positive number", s)
+	}
+	if l.Period, err = time.ParseDuration(strings.TrimSpace(period)); err != nil || l.Period <= 0 {
+		return RateLimit{}, fmt.Errorf("invalid rate limit %q: period must be a positive duration", s)
+	}
+	return l, nil
+}
+
+// Enabled reports whether the limit throttles requests.
+func (l RateLimit) Enabled() bool {
+	return l.Burst > 0 && l.Period > 0
+}
+
+// Rate returns how many tokens the bucket regains per second.
+func (l RateLimit) Rate() float64 {
+	return float64(l.Burst) / l.Period.Seconds()
+}
+
+// TokenBucket is the state of one rate limited key. A bucket that was
+// never used is full.
+type TokenBucket struct {
+	Tokens    float64
+	UpdatedAt time.Time
+}
+
+// Take refills the bucket for the time since it was last used and takes a
+// token from it. When the bucket is empty it takes nothing and returns how
+// long until a token is available.
+func (l RateLimit) Take(b *TokenBucket, now time.Time) time.Duration {
+	if b.UpdatedAt.IsZero() {
+		b.Tokens = float64(l.Burst)
+	} else if elapsed := now.Sub(b.UpdatedAt); elapsed > 0 {
+		b.Tokens = math.Min(float64(l.Burst), b.Tokens+elapsed.Seconds()*l.Rate())
+	}
+	b.UpdatedAt = now
+	if b.Tokens >= 1 {
+		b.Tokens--
+		return 0
+	}
+	return l.Wait(b.Tokens)
+}
+
+// Wait returns how long a bucket holding tokens takes to regain a whole token.
+func (l RateLimit) Wait(tokens float64) time.Duration {
+	if tokens >= 1 {
+		return 0
+	}
+	return time.Duration(math.Ceil((1 - tokens) / l.Rate() * float64(time.Second)))
+}
+
+// LockoutPolicy locks an account after Threshold failed logins in a row.
+// The first lockout lasts Delay and every further failure doubles it, up
+// to MaxDelay. Failures are forgotten Window after the last one, counted
+// from the end of its lockout. A Threshold of 0 disables lockouts.
+type LockoutPolicy struct {
+	Threshold int
+	Delay     time.Duration
+	MaxDelay  time.Duration
+	Window    time.Duration
+}
+
+// DefaultLockoutPolicy locks an account for a minute after five failed
+// logins, for up to an hour.
+func DefaultLockoutPolicy() LockoutPolicy {
+	return LockoutPolicy{Threshold: 5, Delay: time.Minute, MaxDelay: time.Hour, Window: 15 * time.Minute}
+}
+
+// Enabled reports whether the policy locks accounts.
+func (p LockoutPolicy) Enabled() bool {
+	return p.Threshold > 0 && p.Delay > 0
+}
+
+// LockFor returns how long an account is locked after its failures-th
+// failed login in a row.
+func (p LockoutPolicy) LockFor(failures int) time.Duration {
+	if !p.Enabled() || failures < p.Threshold {
+		return 0
+	}
+	d := p.Delay
+	for i := p.Threshold; i < failures && d < p.MaxDelay; i++ {
+		d *= 2
+	}
+	if p.MaxDelay > 0 && d > p.MaxDelay {
+		d = p.MaxDelay
+	}
+	return d
+}
+
+// Lockout is the failed login record of an account.
+type Lockout struct {
+	Failures    int
+	LockedUntil time.Time
+}
+
+// RetryAfter returns how long the account stays locked, or 0 when it is not locked.
+func (l Lockout) RetryAfter(now time.Time) time.Duration {
+	if l.LockedUntil.After(now) {
+		return l.LockedUntil.Sub(now)
+	}
+	return 0
+}
+
+// Attempt starts a login attempt at now. While the account is locked it
+// changes nothing and returns how long the lock lasts. Otherwise the attempt
+// counts as a failed login until a successful login resets the record, so
+// that concurrent attempts cannot get past the threshold; Attempt returns 0
+// and when the record can be forgotten.
+func (p LockoutPolicy) Attempt(l *Lockout, now time.Time) (time.Duration, time.Time) {
+	if wait := l.RetryAfter(now); wait > 0 {
+		return wait, time.Time{}
+	}
+	return 0, p.Fail(l, now)
+}
+
+// Fail records a failed login at now and locks the account as the policy
+// demands. It returns when the record can be forgotten.
+func (p LockoutPolicy) Fail(l *Lockout, now time.Time) time.Time {
+	l.Failures++
+	if d := p.LockFor(l.Failures); d > 0 && now.Add(d).After(l.LockedUntil) {
+		l.LockedUntil = now.Add(d)
+	}
+	if l.LockedUntil.After(now) {
+		return l.LockedUntil.Add(p.Window)
+	}
+	return now.Add(p.Window)
+}
@@ -0,0 +1,402 @@
+package domain
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	// Embed the time zone database so recurrence time zones resolve on hosts without one
+	_ "time/tzdata"
+)
+
+// Recurrence frequencies.
+const (
+	FreqDaily   = "DAILY"
+	FreqWeekly  = "WEEKLY"
+	FreqMonthly = "MONTHLY"
+	FreqYearly  = "YEARLY"
+)
+
+// Recurrence limits.
+const (
+	MaxRecurrenceInterval = 1000
+	// maxOccurrenceSteps bounds the search for the next occurrence
+	maxOccurrenceSteps = 100000
+	// timeOfDayLayout formats Recurrence.TimeOfDay
+	timeOfDayLayout = "15:04:05.999999999"
+)
+
+// Recurrence repeats a task on a schedule. Rule is a subset of the iCalendar
+// RRULE syntax: FREQ (DAILY, WEEKLY, MONTHLY or YEARLY), INTERVAL, BYDAY
+// (weekdays, WEEKLY only), BYMONTHDAY (MONTHLY only, negative days count
+// from the end of the month), WKST, and one of COUNT or UNTIL. The shorthands
+// "daily", "weekly", "monthly" and "yearly" are accepted too.
+// Occurrences are due at TimeOfDay, a wall-clock time such as "09:30:00" in
+// Timezone, an IANA time zone name that defaults to UTC. TimeOfDay is taken
+// from the due date of the task the series is set on.
+type Recurrence struct {
+	Rule      string `json:"rule" bson:"rule"`
+	Timezone  string `json:"timezone,omitempty" bson:"timezone,omitempty"`
+	TimeOfDay string `json:"time_of_day,omitempty" bson:"time_of_day,omitempty"`
+}
+
+// Normalize rewrites a valid rule in its canonical RRULE form.
+func (r *Recurrence) Normalize() {
+	r.Timezone = strings.TrimSpace(r.Timezone)
+	if rule, err := parseRRule(r.Rule); err == nil {
+		r.Rule = rule.String()
+	}
+}
+
+// Validate checks the rule and the time zone.
+func (r Recurrence) Validate() error {
+	if _, err := parseRRule(r.Rule); err != nil {
+		return invalidField("recurrence", err.Error())
+	}
+	if _, err := r.location(); err != nil {
+		return invalidField("recurrence", "unknown time zone: "+r.Timezone)
+	}
+	if r.TimeOfDay != "" {
+		if _, err := time.Parse(timeOfDayLayout, r.TimeOfDay); err != nil {
+			return invalidField("recurrence", "invalid time of day: "+r.TimeOfDay)
+		}
+	}
+	return nil
+}
+
+// anchor sets TimeOfDay to the local time of due. An occurrence that was
+// moved out of a DST gap keeps the series' time of day, so the following
+// occurrences return to it.
+func (r *Recurrence) anchor(due time.Time) {
+	loc, err := r.location()
+	if err != nil || due.IsZero() {
+		return
+	}
+	local := due.In(loc)
+	if clock, err := time.Parse(timeOfDayLayout, r.TimeOfDay); err == nil && occurrenceAt(localDate(local), clock, loc).Equal(due) {
+		return
+	}
+	r.TimeOfDay = local.Format(timeOfDayLayout)
+}
+
+func (r Recurrence) location() (*time.Location, error) {
+	if r.Timezone == "" {
+		return time.UTC, nil
+	}
+	return time.LoadLocation(r.Timezone)
+}
+
+// Next finds the first occurrence after due that is also after notBefore,
+// so occurrences missed while the task was open are skipped. It returns that
+// due date together with the recurrence the occurrence carries, whose COUNT
+// is reduced by the occurrences used up. ok is false once the series has ended.
+func (r Recurrence) Next(due, notBefore time.Time) (next Recurrence, nextDue time.Time, ok bool) {
+	rule, err := parseRRule(r.Rule)
+	if err != nil {
+		return Recurrence{}, time.Time{}, false
+	}
+	loc, err := r.location()
+	if err != nil {
+		return Recurrence{}, time.Time{}, false
+	}
+	if rule.untilText != "" {
+		rule.until, _ = parseRRuleUntil(rule.untilText, loc)
+	}
+	local := due.In(loc)
+	clock, err := time.Parse(timeOfDayLayout, r.TimeOfDay)
+	if err != nil {
+		clock = local
+	}
+
+	date := localDate(local)
+	for i := 0; i < maxOccurrenceSteps; i++ {
+		if rule.count > 0 {
+			if rule.count == 1 {
+				return Recurrence{}, time.Time{}, false
+			}
+			rule.count--
+		}
+		if date, ok = rule.step(date); !ok {
+			return Recurrence{}, time.Time{}, false
+		}
+		t := occurrenceAt(date, clock, loc)
+		if !rule.until.IsZero() && t.After(rule.until) {
+			return Recurrence{}, time.Time{}, false
+		}
+		if t.After(notBefore) {
+			next := Recurrence{Rule: rule.String(), Timezone: r.Timezone, TimeOfDay: clock.Format(timeOfDayLayout)}
+			return next, t.UTC(), true
+		}
+	}
+	return Recurrence{}, time.Time{}, false
+}
+
+// rrule is a parsed recurrence rule.
+type rrule struct {
+	freq       string
+	interval   int
+	byDay      []time.Weekday // sorted from weekStart
+	byMonthDay []int
+	weekStart  time.Weekday
+	count      int
+	until      time.Time
+	// untilText keeps UNTIL as written, since its meaning depends on the time zone
+	untilText string
+}
+
+var rruleShorthands = map[string]string{
+	"daily":   FreqDaily,
+	"weekly":  FreqWeekly,
+	"monthly": FreqMonthly,
+	"yearly":  FreqYearly,
+}
+
+var rruleWeekdays = map[string]time.Weekday{
+	"SU": time.Sunday, "MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday,
+	"TH": time.Thursday, "FR": time.Friday, "SA": time.Saturday,
+}
+
+func parseRRule(s string) (rrule, error) {
+	s = strings.TrimSpace(s)
+	rule := rrule{interval: 1, weekStart: time.Monday}
+	if freq, ok := rruleShorthands[strings.ToLower(s)]; ok {
+		rule.freq = freq
+		return rule, nil
+	}
+	s = strings.TrimPrefix(strings.ToUpper(s), "RRULE:")
+	if s == "" {
+		return rrule{}, fmt.Errorf("rule is required")
+	}
+
+	seen := map[string]bool{}
+	for _, part := range strings.Split(s, ";") {
+		name, value, found := strings.Cut(part, "=")
+		if !found || value == "" {
+			return rrule{}, fmt.Errorf("invalid rule part %q", part)
+		}
+		if seen[name] {
+			return rrule{}, fmt.Errorf("%s is given more than once", name)
+		}
+		seen[name] = true
+
+		var err error
+		switch name {
+		case "FREQ":
+			if value != FreqDaily && value != FreqWeekly && value != FreqMonthly && value != FreqYearly {
+				return rrule{}, fmt.Errorf("unsupported FREQ %s", value)
+			}
+			rule.freq = value
+		case "INTERVAL":
+			rule.interval, err = strconv.Atoi(value)
+			if err != nil || rule.interval < 1 || rule.interval > MaxRecurrenceInterval {
+				return rrule{}, fmt.Errorf("INTERVAL must be between 1 and %d", MaxRecurrenceInterval)
+			}
+		case "COUNT":
+			rule.count, err = strconv.Atoi(value)
+			if err != nil || rule.count < 1 {
+				return rrule{}, fmt.Errorf("COUNT must be a positive number")
+			}
+		case "UNTIL":
+			if rule.until, err = parseRRuleUntil(value, time.UTC); err != nil {
+				return rrule{}, err
+			}
+			rule.untilText = value
+		case "WKST":
+			day, ok := rruleWeekdays[value]
+			if !ok {
+				return rrule{}, fmt.Errorf("invalid WKST %s", value)
+			}
+			rule.weekStart = day
+		case "BYDAY":
+			for _, name := range strings.Split(value, ",") {
+				day, ok := rruleWeekdays[name]
+				if !ok {
+					return rrule{}, fmt.Errorf("invalid BYDAY %s", name)
+				}
+				if !slices.Contains(rule.byDay, day) {
+					rule.byDay = append(rule.byDay, day)
+				}
+			}
+		case "BYMONTHDAY":
+			for _, v := range strings.Split(value, ",") {
+				day, err := strconv.Atoi(v)
+				if err != nil || day == 0 || day < -31 || day > 31 {
+					return rrule{}, fmt.Errorf("invalid BYMONTHDAY %s", v)
+				}
+				if !slices.Contains(rule.byMonthDay, day) {
+					rule.byMonthDay = append(rule.byMonthDay, day)
+				}
+			}
+		default:
+			return rrule{}, fmt.Errorf("unsupported rule part %s", name)
+		}
+	}
+
+	switch {
+	case rule.freq == "":
+		return rrule{}, fmt.Errorf("FREQ is required")
+	case rule.count > 0 && rule.untilText != "":
+		return rrule{}, fmt.Errorf("COUNT and UNTIL cannot be combined")
+	case len(rule.byDay) > 0 && rule.freq != FreqWeekly:
+		return rrule{}, fmt.Errorf("BYDAY requires FREQ=WEEKLY")
+	case len(rule.byMonthDay) > 0 && rule.freq != FreqMonthly:
+		return rrule{}, fmt.Errorf("BYMONTHDAY requires FREQ=MONTHLY")
+	}
+	slices.SortFunc(rule.byDay, func(a, b time.Weekday) int { return rule.dayOfWeek(a) - rule.dayOfWeek(b) })
+	slices.Sort(rule.byMonthDay)
+	return rule, nil
+}
+
+// parseRRuleUntil parses an UNTIL value: a UTC date-time such as
+// 20250131T090000Z, a floating date-time read in loc, or a date that
+// includes the whole day in loc.
+func parseRRuleUntil(value string, loc *time.Location) (time.Time, error) {
+	if t, err := time.Parse("20060102T150405Z", value); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("20060102T150405", value, loc); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("20060102", value, loc); err == nil {
+		return t.AddDate(0, 0, 1).Add(-time.Nanosecond), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid UNTIL %s", value)
+}
+
+// String formats the rule in canonical RRULE form.
+func (r rrule) String() string {
+	parts := []string{"FREQ=" + r.freq}
+	if r.interval != 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.interval))
+	}
+	if len(r.byDay) > 0 {
+		days := make([]string, len(r.byDay))
+		for i, day := range r.byDay {
+			days[i] = strings.ToUpper(day.String()[:2])
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+	if len(r.byMonthDay) > 0 {
+		days := make([]string, len(r.byMonthDay))
+		for i, day := range r.byMonthDay {
+			days[i] = strconv.Itoa(day)
+		}
+		parts = append(parts, "BYMONTHDAY="+strings.Join(days, ","))
+	}
+	if r.weekStart != time.Monday {
+		parts = append(parts, "WKST="+strings.ToUpper(r.weekStart.String()[:2]))
+	}
+	if r.count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.count))
+	}
+	if r.untilText != "" {
+		parts = append(parts, "UNTIL="+r.untilText)
+	}
+	return strings.Join(parts, ";")
+}
+
+// dayOfWeek numbers the weekday from the rule's week start.
+func (r rrule) dayOfWeek(day time.Weekday) int {
+	return (int(day) - int(r.weekStart) + 7) % 7
+}
+
+// localDate returns the calendar date of t as midnight UTC.
+func localDate(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// occurrenceAt returns the instant the wall clock in loc shows clock on date.
+// As in RFC 5545, a time that falls in a DST gap is read with the offset
+// from before the gap, which moves it forward by the length of the gap, and
+// a time that occurs twice in a DST overlap is the first of the two.
+func occurrenceAt(date, clock time.Time, loc *time.Location) time.Time {
+	year, month, day := date.Date()
+	hour, minute, second := clock.Clock()
+	t := time.Date(year, month, day, hour, minute, second, clock.Nanosecond(), loc)
+	if t.Day() == day && t.Hour() == hour && t.Minute() == minute {
+		return t
+	}
+	// time.Date read the wall clock with one of the offsets around the gap
+	// and t has the other; the later reading uses the offset from before it
+	_, offset := t.Zone()
+	wall := time.Date(year, month, day, hour, minute, second, clock.Nanosecond(), time.UTC)
+	if other := wall.Add(-time.Duration(offset) * time.Second); other.After(t) {
+		return other.In(loc)
+	}
+	return t
+}
+
+// step returns the date of the occurrence after the one on date. Dates are
+// calendar days in the rule's time zone, held as midnight UTC, so that
+// stepping never depends on the time of day or DST changes.
+func (r rrule) step(t time.Time) (time.Time, bool) {
+	switch r.freq {
+	case FreqDaily:
+		return t.AddDate(0, 0, r.interval), true
+	case FreqWeekly:
+		return r.stepWeekly(t), true
+	case FreqMonthly:
+		return r.stepMonthly(t)
+	default:
+		return r.stepYearly(t)
+	}
+}
+
+func (r rrule) stepWeekly(t time.Time) time.Time {
+	if len(r.byDay) == 0 {
+		return t.AddDate(0, 0, 7*r.interval)
+	}
+	current := r.dayOfWeek(t.Weekday())
+	for _, day := range r.byDay {
+		if d := r.dayOfWeek(day); d > current {
+			return t.AddDate(0, 0, d-current)
+		}
+	}
+	// On to the first day of the next week in the interval
+	return t.AddDate(0, 0, 7*r.interval-current+r.dayOfWeek(r.byDay[0]))
+}
+
+// stepMonthly moves to the next listed day of the month, or the same day
+// when none are listed. Months without that day are skipped.
+func (r rrule) stepMonthly(t time.Time) (time.Time, bool) {
+	days := r.byMonthDay
+	if len(days) == 0 {
+		days = []int{t.Day()}
+	}
+	year, month, _ := t.Date()
+	for i := 0; i*r.interval <= 12*28; i++ {
+		first := time.Date(year, month+time.Month(i*r.interval), 1, 0, 0, 0, 0, time.UTC)
+		last := first.AddDate(0, 1, -1).Day()
+		var candidates []int
+		for _, day := range days {
+			if day < 0 {
+				day = last + 1 + day
+			}
+			if day >= 1 && day <= last {
+				candidates = append(candidates, day)
+			}
+		}
+		slices.Sort(candidates)
+		for _, day := range candidates {
+			next := time.Date(first.Year(), first.Month(), day, 0, 0, 0, 0, time.UTC)
+			if next.After(t) {
+				return next, true
+			}
+		}
+	}
+	return time.Time{}, false
+}
+
+// stepYearly moves to the same day in a later year, skipping years without it.
+func (r rrule) stepYearly(t time.Time) (time.Time, bool) {
+	for years := r.interval; years <= 8*r.interval; years += r.interval {
+		next := time.Date(t.Year()+years, t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+		if next.Day() == t.Day() {
+			return next, true
+		}
+	}
+	return time.Time{}, false
+}
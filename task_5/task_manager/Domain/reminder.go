@@ -0,0 +1,25 @@
+package domain
+
+import "time"
+
+// Reminder records that a reminder event was sent for one due date of a
+// task, so that it is sent only once. Changing the due date re-arms it.
+type Reminder struct {
+	ID      string    `json:"id" bson:"_id"`
+	TaskID  string    `json:"task_id" bson:"task_id"`
+	Type    string    `json:"type" bson:"type"` // task.due_soon or task.overdue
+	DueDate time.Time `json:"due_date" bson:"due_date"`
+	SentAt  time.Time `json:"sent_at" bson:"sent_at"`
+}
+
+// NewReminder builds the reminder of the given type for the task's current due date.
+func NewReminder(task Task, eventType string, sentAt time.Time) Reminder {
+	due := task.DueDate.UTC()
+	return Reminder{
+		ID:      task.ID + "/" + eventType + "/" + due.Format(time.RFC3339Nano),
+		TaskID:  task.ID,
+		Type:    eventType,
+		DueDate: due,
+		SentAt:  sentAt,
+	}
+}
@@ -0,0 +1,319 @@
+package domain
+
+import (
+	"html"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Search limits.
+const (
+	MaxSearchTerms  = 10
+	MinSearchPrefix = 2 // characters a prefix term needs
+)
+
+// Relevance weights: a match in the title counts titleWeight times one in
+// the description, and a prefix match half as much as a whole word.
+const (
+	titleWeight  = 3
+	prefixWeight = 0.5
+)
+
+// snippetLength is roughly how many bytes of the description a snippet shows,
+// and snippetContext how many of them precede the first match.
+const (
+	snippetLength  = 160
+	snippetContext = 40
+)
+
+// Token is a word of a text and where it is in the text.
+type Token struct {
+	Word  string // lowercase, without punctuation
+	Start int    // byte offset of the word's first character
+	End   int    // byte offset just past its last character
+}
+
+// Tokenize splits text into the words search matches: runs of
+// non-space characters, lowercased and with punctuation removed, so that
+// "Don't" and "dont" are the same word.
+func Tokenize(text string) []Token {
+	var tokens []Token
+	var word strings.Builder
+	start, end := -1, -1
+	flush := func() {
+		if word.Len() > 0 {
+			tokens = append(tokens, Token{Word: word.String(), Start: start, End: end})
+		}
+		word.Reset()
+		start, end = -1, -1
+	}
+	for i, r := range text {
+		switch {
+		case unicode.IsSpace(r):
+			flush()
+		case unicode.IsPunct(r):
+			// Punctuation is dropped, and left outside the word's span
+		default:
+			if start < 0 {
+				start = i
+			}
+			end = i + utf8.RuneLen(r)
+			word.WriteString(strings.ToLower(string(r)))
+		}
+	}
+	flush()
+	return tokens
+}
+
+// SearchTerm is one part of a search: a word, a phrase of consecutive
+// words or, with Prefix, the start of a word.
+type SearchTerm struct {
+	Words  []string
+	Prefix bool
+}
+
+// TaskSearch is a full-text search over the titles and descriptions of
+// live tasks. Every term must match for a task to be found.
+type TaskSearch struct {
+	// Text is the search as typed: words, "quoted phrases" and prefixes
+	// ending in *. Normalize parses it into Terms.
+	Text      string
+	Terms     []SearchTerm
+	OwnerID   string
+	ProjectID string
+	Page      int
+	PageSize  int
+}
+
+// Normalize parses the search text and fills in the paging defaults.
+func (s *TaskSearch) Normalize() error {
+	s.Terms = nil
+	segments := strings.Split(s.Text, `"`)
+	for i, segment := range segments {
+		// Odd segments are inside quotes; an unclosed quote runs to the end
+		if i%2 == 1 {
+			if words := searchWords(segment); len(words) > 0 {
+				s.Terms = append(s.Terms, SearchTerm{Words: words})
+			}
+			continue
+		}
+		for _, field := range strings.Fields(segment) {
+			prefix := strings.HasSuffix(field, "*")
+			words := searchWords(field)
+			if len(words) == 0 {
+				continue
+			}
+			if prefix && utf8.RuneCountInString(words[0]) < MinSearchPrefix {
+				return invalidField("q", "prefixes must be at least 2 characters long")
+			}
+			s.Terms = append(s.Terms, SearchTerm{Words: words, Prefix: prefix})
+		}
+	}
+	if len(s.Terms) == 0 {
+		return invalidField("q", "search text is required")
+	}
+	if len(s.Terms) > MaxSearchTerms {
+		return invalidField("q", "too many search terms")
+	}
+
+	if s.Page == 0 {
+		s.Page = 1
+	}
+	if s.Page < 0 {
+		return invalidField("page", "invalid page")
+	}
+	if s.PageSize == 0 {
+		s.PageSize = DefaultPageSize
+	}
+	if s.PageSize < 0 || s.PageSize > MaxPageSize {
+		return invalidField("page_size", "invalid page size")
+	}
+	return nil
+}
+
+func searchWords(text string) []string {
+	tokens := Tokenize(text)
+	words := make([]string, len(tokens))
+	for i, t := range tokens {
+		words[i] = t.Word
+	}
+	return words
+}
+
+// matches returns where the term matches the tokens, as token index ranges.
+func (term SearchTerm) matches(tokens []Token) [][2]int {
+	var found [][2]int
+	n := len(term.Words)
+	for i := 0; i+n <= len(tokens); i++ {
+		ok := true
+		for j, word := range term.Words {
+			got := tokens[i+j].Word
+			if term.Prefix && j == n-1 {
+				ok = strings.HasPrefix(got, word)
+			} else {
+				ok = got == word
+			}
+			if !ok {
+				break
+			}
+		}
+		if ok {
+			found = append(found, [2]int{i, i + n})
+		}
+	}
+	return found
+}
+
+// weight is what one match of the term adds to a task's score. Phrases
+// count each of their words.
+func (term SearchTerm) weight() float64 {
+	if term.Prefix {
+		return prefixWeight * float64(len(term.Words))
+	}
+	return float64(len(term.Words))
+}
+
+// SearchHit is a task found by a search, with its relevance and the
+// matching fields highlighted.
+type SearchHit struct {
+	Task  Task    `json:"task"`
+	Score float64 `json:"score"`
+	// Highlights maps "title" and "description", when they match, to
+	// HTML-escaped snippets in which the matches are wrapped in <mark>.
+	Highlights map[string]string `json:"highlights"`
+}
+
+// TaskSearchPage is one page of search results, most relevant first.
+type TaskSearchPage struct {
+	Hits     []SearchHit `json:"data"`
+	Total    int64       `json:"total"`
+	Page     int         `json:"page"`
+	PageSize int         `json:"page_size"`
+	NextPage int         `json:"next_page,omitempty"`
+}
+
+// Match scores the task against the search, reporting false when a term
+// matches neither its title nor its description.
+func (s TaskSearch) Match(t Task) (SearchHit, bool) {
+	title, description := Tokenize(t.Title), Tokenize(t.Description)
+	var titleMatches, descriptionMatches [][2]int
+	score := 0.0
+	for _, term := range s.Terms {
+		inTitle, inDescription := term.matches(title), term.matches(description)
+		if len(inTitle) == 0 && len(inDescription) == 0 {
+			return SearchHit{}, false
+		}
+		score += term.weight() * float64(titleWeight*len(inTitle)+len(inDescription))
+		titleMatches = append(titleMatches, inTitle...)
+		descriptionMatches = append(descriptionMatches, inDescription...)
+	}
+
+	hit := SearchHit{Task: t, Score: score, Highlights: make(map[string]string)}
+	if len(titleMatches) > 0 {
+		hit.Highlights["title"] = highlight(t.Title, spans(title, titleMatches), 0, len(t.Title))
+	}
+	if len(descriptionMatches) > 0 {
+		marks := spans(description, descriptionMatches)
+		start, end := snippetBounds(t.Description, marks[0][0])
+		hit.Highlights["description"] = highlight(t.Description, marks, start, end)
+	}
+	return hit, true
+}
+
+// Rank matches the tasks against the search and returns the requested page
+// of hits, ordered by score, then due date and ID.
+func (s TaskSearch) Rank(tasks []Task) TaskSearchPage {
+	hits := []SearchHit{}
+	for _, t := range tasks {
+		if hit, ok := s.Match(t); ok {
+			hits = append(hits, hit)
+		}
+	}
+	sort.Slice(hits, func(i, j int) bool {
+		a, b := hits[i], hits[j]
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		if c := a.Task.DueDate.Compare(b.Task.DueDate); c != 0 {
+			return c < 0
+		}
+		return a.Task.ID < b.Task.ID
+	})
+
+	page := TaskSearchPage{Total: int64(len(hits)), Page: s.Page, PageSize: s.PageSize}
+	start := min((s.Page-1)*s.PageSize, len(hits))
+	end := min(start+s.PageSize, len(hits))
+	page.Hits = hits[start:end]
+	if end < len(hits) {
+		page.NextPage = s.Page + 1
+	}
+	return page
+}
+
+// spans turns token ranges into sorted, merged byte ranges of the text.
+func spans(tokens []Token, matches [][2]int) [][2]int {
+	ranges := make([][2]int, len(matches))
+	for i, m := range matches {
+		ranges[i] = [2]int{tokens[m[0]].Start, tokens[m[1]-1].End}
+	}
+	sort.Slice(ranges, func(i, j int) bool { return ranges[i][0] < ranges[j][0] })
+	merged := ranges[:1]
+	for _, r := range ranges[1:] {
+		last := &merged[len(merged)-1]
+		if r[0] <= last[1] {
+			last[1] = max(last[1], r[1])
+			continue
+		}
+		merged = append(merged, r)
+	}
+	return merged
+}
+
+// snippetBounds picks the part of text around the match starting at first,
+// cut at spaces.
+func snippetBounds(text string, first int) (int, int) {
+	if len(text) <= snippetLength {
+		return 0, len(text)
+	}
+	start := max(first-snippetContext, 0)
+	if start > 0 {
+		if i := strings.IndexFunc(text[start:first], unicode.IsSpace); i >= 0 {
+			start += i + 1
+		} else {
+			start = first
+		}
+	}
+	end := min(start+snippetLength, len(text))
+	if end < len(text) {
+		if i := strings.LastIndexFunc(text[first:end], unicode.IsSpace); i > 0 {
+			end = first + i
+		}
+	}
+	return start, end
+}
+
+// highlight escapes text[start:end] and marks the ranges inside it.
+func highlight(text string, marks [][2]int, start, end int) string {
+	var b strings.Builder
+	if start > 0 {
+		b.WriteString("…")
+	}
+	at := start
+	for _, m := range marks {
+		if m[0] < at || m[1] > end {
+			continue
+		}
+		b.WriteString(html.EscapeString(text[at:m[0]]))
+		b.WriteString("<mark>")
+		b.WriteString(html.EscapeString(text[m[0]:m[1]]))
+		b.WriteString("</mark>")
+		at = m[1]
+	}
+	b.WriteString(html.EscapeString(text[at:end]))
+	if end < len(text) {
+		b.WriteString("…")
+	}
+	return b.String()
+}
@@ -0,0 +1,137 @@
+package domain
+
+import (
+	"encoding/json"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+)
+
+// WebhookEvents lists the task events webhooks can subscribe to.
+var WebhookEvents = []string{
+	EventTaskCreated, EventTaskUpdated, EventTaskStatusChanged, EventTaskDeleted,
+	EventTaskDueSoon, EventTaskOverdue,
+}
+
+// Webhook is an endpoint that receives task events.
+type Webhook struct {
+	ID     string   `json:"id" bson:"_id,omitempty"`
+	URL    string   `json:"url" bson:"url"`
+	Events []string `json:"events" bson:"events"`
+	// Secret keys the delivery signatures. It is only shown when the webhook is created.
+	Secret    string    `json:"secret,omitempty" bson:"secret"`
+	CreatedBy string    `json:"created_by" bson:"created_by"`
+	CreatedAt time.Time `json:"created_at" bson:"created_at"`
+}
+
+// Normalize drops blank and duplicate events.
+func (w *Webhook) Normalize() {
+	w.URL = strings.TrimSpace(w.URL)
+	w.Events = uniqueStrings(w.Events, strings.TrimSpace)
+}
+
+// Validate checks that the webhook has an absolute HTTP(S) URL and known events.
+func (w *Webhook) Validate() error {
+	u, err := url.Parse(w.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return invalidField("url", "url must be an absolute http or https URL")
+	}
+	if len(w.Events) == 0 {
+		return invalidField("events", "at least one event is required")
+	}
+	for _, event := range w.Events {
+		if !slices.Contains(WebhookEvents, event) {
+			return invalidField("events", "unknown event: "+event)
+		}
+	}
+	return nil
+}
+
+// Subscribes reports whether the webhook receives events of the type.
+func (w Webhook) Subscribes(eventType string) bool {
+	return slices.Contains(w.Events, eventType)
+}
+
+// Webhook delivery statuses.
+const (
+	DeliveryPending   = "pending"   // waiting for its next attempt
+	DeliverySucceeded = "succeeded" // the endpoint answered with a 2xx status
+	DeliveryFailed    = "failed"    // every attempt failed
+)
+
+// WebhookDelivery is one event sent, or to be sent, to a webhook.
+type WebhookDelivery struct {
+	ID        string          `json:"id" bson:"_id,omitempty"`
+	WebhookID string          `json:"webhook_id" bson:"webhook_id"`
+	EventID   int64           `json:"event_id" bson:"event_id"`
+	EventType string          `json:"event_type" bson:"event_type"`
+	Payload   json.RawMessage `json:"payload" bson:"payload"` // the request body
+	Status    string          `json:"status" bson:"status"`
+	Attempts  int             `json:"attempts" bson:"attempts"`
+	// ResponseStatus and LastError describe the latest attempt.
+	ResponseStatus int       `json:"response_status,omitempty" bson:"response_status"`
+	LastError      string    `json:"last_error,omitempty" bson:"last_error"`
+	NextAttemptAt  time.Time `json:"next_attempt_at" bson:"next_attempt_at"`
+	CreatedAt      time.Time `json:"created_at" bson:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at" bson:"updated_at"`
+}
+
+// DeliveryQuery describes a paginated listing of a webhook's deliveries, newest first.
+type DeliveryQuery struct {
+	WebhookID string
+	Status    string
+	Page      int
+	PageSize  int
+}
+
+// Normalize fills in defaults and validates the query.
+func (q *DeliveryQuery) Normalize() error {
+	if q.Status != "" && q.Status != DeliveryPending && q.Status != DeliverySucceeded && q.Status != DeliveryFailed {
+		return invalidField("status", "invalid status")
+	}
+	if q.Page == 0 {
+		q.Page = 1
+	}
+	if q.Page < 0 {
+		return invalidField("page", "invalid page")
+	}
+	if q.PageSize == 0 {
+		q.PageSize = DefaultPageSize
+	}
+	if q.PageSize < 0 || q.PageSize > MaxPageSize {
+		return invalidField("page_size", "invalid page size")
+	}
+	return nil
+}
+
+// Matches reports whether the delivery satisfies the query's filters.
+func (q DeliveryQuery) Matches(d WebhookDelivery) bool {
+	return d.WebhookID == q.WebhookID && (q.Status == "" || d.Status == q.Status)
+}
+
+// Skip returns the number of deliveries before the requested page.
+func (q DeliveryQuery) Skip() int64 {
+	return int64(q.Page-1) * int64(q.PageSize)
+}
+
+// DeliveryPage is one page of a delivery listing.
+type DeliveryPage struct {
+	Deliveries []WebhookDelivery `json:"data"`
+	Total      int64             `json:"total"`
+	Page       int               `json:"page"`
+	PageSize   int               `json:"page_size"`
+	NextPage   int               `json:"next_page,omitempty"`
+}
+
+// NewDeliveryPage builds the page for the query, setting NextPage when more deliveries remain.
+func NewDeliveryPage(q DeliveryQuery, deliveries []WebhookDelivery, total int64) DeliveryPage {
+	if deliveries == nil {
+		deliveries = []WebhookDelivery{}
+	}
+	page := DeliveryPage{Deliveries: deliveries, Total: total, Page: q.Page, PageSize: q.PageSize}
+	if q.Skip()+int64(len(deliveries)) < total {
+		page.NextPage = q.Page + 1
+	}
+	return page
+}
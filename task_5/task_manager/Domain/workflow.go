@@ -0,0 +1,150 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+)
+
+// Transition allows tasks to move from one status to another.
+// When Roles is set, only actors with one of those roles may make the move.
+type Transition struct {
+	From  string   `json:"from"`
+	To    string   `json:"to"`
+	Roles []string `json:"roles,omitempty"`
+}
+
+// Workflow is the set of statuses a task can be in and the transitions
+// allowed between them.
+type Workflow struct {
+	Statuses    []string     `json:"statuses"`
+	Initial     string       `json:"initial"`   // status of new tasks that do not name one
+	Completed   string       `json:"completed"` // status that counts as done for subtask rules
+	Transitions []Transition `json:"transitions"`
+}
+
+// DefaultWorkflow returns the pending, in_progress and completed workflow
+// in which only admins may reopen a completed task.
+func DefaultWorkflow() Workflow {
+	return Workflow{
+		Statuses:  []string{StatusPending, StatusInProgress, StatusCompleted},
+		Initial:   StatusPending,
+		Completed: StatusCompleted,
+		Transitions: []Transition{
+			{From: StatusPending, To: StatusInProgress},
+			{From: StatusPending, To: StatusCompleted},
+			{From: StatusInProgress, To: StatusPending},
+			{From: StatusInProgress, To: StatusCompleted},
+			{From: StatusCompleted, To: StatusPending, Roles: []string{RoleAdmin}},
+			{From: StatusCompleted, To: StatusInProgress, Roles: []string{RoleAdmin}},
+		},
+	}
+}
+
+// ParseWorkflow decodes a JSON workflow definition and validates it.
+func ParseWorkflow(data []byte) (Workflow, error) {
+	var w Workflow
+	if err := json.Unmarshal(data, &w); err != nil {
+		return Workflow{}, fmt.Errorf("invalid workflow: %w", err)
+	}
+	if err := w.Validate(); err != nil {
+		return Workflow{}, err
+	}
+	return w, nil
+}
+
+// Validate checks that the workflow only refers to its own statuses.
+func (w Workflow) Validate() error {
+	if len(w.Statuses) == 0 {
+		return invalidField("statuses", "a workflow needs at least one status")
+	}
+	seen := make(map[string]bool, len(w.Statuses))
+	for _, s := range w.Statuses {
+		if s == "" || seen[s] {
+			return invalidField("statuses", "statuses must be unique and non-empty")
+		}
+		seen[s] = true
+	}
+	if !seen[w.Initial] {
+		return invalidField("initial", "unknown initial status: "+w.Initial)
+	}
+	if !seen[w.Completed] {
+		return invalidField("completed", "unknown completed status: "+w.Completed)
+	}
+	for _, t := range w.Transitions {
+		if !seen[t.From] || !seen[t.To] || t.From == t.To {
+			return invalidField("transitions", fmt.Sprintf("invalid transition from %q to %q", t.From, t.To))
+		}
+	}
+	return nil
+}
+
+// HasStatus reports whether the workflow defines the status.
+func (w Workflow) HasStatus(status string) bool {
+	return slices.Contains(w.Statuses, status)
+}
+
+// IsCompleted reports whether the status counts as done.
+func (w Workflow) IsCompleted(status string) bool {
+	return status == w.Completed
+}
+
+// CheckStatus rejects statuses the workflow does not define.
+func (w Workflow) CheckStatus(status string) error {
+	if !w.HasStatus(status) {
+		return invalidField("status", "invalid status")
+	}
+	return nil
+}
+
+// CheckTransition reports whether an actor with the role may move a task
+// from one status to another. Staying in the same status is always allowed.
+func (w Workflow) CheckTransition(from, to, role string) error {
+	if from == to {
+		return nil
+	}
+	allowed := false
+	for _, t := range w.Transitions {
+		if t.From != from || t.To != to {
+			continue
+		}
+		if len(t.Roles) == 0 || slices.Contains(t.Roles, role) {
+			return nil
+		}
+		allowed = true
+	}
+	message := fmt.Sprintf("cannot move a task from %s to %s", from, to)
+	if allowed {
+		return NewForbiddenError(message + " with role " + role)
+	}
+	return &Error{Code: CodeInvalidTransition, Message: message, Fields: []FieldError{{Field: "status", Message: message}}}
+}
+
+// NextStatuses lists the statuses an actor with the role may move a task to
+// from the given status.
+func (w Workflow) NextStatuses(from, role string) []string {
+	var next []string
+	for _, t := range w.Transitions {
+		if t.From != from || slices.Contains(next, t.To) {
+			continue
+		}
+		if len(t.Roles) == 0 || slices.Contains(t.Roles, role) {
+			next = append(next, t.To)
+		}
+	}
+	return next
+}
+
+// ValidateSubtasks checks the task against its subtasks:
+// a task can only be completed once every subtask is completed.
+func (w Workflow) ValidateSubtasks(t Task, subtasks []Task) error {
+	if !w.IsCompleted(t.Status) {
+		return nil
+	}
+	for _, sub := range subtasks {
+		if !w.IsCompleted(sub.Status) {
+			return invalidField("status", "cannot complete a task with incomplete subtasks")
+		}
+	}
+	return nil
+}
@@ -1,20 +1,40 @@
 package infrastructure
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"net/http"
 	"strings"
 
+	domain "task_manager/Domain"
+	repositories "task_manager/Repositories"
+
 	"github.com/gin-gonic/gin"
 )
 
+// PermissionResolver resolves the current role of a user and the
+// permissions granted by a role.
+type PermissionResolver interface {
+	UserRole(ctx context.Context, userID string) (string, error)
+	PermissionsForRole(ctx context.Context, role string) ([]string, error)
+}
+
+// ProjectResolver resolves what a caller may do in a project.
+type ProjectResolver interface {
+	ProjectAccess(ctx context.Context, actor domain.Actor, projectID string) (domain.ProjectAccess, error)
+}
+
 // AuthMiddleware handles JWT authentication.
 type AuthMiddleware struct {
-	jwtService *JWTService
+	jwtService  *JWTService
+	revocations repositories.IRevocationStore
+	permissions PermissionResolver
 }
 
 // NewAuthMiddleware creates a new auth middleware.
-func NewAuthMiddleware(jwtService *JWTService) *AuthMiddleware {
-	return &AuthMiddleware{jwtService: jwtService}
+func NewAuthMiddleware(jwtService *JWTService, revocations repositories.IRevocationStore, permissions PermissionResolver) *AuthMiddleware {
+	return &AuthMiddleware{jwtService: jwtService, revocations: revocations, permissions: permissions}
 }
 
 // AuthRequired middleware checks for valid JWT token.
@@ -22,39 +42,124 @@ func (a *AuthMiddleware) AuthRequired() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "authorization header required"})
+			c.Error(domain.NewUnauthorizedError("authorization header required"))
 			c.Abort()
 			return
 		}
 
 		tokenString := strings.TrimPrefix(authHeader, "Bearer ")
 		if tokenString == authHeader {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "bearer token required"})
+			c.Error(domain.NewUnauthorizedError("bearer token required"))
 			c.Abort()
 			return
 		}
 
 		claims, err := a.jwtService.ValidateToken(tokenString)
 		if err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid token"})
+			c.Error(domain.NewUnauthorizedError("invalid token"))
+			c.Abort()
+			return
+		}
+
+		jti, _ := claims["jti"].(string)
+		if jti == "" {
+			c.Error(domain.NewUnauthorizedError("invalid token"))
+			c.Abort()
+			return
+		}
+		revoked, err := a.revocations.IsAccessTokenRevoked(c.Request.Context(), jti)
+		if err != nil {
+			c.Error(fmt.Errorf("failed to check token revocation: %w", err))
+			c.Abort()
+			return
+		}
+		if revoked {
+			c.Error(domain.NewUnauthorizedError("token revoked"))
+			c.Abort()
+			return
+		}
+
+		// The role and its permissions are resolved per request rather than
+		// taken from the token, so that promotions, demotions and changes to
+		// a role apply to tokens already issued
+		userID, _ := claims["sub"].(string)
+		role, err := a.permissions.UserRole(c.Request.Context(), userID)
+		if err != nil {
+			if errors.Is(err, repositories.ErrUserNotFound) {
+				c.Error(domain.NewUnauthorizedError("user not found"))
+			} else {
+				c.Error(fmt.Errorf("failed to resolve role: %w", err))
+			}
+			c.Abort()
+			return
+		}
+		permissions, err := a.permissions.PermissionsForRole(c.Request.Context(), role)
+		if err != nil {
+			c.Error(fmt.Errorf("failed to resolve permissions: %w", err))
 			c.Abort()
 			return
 		}
 
 		// Set user info in context
-		c.Set("user_id", claims["sub"])
+		c.Set("user_id", userID)
 		c.Set("username", claims["usr"])
-		c.Set("role", claims["role"])
+		c.Set("role", role)
+		c.Set("permissions", permissions)
+		c.Set("jti", jti)
+		c.Next()
+	}
+}
+
+// RequirePermission middleware checks that the caller's role grants the permission.
+func (a *AuthMiddleware) RequirePermission(permission string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		for _, p := range c.GetStringSlice("permissions") {
+			if p == permission {
+				c.Next()
+				return
+			}
+		}
+		c.Error(domain.NewForbiddenError("permission required: " + permission))
+		c.Abort()
+	}
+}
+
+// ProjectScope middleware scopes the request to the project named by the
+// :pid route parameter. It must run after AuthRequired, whose permissions
+// it replaces with those the caller has in the project. Archived projects
+// only accept reads.
+func ProjectScope(projects ProjectResolver) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		actor := domain.Actor{
+			UserID:      c.GetString("user_id"),
+			Role:        c.GetString("role"),
+			Permissions: c.GetStringSlice("permissions"),
+		}
+		access, err := projects.ProjectAccess(c.Request.Context(), actor, c.Param("pid"))
+		if err != nil {
+			c.Error(err)
+			c.Abort()
+			return
+		}
+		if access.ReadOnly && c.Request.Method != http.MethodGet && c.Request.Method != http.MethodHead {
+			c.Error(domain.NewConflictError("project is archived"))
+			c.Abort()
+			return
+		}
+		c.Set("project_id", access.ProjectID)
+		c.Set("permissions", access.Permissions)
 		c.Next()
 	}
 }
 
 // AdminRequired middleware checks for admin role.
+//
+// Deprecated: use RequirePermission.
 func (a *AuthMiddleware) AdminRequired() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		role, exists := c.Get("role")
 		if !exists || role != "admin" {
-			c.JSON(http.StatusForbidden, gin.H{"error": "admin access required"})
+			c.Error(domain.NewForbiddenError("admin access required"))
 			c.Abort()
 			return
 		}
@@ -0,0 +1,111 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+
+	domain "task_manager/Domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequestIDHeader carries the request ID to and from clients.
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength bounds request IDs accepted from clients.
+const maxRequestIDLength = 64
+
+// Error codes of failures that are not domain errors.
+const (
+	CodeTimeout  = "timeout"
+	CodeInternal = "internal_error"
+)
+
+// ErrorResponse is the JSON body of every error response.
+type ErrorResponse struct {
+	Error ErrorBody `json:"error"`
+}
+
+// ErrorBody describes a failed request.
+type ErrorBody struct {
+	Code      string              `json:"code"`
+	Message   string              `json:"message"`
+	Fields    []domain.FieldError `json:"fields,omitempty"`
+	RequestID string              `json:"request_id"`
+}
+
+// ErrorHandler assigns every request an ID and turns the last error a
+// handler attached with c.Error into an ErrorResponse.
+// It must run before any middleware or handler that reports errors.
+func ErrorHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if requestID == "" || len(requestID) > maxRequestIDLength {
+			requestID, _ = randomToken(16)
+		}
+		c.Set("request_id", requestID)
+		c.Header(RequestIDHeader, requestID)
+
+		c.Next()
+
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+		err := c.Errors.Last().Err
+		status, body := DescribeError(err)
+		if status == http.StatusInternalServerError {
+			log.Printf("request %s failed: %v", requestID, err)
+		}
+		body.RequestID = requestID
+		var domainErr *domain.Error
+		if errors.As(err, &domainErr) && domainErr.RetryAfter > 0 {
+			c.Header("Retry-After", strconv.FormatInt(int64(math.Ceil(domainErr.RetryAfter.Seconds())), 10))
+		}
+		c.JSON(status, ErrorResponse{Error: body})
+	}
+}
+
+// DescribeError maps an error to its response status and body, leaving the request ID unset.
+// Errors that are not domain errors are reported without their details.
+func DescribeError(err error) (int, ErrorBody) {
+	var domainErr *domain.Error
+	if errors.As(err, &domainErr) {
+		return errorStatus(domainErr.Code), ErrorBody{
+			Code:    string(domainErr.Code),
+			Message: domainErr.Message,
+			Fields:  domainErr.Fields,
+		}
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return http.StatusGatewayTimeout, ErrorBody{Code: CodeTimeout, Message: "request timed out"}
+	}
+	return http.StatusInternalServerError, ErrorBody{Code: CodeInternal, Message: "internal server error"}
+}
+
+func errorStatus(code domain.ErrorCode) int {
+	switch code {
+	case domain.CodeNotFound:
+		return http.StatusNotFound
+	case domain.CodeConflict, domain.CodeInvalidTransition:
+		return http.StatusConflict
+	case domain.CodeValidation:
+		return http.StatusBadRequest
+	case domain.CodeUnauthorized:
+		return http.StatusUnauthorized
+	case domain.CodeForbidden:
+		return http.StatusForbidden
+	case domain.CodePreconditionFailed:
+		return http.StatusPreconditionFailed
+	case domain.CodeTooLarge:
+		return http.StatusRequestEntityTooLarge
+	case domain.CodeUnsupportedMedia:
+		return http.StatusUnsupportedMediaType
+	case domain.CodeRateLimited:
+		return http.StatusTooManyRequests
+	}
+	return http.StatusInternalServerError
+}
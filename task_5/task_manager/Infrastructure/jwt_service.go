@@ -1,7 +1,14 @@
 package infrastructure
 
 import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"encoding/hex"
 	"errors"
+	"fmt"
+	"log"
+	"sync"
 	"time"
 
 	domain "task_manager/Domain"
@@ -9,30 +16,124 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 )
 
+// AccessTokenTTL is the lifetime of an access token.
+const AccessTokenTTL = 15 * time.Minute
+
+// Default issuer and audience of access tokens.
+const (
+	DefaultIssuer   = "task_manager"
+	DefaultAudience = "task_manager"
+)
+
+// JWTConfig configures a JWTService.
+type JWTConfig struct {
+	// Algorithm is HS256, RS256 or EdDSA.
+	Algorithm string
+	// Secret is the shared HMAC secret used with HS256.
+	Secret string
+	// Keys are the RS256 or EdDSA private keys. The last key signs new
+	// tokens; the others only verify. A key is generated when empty.
+	Keys     []crypto.Signer
+	Issuer   string
+	Audience string
+}
+
 // JWTService handles JWT token operations.
 type JWTService struct {
-	secret []byte
+	mu        sync.RWMutex
+	algorithm string
+	keys      []*signingKey // the last key is the current signer
+	issuer    string
+	audience  string
 }
 
-// NewJWTService creates a new JWT service with the given secret.
+// NewJWTService creates a new HS256 JWT service with the given secret.
 func NewJWTService(secret string) *JWTService {
-	return &JWTService{secret: []byte(secret)}
+	return &JWTService{
+		algorithm: AlgorithmHS256,
+		keys: []*signingKey{{
+			method:  jwt.SigningMethodHS256,
+			private: []byte(secret),
+			public:  []byte(secret),
+		}},
+		issuer:   DefaultIssuer,
+		audience: DefaultAudience,
+	}
+}
+
+// NewJWTServiceFromConfig creates a JWT service for the configured algorithm and keys.
+func NewJWTServiceFromConfig(cfg JWTConfig) (*JWTService, error) {
+	if cfg.Issuer == "" {
+		cfg.Issuer = DefaultIssuer
+	}
+	if cfg.Audience == "" {
+		cfg.Audience = DefaultAudience
+	}
+
+	switch cfg.Algorithm {
+	case "", AlgorithmHS256:
+		if cfg.Secret == "" {
+			return nil, errors.New("JWT secret not configured")
+		}
+		j := NewJWTService(cfg.Secret)
+		j.issuer, j.audience = cfg.Issuer, cfg.Audience
+		return j, nil
+	case AlgorithmRS256, AlgorithmEdDSA:
+	default:
+		return nil, fmt.Errorf("unsupported JWT algorithm %q", cfg.Algorithm)
+	}
+
+	j := &JWTService{algorithm: cfg.Algorithm, issuer: cfg.Issuer, audience: cfg.Audience}
+	for _, private := range cfg.Keys {
+		key, err := newSigningKey(private)
+		if err != nil {
+			return nil, err
+		}
+		if key.method.Alg() != cfg.Algorithm {
+			return nil, fmt.Errorf("key %s does not match algorithm %s", key.id, cfg.Algorithm)
+		}
+		j.keys = append(j.keys, key)
+	}
+	if len(j.keys) == 0 {
+		if err := j.RotateKey(); err != nil {
+			return nil, err
+		}
+	}
+	return j, nil
 }
 
 // GenerateToken generates a JWT token for the user.
 func (j *JWTService) GenerateToken(user domain.User) (string, error) {
-	if len(j.secret) == 0 {
+	j.mu.RLock()
+	key := j.keys[len(j.keys)-1]
+	j.mu.RUnlock()
+
+	if secret, ok := key.private.([]byte); ok && len(secret) == 0 {
 		return "", errors.New("JWT secret not configured")
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+	jti, err := randomToken(16)
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	token := jwt.NewWithClaims(key.method, jwt.MapClaims{
 		"sub":  user.ID.Hex(),
 		"usr":  user.Username,
 		"role": user.Role,
-		"exp":  time.Now().Add(24 * time.Hour).Unix(),
+		"jti":  jti,
+		"iss":  j.issuer,
+		"aud":  j.audience,
+		"iat":  now.Unix(),
+		"nbf":  now.Unix(),
+		"exp":  now.Add(AccessTokenTTL).Unix(),
 	})
+	if key.id != "" {
+		token.Header["kid"] = key.id
+	}
 
-	tokenString, err := token.SignedString(j.secret)
+	tokenString, err := token.SignedString(key.private)
 	if err != nil {
 		return "", err
 	}
@@ -42,20 +143,106 @@ func (j *JWTService) GenerateToken(user domain.User) (string, error) {
 
 // ValidateToken validates the JWT token and returns the claims.
 func (j *JWTService) ValidateToken(tokenString string) (jwt.MapClaims, error) {
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, errors.New("unexpected signing method")
-		}
-		return j.secret, nil
-	})
+	token, err := jwt.Parse(tokenString, j.verificationKey,
+		jwt.WithValidMethods([]string{j.algorithm}),
+		jwt.WithIssuer(j.issuer),
+		jwt.WithAudience(j.audience),
+		jwt.WithIssuedAt(),
+		jwt.WithExpirationRequired(),
+	)
 
 	if err != nil {
 		return nil, err
 	}
 
-	if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
-		return claims, nil
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || !token.Valid {
+		return nil, errors.New("invalid token")
+	}
+	// The parser checks nbf only when present
+	if nbf, err := claims.GetNotBefore(); err != nil || nbf == nil {
+		return nil, errors.New("token has no not-before claim")
+	}
+
+	return claims, nil
+}
+
+// verificationKey picks the key named by the token's kid header.
+func (j *JWTService) verificationKey(token *jwt.Token) (interface{}, error) {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+
+	kid, _ := token.Header["kid"].(string)
+	for _, key := range j.keys {
+		if key.id == kid && key.method.Alg() == token.Method.Alg() {
+			return key.public, nil
+		}
+	}
+	return nil, errors.New("unknown signing key")
+}
+
+// RotateKey generates a new signing key and retires the current one.
+// Retired keys keep verifying until every token they signed has expired.
+func (j *JWTService) RotateKey() error {
+	key, err := generateSigningKey(j.algorithm)
+	if err != nil {
+		return err
 	}
 
-	return nil, errors.New("invalid token")
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	now := time.Now()
+	keys := make([]*signingKey, 0, len(j.keys)+1)
+	for _, k := range j.keys {
+		if k.retiredAt.IsZero() {
+			k.retiredAt = now
+		}
+		if now.Sub(k.retiredAt) <= AccessTokenTTL {
+			keys = append(keys, k)
+		}
+	}
+	j.keys = append(keys, key)
+	return nil
+}
+
+// StartKeyRotation rotates the signing key every interval until ctx is done.
+func (j *JWTService) StartKeyRotation(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := j.RotateKey(); err != nil {
+					log.Printf("JWT key rotation failed: %v", err)
+				}
+			}
+		}
+	}()
+}
+
+// JWKS returns the public keys that can verify tokens issued by this service.
+func (j *JWTService) JWKS() JWKS {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+
+	set := JWKS{Keys: []JWK{}}
+	for _, key := range j.keys {
+		if jwk, ok := key.jwk(); ok {
+			set.Keys = append(set.Keys, jwk)
+		}
+	}
+	return set
+}
+
+// randomToken returns n random bytes encoded as hex.
+func randomToken(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
 }
@@ -0,0 +1,155 @@
+package infrastructure
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"log"
+	"mime"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+
+	domain "task_manager/Domain"
+)
+
+// LogNotifier writes reminder events to a logger.
+type LogNotifier struct {
+	logger *log.Logger
+}
+
+// NewLogNotifier creates a notifier that logs to logger, or to the standard logger when nil.
+func NewLogNotifier(logger *log.Logger) *LogNotifier {
+	if logger == nil {
+		logger = log.Default()
+	}
+	return &LogNotifier{logger: logger}
+}
+
+// Notify logs the event.
+func (n *LogNotifier) Notify(ctx context.Context, event domain.TaskEvent) error {
+	n.logger.Printf("Reminder %s: task %s %q is due %s", event.Type, event.Task.ID, event.Task.Title,
+		event.Task.DueDate.UTC().Format(time.RFC3339))
+	return nil
+}
+
+// DefaultSMTPTimeout bounds one SMTP conversation.
+const DefaultSMTPTimeout = 10 * time.Second
+
+// SMTPConfig describes the mail server reminders are sent through.
+type SMTPConfig struct {
+	Addr     string // host:port
+	Username string // empty to send without authentication
+	Password string
+	From     string
+	To       []string
+	Timeout  time.Duration
+}
+
+// SMTPNotifier emails reminder events to a fixed list of recipients.
+type SMTPNotifier struct {
+	config SMTPConfig
+}
+
+// NewSMTPNotifier creates a notifier that sends mail as configured.
+func NewSMTPNotifier(config SMTPConfig) (*SMTPNotifier, error) {
+	if _, _, err := net.SplitHostPort(config.Addr); err != nil {
+		return nil, fmt.Errorf("invalid SMTP address %q: %w", config.Addr, err)
+	}
+	var to []string
+	for _, addr := range config.To {
+		if addr = strings.TrimSpace(addr); addr != "" {
+			to = append(to, addr)
+		}
+	}
+	config.To = to
+	if config.From == "" || len(config.To) == 0 {
+		return nil, fmt.Errorf("SMTP sender and recipients are required")
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = DefaultSMTPTimeout
+	}
+	return &SMTPNotifier{config: config}, nil
+}
+
+// Notify emails the event. The connection is upgraded with STARTTLS when
+// the server offers it, and authentication is only attempted over TLS or to
+// localhost.
+func (n *SMTPNotifier) Notify(ctx context.Context, event domain.TaskEvent) error {
+	ctx, cancel := context.WithTimeout(ctx, n.config.Timeout)
+	defer cancel()
+
+	host, _, _ := net.SplitHostPort(n.config.Addr)
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", n.config.Addr)
+	if err != nil {
+		return fmt.Errorf("failed to connect to SMTP server: %w", err)
+	}
+	deadline, _ := ctx.Deadline()
+	conn.SetDeadline(deadline)
+
+	client, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to start SMTP session: %w", err)
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: host}); err != nil {
+			return fmt.Errorf("failed to start TLS: %w", err)
+		}
+	}
+	if n.config.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", n.config.Username, n.config.Password, host)); err != nil {
+			return fmt.Errorf("SMTP authentication failed: %w", err)
+		}
+	}
+	if err := client.Mail(n.config.From); err != nil {
+		return fmt.Errorf("SMTP server refused sender: %w", err)
+	}
+	for _, to := range n.config.To {
+		if err := client.Rcpt(to); err != nil {
+			return fmt.Errorf("SMTP server refused recipient %s: %w", to, err)
+		}
+	}
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("failed to send mail: %w", err)
+	}
+	if _, err := w.Write(n.message(event)); err != nil {
+		return fmt.Errorf("failed to send mail: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("failed to send mail: %w", err)
+	}
+	return client.Quit()
+}
+
+// message renders the event as a plain-text email.
+func (n *SMTPNotifier) message(event domain.TaskEvent) []byte {
+	task := event.Task
+	// Titles are user input; keep them on one header line
+	title := strings.Join(strings.Fields(task.Title), " ")
+	subject := "Task due soon: " + title
+	if event.Type == domain.EventTaskOverdue {
+		subject = "Task overdue: " + title
+	}
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "From: %s\r\n", n.config.From)
+	fmt.Fprintf(&b, "To: %s\r\n", strings.Join(n.config.To, ", "))
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	b.WriteString("\r\n")
+	fmt.Fprintf(&b, "Task: %s\r\n", title)
+	fmt.Fprintf(&b, "ID: %s\r\n", task.ID)
+	fmt.Fprintf(&b, "Status: %s\r\n", task.Status)
+	fmt.Fprintf(&b, "Due: %s\r\n", task.DueDate.UTC().Format(time.RFC3339))
+	fmt.Fprintf(&b, "Owner: %s\r\n", task.OwnerID)
+	return b.Bytes()
+}
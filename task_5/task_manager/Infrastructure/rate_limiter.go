@@ -0,0 +1,142 @@
+package infrastructure
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	domain "task_manager/Domain"
+	repositories "task_manager/Repositories"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Rate limit keys: requests are counted per client IP and per username.
+const (
+	RateLimitByIP       = "ip"
+	RateLimitByUsername = "username"
+)
+
+// maxRateLimitedBody bounds the request bodies read for their username.
+const maxRateLimitedBody = 64 << 10
+
+// RateLimits holds the limit of each route and key, named
+// "<route>.<key>", for example "login.ip" or "register.username".
+// Routes and keys without a limit are not limited.
+type RateLimits map[string]domain.RateLimit
+
+// DefaultRateLimits throttles logins per client IP and per account, and
+// account creation per client IP.
+func DefaultRateLimits() RateLimits {
+	return RateLimits{
+		"login.ip":       {Burst: 20, Period: time.Minute},
+		"login.username": {Burst: 10, Period: time.Minute},
+		"register.ip":    {Burst: 5, Period: time.Hour},
+	}
+}
+
+// ParseRateLimits applies overrides such as "login.ip=10/1m,register.ip=0"
+// to a copy of limits. A limit of 0 turns the limit off.
+func ParseRateLimits(limits RateLimits, overrides string) (RateLimits, error) {
+	parsed := make(RateLimits, len(limits))
+	for name, limit := range limits {
+		parsed[name] = limit
+	}
+	for _, entry := range strings.Split(overrides, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid rate limit %q: expected route.key=burst/period", entry)
+		}
+		name = strings.TrimSpace(name)
+		_, key, _ := strings.Cut(name, ".")
+		if key != RateLimitByIP && key != RateLimitByUsername {
+			return nil, fmt.Errorf("invalid rate limit %q: key must be %s or %s", name, RateLimitByIP, RateLimitByUsername)
+		}
+		limit, err := domain.ParseRateLimit(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid rate limit for %s: %w", name, err)
+		}
+		parsed[name] = limit
+	}
+	return parsed, nil
+}
+
+// RateLimiter throttles requests with token buckets kept in a store, so
+// that every server sharing the store enforces the same limits.
+type RateLimiter struct {
+	store  repositories.IRateLimitStore
+	limits RateLimits
+}
+
+// NewRateLimiter creates a rate limiter enforcing the limits.
+func NewRateLimiter(store repositories.IRateLimitStore, limits RateLimits) *RateLimiter {
+	return &RateLimiter{store: store, limits: limits}
+}
+
+// Limit middleware throttles the route per client IP and per the username
+// in the JSON request body, as configured for it. Usernames are counted
+// from every client IP together, so that guesses spread over many addresses
+// are throttled too. Rejected requests get 429 Too Many Requests with a
+// Retry-After header.
+func (rl *RateLimiter) Limit(route string) gin.HandlerFunc {
+	byIP := rl.limits[route+"."+RateLimitByIP]
+	byUsername := rl.limits[route+"."+RateLimitByUsername]
+	return func(c *gin.Context) {
+		if byIP.Enabled() && !rl.take(c, route+"."+RateLimitByIP+":"+c.ClientIP(), byIP) {
+			return
+		}
+		if byUsername.Enabled() {
+			if username := requestUsername(c); username != "" && !rl.take(c, route+"."+RateLimitByUsername+":"+username, byUsername) {
+				return
+			}
+		}
+		c.Next()
+	}
+}
+
+// take takes a token for key, aborting the request when there is none.
+func (rl *RateLimiter) take(c *gin.Context, key string, limit domain.RateLimit) bool {
+	wait, err := rl.store.Take(c.Request.Context(), key, limit, time.Now())
+	if err != nil {
+		c.Error(fmt.Errorf("failed to check rate limit: %w", err))
+		c.Abort()
+		return false
+	}
+	if wait > 0 {
+		c.Error(domain.NewRateLimitedError("too many requests", wait))
+		c.Abort()
+		return false
+	}
+	return true
+}
+
+// requestUsername reads the username from the JSON request body and
+// restores the body for the handler.
+func requestUsername(c *gin.Context) string {
+	if c.Request.Body == nil {
+		return ""
+	}
+	original := c.Request.Body
+	body, err := io.ReadAll(io.LimitReader(original, maxRateLimitedBody))
+	c.Request.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(body), original), original}
+	if err != nil || len(body) == maxRateLimitedBody {
+		return ""
+	}
+	var input struct {
+		Username string `json:"username"`
+	}
+	if json.Unmarshal(body, &input) != nil {
+		return ""
+	}
+	return input.Username
+}
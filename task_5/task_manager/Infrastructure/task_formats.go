@@ -0,0 +1,353 @@
+package infrastructure
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	domain "task_manager/Domain"
+)
+
+// Formats tasks are exported and imported in.
+const (
+	FormatCSV   = "csv"
+	FormatJSONL = "jsonl"
+	FormatICS   = "ics"
+)
+
+// taskFormatTypes maps each format to its media type.
+var taskFormatTypes = map[string]string{
+	FormatCSV:   "text/csv; charset=utf-8",
+	FormatJSONL: "application/jsonl",
+	FormatICS:   "text/calendar; charset=utf-8",
+}
+
+// TaskFormatType returns the media type of the format, or false when the format is unknown.
+func TaskFormatType(format string) (string, bool) {
+	t, ok := taskFormatTypes[format]
+	return t, ok
+}
+
+func unknownFormat(format string) error {
+	return domain.NewValidationError("unknown format: "+format,
+		domain.FieldError{Field: "format", Message: "format must be csv, jsonl or ics"})
+}
+
+// TaskEncoder writes tasks in one of the transfer formats.
+type TaskEncoder interface {
+	Encode(t domain.Task) error
+	// Close writes what the format needs after the last task. It does not
+	// close the underlying writer.
+	Close() error
+}
+
+// NewTaskEncoder creates an encoder of the format that writes to w. The
+// workflow maps statuses onto those of iCalendar.
+func NewTaskEncoder(format string, w io.Writer, workflow domain.Workflow) (TaskEncoder, error) {
+	switch format {
+	case FormatCSV:
+		return &csvTaskEncoder{w: csv.NewWriter(w)}, nil
+	case FormatJSONL:
+		return &jsonlTaskEncoder{enc: json.NewEncoder(w)}, nil
+	case FormatICS:
+		return newICSTaskEncoder(w, workflow), nil
+	}
+	return nil, unknownFormat(format)
+}
+
+// TaskRecord is a task read by a TaskDecoder, starting at Line, or why it
+// could not be read.
+type TaskRecord struct {
+	Line int
+	Task domain.Task
+	Err  error
+}
+
+// TaskDecoder reads tasks in one of the transfer formats.
+type TaskDecoder interface {
+	// Next returns the next task, or io.EOF after the last one. A task that
+	// cannot be read is reported in the record's Err; other errors mean the
+	// rest of the input cannot be read.
+	Next() (TaskRecord, error)
+}
+
+// NewTaskDecoder creates a decoder of the format that reads from r. The
+// workflow maps iCalendar statuses onto its own.
+func NewTaskDecoder(format string, r io.Reader, workflow domain.Workflow) (TaskDecoder, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVTaskDecoder(r), nil
+	case FormatJSONL:
+		return newJSONLTaskDecoder(r), nil
+	case FormatICS:
+		return newICSTaskDecoder(r, workflow), nil
+	}
+	return nil, unknownFormat(format)
+}
+
+// invalidRecord reports a field of an imported task that cannot be read.
+func invalidRecord(field, message string) error {
+	return domain.NewValidationError(message, domain.FieldError{Field: field, Message: message})
+}
+
+// malformedInput reports input that cannot be read past the line.
+func malformedInput(line int, err error) error {
+	return domain.NewValidationError(fmt.Sprintf("line %d: %v", line, err))
+}
+
+// csvTaskColumns are the columns of exported CSV files. Imports accept them
+// in any order, and ignore id, owner_id and version.
+var csvTaskColumns = []string{"id", "title", "description", "due_date", "status", "priority", "tags",
+	"assignee_ids", "parent_id", "recurrence", "timezone", "owner_id", "version"}
+
+// csvFormulaPrefixes start the cells that spreadsheets run as formulas.
+const csvFormulaPrefixes = "=+-@\t\r"
+
+// csvTextCell guards a free-text cell against CSV injection: a cell that a
+// spreadsheet would run as a formula is prefixed with an apostrophe, which
+// makes it text. Cells already starting with one get another, so that
+// csvCellText can tell the prefix apart.
+func csvTextCell(s string) string {
+	if s != "" && strings.ContainsRune(csvFormulaPrefixes+"'", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
+
+// csvCellText removes the prefix csvTextCell added.
+func csvCellText(s string) string {
+	if len(s) > 1 && s[0] == '\'' && strings.ContainsRune(csvFormulaPrefixes+"'", rune(s[1])) {
+		return s[1:]
+	}
+	return s
+}
+
+type csvTaskEncoder struct {
+	w             *csv.Writer
+	headerWritten bool
+}
+
+func (e *csvTaskEncoder) writeHeader() error {
+	if e.headerWritten {
+		return nil
+	}
+	e.headerWritten = true
+	return e.w.Write(csvTaskColumns)
+}
+
+func (e *csvTaskEncoder) Encode(t domain.Task) error {
+	if err := e.writeHeader(); err != nil {
+		return err
+	}
+	var rule, timezone string
+	if t.Recurrence != nil {
+		rule, timezone = t.Recurrence.Rule, t.Recurrence.Timezone
+	}
+	if err := e.w.Write([]string{t.ID, csvTextCell(t.Title), csvTextCell(t.Description), t.DueDate.UTC().Format(time.RFC3339),
+		t.Status, t.Priority, csvTextCell(strings.Join(t.Tags, ",")), csvTextCell(strings.Join(t.AssigneeIDs, ",")),
+		t.ParentID, rule, timezone, t.OwnerID,
+		strconv.FormatInt(t.Version, 10)}); err != nil {
+		return err
+	}
+	// Flush per row so the export streams
+	e.w.Flush()
+	return e.w.Error()
+}
+
+func (e *csvTaskEncoder) Close() error {
+	if err := e.writeHeader(); err != nil {
+		return err
+	}
+	e.w.Flush()
+	return e.w.Error()
+}
+
+type csvTaskDecoder struct {
+	r       *csv.Reader
+	columns []string
+}
+
+func newCSVTaskDecoder(r io.Reader) *csvTaskDecoder {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	return &csvTaskDecoder{r: reader}
+}
+
+func (d *csvTaskDecoder) Next() (TaskRecord, error) {
+	if d.columns == nil {
+		if err := d.readHeader(); err != nil {
+			return TaskRecord{}, err
+		}
+	}
+
+	row, err := d.r.Read()
+	if err == io.EOF {
+		return TaskRecord{}, io.EOF
+	}
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) && errors.Is(parseErr.Err, csv.ErrFieldCount) {
+		return TaskRecord{Line: parseErr.StartLine, Err: invalidRecord("row",
+			fmt.Sprintf("expected %d fields, found %d", len(d.columns), len(row)))}, nil
+	}
+	if err != nil {
+		return TaskRecord{}, csvError(err)
+	}
+	line, _ := d.r.FieldPos(0)
+
+	record := TaskRecord{Line: line}
+	var recurrence domain.Recurrence
+	for i, value := range row {
+		switch d.columns[i] {
+		case "title":
+			record.Task.Title = csvCellText(value)
+		case "description":
+			record.Task.Description = csvCellText(value)
+		case "due_date":
+			if record.Task.DueDate, err = parseImportTime(value); err != nil {
+				record.Err = invalidRecord("due_date", err.Error())
+				return record, nil
+			}
+		case "status":
+			record.Task.Status = value
+		case "priority":
+			record.Task.Priority = value
+		case "tags":
+			record.Task.Tags = splitList(csvCellText(value))
+		case "assignee_ids":
+			record.Task.AssigneeIDs = splitList(csvCellText(value))
+		case "parent_id":
+			record.Task.ParentID = value
+		case "recurrence":
+			recurrence.Rule = value
+		case "timezone":
+			recurrence.Timezone = value
+		}
+	}
+	if recurrence != (domain.Recurrence{}) {
+		record.Task.Recurrence = &recurrence
+	}
+	return record, nil
+}
+
+// readHeader reads the column names from the first row.
+func (d *csvTaskDecoder) readHeader() error {
+	header, err := d.r.Read()
+	if err == io.EOF {
+		return io.EOF
+	}
+	if err != nil {
+		return csvError(err)
+	}
+	known := make(map[string]bool, len(csvTaskColumns))
+	for _, column := range csvTaskColumns {
+		known[column] = true
+	}
+	seen := make(map[string]bool, len(header))
+	for i, column := range header {
+		column = strings.ToLower(strings.TrimSpace(column))
+		if i == 0 {
+			// Spreadsheets often save UTF-8 with a byte order mark
+			column = strings.TrimPrefix(column, "\ufeff")
+		}
+		if !known[column] {
+			return malformedInput(1, fmt.Errorf("unknown column %q", column))
+		}
+		if seen[column] {
+			return malformedInput(1, fmt.Errorf("duplicate column %q", column))
+		}
+		seen[column] = true
+		header[i] = column
+	}
+	d.columns = header
+	return nil
+}
+
+func csvError(err error) error {
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		return malformedInput(parseErr.Line, parseErr.Err)
+	}
+	return err
+}
+
+// splitList splits a comma-separated cell, dropping empty entries.
+func splitList(value string) []string {
+	var values []string
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+// parseImportTime accepts RFC 3339 times and, for spreadsheets, plain
+// dates, which are read as midnight UTC. An empty value is the zero time.
+func parseImportTime(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.DateOnly, value); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q: expected RFC 3339 or YYYY-MM-DD", value)
+}
+
+type jsonlTaskEncoder struct {
+	enc *json.Encoder
+}
+
+func (e *jsonlTaskEncoder) Encode(t domain.Task) error {
+	t.NextStatuses = nil
+	return e.enc.Encode(t)
+}
+
+func (e *jsonlTaskEncoder) Close() error {
+	return nil
+}
+
+// maxImportLine bounds one line of a JSON Lines or iCalendar import.
+const maxImportLine = 1 << 20
+
+type jsonlTaskDecoder struct {
+	scanner *bufio.Scanner
+	line    int
+}
+
+func newJSONLTaskDecoder(r io.Reader) *jsonlTaskDecoder {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxImportLine)
+	return &jsonlTaskDecoder{scanner: scanner}
+}
+
+func (d *jsonlTaskDecoder) Next() (TaskRecord, error) {
+	for d.scanner.Scan() {
+		d.line++
+		line := strings.TrimSpace(d.scanner.Text())
+		if line == "" {
+			continue
+		}
+		record := TaskRecord{Line: d.line}
+		if err := json.Unmarshal([]byte(line), &record.Task); err != nil {
+			record.Err = domain.NewValidationError("invalid JSON: " + err.Error())
+		}
+		return record, nil
+	}
+	if err := d.scanner.Err(); err != nil {
+		if errors.Is(err, bufio.ErrTooLong) {
+			return TaskRecord{}, malformedInput(d.line+1, fmt.Errorf("line longer than %d bytes", maxImportLine))
+		}
+		return TaskRecord{}, err
+	}
+	return TaskRecord{}, io.EOF
+}
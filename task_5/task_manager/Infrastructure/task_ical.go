@@ -0,0 +1,417 @@
+package infrastructure
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	domain "task_manager/Domain"
+)
+
+// icsStatusProperty carries the task's own status, which iCalendar's
+// STATUS can only approximate.
+const icsStatusProperty = "X-TASK-STATUS"
+
+// icsLineLength is the length in octets iCalendar lines are folded at.
+const icsLineLength = 75
+
+// Layouts of iCalendar DATE-TIME and DATE values.
+const (
+	icsUTCLayout   = "20060102T150405Z"
+	icsLocalLayout = "20060102T150405"
+	icsDateLayout  = "20060102"
+)
+
+// icsPriorities maps task priorities onto iCalendar's 1 (highest) to 9 (lowest).
+var icsPriorities = map[string]int{
+	domain.PriorityUrgent: 1,
+	domain.PriorityHigh:   3,
+	domain.PriorityMedium: 5,
+	domain.PriorityLow:    9,
+}
+
+// icsTaskEncoder writes tasks as the VTODO components of one VCALENDAR.
+type icsTaskEncoder struct {
+	w        io.Writer
+	workflow domain.Workflow
+	stamp    string
+	started  bool
+	err      error
+}
+
+func newICSTaskEncoder(w io.Writer, workflow domain.Workflow) *icsTaskEncoder {
+	return &icsTaskEncoder{w: w, workflow: workflow, stamp: time.Now().UTC().Format(icsUTCLayout)}
+}
+
+// writeLine folds and writes one content line, keeping the first error.
+func (e *icsTaskEncoder) writeLine(line string) {
+	if e.err != nil {
+		return
+	}
+	var b strings.Builder
+	for len(line) > icsLineLength {
+		// Fold on a character boundary, leaving room for the leading space
+		cut := icsLineLength
+		if b.Len() > 0 {
+			cut--
+		}
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		b.WriteString(line[:cut])
+		b.WriteString("\r\n ")
+		line = line[cut:]
+	}
+	b.WriteString(line)
+	b.WriteString("\r\n")
+	_, e.err = io.WriteString(e.w, b.String())
+}
+
+func (e *icsTaskEncoder) start() {
+	if e.started {
+		return
+	}
+	e.started = true
+	e.writeLine("BEGIN:VCALENDAR")
+	e.writeLine("VERSION:2.0")
+	e.writeLine("PRODID:-//task_manager//Task Manager API//EN")
+}
+
+func (e *icsTaskEncoder) Encode(t domain.Task) error {
+	e.start()
+	e.writeLine("BEGIN:VTODO")
+	e.writeLine("UID:" + icsEscape(t.ID))
+	e.writeLine("DTSTAMP:" + e.stamp)
+	e.writeLine("SUMMARY:" + icsEscape(t.Title))
+	if t.Description != "" {
+		e.writeLine("DESCRIPTION:" + icsEscape(t.Description))
+	}
+	if t.Recurrence != nil && t.Recurrence.Timezone != "" {
+		// Recurring tasks keep their local time, so their due date is too
+		loc, err := time.LoadLocation(t.Recurrence.Timezone)
+		if err != nil {
+			return fmt.Errorf("task %s: %w", t.ID, err)
+		}
+		e.writeLine("DUE;TZID=" + t.Recurrence.Timezone + ":" + t.DueDate.In(loc).Format(icsLocalLayout))
+	} else {
+		e.writeLine("DUE:" + t.DueDate.UTC().Format(icsUTCLayout))
+	}
+	e.writeLine("STATUS:" + e.icsStatus(t.Status))
+	e.writeLine(icsStatusProperty + ":" + icsEscape(t.Status))
+	if p, ok := icsPriorities[t.Priority]; ok {
+		e.writeLine("PRIORITY:" + strconv.Itoa(p))
+	}
+	if len(t.Tags) > 0 {
+		tags := make([]string, len(t.Tags))
+		for i, tag := range t.Tags {
+			tags[i] = icsEscape(tag)
+		}
+		e.writeLine("CATEGORIES:" + strings.Join(tags, ","))
+	}
+	if t.ParentID != "" {
+		e.writeLine("RELATED-TO:" + icsEscape(t.ParentID))
+	}
+	if t.Recurrence != nil {
+		e.writeLine("RRULE:" + t.Recurrence.Rule)
+	}
+	e.writeLine("END:VTODO")
+	return e.err
+}
+
+func (e *icsTaskEncoder) Close() error {
+	e.start()
+	e.writeLine("END:VCALENDAR")
+	return e.err
+}
+
+// icsStatus approximates a workflow status with an iCalendar one.
+func (e *icsTaskEncoder) icsStatus(status string) string {
+	switch status {
+	case e.workflow.Completed:
+		return "COMPLETED"
+	case e.workflow.Initial:
+		return "NEEDS-ACTION"
+	}
+	return "IN-PROCESS"
+}
+
+var icsEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+func icsEscape(s string) string {
+	return icsEscaper.Replace(s)
+}
+
+// icsSplit splits a TEXT list on its unescaped commas and unescapes each value.
+func icsSplit(value string) []string {
+	return icsText(value, true)
+}
+
+// icsUnescape unescapes a single TEXT value.
+func icsUnescape(value string) string {
+	return icsText(value, false)[0]
+}
+
+func icsText(value string, split bool) []string {
+	var values []string
+	var b strings.Builder
+	for i := 0; i < len(value); i++ {
+		switch c := value[i]; {
+		case c == '\\' && i+1 < len(value):
+			i++
+			switch value[i] {
+			case 'n', 'N':
+				b.WriteByte('\n')
+			default:
+				b.WriteByte(value[i])
+			}
+		case c == ',' && split:
+			values = append(values, b.String())
+			b.Reset()
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return append(values, b.String())
+}
+
+// icsProperty is one unfolded content line.
+type icsProperty struct {
+	name   string
+	params map[string]string
+	value  string
+}
+
+// parseICSLine splits a content line into its name, parameters and value.
+func parseICSLine(line string) (icsProperty, error) {
+	// The value starts at the first colon outside a quoted parameter value
+	quoted := false
+	colon := -1
+	for i := 0; i < len(line) && colon < 0; i++ {
+		switch line[i] {
+		case '"':
+			quoted = !quoted
+		case ':':
+			if !quoted {
+				colon = i
+			}
+		}
+	}
+	if colon < 0 {
+		return icsProperty{}, fmt.Errorf("invalid content line %q", line)
+	}
+	parts := strings.Split(line[:colon], ";")
+	p := icsProperty{name: strings.ToUpper(parts[0]), params: make(map[string]string), value: line[colon+1:]}
+	for _, param := range parts[1:] {
+		name, value, _ := strings.Cut(param, "=")
+		p.params[strings.ToUpper(name)] = strings.Trim(value, `"`)
+	}
+	return p, nil
+}
+
+// icsTaskDecoder reads the VTODO components of an iCalendar file.
+// Other components are skipped.
+type icsTaskDecoder struct {
+	scanner  *bufio.Scanner
+	workflow domain.Workflow
+	line     int // physical lines read
+	// pending is the next line, read ahead to unfold the current one
+	pending    string
+	hasPending bool
+	started    bool // BEGIN:VCALENDAR was read
+}
+
+func newICSTaskDecoder(r io.Reader, workflow domain.Workflow) *icsTaskDecoder {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxImportLine)
+	return &icsTaskDecoder{scanner: scanner, workflow: workflow}
+}
+
+// readLine returns the next unfolded content line and the line it starts on.
+func (d *icsTaskDecoder) readLine() (string, int, error) {
+	if !d.hasPending {
+		if !d.scanner.Scan() {
+			return "", 0, d.scanErr()
+		}
+		d.line++
+		d.pending, d.hasPending = strings.TrimRight(d.scanner.Text(), "\r"), true
+	}
+	line, start := d.pending, d.line
+	d.hasPending = false
+	for d.scanner.Scan() {
+		d.line++
+		next := strings.TrimRight(d.scanner.Text(), "\r")
+		if next != "" && (next[0] == ' ' || next[0] == '\t') {
+			line += next[1:]
+			continue
+		}
+		d.pending, d.hasPending = next, true
+		break
+	}
+	if err := d.scanner.Err(); err != nil {
+		return "", 0, d.scanErr()
+	}
+	return line, start, nil
+}
+
+func (d *icsTaskDecoder) scanErr() error {
+	if err := d.scanner.Err(); err != nil {
+		if errors.Is(err, bufio.ErrTooLong) {
+			return malformedInput(d.line+1, fmt.Errorf("line longer than %d bytes", maxImportLine))
+		}
+		return err
+	}
+	return io.EOF
+}
+
+func (d *icsTaskDecoder) Next() (TaskRecord, error) {
+	for {
+		line, start, err := d.readLine()
+		if err != nil {
+			return TaskRecord{}, err
+		}
+		if line == "" {
+			continue
+		}
+		p, err := parseICSLine(line)
+		if err != nil {
+			return TaskRecord{}, malformedInput(start, err)
+		}
+		if !d.started {
+			if p.name != "BEGIN" || !strings.EqualFold(p.value, "VCALENDAR") {
+				return TaskRecord{}, malformedInput(start, errors.New("expected BEGIN:VCALENDAR"))
+			}
+			d.started = true
+			continue
+		}
+		if p.name == "BEGIN" && strings.EqualFold(p.value, "VTODO") {
+			return d.readTodo(start)
+		}
+	}
+}
+
+// readTodo reads the properties of a VTODO up to its END line.
+func (d *icsTaskDecoder) readTodo(start int) (TaskRecord, error) {
+	record := TaskRecord{Line: start}
+	var status, ownStatus, rule, timezone string
+	nested := 0
+	for {
+		line, at, err := d.readLine()
+		if err == io.EOF {
+			return TaskRecord{}, malformedInput(start, errors.New("VTODO is not closed"))
+		}
+		if err != nil {
+			return TaskRecord{}, err
+		}
+		if line == "" {
+			continue
+		}
+		p, err := parseICSLine(line)
+		if err != nil {
+			return TaskRecord{}, malformedInput(at, err)
+		}
+		// Properties of nested components such as VALARM are skipped
+		switch {
+		case p.name == "BEGIN":
+			nested++
+			continue
+		case p.name == "END" && nested > 0:
+			nested--
+			continue
+		case p.name == "END":
+			if ownStatus != "" {
+				record.Task.Status = ownStatus
+			} else {
+				record.Task.Status = d.status(status)
+			}
+			if rule != "" {
+				record.Task.Recurrence = &domain.Recurrence{Rule: rule, Timezone: timezone}
+			}
+			return record, nil
+		case nested > 0 || record.Err != nil:
+			continue
+		}
+
+		switch p.name {
+		case "SUMMARY":
+			record.Task.Title = icsUnescape(p.value)
+		case "DESCRIPTION":
+			record.Task.Description = icsUnescape(p.value)
+		case "DUE":
+			record.Task.DueDate, err = parseICSTime(p)
+			if err != nil {
+				record.Err = invalidRecord("due_date", err.Error())
+			}
+			timezone = p.params["TZID"]
+		case "STATUS":
+			status = strings.ToUpper(p.value)
+		case icsStatusProperty:
+			ownStatus = icsUnescape(p.value)
+		case "PRIORITY":
+			record.Task.Priority, err = parseICSPriority(p.value)
+			if err != nil {
+				record.Err = invalidRecord("priority", err.Error())
+			}
+		case "CATEGORIES":
+			for _, tag := range icsSplit(p.value) {
+				if tag = strings.TrimSpace(tag); tag != "" {
+					record.Task.Tags = append(record.Task.Tags, tag)
+				}
+			}
+		case "RELATED-TO":
+			if reltype := p.params["RELTYPE"]; reltype == "" || strings.EqualFold(reltype, "PARENT") {
+				record.Task.ParentID = icsUnescape(p.value)
+			}
+		case "RRULE":
+			rule = p.value
+		}
+	}
+}
+
+// status maps an iCalendar status onto the workflow. Statuses other than
+// COMPLETED are left to the workflow's initial status.
+func (d *icsTaskDecoder) status(status string) string {
+	if status == "COMPLETED" {
+		return d.workflow.Completed
+	}
+	return ""
+}
+
+// parseICSTime reads a DATE-TIME in UTC, in the zone of its TZID or, when
+// floating, as UTC, or a DATE as midnight in that zone.
+func parseICSTime(p icsProperty) (time.Time, error) {
+	loc := time.UTC
+	if tzid := p.params["TZID"]; tzid != "" {
+		var err error
+		if loc, err = time.LoadLocation(tzid); err != nil {
+			return time.Time{}, fmt.Errorf("unknown time zone %q", tzid)
+		}
+	}
+	for _, layout := range []string{icsUTCLayout, icsLocalLayout, icsDateLayout} {
+		if t, err := time.ParseInLocation(layout, p.value, loc); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q", p.value)
+}
+
+func parseICSPriority(value string) (string, error) {
+	p, err := strconv.Atoi(strings.TrimSpace(value))
+	switch {
+	case err != nil || p < 0 || p > 9:
+		return "", fmt.Errorf("invalid priority %q", value)
+	case p == 0:
+		return "", nil
+	case p == 1:
+		return domain.PriorityUrgent, nil
+	case p <= 4:
+		return domain.PriorityHigh, nil
+	case p == 5:
+		return domain.PriorityMedium, nil
+	}
+	return domain.PriorityLow, nil
+}
@@ -0,0 +1,143 @@
+package infrastructure
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	domain "task_manager/Domain"
+	repositories "task_manager/Repositories"
+)
+
+// RefreshTokenTTL is the lifetime of a refresh token.
+const RefreshTokenTTL = 7 * 24 * time.Hour
+
+var ErrInvalidRefreshToken error = domain.NewUnauthorizedError("invalid refresh token")
+
+// TokenPair is the access and refresh token handed to a client.
+type TokenPair struct {
+	AccessToken  string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int64  `json:"expires_in"`
+}
+
+// TokenService issues, rotates and revokes access/refresh token pairs.
+type TokenService struct {
+	jwtService *JWTService
+	store      repositories.ITokenStore
+	userRepo   repositories.IUserRepository
+}
+
+// NewTokenService creates a new token service.
+func NewTokenService(jwtService *JWTService, store repositories.ITokenStore, userRepo repositories.IUserRepository) *TokenService {
+	return &TokenService{jwtService: jwtService, store: store, userRepo: userRepo}
+}
+
+// Issue starts a new refresh token family for the user and returns its first token pair.
+func (s *TokenService) Issue(ctx context.Context, user domain.User) (TokenPair, error) {
+	familyID, err := randomToken(16)
+	if err != nil {
+		return TokenPair{}, err
+	}
+	return s.issue(ctx, user, familyID)
+}
+
+// Refresh exchanges a refresh token for a new token pair in the same family.
+// Presenting a token that was already exchanged revokes the whole family.
+func (s *TokenService) Refresh(ctx context.Context, refreshToken string) (TokenPair, error) {
+	rt, err := s.store.UseRefreshToken(ctx, hashToken(refreshToken))
+	if err != nil {
+		if errors.Is(err, repositories.ErrRefreshTokenReused) {
+			if err := s.store.RevokeFamily(ctx, rt.FamilyID); err != nil {
+				return TokenPair{}, err
+			}
+			return TokenPair{}, ErrInvalidRefreshToken
+		}
+		if errors.Is(err, repositories.ErrRefreshTokenNotFound) || errors.Is(err, repositories.ErrRefreshTokenRevoked) {
+			return TokenPair{}, ErrInvalidRefreshToken
+		}
+		return TokenPair{}, err
+	}
+	if rt.IsExpired(time.Now()) {
+		return TokenPair{}, ErrInvalidRefreshToken
+	}
+
+	// Reload the user so role changes take effect on refresh
+	user, err := s.userRepo.GetByUsername(ctx, rt.Username)
+	if err != nil {
+		if errors.Is(err, repositories.ErrUserNotFound) {
+			return TokenPair{}, ErrInvalidRefreshToken
+		}
+		return TokenPair{}, err
+	}
+	if user.ID.Hex() != rt.UserID {
+		return TokenPair{}, ErrInvalidRefreshToken
+	}
+
+	return s.issue(ctx, user, rt.FamilyID)
+}
+
+// Logout revokes the access token identified by jti and, when given,
+// the refresh token family of userID's refresh token.
+func (s *TokenService) Logout(ctx context.Context, userID, jti, refreshToken string) error {
+	if refreshToken != "" {
+		rt, err := s.store.GetRefreshToken(ctx, hashToken(refreshToken))
+		if err != nil {
+			if errors.Is(err, repositories.ErrRefreshTokenNotFound) {
+				return ErrInvalidRefreshToken
+			}
+			return err
+		}
+		if rt.UserID != userID {
+			return ErrInvalidRefreshToken
+		}
+		if err := s.store.RevokeFamily(ctx, rt.FamilyID); err != nil {
+			return err
+		}
+	}
+	return s.store.RevokeAccessToken(ctx, jti, time.Now().Add(AccessTokenTTL))
+}
+
+// JWKS returns the public keys that verify issued access tokens.
+func (s *TokenService) JWKS() JWKS {
+	return s.jwtService.JWKS()
+}
+
+func (s *TokenService) issue(ctx context.Context, user domain.User, familyID string) (TokenPair, error) {
+	accessToken, err := s.jwtService.GenerateToken(user)
+	if err != nil {
+		return TokenPair{}, err
+	}
+
+	refreshToken, err := randomToken(32)
+	if err != nil {
+		return TokenPair{}, err
+	}
+
+	now := time.Now().UTC()
+	err = s.store.SaveRefreshToken(ctx, domain.RefreshToken{
+		Hash:      hashToken(refreshToken),
+		FamilyID:  familyID,
+		UserID:    user.ID.Hex(),
+		Username:  user.Username,
+		ExpiresAt: now.Add(RefreshTokenTTL),
+		CreatedAt: now,
+	})
+	if err != nil {
+		return TokenPair{}, err
+	}
+
+	return TokenPair{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int64(AccessTokenTTL / time.Second),
+	}, nil
+}
+
+// hashToken returns the SHA-256 digest under which a refresh token is stored.
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,190 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	domain "task_manager/Domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var (
+	ErrAttachmentNotFound error = domain.NewNotFoundError("attachment not found")
+	// ErrTooManyAttachments is returned when attaching a file to a task that has the most attachments allowed.
+	ErrTooManyAttachments error = domain.NewConflictError(fmt.Sprintf("a task can have at most %d attachments", domain.MaxAttachmentsPerTask))
+)
+
+// IAttachmentRepository defines the interface for the metadata of task
+// attachments. Their content is kept in an IBlobStore.
+type IAttachmentRepository interface {
+	// Create stores a new attachment, assigning its ID. It returns
+	// ErrTooManyAttachments instead when the task already has
+	// domain.MaxAttachmentsPerTask attachments, even under concurrent calls.
+	Create(ctx context.Context, a domain.Attachment) (domain.Attachment, error)
+	GetByID(ctx context.Context, id string) (domain.Attachment, error)
+	// ListByTask returns the attachments of a task, oldest first.
+	ListByTask(ctx context.Context, taskID string) ([]domain.Attachment, error)
+	Delete(ctx context.Context, id string) error
+	// TaskIDs returns the IDs of every task that has attachments.
+	TaskIDs(ctx context.Context) ([]string, error)
+	Close() error
+}
+
+// MongoAttachmentRepository implements IAttachmentRepository using MongoDB.
+type MongoAttachmentRepository struct {
+	collection *mongo.Collection
+	timeouts   Timeouts
+}
+
+func NewMongoAttachmentRepository(db *mongo.Database, collectionName string, timeouts Timeouts) (IAttachmentRepository, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), mongoConnectTimeout)
+	defer cancel()
+
+	collection := db.Collection(collectionName)
+	_, err := collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "task_id", Value: 1}, {Key: "created_at", Value: 1}}},
+		// Attachments stored before slots existed have none
+		{
+			Keys: bson.D{{Key: "task_id", Value: 1}, {Key: "slot", Value: 1}},
+			Options: options.Index().SetUnique(true).
+				SetPartialFilterExpression(bson.M{"slot": bson.M{"$exists": true}}),
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create attachment indexes: %w", err)
+	}
+	return &MongoAttachmentRepository{collection: collection, timeouts: timeouts}, nil
+}
+
+// Close is a no-op; the shared client is disconnected by its owner.
+func (r *MongoAttachmentRepository) Close() error {
+	return nil
+}
+
+// mongoAttachment is an attachment as stored in MongoDB. Every attachment
+// of a task takes one of its domain.MaxAttachmentsPerTask slots, and a
+// unique index keeps two attachments out of the same slot.
+type mongoAttachment struct {
+	domain.Attachment `bson:",inline"`
+	Slot              int `bson:"slot"`
+}
+
+func (r *MongoAttachmentRepository) Create(ctx context.Context, a domain.Attachment) (domain.Attachment, error) {
+	ctx, cancel := r.timeouts.withTimeout(ctx, "attachment.create")
+	defer cancel()
+
+	a.ID = primitive.NewObjectID().Hex()
+	for {
+		slot, err := r.freeSlot(ctx, a.TaskID)
+		if err != nil {
+			return domain.Attachment{}, err
+		}
+		_, err = r.collection.InsertOne(ctx, mongoAttachment{Attachment: a, Slot: slot})
+		if err == nil {
+			return a, nil
+		}
+		if !mongo.IsDuplicateKeyError(err) {
+			return domain.Attachment{}, fmt.Errorf("failed to insert attachment: %w", err)
+		}
+		// A concurrent upload took the slot first; look for another
+	}
+}
+
+// freeSlot returns the lowest slot no attachment of the task takes, or
+// ErrTooManyAttachments when they take them all. Attachments without a
+// slot use up the highest ones.
+func (r *MongoAttachmentRepository) freeSlot(ctx context.Context, taskID string) (int, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{"task_id": taskID}, options.Find().SetProjection(bson.M{"slot": 1}))
+	if err != nil {
+		return 0, fmt.Errorf("failed to find attachments: %w", err)
+	}
+	var taken []struct {
+		Slot *int `bson:"slot"`
+	}
+	if err := cursor.All(ctx, &taken); err != nil {
+		return 0, fmt.Errorf("failed to decode attachments: %w", err)
+	}
+
+	slots := domain.MaxAttachmentsPerTask
+	used := make(map[int]bool, len(taken))
+	for _, t := range taken {
+		if t.Slot == nil {
+			slots--
+		} else {
+			used[*t.Slot] = true
+		}
+	}
+	for slot := 0; slot < slots; slot++ {
+		if !used[slot] {
+			return slot, nil
+		}
+	}
+	return 0, ErrTooManyAttachments
+}
+
+func (r *MongoAttachmentRepository) GetByID(ctx context.Context, id string) (domain.Attachment, error) {
+	ctx, cancel := r.timeouts.withTimeout(ctx, "attachment.get")
+	defer cancel()
+
+	var a domain.Attachment
+	if err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&a); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return domain.Attachment{}, ErrAttachmentNotFound
+		}
+		return domain.Attachment{}, fmt.Errorf("failed to find attachment: %w", err)
+	}
+	return a, nil
+}
+
+func (r *MongoAttachmentRepository) ListByTask(ctx context.Context, taskID string) ([]domain.Attachment, error) {
+	ctx, cancel := r.timeouts.withTimeout(ctx, "attachment.list")
+	defer cancel()
+
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}})
+	cursor, err := r.collection.Find(ctx, bson.M{"task_id": taskID}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find attachments: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	attachments := []domain.Attachment{}
+	if err := cursor.All(ctx, &attachments); err != nil {
+		return nil, fmt.Errorf("failed to decode attachments: %w", err)
+	}
+	return attachments, nil
+}
+
+func (r *MongoAttachmentRepository) Delete(ctx context.Context, id string) error {
+	ctx, cancel := r.timeouts.withTimeout(ctx, "attachment.delete")
+	defer cancel()
+
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return fmt.Errorf("failed to delete attachment: %w", err)
+	}
+	if result.DeletedCount == 0 {
+		return ErrAttachmentNotFound
+	}
+	return nil
+}
+
+func (r *MongoAttachmentRepository) TaskIDs(ctx context.Context) ([]string, error) {
+	ctx, cancel := r.timeouts.withTimeout(ctx, "attachment.task_ids")
+	defer cancel()
+
+	values, err := r.collection.Distinct(ctx, "task_id", bson.M{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list attachment tasks: %w", err)
+	}
+	ids := make([]string, 0, len(values))
+	for _, v := range values {
+		if id, ok := v.(string); ok {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
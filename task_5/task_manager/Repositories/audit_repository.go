@@ -0,0 +1,118 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	domain "task_manager/Domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// IAuditRepository defines the interface for the append-only audit log.
+type IAuditRepository interface {
+	// Append stores a new entry, assigning its ID.
+	Append(ctx context.Context, entry domain.AuditEntry) error
+	// List returns a page of the entries matching the query, newest first.
+	List(ctx context.Context, q domain.AuditQuery) (domain.AuditPage, error)
+	Close() error
+}
+
+// MongoAuditRepository implements IAuditRepository using MongoDB.
+type MongoAuditRepository struct {
+	collection *mongo.Collection
+	timeouts   Timeouts
+}
+
+func NewMongoAuditRepository(db *mongo.Database, collectionName string, timeouts Timeouts) (IAuditRepository, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), mongoConnectTimeout)
+	defer cancel()
+
+	collection := db.Collection(collectionName)
+
+	// Indexes backing the history of one target and the filtered listings
+	_, err := collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "target_type", Value: 1}, {Key: "target_id", Value: 1}, {Key: "created_at", Value: -1}}},
+		{Keys: bson.D{{Key: "actor_id", Value: 1}, {Key: "created_at", Value: -1}}},
+		{Keys: bson.D{{Key: "created_at", Value: -1}}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create audit indexes: %w", err)
+	}
+
+	return &MongoAuditRepository{collection: collection, timeouts: timeouts}, nil
+}
+
+// Close is a no-op; the shared client is disconnected by its owner.
+func (r *MongoAuditRepository) Close() error {
+	return nil
+}
+
+func (r *MongoAuditRepository) Append(ctx context.Context, entry domain.AuditEntry) error {
+	ctx, cancel := r.timeouts.withTimeout(ctx, "audit.append")
+	defer cancel()
+
+	entry.ID = primitive.NewObjectID().Hex()
+	if _, err := r.collection.InsertOne(ctx, entry); err != nil {
+		return fmt.Errorf("failed to append audit entry: %w", err)
+	}
+	return nil
+}
+
+func (r *MongoAuditRepository) List(ctx context.Context, q domain.AuditQuery) (domain.AuditPage, error) {
+	ctx, cancel := r.timeouts.withTimeout(ctx, "audit.list")
+	defer cancel()
+
+	filter := auditQueryFilter(q)
+	total, err := r.collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return domain.AuditPage{}, fmt.Errorf("failed to count audit entries: %w", err)
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}).
+		SetSkip(q.Skip()).
+		SetLimit(int64(q.PageSize))
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return domain.AuditPage{}, fmt.Errorf("failed to find audit entries: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var entries []domain.AuditEntry
+	if err := cursor.All(ctx, &entries); err != nil {
+		return domain.AuditPage{}, fmt.Errorf("failed to decode audit entries: %w", err)
+	}
+	return domain.NewAuditPage(q, entries, total), nil
+}
+
+// auditQueryFilter translates an audit query into a MongoDB filter.
+func auditQueryFilter(q domain.AuditQuery) bson.M {
+	filter := bson.M{}
+	if q.ActorID != "" {
+		filter["actor_id"] = q.ActorID
+	}
+	if q.Action != "" {
+		filter["action"] = q.Action
+	}
+	if q.TargetType != "" {
+		filter["target_type"] = q.TargetType
+	}
+	if q.TargetID != "" {
+		filter["target_id"] = q.TargetID
+	}
+	created := bson.M{}
+	if !q.Since.IsZero() {
+		created["$gte"] = q.Since
+	}
+	if !q.Until.IsZero() {
+		created["$lte"] = q.Until
+	}
+	if len(created) > 0 {
+		filter["created_at"] = created
+	}
+	return filter
+}
@@ -0,0 +1,121 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/gridfs"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ErrBlobNotFound is returned for keys that hold no content.
+var ErrBlobNotFound = errors.New("blob not found")
+
+// IBlobStore defines the interface for storing file content, such as the
+// content of attachments, under caller chosen keys. Keys consist of
+// letters, digits, '-' and '_'.
+//
+// Transfers are bounded by the caller's context only, not by the
+// operation timeouts of the repositories.
+type IBlobStore interface {
+	// Put stores the content read from r under a new key. Nothing is kept
+	// if reading or storing fails.
+	Put(ctx context.Context, key string, r io.Reader) error
+	// Open returns a reader of the content; the caller must close it.
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+	Close() error
+}
+
+// validBlobKey reports whether the key only uses the characters allowed in keys.
+func validBlobKey(key string) bool {
+	if key == "" {
+		return false
+	}
+	for _, c := range key {
+		if !('a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' || c == '-' || c == '_') {
+			return false
+		}
+	}
+	return true
+}
+
+// contextReader stops reading once its context is done.
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (cr contextReader) Read(p []byte) (int, error) {
+	if err := cr.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return cr.r.Read(p)
+}
+
+// GridFSBlobStore implements IBlobStore using a MongoDB GridFS bucket.
+type GridFSBlobStore struct {
+	bucket *gridfs.Bucket
+}
+
+func NewGridFSBlobStore(db *mongo.Database, bucketName string) (IBlobStore, error) {
+	bucket, err := gridfs.NewBucket(db, options.GridFSBucket().SetName(bucketName))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open GridFS bucket: %w", err)
+	}
+	return &GridFSBlobStore{bucket: bucket}, nil
+}
+
+// Close is a no-op; the shared client is disconnected by its owner.
+func (s *GridFSBlobStore) Close() error {
+	return nil
+}
+
+func (s *GridFSBlobStore) Put(ctx context.Context, key string, r io.Reader) error {
+	if !validBlobKey(key) {
+		return fmt.Errorf("invalid blob key %q", key)
+	}
+	upload, err := s.bucket.OpenUploadStreamWithID(key, key)
+	if err != nil {
+		return fmt.Errorf("failed to open blob upload: %w", err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		upload.SetWriteDeadline(deadline)
+	}
+	if _, err := io.Copy(upload, contextReader{ctx, r}); err != nil {
+		upload.Abort()
+		return fmt.Errorf("failed to upload blob: %w", err)
+	}
+	if err := upload.Close(); err != nil {
+		upload.Abort()
+		return fmt.Errorf("failed to upload blob: %w", err)
+	}
+	return nil
+}
+
+func (s *GridFSBlobStore) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	download, err := s.bucket.OpenDownloadStream(key)
+	if err != nil {
+		if errors.Is(err, gridfs.ErrFileNotFound) {
+			return nil, ErrBlobNotFound
+		}
+		return nil, fmt.Errorf("failed to open blob: %w", err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		download.SetReadDeadline(deadline)
+	}
+	return download, nil
+}
+
+func (s *GridFSBlobStore) Delete(ctx context.Context, key string) error {
+	if err := s.bucket.DeleteContext(ctx, key); err != nil {
+		if errors.Is(err, gridfs.ErrFileNotFound) {
+			return ErrBlobNotFound
+		}
+		return fmt.Errorf("failed to delete blob: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	domain "task_manager/Domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var ErrCommentNotFound error = domain.NewNotFoundError("comment not found")
+
+// ICommentRepository defines the interface for the comments on tasks.
+type ICommentRepository interface {
+	// Create stores a new comment, assigning its ID.
+	Create(ctx context.Context, c domain.Comment) (domain.Comment, error)
+	GetByID(ctx context.Context, id string) (domain.Comment, error)
+	// Update replaces the body, mentions and edit time of a stored comment.
+	Update(ctx context.Context, c domain.Comment) error
+	Delete(ctx context.Context, id string) error
+	// List returns a page of a task's comments, oldest first.
+	List(ctx context.Context, q domain.CommentQuery) (domain.CommentPage, error)
+	Close() error
+}
+
+// MongoCommentRepository implements ICommentRepository using MongoDB.
+type MongoCommentRepository struct {
+	collection *mongo.Collection
+	timeouts   Timeouts
+}
+
+func NewMongoCommentRepository(db *mongo.Database, collectionName string, timeouts Timeouts) (ICommentRepository, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), mongoConnectTimeout)
+	defer cancel()
+
+	collection := db.Collection(collectionName)
+	_, err := collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "task_id", Value: 1}, {Key: "created_at", Value: 1}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create comment indexes: %w", err)
+	}
+	return &MongoCommentRepository{collection: collection, timeouts: timeouts}, nil
+}
+
+// Close is a no-op; the shared client is disconnected by its owner.
+func (r *MongoCommentRepository) Close() error {
+	return nil
+}
+
+func (r *MongoCommentRepository) Create(ctx context.Context, c domain.Comment) (domain.Comment, error) {
+	ctx, cancel := r.timeouts.withTimeout(ctx, "comment.create")
+	defer cancel()
+
+	c.ID = primitive.NewObjectID().Hex()
+	if _, err := r.collection.InsertOne(ctx, c); err != nil {
+		return domain.Comment{}, fmt.Errorf("failed to insert comment: %w", err)
+	}
+	return c, nil
+}
+
+func (r *MongoCommentRepository) GetByID(ctx context.Context, id string) (domain.Comment, error) {
+	ctx, cancel := r.timeouts.withTimeout(ctx, "comment.get")
+	defer cancel()
+
+	var c domain.Comment
+	if err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&c); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return domain.Comment{}, ErrCommentNotFound
+		}
+		return domain.Comment{}, fmt.Errorf("failed to find comment: %w", err)
+	}
+	return c, nil
+}
+
+func (r *MongoCommentRepository) Update(ctx context.Context, c domain.Comment) error {
+	ctx, cancel := r.timeouts.withTimeout(ctx, "comment.update")
+	defer cancel()
+
+	update := bson.M{"$set": bson.M{"body": c.Body, "mentions": c.Mentions, "edited_at": c.EditedAt}}
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": c.ID}, update)
+	if err != nil {
+		return fmt.Errorf("failed to update comment: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return ErrCommentNotFound
+	}
+	return nil
+}
+
+func (r *MongoCommentRepository) Delete(ctx context.Context, id string) error {
+	ctx, cancel := r.timeouts.withTimeout(ctx, "comment.delete")
+	defer cancel()
+
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return fmt.Errorf("failed to delete comment: %w", err)
+	}
+	if result.DeletedCount == 0 {
+		return ErrCommentNotFound
+	}
+	return nil
+}
+
+func (r *MongoCommentRepository) List(ctx context.Context, q domain.CommentQuery) (domain.CommentPage, error) {
+	ctx, cancel := r.timeouts.withTimeout(ctx, "comment.list")
+	defer cancel()
+
+	filter := bson.M{"task_id": q.TaskID}
+	total, err := r.collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return domain.CommentPage{}, fmt.Errorf("failed to count comments: %w", err)
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}}).
+		SetSkip(q.Skip()).
+		SetLimit(int64(q.PageSize))
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return domain.CommentPage{}, fmt.Errorf("failed to find comments: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var comments []domain.Comment
+	if err := cursor.All(ctx, &comments); err != nil {
+		return domain.CommentPage{}, fmt.Errorf("failed to decode comments: %w", err)
+	}
+	return domain.NewCommentPage(q, comments, total), nil
+}
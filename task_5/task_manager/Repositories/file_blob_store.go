@@ -0,0 +1,78 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// FileBlobStore implements IBlobStore with one file per key in a directory.
+type FileBlobStore struct {
+	dir string
+}
+
+// NewFileBlobStore stores blobs in dir, creating it if needed.
+func NewFileBlobStore(dir string) (IBlobStore, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create blob directory: %w", err)
+	}
+	return &FileBlobStore{dir: dir}, nil
+}
+
+func (s *FileBlobStore) Close() error {
+	return nil
+}
+
+func (s *FileBlobStore) Put(ctx context.Context, key string, r io.Reader) error {
+	if !validBlobKey(key) {
+		return fmt.Errorf("invalid blob key %q", key)
+	}
+	// Write to a temporary file first so that readers never see partial content
+	tmp, err := os.CreateTemp(s.dir, ".upload-*")
+	if err != nil {
+		return fmt.Errorf("failed to create blob: %w", err)
+	}
+	_, err = io.Copy(tmp, contextReader{ctx, r})
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), filepath.Join(s.dir, key))
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write blob: %w", err)
+	}
+	return nil
+}
+
+func (s *FileBlobStore) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	if !validBlobKey(key) {
+		return nil, ErrBlobNotFound
+	}
+	f, err := os.Open(filepath.Join(s.dir, key))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, ErrBlobNotFound
+		}
+		return nil, fmt.Errorf("failed to open blob: %w", err)
+	}
+	return f, nil
+}
+
+func (s *FileBlobStore) Delete(ctx context.Context, key string) error {
+	if !validBlobKey(key) {
+		return ErrBlobNotFound
+	}
+	if err := os.Remove(filepath.Join(s.dir, key)); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return ErrBlobNotFound
+		}
+		return fmt.Errorf("failed to delete blob: %w", err)
+	}
+	return nil
+}
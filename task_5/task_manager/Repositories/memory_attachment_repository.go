@@ -0,0 +1,97 @@
+package repositories
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	domain "task_manager/Domain"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MemoryAttachmentRepository implements IAttachmentRepository in memory.
+// It is safe for concurrent use and loses its state on restart.
+type MemoryAttachmentRepository struct {
+	mu          sync.RWMutex
+	attachments map[string]domain.Attachment
+}
+
+func NewMemoryAttachmentRepository() IAttachmentRepository {
+	return &MemoryAttachmentRepository{attachments: make(map[string]domain.Attachment)}
+}
+
+func (r *MemoryAttachmentRepository) Close() error {
+	return nil
+}
+
+func (r *MemoryAttachmentRepository) Create(ctx context.Context, a domain.Attachment) (domain.Attachment, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	count := 0
+	for _, existing := range r.attachments {
+		if existing.TaskID == a.TaskID {
+			count++
+		}
+	}
+	if count >= domain.MaxAttachmentsPerTask {
+		return domain.Attachment{}, ErrTooManyAttachments
+	}
+	a.ID = primitive.NewObjectID().Hex()
+	r.attachments[a.ID] = a
+	return a, nil
+}
+
+func (r *MemoryAttachmentRepository) GetByID(ctx context.Context, id string) (domain.Attachment, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	a, ok := r.attachments[id]
+	if !ok {
+		return domain.Attachment{}, ErrAttachmentNotFound
+	}
+	return a, nil
+}
+
+func (r *MemoryAttachmentRepository) ListByTask(ctx context.Context, taskID string) ([]domain.Attachment, error) {
+	r.mu.RLock()
+	attachments := []domain.Attachment{}
+	for _, a := range r.attachments {
+		if a.TaskID == taskID {
+			attachments = append(attachments, a)
+		}
+	}
+	r.mu.RUnlock()
+	// Oldest first; IDs break ties in creation order
+	sort.Slice(attachments, func(i, j int) bool {
+		if c := attachments[i].CreatedAt.Compare(attachments[j].CreatedAt); c != 0 {
+			return c < 0
+		}
+		return attachments[i].ID < attachments[j].ID
+	})
+	return attachments, nil
+}
+
+func (r *MemoryAttachmentRepository) Delete(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.attachments[id]; !ok {
+		return ErrAttachmentNotFound
+	}
+	delete(r.attachments, id)
+	return nil
+}
+
+func (r *MemoryAttachmentRepository) TaskIDs(ctx context.Context) ([]string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	seen := make(map[string]bool)
+	ids := []string{}
+	for _, a := range r.attachments {
+		if !seen[a.TaskID] {
+			seen[a.TaskID] = true
+			ids = append(ids, a.TaskID)
+		}
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
@@ -0,0 +1,52 @@
+package repositories
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	domain "task_manager/Domain"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MemoryAuditRepository implements IAuditRepository in memory.
+// It is safe for concurrent use and loses its state on restart.
+type MemoryAuditRepository struct {
+	mu      sync.RWMutex
+	entries []domain.AuditEntry // oldest first
+}
+
+func NewMemoryAuditRepository() IAuditRepository {
+	return &MemoryAuditRepository{}
+}
+
+func (r *MemoryAuditRepository) Close() error {
+	return nil
+}
+
+func (r *MemoryAuditRepository) Append(ctx context.Context, entry domain.AuditEntry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	entry.ID = primitive.NewObjectID().Hex()
+	entry.Changes = slices.Clone(entry.Changes)
+	r.entries = append(r.entries, entry)
+	return nil
+}
+
+func (r *MemoryAuditRepository) List(ctx context.Context, q domain.AuditQuery) (domain.AuditPage, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var matched []domain.AuditEntry
+	for i := len(r.entries) - 1; i >= 0; i-- {
+		if q.Matches(r.entries[i]) {
+			matched = append(matched, r.entries[i])
+		}
+	}
+
+	total := int64(len(matched))
+	start := min(q.Skip(), total)
+	end := min(start+int64(q.PageSize), total)
+	return domain.NewAuditPage(q, slices.Clone(matched[start:end]), total), nil
+}
@@ -0,0 +1,59 @@
+package repositories
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// MemoryBlobStore implements IBlobStore in memory.
+// It is safe for concurrent use and loses its state on restart.
+type MemoryBlobStore struct {
+	mu    sync.RWMutex
+	blobs map[string][]byte
+}
+
+func NewMemoryBlobStore() IBlobStore {
+	return &MemoryBlobStore{blobs: make(map[string][]byte)}
+}
+
+func (s *MemoryBlobStore) Close() error {
+	return nil
+}
+
+func (s *MemoryBlobStore) Put(ctx context.Context, key string, r io.Reader) error {
+	if !validBlobKey(key) {
+		return fmt.Errorf("invalid blob key %q", key)
+	}
+	content, err := io.ReadAll(contextReader{ctx, r})
+	if err != nil {
+		return fmt.Errorf("failed to read blob: %w", err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.blobs[key] = content
+	return nil
+}
+
+func (s *MemoryBlobStore) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	content, ok := s.blobs[key]
+	if !ok {
+		return nil, ErrBlobNotFound
+	}
+	// Stored content is never modified, so readers can share it
+	return io.NopCloser(bytes.NewReader(content)), nil
+}
+
+func (s *MemoryBlobStore) Delete(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.blobs[key]; !ok {
+		return ErrBlobNotFound
+	}
+	delete(s.blobs, key)
+	return nil
+}
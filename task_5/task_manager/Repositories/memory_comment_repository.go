@@ -0,0 +1,100 @@
+package repositories
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"sync"
+
+	domain "task_manager/Domain"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MemoryCommentRepository implements ICommentRepository in memory.
+// It is safe for concurrent use and loses its state on restart.
+type MemoryCommentRepository struct {
+	mu       sync.RWMutex
+	comments map[string]domain.Comment
+}
+
+func NewMemoryCommentRepository() ICommentRepository {
+	return &MemoryCommentRepository{comments: make(map[string]domain.Comment)}
+}
+
+func (r *MemoryCommentRepository) Close() error {
+	return nil
+}
+
+func (r *MemoryCommentRepository) Create(ctx context.Context, c domain.Comment) (domain.Comment, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	c.ID = primitive.NewObjectID().Hex()
+	r.comments[c.ID] = cloneComment(c)
+	return c, nil
+}
+
+func (r *MemoryCommentRepository) GetByID(ctx context.Context, id string) (domain.Comment, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	c, ok := r.comments[id]
+	if !ok {
+		return domain.Comment{}, ErrCommentNotFound
+	}
+	return cloneComment(c), nil
+}
+
+func (r *MemoryCommentRepository) Update(ctx context.Context, c domain.Comment) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored, ok := r.comments[c.ID]
+	if !ok {
+		return ErrCommentNotFound
+	}
+	stored.Body, stored.Mentions, stored.EditedAt = c.Body, c.Mentions, c.EditedAt
+	r.comments[c.ID] = cloneComment(stored)
+	return nil
+}
+
+func (r *MemoryCommentRepository) Delete(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.comments[id]; !ok {
+		return ErrCommentNotFound
+	}
+	delete(r.comments, id)
+	return nil
+}
+
+func (r *MemoryCommentRepository) List(ctx context.Context, q domain.CommentQuery) (domain.CommentPage, error) {
+	r.mu.RLock()
+	matched := []domain.Comment{}
+	for _, c := range r.comments {
+		if c.TaskID == q.TaskID {
+			matched = append(matched, cloneComment(c))
+		}
+	}
+	r.mu.RUnlock()
+	// Oldest first; IDs break ties in creation order
+	sort.Slice(matched, func(i, j int) bool {
+		if c := matched[i].CreatedAt.Compare(matched[j].CreatedAt); c != 0 {
+			return c < 0
+		}
+		return matched[i].ID < matched[j].ID
+	})
+
+	total := int64(len(matched))
+	start := min(q.Skip(), total)
+	end := min(start+int64(q.PageSize), total)
+	return domain.NewCommentPage(q, matched[start:end], total), nil
+}
+
+// cloneComment copies the comment's slices and pointers so stored comments never alias caller memory.
+func cloneComment(c domain.Comment) domain.Comment {
+	c.Mentions = slices.Clone(c.Mentions)
+	if c.EditedAt != nil {
+		editedAt := *c.EditedAt
+		c.EditedAt = &editedAt
+	}
+	return c
+}
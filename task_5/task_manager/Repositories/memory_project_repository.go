@@ -0,0 +1,86 @@
+package repositories
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"sync"
+
+	domain "task_manager/Domain"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MemoryProjectRepository implements IProjectRepository in memory.
+// It is safe for concurrent use and loses its state on restart.
+type MemoryProjectRepository struct {
+	mu       sync.RWMutex
+	projects map[string]domain.Project
+}
+
+func NewMemoryProjectRepository() IProjectRepository {
+	return &MemoryProjectRepository{projects: make(map[string]domain.Project)}
+}
+
+func (r *MemoryProjectRepository) Close() error {
+	return nil
+}
+
+func (r *MemoryProjectRepository) Create(ctx context.Context, p domain.Project) (domain.Project, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	p.ID = primitive.NewObjectID().Hex()
+	r.projects[p.ID] = cloneProject(p)
+	return p, nil
+}
+
+func (r *MemoryProjectRepository) GetByID(ctx context.Context, id string) (domain.Project, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	p, ok := r.projects[id]
+	if !ok {
+		return domain.Project{}, ErrProjectNotFound
+	}
+	return cloneProject(p), nil
+}
+
+func (r *MemoryProjectRepository) List(ctx context.Context, userID string) ([]domain.Project, error) {
+	r.mu.RLock()
+	projects := []domain.Project{}
+	for _, p := range r.projects {
+		if _, member := p.MemberRole(userID); userID == "" || member {
+			projects = append(projects, cloneProject(p))
+		}
+	}
+	r.mu.RUnlock()
+	// Oldest first; IDs break ties in creation order
+	sort.Slice(projects, func(i, j int) bool {
+		if c := projects[i].CreatedAt.Compare(projects[j].CreatedAt); c != 0 {
+			return c < 0
+		}
+		return projects[i].ID < projects[j].ID
+	})
+	return projects, nil
+}
+
+func (r *MemoryProjectRepository) Update(ctx context.Context, p domain.Project) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored, ok := r.projects[p.ID]
+	if !ok {
+		return ErrProjectNotFound
+	}
+	stored.Name, stored.Description, stored.Members, stored.ArchivedAt = p.Name, p.Description, p.Members, p.ArchivedAt
+	r.projects[p.ID] = cloneProject(stored)
+	return nil
+}
+
+// cloneProject copies the project's slices and pointers so stored projects never alias caller memory.
+func cloneProject(p domain.Project) domain.Project {
+	p.Members = slices.Clone(p.Members)
+	if p.ArchivedAt != nil {
+		archivedAt := *p.ArchivedAt
+		p.ArchivedAt = &archivedAt
+	}
+	return p
+}
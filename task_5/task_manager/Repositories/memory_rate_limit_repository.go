@@ -0,0 +1,97 @@
+package repositories
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	domain "task_manager/Domain"
+)
+
+// memoryBucket is a token bucket and when it is full again.
+type memoryBucket struct {
+	bucket    domain.TokenBucket
+	expiresAt time.Time
+}
+
+// memoryLockout is a failed login record and when it is forgotten.
+type memoryLockout struct {
+	lockout   domain.Lockout
+	expiresAt time.Time
+}
+
+// MemoryRateLimitStore implements IRateLimitStore in memory.
+// It is safe for concurrent use and loses its state on restart.
+type MemoryRateLimitStore struct {
+	mu        sync.Mutex
+	buckets   map[string]memoryBucket
+	failures  map[string]memoryLockout
+	lastSweep time.Time
+}
+
+func NewMemoryRateLimitStore() IRateLimitStore {
+	return &MemoryRateLimitStore{
+		buckets:  make(map[string]memoryBucket),
+		failures: make(map[string]memoryLockout),
+	}
+}
+
+func (s *MemoryRateLimitStore) Close() error {
+	return nil
+}
+
+func (s *MemoryRateLimitStore) Take(ctx context.Context, key string, limit domain.RateLimit, now time.Time) (time.Duration, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sweep(now)
+
+	b := s.buckets[key]
+	wait := limit.Take(&b.bucket, now)
+	b.expiresAt = now.Add(limit.Period)
+	s.buckets[key] = b
+	return wait, nil
+}
+
+func (s *MemoryRateLimitStore) Attempt(ctx context.Context, key string, policy domain.LockoutPolicy, now time.Time) (time.Duration, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sweep(now)
+
+	f, ok := s.failures[key]
+	if !ok || !f.expiresAt.After(now) {
+		f = memoryLockout{}
+	}
+	wait, expiresAt := policy.Attempt(&f.lockout, now)
+	if wait > 0 {
+		return wait, nil
+	}
+	f.expiresAt = expiresAt
+	s.failures[key] = f
+	return 0, nil
+}
+
+func (s *MemoryRateLimitStore) ResetFailures(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.failures, key)
+	return nil
+}
+
+// sweep drops expired buckets and failures, at most once a minute, so
+// that keys that are never used again do not pile up.
+func (s *MemoryRateLimitStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < time.Minute {
+		return
+	}
+	s.lastSweep = now
+	for key, b := range s.buckets {
+		if !b.expiresAt.After(now) {
+			delete(s.buckets, key)
+		}
+	}
+	for key, f := range s.failures {
+		if !f.expiresAt.After(now) {
+			delete(s.failures, key)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package repositories
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	domain "task_manager/Domain"
+)
+
+// MemoryReminderRepository implements IReminderRepository in memory.
+// It is safe for concurrent use and loses its state on restart.
+type MemoryReminderRepository struct {
+	mu        sync.Mutex
+	reminders map[string]domain.Reminder
+}
+
+func NewMemoryReminderRepository() IReminderRepository {
+	return &MemoryReminderRepository{reminders: make(map[string]domain.Reminder)}
+}
+
+func (r *MemoryReminderRepository) Close() error {
+	return nil
+}
+
+func (r *MemoryReminderRepository) Claim(ctx context.Context, reminder domain.Reminder) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.reminders[reminder.ID]; ok {
+		return false, nil
+	}
+	r.reminders[reminder.ID] = reminder
+	return true, nil
+}
+
+func (r *MemoryReminderRepository) Release(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.reminders, id)
+	return nil
+}
+
+func (r *MemoryReminderRepository) DeleteDueBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var deleted int64
+	for id, reminder := range r.reminders {
+		if reminder.DueDate.Before(cutoff) {
+			delete(r.reminders, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
@@ -0,0 +1,63 @@
+package repositories
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	domain "task_manager/Domain"
+)
+
+// MemoryRoleRepository implements IRoleRepository in memory.
+// It is safe for concurrent use and loses its state on restart.
+type MemoryRoleRepository struct {
+	mu    sync.RWMutex
+	roles map[string]domain.Role
+}
+
+func NewMemoryRoleRepository() IRoleRepository {
+	return &MemoryRoleRepository{roles: make(map[string]domain.Role)}
+}
+
+func (r *MemoryRoleRepository) Close() error {
+	return nil
+}
+
+func (r *MemoryRoleRepository) GetByName(ctx context.Context, name string) (domain.Role, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	role, ok := r.roles[name]
+	if !ok {
+		return domain.Role{}, ErrRoleNotFound
+	}
+	return role, nil
+}
+
+func (r *MemoryRoleRepository) List(ctx context.Context) ([]domain.Role, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	roles := make([]domain.Role, 0, len(r.roles))
+	for _, role := range r.roles {
+		roles = append(roles, role)
+	}
+	sort.Slice(roles, func(i, j int) bool { return roles[i].Name < roles[j].Name })
+	return roles, nil
+}
+
+func (r *MemoryRoleRepository) Save(ctx context.Context, role domain.Role) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	role.Permissions = append([]string(nil), role.Permissions...)
+	r.roles[role.Name] = role
+	return nil
+}
+
+func (r *MemoryRoleRepository) Delete(ctx context.Context, name string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.roles[name]; !ok {
+		return ErrRoleNotFound
+	}
+	delete(r.roles, name)
+	return nil
+}
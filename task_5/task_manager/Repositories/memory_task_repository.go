@@ -0,0 +1,312 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	domain "task_manager/Domain"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MemoryTaskRepository implements ITaskRepository in memory.
+// It is safe for concurrent use and loses its state on restart.
+type MemoryTaskRepository struct {
+	mu    sync.RWMutex
+	tasks map[string]domain.Task
+	index *searchIndex
+}
+
+func NewMemoryTaskRepository() ITaskRepository {
+	return &MemoryTaskRepository{tasks: make(map[string]domain.Task), index: newSearchIndex(true)}
+}
+
+func (r *MemoryTaskRepository) List(ctx context.Context, q domain.TaskQuery) (domain.TaskPage, error) {
+	tasks := r.filter(q.Matches)
+	sortTasks(tasks, q.SortBy, q.SortDesc)
+
+	total := int64(len(tasks))
+	start := q.Skip()
+	if start > total {
+		start = total
+	}
+	end := start + int64(q.PageSize)
+	if end > total {
+		end = total
+	}
+	return domain.NewTaskPage(q, tasks[start:end], total), nil
+}
+
+func (r *MemoryTaskRepository) Each(ctx context.Context, q domain.TaskQuery, fn func(domain.Task) error) error {
+	tasks := r.filter(q.Matches)
+	sortTasks(tasks, q.SortBy, q.SortDesc)
+	for _, t := range tasks {
+		if err := fn(t); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *MemoryTaskRepository) Search(ctx context.Context, s domain.TaskSearch) (domain.TaskSearchPage, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	ids, err := r.index.candidates(s, nil)
+	if err != nil {
+		return domain.TaskSearchPage{}, err
+	}
+	tasks := make([]domain.Task, 0, len(ids))
+	for _, id := range ids {
+		t, ok := r.tasks[id]
+		if ok && t.DeletedAt == nil && (s.OwnerID == "" || t.OwnerID == s.OwnerID) && (s.ProjectID == "" || t.Project() == s.ProjectID) {
+			tasks = append(tasks, cloneTask(t))
+		}
+	}
+	return s.Rank(tasks), nil
+}
+
+func (r *MemoryTaskRepository) GetChildren(ctx context.Context, parentID string) ([]domain.Task, error) {
+	return r.filter(func(t domain.Task) bool { return t.DeletedAt == nil && t.ParentID == parentID }), nil
+}
+
+func (r *MemoryTaskRepository) GetByID(ctx context.Context, id string) (domain.Task, error) {
+	return r.get(id, false)
+}
+
+func (r *MemoryTaskRepository) GetDeleted(ctx context.Context, id string) (domain.Task, error) {
+	return r.get(id, true)
+}
+
+// get returns the task if it is in the trash exactly when deleted is set.
+func (r *MemoryTaskRepository) get(id string, deleted bool) (domain.Task, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	t, ok := r.tasks[id]
+	if !ok || (t.DeletedAt != nil) != deleted {
+		return domain.Task{}, ErrNotFound
+	}
+	return cloneTask(t), nil
+}
+
+func (r *MemoryTaskRepository) Create(ctx context.Context, t domain.Task) (domain.Task, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.create(t), nil
+}
+
+func (r *MemoryTaskRepository) create(t domain.Task) domain.Task {
+	// Generate a new ID if not provided
+	if t.ID == "" {
+		t.ID = primitive.NewObjectID().Hex()
+	}
+	t.ProjectID = t.Project()
+	t.Version = 1
+	r.tasks[t.ID] = cloneTask(t)
+	r.index.add(t)
+	return t
+}
+
+func (r *MemoryTaskRepository) Update(ctx context.Context, id string, t domain.Task) (domain.Task, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.update(id, t)
+}
+
+func (r *MemoryTaskRepository) update(id string, t domain.Task) (domain.Task, error) {
+	stored, ok := r.tasks[id]
+	if !ok || stored.DeletedAt != nil {
+		return domain.Task{}, ErrNotFound
+	}
+	if stored.Version != t.Version {
+		return domain.Task{}, ErrVersionConflict
+	}
+	t.ID = id
+	t.ProjectID = stored.ProjectID
+	t.Version++
+	t.DeletedAt = nil
+	r.tasks[id] = cloneTask(t)
+	r.index.add(t)
+	return t, nil
+}
+
+func (r *MemoryTaskRepository) Delete(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	t, ok := r.tasks[id]
+	if !ok || t.DeletedAt != nil {
+		return ErrNotFound
+	}
+	now := time.Now().UTC()
+	t.DeletedAt = &now
+	t.Version++
+	r.tasks[id] = t
+	return nil
+}
+
+func (r *MemoryTaskRepository) Restore(ctx context.Context, id string) (domain.Task, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	t, ok := r.tasks[id]
+	if !ok || t.DeletedAt == nil {
+		return domain.Task{}, ErrNotFound
+	}
+	t.DeletedAt = nil
+	t.Version++
+	r.tasks[id] = t
+	return cloneTask(t), nil
+}
+
+func (r *MemoryTaskRepository) Purge(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	t, ok := r.tasks[id]
+	if !ok || t.DeletedAt == nil {
+		return ErrNotFound
+	}
+	delete(r.tasks, id)
+	r.index.remove(id)
+	return nil
+}
+
+func (r *MemoryTaskRepository) PurgeDeletedBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var n int64
+	for id, t := range r.tasks {
+		if t.DeletedAt != nil && t.DeletedAt.Before(cutoff) {
+			delete(r.tasks, id)
+			r.index.remove(id)
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (r *MemoryTaskRepository) BulkWrite(ctx context.Context, writes []TaskWrite, atomic bool) ([]TaskWriteResult, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Atomic bulk writes roll back by restoring the tasks as they were
+	var snapshot map[string]domain.Task
+	if atomic {
+		snapshot = maps.Clone(r.tasks)
+	}
+	results := make([]TaskWriteResult, len(writes))
+	for i, w := range writes {
+		var err error
+		switch w.Op {
+		case WriteCreate:
+			results[i].Task = r.create(w.Task)
+		case WriteUpdate:
+			results[i].Task, err = r.update(w.Task.ID, w.Task)
+		case WriteDelete:
+			results[i].Task, err = r.deleteVersion(w.Task.ID, w.Task.Version)
+		default:
+			err = fmt.Errorf("unknown task write %q", w.Op)
+		}
+		results[i].Err = err
+	}
+	if atomic && bulkFailed(results) {
+		r.tasks = snapshot
+		for i, w := range writes {
+			id := w.Task.ID
+			if w.Op == WriteCreate {
+				id = results[i].Task.ID
+			}
+			if t, ok := snapshot[id]; ok {
+				r.index.add(t)
+			} else {
+				r.index.remove(id)
+			}
+		}
+		abortBulk(results)
+	}
+	return results, nil
+}
+
+// deleteVersion moves the task to the trash if it is still at the version.
+func (r *MemoryTaskRepository) deleteVersion(id string, version int64) (domain.Task, error) {
+	t, ok := r.tasks[id]
+	if !ok || t.DeletedAt != nil {
+		return domain.Task{}, ErrNotFound
+	}
+	if t.Version != version {
+		return domain.Task{}, ErrVersionConflict
+	}
+	now := time.Now().UTC()
+	t.DeletedAt = &now
+	t.Version++
+	r.tasks[id] = t
+	return cloneTask(t), nil
+}
+
+func (r *MemoryTaskRepository) Close() error {
+	return nil
+}
+
+// filter returns the tasks accepted by keep, ordered by ID.
+func (r *MemoryTaskRepository) filter(keep func(domain.Task) bool) []domain.Task {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	tasks := []domain.Task{}
+	for _, t := range r.tasks {
+		if keep(t) {
+			tasks = append(tasks, cloneTask(t))
+		}
+	}
+	sort.Slice(tasks, func(i, j int) bool { return tasks[i].ID < tasks[j].ID })
+	return tasks
+}
+
+// cloneTask copies the task's slices and pointers so stored tasks never alias caller memory.
+func cloneTask(t domain.Task) domain.Task {
+	t.Tags = slices.Clone(t.Tags)
+	t.AssigneeIDs = slices.Clone(t.AssigneeIDs)
+	if t.DeletedAt != nil {
+		deletedAt := *t.DeletedAt
+		t.DeletedAt = &deletedAt
+	}
+	if t.Recurrence != nil {
+		recurrence := *t.Recurrence
+		t.Recurrence = &recurrence
+	}
+	return t
+}
+
+// sortTasks orders tasks by the given field, breaking ties by ID.
+func sortTasks(tasks []domain.Task, field string, desc bool) {
+	sort.SliceStable(tasks, func(i, j int) bool {
+		a, b := tasks[i], tasks[j]
+		var c int
+		switch field {
+		case "title":
+			c = strings.Compare(a.Title, b.Title)
+		case "status":
+			c = strings.Compare(a.Status, b.Status)
+		case "deleted_at":
+			c = deletedTime(a).Compare(deletedTime(b))
+		default:
+			c = a.DueDate.Compare(b.DueDate)
+		}
+		if c == 0 {
+			c = strings.Compare(a.ID, b.ID)
+		}
+		if desc {
+			return c > 0
+		}
+		return c < 0
+	})
+}
+
+func deletedTime(t domain.Task) time.Time {
+	if t.DeletedAt == nil {
+		return time.Time{}
+	}
+	return *t.DeletedAt
+}
@@ -0,0 +1,97 @@
+package repositories
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	domain "task_manager/Domain"
+)
+
+// MemoryTokenStore implements ITokenStore in memory.
+// It is safe for concurrent use and loses its state on restart.
+type MemoryTokenStore struct {
+	mu            sync.Mutex
+	refreshTokens map[string]domain.RefreshToken
+	revokedTokens map[string]time.Time
+}
+
+func NewMemoryTokenStore() ITokenStore {
+	return &MemoryTokenStore{
+		refreshTokens: make(map[string]domain.RefreshToken),
+		revokedTokens: make(map[string]time.Time),
+	}
+}
+
+func (s *MemoryTokenStore) Close() error {
+	return nil
+}
+
+func (s *MemoryTokenStore) SaveRefreshToken(ctx context.Context, rt domain.RefreshToken) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.refreshTokens[rt.Hash] = rt
+	return nil
+}
+
+func (s *MemoryTokenStore) GetRefreshToken(ctx context.Context, hash string) (domain.RefreshToken, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	rt, ok := s.refreshTokens[hash]
+	if !ok {
+		return domain.RefreshToken{}, ErrRefreshTokenNotFound
+	}
+	return rt, nil
+}
+
+func (s *MemoryTokenStore) UseRefreshToken(ctx context.Context, hash string) (domain.RefreshToken, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	rt, ok := s.refreshTokens[hash]
+	if !ok {
+		return domain.RefreshToken{}, ErrRefreshTokenNotFound
+	}
+	if rt.Revoked {
+		return rt, ErrRefreshTokenRevoked
+	}
+	if rt.Used {
+		return rt, ErrRefreshTokenReused
+	}
+	rt.Used = true
+	s.refreshTokens[hash] = rt
+	return rt, nil
+}
+
+func (s *MemoryTokenStore) RevokeFamily(ctx context.Context, familyID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for hash, rt := range s.refreshTokens {
+		if rt.FamilyID == familyID {
+			rt.Revoked = true
+			s.refreshTokens[hash] = rt
+		}
+	}
+	return nil
+}
+
+func (s *MemoryTokenStore) RevokeAccessToken(ctx context.Context, jti string, expiresAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Drop entries for tokens that have expired on their own
+	now := time.Now()
+	for id, exp := range s.revokedTokens {
+		if !now.Before(exp) {
+			delete(s.revokedTokens, id)
+		}
+	}
+	s.revokedTokens[jti] = expiresAt
+	return nil
+}
+
+func (s *MemoryTokenStore) IsAccessTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.revokedTokens[jti]
+	return ok, nil
+}
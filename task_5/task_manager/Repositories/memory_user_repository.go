@@ -0,0 +1,120 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	domain "task_manager/Domain"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// MemoryUserRepository implements IUserRepository in memory.
+// It is safe for concurrent use and loses its state on restart.
+type MemoryUserRepository struct {
+	mu    sync.RWMutex
+	users map[primitive.ObjectID]domain.User
+}
+
+func NewMemoryUserRepository() IUserRepository {
+	return &MemoryUserRepository{users: make(map[primitive.ObjectID]domain.User)}
+}
+
+func (r *MemoryUserRepository) Close() error {
+	return nil
+}
+
+func (r *MemoryUserRepository) IsEmpty(ctx context.Context) (bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return len(r.users) == 0, nil
+}
+
+func (r *MemoryUserRepository) CreateUser(ctx context.Context, username, password string) (domain.User, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return domain.User{}, fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, existing := range r.users {
+		if existing.Username == username {
+			return domain.User{}, ErrUsernameTaken
+		}
+	}
+
+	role := domain.RoleUser
+	if len(r.users) == 0 {
+		role = domain.RoleAdmin
+	}
+
+	u := domain.User{
+		ID:           primitive.NewObjectID(),
+		Username:     username,
+		PasswordHash: string(hash),
+		Role:         role,
+		CreatedAt:    time.Now().UTC(),
+	}
+	r.users[u.ID] = u
+
+	u.PasswordHash = ""
+	return u, nil
+}
+
+func (r *MemoryUserRepository) GetByUsername(ctx context.Context, username string) (domain.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, u := range r.users {
+		if u.Username == username {
+			return u, nil
+		}
+	}
+	return domain.User{}, ErrUserNotFound
+}
+
+func (r *MemoryUserRepository) GetByID(ctx context.Context, idHex string) (domain.User, error) {
+	id, err := primitive.ObjectIDFromHex(idHex)
+	if err != nil {
+		return domain.User{}, ErrUserNotFound
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	u, ok := r.users[id]
+	if !ok {
+		return domain.User{}, ErrUserNotFound
+	}
+	u.PasswordHash = ""
+	return u, nil
+}
+
+func (r *MemoryUserRepository) VerifyPassword(u domain.User, password string) bool {
+	err := bcrypt.CompareHashAndPassword([]byte(u.PasswordHash), []byte(password))
+	return err == nil
+}
+
+func (r *MemoryUserRepository) PromoteUser(ctx context.Context, idHex string) error {
+	return r.SetRole(ctx, idHex, domain.RoleAdmin)
+}
+
+func (r *MemoryUserRepository) SetRole(ctx context.Context, idHex, role string) error {
+	id, err := primitive.ObjectIDFromHex(idHex)
+	if err != nil {
+		return ErrUserNotFound
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	u, ok := r.users[id]
+	if !ok {
+		return ErrUserNotFound
+	}
+	u.Role = role
+	r.users[id] = u
+	return nil
+}
@@ -0,0 +1,150 @@
+package repositories
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	domain "task_manager/Domain"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MemoryWebhookRepository implements IWebhookRepository in memory.
+// It is safe for concurrent use and loses its state on restart.
+type MemoryWebhookRepository struct {
+	mu         sync.RWMutex
+	webhooks   map[string]domain.Webhook
+	deliveries map[string]domain.WebhookDelivery
+}
+
+func NewMemoryWebhookRepository() IWebhookRepository {
+	return &MemoryWebhookRepository{
+		webhooks:   make(map[string]domain.Webhook),
+		deliveries: make(map[string]domain.WebhookDelivery),
+	}
+}
+
+func (r *MemoryWebhookRepository) Close() error {
+	return nil
+}
+
+func (r *MemoryWebhookRepository) Create(ctx context.Context, w domain.Webhook) (domain.Webhook, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	w.ID = primitive.NewObjectID().Hex()
+	w.Events = slices.Clone(w.Events)
+	r.webhooks[w.ID] = w
+	return w, nil
+}
+
+func (r *MemoryWebhookRepository) GetByID(ctx context.Context, id string) (domain.Webhook, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	w, ok := r.webhooks[id]
+	if !ok {
+		return domain.Webhook{}, ErrWebhookNotFound
+	}
+	w.Events = slices.Clone(w.Events)
+	return w, nil
+}
+
+func (r *MemoryWebhookRepository) List(ctx context.Context) ([]domain.Webhook, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	webhooks := []domain.Webhook{}
+	for _, w := range r.webhooks {
+		w.Events = slices.Clone(w.Events)
+		webhooks = append(webhooks, w)
+	}
+	sort.Slice(webhooks, func(i, j int) bool { return webhooks[i].ID < webhooks[j].ID })
+	return webhooks, nil
+}
+
+func (r *MemoryWebhookRepository) Delete(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.webhooks[id]; !ok {
+		return ErrWebhookNotFound
+	}
+	delete(r.webhooks, id)
+	for deliveryID, d := range r.deliveries {
+		if d.WebhookID == id {
+			delete(r.deliveries, deliveryID)
+		}
+	}
+	return nil
+}
+
+func (r *MemoryWebhookRepository) CreateDelivery(ctx context.Context, d domain.WebhookDelivery) (domain.WebhookDelivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	d.ID = primitive.NewObjectID().Hex()
+	d.Payload = slices.Clone(d.Payload)
+	r.deliveries[d.ID] = d
+	return d, nil
+}
+
+func (r *MemoryWebhookRepository) UpdateDelivery(ctx context.Context, d domain.WebhookDelivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.deliveries[d.ID]; !ok {
+		return ErrDeliveryNotFound
+	}
+	d.Payload = slices.Clone(d.Payload)
+	r.deliveries[d.ID] = d
+	return nil
+}
+
+func (r *MemoryWebhookRepository) GetDelivery(ctx context.Context, id string) (domain.WebhookDelivery, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	d, ok := r.deliveries[id]
+	if !ok {
+		return domain.WebhookDelivery{}, ErrDeliveryNotFound
+	}
+	return d, nil
+}
+
+func (r *MemoryWebhookRepository) ListDeliveries(ctx context.Context, q domain.DeliveryQuery) (domain.DeliveryPage, error) {
+	matched := r.filterDeliveries(q.Matches)
+	// Newest first; IDs break ties in creation order
+	sort.Slice(matched, func(i, j int) bool {
+		if c := matched[i].CreatedAt.Compare(matched[j].CreatedAt); c != 0 {
+			return c > 0
+		}
+		return matched[i].ID > matched[j].ID
+	})
+
+	total := int64(len(matched))
+	start := min(q.Skip(), total)
+	end := min(start+int64(q.PageSize), total)
+	return domain.NewDeliveryPage(q, matched[start:end], total), nil
+}
+
+func (r *MemoryWebhookRepository) DueDeliveries(ctx context.Context, now time.Time, limit int) ([]domain.WebhookDelivery, error) {
+	due := r.filterDeliveries(func(d domain.WebhookDelivery) bool {
+		return d.Status == domain.DeliveryPending && !d.NextAttemptAt.After(now)
+	})
+	sort.Slice(due, func(i, j int) bool {
+		if c := due[i].NextAttemptAt.Compare(due[j].NextAttemptAt); c != 0 {
+			return c < 0
+		}
+		return due[i].ID < due[j].ID
+	})
+	return due[:min(len(due), limit)], nil
+}
+
+func (r *MemoryWebhookRepository) filterDeliveries(keep func(domain.WebhookDelivery) bool) []domain.WebhookDelivery {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	deliveries := []domain.WebhookDelivery{}
+	for _, d := range r.deliveries {
+		if keep(d) {
+			deliveries = append(deliveries, d)
+		}
+	}
+	return deliveries
+}
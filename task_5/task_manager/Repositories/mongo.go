@@ -0,0 +1,88 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+	"go.mongodb.org/mongo-driver/mongo/writeconcern"
+)
+
+// mongoConnectTimeout bounds connecting to and pinging MongoDB.
+const mongoConnectTimeout = 10 * time.Second
+
+// MongoConfig configures the MongoDB client shared by the Mongo repositories.
+type MongoConfig struct {
+	URI      string
+	Database string
+	// MaxPoolSize and MinPoolSize bound the connection pool; zero keeps the driver default.
+	MaxPoolSize uint64
+	MinPoolSize uint64
+	// ReadPreference is primary, primaryPreferred, secondary, secondaryPreferred or nearest.
+	ReadPreference string
+	// WriteConcern is "majority" or the number of acknowledging nodes.
+	WriteConcern string
+}
+
+// OpenMongo connects to MongoDB and verifies the connection.
+// The returned client is shared by every Mongo repository; its owner disconnects it.
+func OpenMongo(cfg MongoConfig) (*mongo.Client, error) {
+	opts := options.Client().ApplyURI(cfg.URI)
+	if cfg.MaxPoolSize > 0 {
+		opts.SetMaxPoolSize(cfg.MaxPoolSize)
+	}
+	if cfg.MinPoolSize > 0 {
+		opts.SetMinPoolSize(cfg.MinPoolSize)
+	}
+	if cfg.ReadPreference != "" {
+		mode, err := readpref.ModeFromString(cfg.ReadPreference)
+		if err != nil {
+			return nil, fmt.Errorf("invalid read preference %q", cfg.ReadPreference)
+		}
+		rp, err := readpref.New(mode)
+		if err != nil {
+			return nil, fmt.Errorf("invalid read preference %q: %w", cfg.ReadPreference, err)
+		}
+		opts.SetReadPreference(rp)
+	}
+	if cfg.WriteConcern != "" {
+		wc, err := parseWriteConcern(cfg.WriteConcern)
+		if err != nil {
+			return nil, err
+		}
+		opts.SetWriteConcern(wc)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), mongoConnectTimeout)
+	defer cancel()
+
+	client, err := mongo.Connect(ctx, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to MongoDB: %w", err)
+	}
+
+	// Ping the database to verify connection
+	if err := client.Ping(ctx, nil); err != nil {
+		client.Disconnect(context.Background())
+		return nil, fmt.Errorf("failed to ping MongoDB: %w", err)
+	}
+
+	log.Println("Connected to MongoDB")
+	return client, nil
+}
+
+func parseWriteConcern(value string) (*writeconcern.WriteConcern, error) {
+	if value == "majority" {
+		return writeconcern.Majority(), nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		return nil, fmt.Errorf("invalid write concern %q: expected majority or a node count", value)
+	}
+	return &writeconcern.WriteConcern{W: n}, nil
+}
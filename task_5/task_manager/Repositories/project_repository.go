@@ -0,0 +1,121 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	domain "task_manager/Domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var ErrProjectNotFound error = domain.NewNotFoundError("project not found")
+
+// IProjectRepository defines the interface for projects and their members.
+type IProjectRepository interface {
+	// Create stores a new project, assigning its ID.
+	Create(ctx context.Context, p domain.Project) (domain.Project, error)
+	GetByID(ctx context.Context, id string) (domain.Project, error)
+	// List returns the projects the user is a member of, or every project
+	// when userID is empty, oldest first.
+	List(ctx context.Context, userID string) ([]domain.Project, error)
+	// Update replaces the name, description, members and archive time of a stored project.
+	Update(ctx context.Context, p domain.Project) error
+	Close() error
+}
+
+// MongoProjectRepository implements IProjectRepository using MongoDB.
+type MongoProjectRepository struct {
+	collection *mongo.Collection
+	timeouts   Timeouts
+}
+
+func NewMongoProjectRepository(db *mongo.Database, collectionName string, timeouts Timeouts) (IProjectRepository, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), mongoConnectTimeout)
+	defer cancel()
+
+	collection := db.Collection(collectionName)
+	_, err := collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "members.user_id", Value: 1}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create project indexes: %w", err)
+	}
+	return &MongoProjectRepository{collection: collection, timeouts: timeouts}, nil
+}
+
+// Close is a no-op; the shared client is disconnected by its owner.
+func (r *MongoProjectRepository) Close() error {
+	return nil
+}
+
+func (r *MongoProjectRepository) Create(ctx context.Context, p domain.Project) (domain.Project, error) {
+	ctx, cancel := r.timeouts.withTimeout(ctx, "project.create")
+	defer cancel()
+
+	p.ID = primitive.NewObjectID().Hex()
+	if _, err := r.collection.InsertOne(ctx, p); err != nil {
+		return domain.Project{}, fmt.Errorf("failed to insert project: %w", err)
+	}
+	return p, nil
+}
+
+func (r *MongoProjectRepository) GetByID(ctx context.Context, id string) (domain.Project, error) {
+	ctx, cancel := r.timeouts.withTimeout(ctx, "project.get")
+	defer cancel()
+
+	var p domain.Project
+	if err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&p); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return domain.Project{}, ErrProjectNotFound
+		}
+		return domain.Project{}, fmt.Errorf("failed to find project: %w", err)
+	}
+	return p, nil
+}
+
+func (r *MongoProjectRepository) List(ctx context.Context, userID string) ([]domain.Project, error) {
+	ctx, cancel := r.timeouts.withTimeout(ctx, "project.list")
+	defer cancel()
+
+	filter := bson.M{}
+	if userID != "" {
+		filter["members.user_id"] = userID
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}})
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find projects: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	projects := []domain.Project{}
+	if err := cursor.All(ctx, &projects); err != nil {
+		return nil, fmt.Errorf("failed to decode projects: %w", err)
+	}
+	return projects, nil
+}
+
+func (r *MongoProjectRepository) Update(ctx context.Context, p domain.Project) error {
+	ctx, cancel := r.timeouts.withTimeout(ctx, "project.update")
+	defer cancel()
+
+	set := bson.M{"name": p.Name, "description": p.Description, "members": p.Members}
+	update := bson.M{"$set": set}
+	if p.ArchivedAt != nil {
+		set["archived_at"] = p.ArchivedAt
+	} else {
+		update["$unset"] = bson.M{"archived_at": ""}
+	}
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": p.ID}, update)
+	if err != nil {
+		return fmt.Errorf("failed to update project: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return ErrProjectNotFound
+	}
+	return nil
+}
@@ -0,0 +1,191 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	domain "task_manager/Domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// IRateLimitStore keeps the token buckets of rate limited keys and the
+// failed logins of accounts. Keys are opaque to the store.
+type IRateLimitStore interface {
+	// Take takes a token from the bucket of key under the limit. When the
+	// bucket is empty it returns how long until a token is available.
+	Take(ctx context.Context, key string, limit domain.RateLimit, now time.Time) (time.Duration, error)
+	// Attempt starts a login attempt for key under the policy. While key
+	// is locked it returns how long the lock lasts. Otherwise it counts the
+	// attempt as a failed login, locking key as the policy demands, until
+	// ResetFailures forgets it. The check and the count are one atomic
+	// operation, so concurrent attempts cannot get past the lock.
+	Attempt(ctx context.Context, key string, policy domain.LockoutPolicy, now time.Time) (time.Duration, error)
+	// ResetFailures forgets the failed logins of key.
+	ResetFailures(ctx context.Context, key string) error
+	Close() error
+}
+
+// MongoRateLimitStore implements IRateLimitStore using MongoDB.
+type MongoRateLimitStore struct {
+	buckets  *mongo.Collection
+	failures *mongo.Collection
+	timeouts Timeouts
+}
+
+// mongoBucket is the stored state of a token bucket.
+type mongoBucket struct {
+	Tokens  float64 `bson:"tokens"`
+	Allowed bool    `bson:"allowed"`
+}
+
+// mongoLockout is the stored failed login record of an account.
+type mongoLockout struct {
+	Failures    int       `bson:"failures"`
+	LockedUntil time.Time `bson:"locked_until,omitempty"`
+	Refused     bool      `bson:"refused"`
+}
+
+func NewMongoRateLimitStore(db *mongo.Database, timeouts Timeouts) (IRateLimitStore, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), mongoConnectTimeout)
+	defer cancel()
+
+	s := &MongoRateLimitStore{
+		buckets:  db.Collection("rate_limits"),
+		failures: db.Collection("login_failures"),
+		timeouts: timeouts,
+	}
+
+	// Idle buckets and forgotten failures are removed by MongoDB's TTL monitor
+	ttl := mongo.IndexModel{
+		Keys:    bson.D{{Key: "expires_at", Value: 1}},
+		Options: options.Index().SetExpireAfterSeconds(0),
+	}
+	if _, err := s.buckets.Indexes().CreateOne(ctx, ttl); err != nil {
+		return nil, fmt.Errorf("failed to create rate limit index: %w", err)
+	}
+	if _, err := s.failures.Indexes().CreateOne(ctx, ttl); err != nil {
+		return nil, fmt.Errorf("failed to create login failure index: %w", err)
+	}
+	return s, nil
+}
+
+// Close is a no-op; the shared client is disconnected by its owner.
+func (s *MongoRateLimitStore) Close() error {
+	return nil
+}
+
+func (s *MongoRateLimitStore) Take(ctx context.Context, key string, limit domain.RateLimit, now time.Time) (time.Duration, error) {
+	ctx, cancel := s.timeouts.withTimeout(ctx, "rate_limit.take")
+	defer cancel()
+
+	// The bucket is refilled and taken from in a single update, the same
+	// way domain.RateLimit.Take does it. A bucket is full again one period
+	// after its last use, so it can expire then.
+	burst := float64(limit.Burst)
+	elapsed := bson.M{"$max": bson.A{0, bson.M{"$divide": bson.A{
+		bson.M{"$subtract": bson.A{now, bson.M{"$ifNull": bson.A{"$updated_at", now}}}}, 1000,
+	}}}}
+	refilled := bson.M{"$min": bson.A{burst, bson.M{"$add": bson.A{
+		bson.M{"$ifNull": bson.A{"$tokens", burst}},
+		bson.M{"$multiply": bson.A{elapsed, limit.Rate()}},
+	}}}}
+	pipeline := mongo.Pipeline{
+		{{Key: "$set", Value: bson.M{"tokens": refilled, "updated_at": now}}},
+		{{Key: "$set", Value: bson.M{"allowed": bson.M{"$gte": bson.A{"$tokens", 1}}}}},
+		{{Key: "$set", Value: bson.M{
+			"tokens":     bson.M{"$cond": bson.A{"$allowed", bson.M{"$subtract": bson.A{"$tokens", 1}}, "$tokens"}},
+			"expires_at": now.Add(limit.Period),
+		}}},
+	}
+
+	var b mongoBucket
+	err := s.buckets.FindOneAndUpdate(ctx, bson.M{"_id": key}, pipeline,
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+	).Decode(&b)
+	if err != nil {
+		return 0, fmt.Errorf("failed to take rate limit token: %w", err)
+	}
+	if b.Allowed {
+		return 0, nil
+	}
+	return limit.Wait(b.Tokens), nil
+}
+
+func (s *MongoRateLimitStore) Attempt(ctx context.Context, key string, policy domain.LockoutPolicy, now time.Time) (time.Duration, error) {
+	ctx, cancel := s.timeouts.withTimeout(ctx, "rate_limit.attempt")
+	defer cancel()
+
+	// The lock is checked and the attempt counted in a single update, the
+	// same way domain.LockoutPolicy.Attempt does it. A record that has
+	// expired but was not removed by the TTL monitor yet starts over.
+	current := bson.M{"$gt": bson.A{bson.M{"$ifNull": bson.A{"$expires_at", time.Time{}}}, now}}
+	lockedUntil := bson.M{"$ifNull": bson.A{"$locked_until", time.Time{}}}
+	lockFor := mongoLockFor(policy)
+	lockedAt := bson.M{"$add": bson.A{now, lockFor}}
+	pipeline := mongo.Pipeline{
+		{{Key: "$set", Value: bson.M{
+			"refused":      bson.M{"$and": bson.A{current, bson.M{"$gt": bson.A{lockedUntil, now}}}},
+			"failures":     bson.M{"$cond": bson.A{current, "$failures", 0}},
+			"locked_until": bson.M{"$cond": bson.A{current, "$locked_until", "$$REMOVE"}},
+		}}},
+		{{Key: "$set", Value: bson.M{
+			"failures": bson.M{"$cond": bson.A{"$refused", "$failures", bson.M{"$add": bson.A{"$failures", 1}}}},
+		}}},
+		{{Key: "$set", Value: bson.M{
+			"locked_until": bson.M{"$cond": bson.A{
+				bson.M{"$and": bson.A{
+					bson.M{"$not": bson.A{"$refused"}}, bson.M{"$gt": bson.A{lockFor, 0}}, bson.M{"$gt": bson.A{lockedAt, lockedUntil}},
+				}},
+				lockedAt, "$locked_until",
+			}},
+		}}},
+		{{Key: "$set", Value: bson.M{
+			"expires_at": bson.M{"$cond": bson.A{"$refused", "$expires_at",
+				bson.M{"$add": bson.A{bson.M{"$max": bson.A{lockedUntil, now}}, policy.Window.Milliseconds()}},
+			}},
+		}}},
+	}
+	var stored mongoLockout
+	err := s.failures.FindOneAndUpdate(ctx, bson.M{"_id": key}, pipeline,
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+	).Decode(&stored)
+	if err != nil {
+		return 0, fmt.Errorf("failed to record login attempt: %w", err)
+	}
+	if stored.Refused {
+		return domain.Lockout{LockedUntil: stored.LockedUntil}.RetryAfter(now), nil
+	}
+	return 0, nil
+}
+
+// mongoLockFor is the aggregation expression of policy.LockFor("$failures")
+// in milliseconds. The lock stops growing at MaxDelay, so it takes only a
+// few branches.
+func mongoLockFor(policy domain.LockoutPolicy) bson.M {
+	if !policy.Enabled() {
+		return bson.M{"$literal": 0}
+	}
+	branches := bson.A{bson.M{"case": bson.M{"$lt": bson.A{"$failures", policy.Threshold}}, "then": 0}}
+	failures := policy.Threshold
+	for ; policy.LockFor(failures+1) != policy.LockFor(failures); failures++ {
+		branches = append(branches, bson.M{
+			"case": bson.M{"$eq": bson.A{"$failures", failures}},
+			"then": policy.LockFor(failures).Milliseconds(),
+		})
+	}
+	return bson.M{"$switch": bson.M{"branches": branches, "default": policy.LockFor(failures).Milliseconds()}}
+}
+
+func (s *MongoRateLimitStore) ResetFailures(ctx context.Context, key string) error {
+	ctx, cancel := s.timeouts.withTimeout(ctx, "rate_limit.reset_failures")
+	defer cancel()
+
+	if _, err := s.failures.DeleteOne(ctx, bson.M{"_id": key}); err != nil {
+		return fmt.Errorf("failed to reset login failures: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	domain "task_manager/Domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// IReminderRepository records which due-date reminders were sent.
+type IReminderRepository interface {
+	// Claim stores the reminder unless one with its ID exists, reporting
+	// whether it was stored. Only the caller that claims a reminder sends it.
+	Claim(ctx context.Context, r domain.Reminder) (bool, error)
+	// Release removes a claimed reminder so that it is sent again.
+	Release(ctx context.Context, id string) error
+	// DeleteDueBefore removes the reminders for due dates before cutoff.
+	DeleteDueBefore(ctx context.Context, cutoff time.Time) (int64, error)
+	Close() error
+}
+
+// MongoReminderRepository implements IReminderRepository using MongoDB.
+type MongoReminderRepository struct {
+	collection *mongo.Collection
+	timeouts   Timeouts
+}
+
+func NewMongoReminderRepository(db *mongo.Database, collectionName string, timeouts Timeouts) (IReminderRepository, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), mongoConnectTimeout)
+	defer cancel()
+
+	collection := db.Collection(collectionName)
+	_, err := collection.Indexes().CreateOne(ctx, mongo.IndexModel{Keys: bson.D{{Key: "due_date", Value: 1}}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create reminder indexes: %w", err)
+	}
+	return &MongoReminderRepository{collection: collection, timeouts: timeouts}, nil
+}
+
+// Close is a no-op; the shared client is disconnected by its owner.
+func (r *MongoReminderRepository) Close() error {
+	return nil
+}
+
+func (r *MongoReminderRepository) Claim(ctx context.Context, reminder domain.Reminder) (bool, error) {
+	ctx, cancel := r.timeouts.withTimeout(ctx, "reminder.claim")
+	defer cancel()
+
+	if _, err := r.collection.InsertOne(ctx, reminder); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to claim reminder: %w", err)
+	}
+	return true, nil
+}
+
+func (r *MongoReminderRepository) Release(ctx context.Context, id string) error {
+	ctx, cancel := r.timeouts.withTimeout(ctx, "reminder.release")
+	defer cancel()
+
+	if _, err := r.collection.DeleteOne(ctx, bson.M{"_id": id}); err != nil {
+		return fmt.Errorf("failed to release reminder: %w", err)
+	}
+	return nil
+}
+
+func (r *MongoReminderRepository) DeleteDueBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	ctx, cancel := r.timeouts.withTimeout(ctx, "reminder.delete_due_before")
+	defer cancel()
+
+	result, err := r.collection.DeleteMany(ctx, bson.M{"due_date": bson.M{"$lt": cutoff}})
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete reminders: %w", err)
+	}
+	return result.DeletedCount, nil
+}
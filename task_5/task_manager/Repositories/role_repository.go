@@ -0,0 +1,93 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	domain "task_manager/Domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var (
+	ErrRoleNotFound error = domain.NewNotFoundError("role not found")
+)
+
+// IRoleRepository defines the interface for custom role data access.
+// Built-in roles are defined in the domain and never stored.
+type IRoleRepository interface {
+	GetByName(ctx context.Context, name string) (domain.Role, error)
+	List(ctx context.Context) ([]domain.Role, error)
+	Save(ctx context.Context, role domain.Role) error
+	Delete(ctx context.Context, name string) error
+	Close() error
+}
+
+// MongoRoleRepository implements IRoleRepository using MongoDB.
+type MongoRoleRepository struct {
+	collection *mongo.Collection
+	timeouts   Timeouts
+}
+
+func NewMongoRoleRepository(db *mongo.Database, collectionName string, timeouts Timeouts) IRoleRepository {
+	return &MongoRoleRepository{collection: db.Collection(collectionName), timeouts: timeouts}
+}
+
+// Close is a no-op; the shared client is disconnected by its owner.
+func (r *MongoRoleRepository) Close() error {
+	return nil
+}
+
+func (r *MongoRoleRepository) GetByName(ctx context.Context, name string) (domain.Role, error) {
+	ctx, cancel := r.timeouts.withTimeout(ctx, "role.get_by_name")
+	defer cancel()
+	var role domain.Role
+	if err := r.collection.FindOne(ctx, bson.M{"_id": name}).Decode(&role); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return domain.Role{}, ErrRoleNotFound
+		}
+		return domain.Role{}, fmt.Errorf("failed to find role: %w", err)
+	}
+	return role, nil
+}
+
+func (r *MongoRoleRepository) List(ctx context.Context) ([]domain.Role, error) {
+	ctx, cancel := r.timeouts.withTimeout(ctx, "role.list")
+	defer cancel()
+	cursor, err := r.collection.Find(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find roles: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var roles []domain.Role
+	if err := cursor.All(ctx, &roles); err != nil {
+		return nil, fmt.Errorf("failed to decode roles: %w", err)
+	}
+	return roles, nil
+}
+
+func (r *MongoRoleRepository) Save(ctx context.Context, role domain.Role) error {
+	ctx, cancel := r.timeouts.withTimeout(ctx, "role.save")
+	defer cancel()
+	_, err := r.collection.ReplaceOne(ctx, bson.M{"_id": role.Name}, role, options.Replace().SetUpsert(true))
+	if err != nil {
+		return fmt.Errorf("failed to save role: %w", err)
+	}
+	return nil
+}
+
+func (r *MongoRoleRepository) Delete(ctx context.Context, name string) error {
+	ctx, cancel := r.timeouts.withTimeout(ctx, "role.delete")
+	defer cancel()
+	res, err := r.collection.DeleteOne(ctx, bson.M{"_id": name})
+	if err != nil {
+		return fmt.Errorf("failed to delete role: %w", err)
+	}
+	if res.DeletedCount == 0 {
+		return ErrRoleNotFound
+	}
+	return nil
+}
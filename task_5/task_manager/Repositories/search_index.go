@@ -0,0 +1,179 @@
+package repositories
+
+import (
+	"strings"
+	"sync"
+
+	domain "task_manager/Domain"
+)
+
+// searchIndex is an inverted index from the words of task titles and
+// descriptions to the tasks containing them, for the backends without a
+// text index of their own. It only narrows a search down to candidates;
+// TaskSearch.Rank checks phrases and scores them. It is safe for
+// concurrent use.
+type searchIndex struct {
+	load     sync.Mutex // held while the index is being built
+	mu       sync.RWMutex
+	built    bool
+	loading  bool
+	pending  map[string]domain.Task         // changes made while loading
+	postings map[string]map[string]struct{} // word -> task IDs
+	words    map[string][]string            // task ID -> its indexed words
+}
+
+// newSearchIndex returns an empty index. An index that is not built
+// ignores changes until the first search loads it in full.
+func newSearchIndex(built bool) *searchIndex {
+	return &searchIndex{
+		built:    built,
+		postings: make(map[string]map[string]struct{}),
+		words:    make(map[string][]string),
+	}
+}
+
+// add indexes the task, replacing what was indexed for it before.
+func (ix *searchIndex) add(t domain.Task) {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	switch {
+	case ix.built:
+		ix.put(t)
+	case ix.loading:
+		ix.pending[t.ID] = t
+	}
+}
+
+func (ix *searchIndex) put(t domain.Task) {
+	ix.drop(t.ID)
+	seen := make(map[string]bool)
+	for _, text := range []string{t.Title, t.Description} {
+		for _, token := range domain.Tokenize(text) {
+			if seen[token.Word] {
+				continue
+			}
+			seen[token.Word] = true
+			ids, ok := ix.postings[token.Word]
+			if !ok {
+				ids = make(map[string]struct{})
+				ix.postings[token.Word] = ids
+			}
+			ids[t.ID] = struct{}{}
+			ix.words[t.ID] = append(ix.words[t.ID], token.Word)
+		}
+	}
+}
+
+// remove forgets the task.
+func (ix *searchIndex) remove(id string) {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	ix.drop(id)
+	delete(ix.pending, id)
+}
+
+func (ix *searchIndex) drop(id string) {
+	for _, word := range ix.words[id] {
+		delete(ix.postings[word], id)
+		if len(ix.postings[word]) == 0 {
+			delete(ix.postings, word)
+		}
+	}
+	delete(ix.words, id)
+}
+
+// reset empties the index so the next search loads it again.
+func (ix *searchIndex) reset() {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	ix.built = false
+	ix.postings = make(map[string]map[string]struct{})
+	ix.words = make(map[string][]string)
+}
+
+// candidates returns the IDs of the tasks containing every word of the
+// search, or a word starting with each prefix. An index that is not built
+// is first filled with the tasks load returns.
+func (ix *searchIndex) candidates(s domain.TaskSearch, load func() ([]domain.Task, error)) ([]string, error) {
+	if err := ix.build(load); err != nil {
+		return nil, err
+	}
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+
+	var found map[string]struct{}
+	for _, term := range s.Terms {
+		for i, word := range term.Words {
+			var ids map[string]struct{}
+			if term.Prefix && i == len(term.Words)-1 {
+				ids = ix.prefixed(word)
+			} else {
+				ids = ix.postings[word]
+			}
+			found = intersect(found, ids)
+			if len(found) == 0 {
+				return nil, nil
+			}
+		}
+	}
+	candidates := make([]string, 0, len(found))
+	for id := range found {
+		candidates = append(candidates, id)
+	}
+	return candidates, nil
+}
+
+func (ix *searchIndex) build(load func() ([]domain.Task, error)) error {
+	ix.load.Lock()
+	defer ix.load.Unlock()
+	ix.mu.Lock()
+	if ix.built {
+		ix.mu.Unlock()
+		return nil
+	}
+	ix.loading, ix.pending = true, make(map[string]domain.Task)
+	ix.mu.Unlock()
+
+	// Tasks written while loading may be loaded as they were before, so
+	// they are indexed again afterwards as add saw them
+	tasks, err := load()
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	pending := ix.pending
+	ix.loading, ix.pending = false, nil
+	if err != nil {
+		return err
+	}
+	for _, t := range tasks {
+		ix.put(t)
+	}
+	for _, t := range pending {
+		ix.put(t)
+	}
+	ix.built = true
+	return nil
+}
+
+// prefixed returns the IDs of the tasks with a word starting with prefix.
+func (ix *searchIndex) prefixed(prefix string) map[string]struct{} {
+	ids := make(map[string]struct{})
+	for word, postings := range ix.postings {
+		if strings.HasPrefix(word, prefix) {
+			for id := range postings {
+				ids[id] = struct{}{}
+			}
+		}
+	}
+	return ids
+}
+
+// intersect returns the IDs in both sets, treating a nil a as all IDs.
+func intersect(a, b map[string]struct{}) map[string]struct{} {
+	out := make(map[string]struct{})
+	for id := range b {
+		if _, ok := a[id]; ok || a == nil {
+			out[id] = struct{}{}
+		}
+	}
+	return out
+}
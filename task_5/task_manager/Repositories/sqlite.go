@@ -0,0 +1,229 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+// sqliteTimeLayout stores times as fixed-width UTC text so they sort chronologically.
+const sqliteTimeLayout = "2006-01-02T15:04:05.000000000Z"
+
+// sqliteMigrations are applied in order; PRAGMA user_version records how many have run.
+var sqliteMigrations = []string{
+	`CREATE TABLE tasks (
+		id          TEXT PRIMARY KEY,
+		title       TEXT NOT NULL,
+		description TEXT NOT NULL DEFAULT '',
+		due_date    TEXT NOT NULL,
+		status      TEXT NOT NULL,
+		owner_id    TEXT NOT NULL DEFAULT ''
+	);
+	CREATE INDEX idx_tasks_owner_due ON tasks (owner_id, due_date);
+	CREATE INDEX idx_tasks_status_due ON tasks (status, due_date);
+	CREATE INDEX idx_tasks_due ON tasks (due_date);
+
+	CREATE TABLE users (
+		id            TEXT PRIMARY KEY,
+		username      TEXT NOT NULL UNIQUE,
+		password_hash TEXT NOT NULL,
+		role          TEXT NOT NULL,
+		created_at    TEXT NOT NULL
+	);
+
+	CREATE TABLE roles (
+		name        TEXT PRIMARY KEY,
+		permissions TEXT NOT NULL
+	);
+
+	CREATE TABLE refresh_tokens (
+		hash       TEXT PRIMARY KEY,
+		family_id  TEXT NOT NULL,
+		user_id    TEXT NOT NULL,
+		username   TEXT NOT NULL,
+		expires_at TEXT NOT NULL,
+		used       INTEGER NOT NULL DEFAULT 0,
+		revoked    INTEGER NOT NULL DEFAULT 0,
+		created_at TEXT NOT NULL
+	);
+	CREATE INDEX idx_refresh_tokens_family ON refresh_tokens (family_id);
+
+	CREATE TABLE revoked_tokens (
+		jti        TEXT PRIMARY KEY,
+		expires_at TEXT NOT NULL
+	);`,
+
+	`ALTER TABLE tasks ADD COLUMN priority TEXT NOT NULL DEFAULT 'medium';
+	ALTER TABLE tasks ADD COLUMN tags TEXT NOT NULL DEFAULT '[]';
+	ALTER TABLE tasks ADD COLUMN assignee_ids TEXT NOT NULL DEFAULT '[]';
+	ALTER TABLE tasks ADD COLUMN parent_id TEXT NOT NULL DEFAULT '';
+	CREATE INDEX idx_tasks_parent ON tasks (parent_id);`,
+
+	`ALTER TABLE tasks ADD COLUMN version INTEGER NOT NULL DEFAULT 1;`,
+
+	`CREATE TABLE audit_log (
+		id          TEXT PRIMARY KEY,
+		actor_id    TEXT NOT NULL,
+		actor_role  TEXT NOT NULL,
+		action      TEXT NOT NULL,
+		target_type TEXT NOT NULL,
+		target_id   TEXT NOT NULL,
+		changes     TEXT NOT NULL,
+		created_at  TEXT NOT NULL
+	);
+	CREATE INDEX idx_audit_target ON audit_log (target_type, target_id, created_at);
+	CREATE INDEX idx_audit_actor ON audit_log (actor_id, created_at);
+	CREATE INDEX idx_audit_created ON audit_log (created_at);`,
+
+	`ALTER TABLE tasks ADD COLUMN deleted_at TEXT;
+	CREATE INDEX idx_tasks_deleted ON tasks (deleted_at);`,
+
+	`CREATE TABLE webhooks (
+		id         TEXT PRIMARY KEY,
+		url        TEXT NOT NULL,
+		events     TEXT NOT NULL,
+		secret     TEXT NOT NULL,
+		created_by TEXT NOT NULL,
+		created_at TEXT NOT NULL
+	);
+
+	CREATE TABLE webhook_deliveries (
+		id              TEXT PRIMARY KEY,
+		webhook_id      TEXT NOT NULL,
+		event_id        INTEGER NOT NULL,
+		event_type      TEXT NOT NULL,
+		payload         TEXT NOT NULL,
+		status          TEXT NOT NULL,
+		attempts        INTEGER NOT NULL DEFAULT 0,
+		response_status INTEGER NOT NULL DEFAULT 0,
+		last_error      TEXT NOT NULL DEFAULT '',
+		next_attempt_at TEXT NOT NULL,
+		created_at      TEXT NOT NULL,
+		updated_at      TEXT NOT NULL
+	);
+	CREATE INDEX idx_webhook_deliveries_due ON webhook_deliveries (status, next_attempt_at);
+	CREATE INDEX idx_webhook_deliveries_webhook ON webhook_deliveries (webhook_id, created_at);`,
+
+	`CREATE TABLE reminders (
+		id       TEXT PRIMARY KEY,
+		task_id  TEXT NOT NULL,
+		type     TEXT NOT NULL,
+		due_date TEXT NOT NULL,
+		sent_at  TEXT NOT NULL
+	);
+	CREATE INDEX idx_reminders_due ON reminders (due_date);`,
+
+	`ALTER TABLE tasks ADD COLUMN recurrence TEXT NOT NULL DEFAULT '';`,
+
+	`CREATE TABLE comments (
+		id         TEXT PRIMARY KEY,
+		task_id    TEXT NOT NULL,
+		author_id  TEXT NOT NULL,
+		body       TEXT NOT NULL,
+		mentions   TEXT NOT NULL DEFAULT '[]',
+		edited_at  TEXT,
+		created_at TEXT NOT NULL
+	);
+	CREATE INDEX idx_comments_task ON comments (task_id, created_at);`,
+
+	`CREATE TABLE attachments (
+		id           TEXT PRIMARY KEY,
+		task_id      TEXT NOT NULL,
+		uploader_id  TEXT NOT NULL,
+		filename     TEXT NOT NULL,
+		content_type TEXT NOT NULL,
+		size         INTEGER NOT NULL,
+		sha256       TEXT NOT NULL,
+		blob_key     TEXT NOT NULL,
+		created_at   TEXT NOT NULL
+	);
+	CREATE INDEX idx_attachments_task ON attachments (task_id, created_at);`,
+
+	`CREATE TABLE projects (
+		id          TEXT PRIMARY KEY,
+		name        TEXT NOT NULL,
+		description TEXT NOT NULL DEFAULT '',
+		members     TEXT NOT NULL DEFAULT '[]',
+		archived_at TEXT,
+		created_by  TEXT NOT NULL,
+		created_at  TEXT NOT NULL
+	);
+
+	ALTER TABLE tasks ADD COLUMN project_id TEXT NOT NULL DEFAULT 'default';
+	CREATE INDEX idx_tasks_project_due ON tasks (project_id, due_date);`,
+
+	`CREATE TABLE rate_limits (
+		key        TEXT PRIMARY KEY,
+		tokens     REAL NOT NULL,
+		updated_at TEXT NOT NULL,
+		expires_at TEXT NOT NULL
+	);
+	CREATE INDEX idx_rate_limits_expires ON rate_limits (expires_at);
+
+	CREATE TABLE login_failures (
+		key          TEXT PRIMARY KEY,
+		failures     INTEGER NOT NULL,
+		locked_until TEXT,
+		expires_at   TEXT NOT NULL
+	);
+	CREATE INDEX idx_login_failures_expires ON login_failures (expires_at);`,
+}
+
+// OpenSQLite opens the SQLite database at path and brings its schema up to date.
+// Use ":memory:" for a throwaway database.
+func OpenSQLite(path string) (*sql.DB, error) {
+	db, err := sql.Open("sqlite", path+"?_pragma=busy_timeout(5000)&_pragma=foreign_keys(1)")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open SQLite: %w", err)
+	}
+	// A single connection serializes writers and keeps ":memory:" databases shared
+	db.SetMaxOpenConns(1)
+
+	if err := migrateSQLite(db); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+func migrateSQLite(db *sql.DB) error {
+	var version int
+	if err := db.QueryRow("PRAGMA user_version").Scan(&version); err != nil {
+		return fmt.Errorf("failed to read schema version: %w", err)
+	}
+
+	for i := version; i < len(sqliteMigrations); i++ {
+		tx, err := db.Begin()
+		if err != nil {
+			return fmt.Errorf("failed to start migration: %w", err)
+		}
+		if _, err := tx.Exec(sqliteMigrations[i]); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed to apply migration %d: %w", i+1, err)
+		}
+		if _, err := tx.Exec(fmt.Sprintf("PRAGMA user_version = %d", i+1)); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed to record migration %d: %w", i+1, err)
+		}
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("failed to commit migration %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+func formatSQLiteTime(t time.Time) string {
+	return t.UTC().Format(sqliteTimeLayout)
+}
+
+func parseSQLiteTime(s string) (time.Time, error) {
+	return time.Parse(sqliteTimeLayout, s)
+}
+
+// escapeLike escapes the LIKE wildcards in s for use with ESCAPE '\'.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
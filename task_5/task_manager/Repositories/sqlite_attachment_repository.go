@@ -0,0 +1,132 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	domain "task_manager/Domain"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const sqliteAttachmentColumns = "id, task_id, uploader_id, filename, content_type, size, sha256, blob_key, created_at"
+
+// SQLiteAttachmentRepository implements IAttachmentRepository using SQLite.
+type SQLiteAttachmentRepository struct {
+	db       *sql.DB
+	timeouts Timeouts
+}
+
+func NewSQLiteAttachmentRepository(db *sql.DB, timeouts Timeouts) IAttachmentRepository {
+	return &SQLiteAttachmentRepository{db: db, timeouts: timeouts}
+}
+
+func (r *SQLiteAttachmentRepository) Close() error {
+	return r.db.Close()
+}
+
+func (r *SQLiteAttachmentRepository) Create(ctx context.Context, a domain.Attachment) (domain.Attachment, error) {
+	ctx, cancel := r.timeouts.withTimeout(ctx, "attachment.create")
+	defer cancel()
+
+	a.ID = primitive.NewObjectID().Hex()
+	// Counting in the insert itself keeps concurrent uploads from both
+	// taking the last place
+	result, err := r.db.ExecContext(ctx, `INSERT INTO attachments (`+sqliteAttachmentColumns+`)
+		SELECT ?, ?, ?, ?, ?, ?, ?, ?, ?
+		WHERE (SELECT COUNT(*) FROM attachments WHERE task_id = ?) < ?`,
+		a.ID, a.TaskID, a.UploaderID, a.Filename, a.ContentType, a.Size, a.SHA256, a.BlobKey, formatSQLiteTime(a.CreatedAt),
+		a.TaskID, domain.MaxAttachmentsPerTask)
+	if err != nil {
+		return domain.Attachment{}, fmt.Errorf("failed to insert attachment: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return domain.Attachment{}, ErrTooManyAttachments
+	}
+	return a, nil
+}
+
+func (r *SQLiteAttachmentRepository) GetByID(ctx context.Context, id string) (domain.Attachment, error) {
+	ctx, cancel := r.timeouts.withTimeout(ctx, "attachment.get")
+	defer cancel()
+
+	attachments, err := r.query(ctx, "SELECT "+sqliteAttachmentColumns+" FROM attachments WHERE id = ?", id)
+	if err != nil {
+		return domain.Attachment{}, err
+	}
+	if len(attachments) == 0 {
+		return domain.Attachment{}, ErrAttachmentNotFound
+	}
+	return attachments[0], nil
+}
+
+func (r *SQLiteAttachmentRepository) ListByTask(ctx context.Context, taskID string) ([]domain.Attachment, error) {
+	ctx, cancel := r.timeouts.withTimeout(ctx, "attachment.list")
+	defer cancel()
+
+	return r.query(ctx, "SELECT "+sqliteAttachmentColumns+" FROM attachments WHERE task_id = ? ORDER BY created_at, id", taskID)
+}
+
+func (r *SQLiteAttachmentRepository) Delete(ctx context.Context, id string) error {
+	ctx, cancel := r.timeouts.withTimeout(ctx, "attachment.delete")
+	defer cancel()
+
+	result, err := r.db.ExecContext(ctx, `DELETE FROM attachments WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete attachment: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return ErrAttachmentNotFound
+	}
+	return nil
+}
+
+func (r *SQLiteAttachmentRepository) TaskIDs(ctx context.Context) ([]string, error) {
+	ctx, cancel := r.timeouts.withTimeout(ctx, "attachment.task_ids")
+	defer cancel()
+
+	rows, err := r.db.QueryContext(ctx, "SELECT DISTINCT task_id FROM attachments ORDER BY task_id")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list attachment tasks: %w", err)
+	}
+	defer rows.Close()
+
+	ids := []string{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to list attachment tasks: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list attachment tasks: %w", err)
+	}
+	return ids, nil
+}
+
+func (r *SQLiteAttachmentRepository) query(ctx context.Context, stmt string, args ...interface{}) ([]domain.Attachment, error) {
+	rows, err := r.db.QueryContext(ctx, stmt, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find attachments: %w", err)
+	}
+	defer rows.Close()
+
+	attachments := []domain.Attachment{}
+	for rows.Next() {
+		var a domain.Attachment
+		var created string
+		if err := rows.Scan(&a.ID, &a.TaskID, &a.UploaderID, &a.Filename, &a.ContentType, &a.Size, &a.SHA256, &a.BlobKey, &created); err != nil {
+			return nil, fmt.Errorf("failed to decode attachments: %w", err)
+		}
+		if a.CreatedAt, err = parseSQLiteTime(created); err != nil {
+			return nil, fmt.Errorf("failed to decode attachments: %w", err)
+		}
+		attachments = append(attachments, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to decode attachments: %w", err)
+	}
+	return attachments, nil
+}
@@ -0,0 +1,108 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	domain "task_manager/Domain"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// SQLiteAuditRepository implements IAuditRepository using SQLite.
+type SQLiteAuditRepository struct {
+	db       *sql.DB
+	timeouts Timeouts
+}
+
+func NewSQLiteAuditRepository(db *sql.DB, timeouts Timeouts) IAuditRepository {
+	return &SQLiteAuditRepository{db: db, timeouts: timeouts}
+}
+
+func (r *SQLiteAuditRepository) Close() error {
+	return r.db.Close()
+}
+
+func (r *SQLiteAuditRepository) Append(ctx context.Context, entry domain.AuditEntry) error {
+	ctx, cancel := r.timeouts.withTimeout(ctx, "audit.append")
+	defer cancel()
+
+	changes, err := json.Marshal(entry.Changes)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit changes: %w", err)
+	}
+	_, err = r.db.ExecContext(ctx, `INSERT INTO audit_log (id, actor_id, actor_role, action, target_type, target_id, changes, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		primitive.NewObjectID().Hex(), entry.ActorID, entry.ActorRole, entry.Action, entry.TargetType, entry.TargetID,
+		string(changes), formatSQLiteTime(entry.CreatedAt))
+	if err != nil {
+		return fmt.Errorf("failed to append audit entry: %w", err)
+	}
+	return nil
+}
+
+func (r *SQLiteAuditRepository) List(ctx context.Context, q domain.AuditQuery) (domain.AuditPage, error) {
+	ctx, cancel := r.timeouts.withTimeout(ctx, "audit.list")
+	defer cancel()
+
+	var where []string
+	var args []interface{}
+	for column, value := range map[string]string{
+		"actor_id":    q.ActorID,
+		"action":      q.Action,
+		"target_type": q.TargetType,
+		"target_id":   q.TargetID,
+	} {
+		if value != "" {
+			where = append(where, column+" = ?")
+			args = append(args, value)
+		}
+	}
+	if !q.Since.IsZero() {
+		where = append(where, "created_at >= ?")
+		args = append(args, formatSQLiteTime(q.Since))
+	}
+	if !q.Until.IsZero() {
+		where = append(where, "created_at <= ?")
+		args = append(args, formatSQLiteTime(q.Until))
+	}
+	clause := ""
+	if len(where) > 0 {
+		clause = " WHERE " + strings.Join(where, " AND ")
+	}
+
+	var total int64
+	if err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM audit_log"+clause, args...).Scan(&total); err != nil {
+		return domain.AuditPage{}, fmt.Errorf("failed to count audit entries: %w", err)
+	}
+
+	rows, err := r.db.QueryContext(ctx, `SELECT id, actor_id, actor_role, action, target_type, target_id, changes, created_at
+		FROM audit_log`+clause+` ORDER BY created_at DESC, id DESC LIMIT ? OFFSET ?`, append(args, q.PageSize, q.Skip())...)
+	if err != nil {
+		return domain.AuditPage{}, fmt.Errorf("failed to find audit entries: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []domain.AuditEntry{}
+	for rows.Next() {
+		var e domain.AuditEntry
+		var changes, created string
+		if err := rows.Scan(&e.ID, &e.ActorID, &e.ActorRole, &e.Action, &e.TargetType, &e.TargetID, &changes, &created); err != nil {
+			return domain.AuditPage{}, fmt.Errorf("failed to decode audit entries: %w", err)
+		}
+		if err := json.Unmarshal([]byte(changes), &e.Changes); err != nil {
+			return domain.AuditPage{}, fmt.Errorf("failed to decode audit entries: %w", err)
+		}
+		if e.CreatedAt, err = parseSQLiteTime(created); err != nil {
+			return domain.AuditPage{}, fmt.Errorf("failed to decode audit entries: %w", err)
+		}
+		entries = append(entries, e)
+	}
+	if err := rows.Err(); err != nil {
+		return domain.AuditPage{}, fmt.Errorf("failed to decode audit entries: %w", err)
+	}
+	return domain.NewAuditPage(q, entries, total), nil
+}
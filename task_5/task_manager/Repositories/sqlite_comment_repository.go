@@ -0,0 +1,165 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	domain "task_manager/Domain"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const sqliteCommentColumns = "id, task_id, author_id, body, mentions, edited_at, created_at"
+
+// SQLiteCommentRepository implements ICommentRepository using SQLite.
+type SQLiteCommentRepository struct {
+	db       *sql.DB
+	timeouts Timeouts
+}
+
+func NewSQLiteCommentRepository(db *sql.DB, timeouts Timeouts) ICommentRepository {
+	return &SQLiteCommentRepository{db: db, timeouts: timeouts}
+}
+
+func (r *SQLiteCommentRepository) Close() error {
+	return r.db.Close()
+}
+
+func (r *SQLiteCommentRepository) Create(ctx context.Context, c domain.Comment) (domain.Comment, error) {
+	ctx, cancel := r.timeouts.withTimeout(ctx, "comment.create")
+	defer cancel()
+
+	c.ID = primitive.NewObjectID().Hex()
+	mentions, err := sqliteMentions(c.Mentions)
+	if err != nil {
+		return domain.Comment{}, err
+	}
+	_, err = r.db.ExecContext(ctx, `INSERT INTO comments (`+sqliteCommentColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		c.ID, c.TaskID, c.AuthorID, c.Body, mentions, sqliteNullTime(c.EditedAt), formatSQLiteTime(c.CreatedAt))
+	if err != nil {
+		return domain.Comment{}, fmt.Errorf("failed to insert comment: %w", err)
+	}
+	return c, nil
+}
+
+func (r *SQLiteCommentRepository) GetByID(ctx context.Context, id string) (domain.Comment, error) {
+	ctx, cancel := r.timeouts.withTimeout(ctx, "comment.get")
+	defer cancel()
+
+	comments, err := r.query(ctx, "SELECT "+sqliteCommentColumns+" FROM comments WHERE id = ?", id)
+	if err != nil {
+		return domain.Comment{}, err
+	}
+	if len(comments) == 0 {
+		return domain.Comment{}, ErrCommentNotFound
+	}
+	return comments[0], nil
+}
+
+func (r *SQLiteCommentRepository) Update(ctx context.Context, c domain.Comment) error {
+	ctx, cancel := r.timeouts.withTimeout(ctx, "comment.update")
+	defer cancel()
+
+	mentions, err := sqliteMentions(c.Mentions)
+	if err != nil {
+		return err
+	}
+	result, err := r.db.ExecContext(ctx, `UPDATE comments SET body = ?, mentions = ?, edited_at = ? WHERE id = ?`,
+		c.Body, mentions, sqliteNullTime(c.EditedAt), c.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update comment: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return ErrCommentNotFound
+	}
+	return nil
+}
+
+func (r *SQLiteCommentRepository) Delete(ctx context.Context, id string) error {
+	ctx, cancel := r.timeouts.withTimeout(ctx, "comment.delete")
+	defer cancel()
+
+	result, err := r.db.ExecContext(ctx, `DELETE FROM comments WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete comment: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return ErrCommentNotFound
+	}
+	return nil
+}
+
+func (r *SQLiteCommentRepository) List(ctx context.Context, q domain.CommentQuery) (domain.CommentPage, error) {
+	ctx, cancel := r.timeouts.withTimeout(ctx, "comment.list")
+	defer cancel()
+
+	var total int64
+	if err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM comments WHERE task_id = ?", q.TaskID).Scan(&total); err != nil {
+		return domain.CommentPage{}, fmt.Errorf("failed to count comments: %w", err)
+	}
+
+	comments, err := r.query(ctx, "SELECT "+sqliteCommentColumns+" FROM comments WHERE task_id = ? ORDER BY created_at, id LIMIT ? OFFSET ?",
+		q.TaskID, q.PageSize, q.Skip())
+	if err != nil {
+		return domain.CommentPage{}, err
+	}
+	return domain.NewCommentPage(q, comments, total), nil
+}
+
+func (r *SQLiteCommentRepository) query(ctx context.Context, stmt string, args ...interface{}) ([]domain.Comment, error) {
+	rows, err := r.db.QueryContext(ctx, stmt, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find comments: %w", err)
+	}
+	defer rows.Close()
+
+	comments := []domain.Comment{}
+	for rows.Next() {
+		var c domain.Comment
+		var mentions, created string
+		var editedAt sql.NullString
+		if err := rows.Scan(&c.ID, &c.TaskID, &c.AuthorID, &c.Body, &mentions, &editedAt, &created); err != nil {
+			return nil, fmt.Errorf("failed to decode comments: %w", err)
+		}
+		if err := json.Unmarshal([]byte(mentions), &c.Mentions); err != nil {
+			return nil, fmt.Errorf("failed to decode comments: %w", err)
+		}
+		if editedAt.Valid {
+			at, err := parseSQLiteTime(editedAt.String)
+			if err != nil {
+				return nil, fmt.Errorf("failed to decode comments: %w", err)
+			}
+			c.EditedAt = &at
+		}
+		if c.CreatedAt, err = parseSQLiteTime(created); err != nil {
+			return nil, fmt.Errorf("failed to decode comments: %w", err)
+		}
+		comments = append(comments, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to decode comments: %w", err)
+	}
+	return comments, nil
+}
+
+func sqliteMentions(mentions []domain.Mention) (string, error) {
+	if mentions == nil {
+		mentions = []domain.Mention{}
+	}
+	encoded, err := json.Marshal(mentions)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode mentions: %w", err)
+	}
+	return string(encoded), nil
+}
+
+// sqliteNullTime stores an unset time as NULL.
+func sqliteNullTime(t *time.Time) interface{} {
+	if t == nil {
+		return nil
+	}
+	return formatSQLiteTime(*t)
+}
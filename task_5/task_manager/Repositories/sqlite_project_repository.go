@@ -0,0 +1,138 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	domain "task_manager/Domain"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const sqliteProjectColumns = "id, name, description, members, archived_at, created_by, created_at"
+
+// SQLiteProjectRepository implements IProjectRepository using SQLite.
+// Members are stored as a JSON array on the project.
+type SQLiteProjectRepository struct {
+	db       *sql.DB
+	timeouts Timeouts
+}
+
+func NewSQLiteProjectRepository(db *sql.DB, timeouts Timeouts) IProjectRepository {
+	return &SQLiteProjectRepository{db: db, timeouts: timeouts}
+}
+
+func (r *SQLiteProjectRepository) Close() error {
+	return r.db.Close()
+}
+
+func (r *SQLiteProjectRepository) Create(ctx context.Context, p domain.Project) (domain.Project, error) {
+	ctx, cancel := r.timeouts.withTimeout(ctx, "project.create")
+	defer cancel()
+
+	p.ID = primitive.NewObjectID().Hex()
+	members, err := sqliteProjectMembers(p.Members)
+	if err != nil {
+		return domain.Project{}, err
+	}
+	_, err = r.db.ExecContext(ctx, `INSERT INTO projects (`+sqliteProjectColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		p.ID, p.Name, p.Description, members, sqliteNullTime(p.ArchivedAt), p.CreatedBy, formatSQLiteTime(p.CreatedAt))
+	if err != nil {
+		return domain.Project{}, fmt.Errorf("failed to insert project: %w", err)
+	}
+	return p, nil
+}
+
+func (r *SQLiteProjectRepository) GetByID(ctx context.Context, id string) (domain.Project, error) {
+	ctx, cancel := r.timeouts.withTimeout(ctx, "project.get")
+	defer cancel()
+
+	projects, err := r.query(ctx, "SELECT "+sqliteProjectColumns+" FROM projects WHERE id = ?", id)
+	if err != nil {
+		return domain.Project{}, err
+	}
+	if len(projects) == 0 {
+		return domain.Project{}, ErrProjectNotFound
+	}
+	return projects[0], nil
+}
+
+func (r *SQLiteProjectRepository) List(ctx context.Context, userID string) ([]domain.Project, error) {
+	ctx, cancel := r.timeouts.withTimeout(ctx, "project.list")
+	defer cancel()
+
+	if userID == "" {
+		return r.query(ctx, "SELECT "+sqliteProjectColumns+" FROM projects ORDER BY created_at, id")
+	}
+	return r.query(ctx, "SELECT "+sqliteProjectColumns+` FROM projects
+		WHERE EXISTS (SELECT 1 FROM json_each(members) WHERE json_extract(value, '$.user_id') = ?)
+		ORDER BY created_at, id`, userID)
+}
+
+func (r *SQLiteProjectRepository) Update(ctx context.Context, p domain.Project) error {
+	ctx, cancel := r.timeouts.withTimeout(ctx, "project.update")
+	defer cancel()
+
+	members, err := sqliteProjectMembers(p.Members)
+	if err != nil {
+		return err
+	}
+	result, err := r.db.ExecContext(ctx, `UPDATE projects SET name = ?, description = ?, members = ?, archived_at = ? WHERE id = ?`,
+		p.Name, p.Description, members, sqliteNullTime(p.ArchivedAt), p.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update project: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return ErrProjectNotFound
+	}
+	return nil
+}
+
+func (r *SQLiteProjectRepository) query(ctx context.Context, stmt string, args ...interface{}) ([]domain.Project, error) {
+	rows, err := r.db.QueryContext(ctx, stmt, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find projects: %w", err)
+	}
+	defer rows.Close()
+
+	projects := []domain.Project{}
+	for rows.Next() {
+		var p domain.Project
+		var members, created string
+		var archivedAt sql.NullString
+		if err := rows.Scan(&p.ID, &p.Name, &p.Description, &members, &archivedAt, &p.CreatedBy, &created); err != nil {
+			return nil, fmt.Errorf("failed to decode projects: %w", err)
+		}
+		if err := json.Unmarshal([]byte(members), &p.Members); err != nil {
+			return nil, fmt.Errorf("failed to decode projects: %w", err)
+		}
+		if archivedAt.Valid {
+			at, err := parseSQLiteTime(archivedAt.String)
+			if err != nil {
+				return nil, fmt.Errorf("failed to decode projects: %w", err)
+			}
+			p.ArchivedAt = &at
+		}
+		if p.CreatedAt, err = parseSQLiteTime(created); err != nil {
+			return nil, fmt.Errorf("failed to decode projects: %w", err)
+		}
+		projects = append(projects, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to decode projects: %w", err)
+	}
+	return projects, nil
+}
+
+func sqliteProjectMembers(members []domain.ProjectMember) (string, error) {
+	if members == nil {
+		members = []domain.ProjectMember{}
+	}
+	encoded, err := json.Marshal(members)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode members: %w", err)
+	}
+	return string(encoded), nil
+}
@@ -0,0 +1,131 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	domain "task_manager/Domain"
+)
+
+// SQLiteRateLimitStore implements IRateLimitStore using SQLite.
+type SQLiteRateLimitStore struct {
+	db       *sql.DB
+	timeouts Timeouts
+}
+
+func NewSQLiteRateLimitStore(db *sql.DB, timeouts Timeouts) IRateLimitStore {
+	return &SQLiteRateLimitStore{db: db, timeouts: timeouts}
+}
+
+func (s *SQLiteRateLimitStore) Close() error {
+	return s.db.Close()
+}
+
+func (s *SQLiteRateLimitStore) Take(ctx context.Context, key string, limit domain.RateLimit, now time.Time) (time.Duration, error) {
+	ctx, cancel := s.timeouts.withTimeout(ctx, "rate_limit.take")
+	defer cancel()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to take rate limit token: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM rate_limits WHERE expires_at <= ?", formatSQLiteTime(now)); err != nil {
+		return 0, fmt.Errorf("failed to prune rate limits: %w", err)
+	}
+	var b domain.TokenBucket
+	var updated string
+	err = tx.QueryRowContext(ctx, "SELECT tokens, updated_at FROM rate_limits WHERE key = ?", key).Scan(&b.Tokens, &updated)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+	case err != nil:
+		return 0, fmt.Errorf("failed to find rate limit: %w", err)
+	default:
+		if b.UpdatedAt, err = parseSQLiteTime(updated); err != nil {
+			return 0, fmt.Errorf("failed to decode rate limit: %w", err)
+		}
+	}
+
+	wait := limit.Take(&b, now)
+	_, err = tx.ExecContext(ctx, `INSERT INTO rate_limits (key, tokens, updated_at, expires_at) VALUES (?, ?, ?, ?)
+		ON CONFLICT (key) DO UPDATE SET tokens = excluded.tokens, updated_at = excluded.updated_at, expires_at = excluded.expires_at`,
+		key, b.Tokens, formatSQLiteTime(b.UpdatedAt), formatSQLiteTime(now.Add(limit.Period)))
+	if err != nil {
+		return 0, fmt.Errorf("failed to save rate limit: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to save rate limit: %w", err)
+	}
+	return wait, nil
+}
+
+func (s *SQLiteRateLimitStore) Attempt(ctx context.Context, key string, policy domain.LockoutPolicy, now time.Time) (time.Duration, error) {
+	ctx, cancel := s.timeouts.withTimeout(ctx, "rate_limit.attempt")
+	defer cancel()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to record login attempt: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM login_failures WHERE expires_at <= ?", formatSQLiteTime(now)); err != nil {
+		return 0, fmt.Errorf("failed to prune login failures: %w", err)
+	}
+	lockout, err := scanSQLiteLockout(tx.QueryRowContext(ctx, "SELECT failures, locked_until FROM login_failures WHERE key = ?", key))
+	if err != nil {
+		return 0, err
+	}
+
+	wait, expiresAt := policy.Attempt(&lockout, now)
+	if wait > 0 {
+		return wait, nil
+	}
+	var lockedUntil *time.Time
+	if !lockout.LockedUntil.IsZero() {
+		lockedUntil = &lockout.LockedUntil
+	}
+	_, err = tx.ExecContext(ctx, `INSERT INTO login_failures (key, failures, locked_until, expires_at) VALUES (?, ?, ?, ?)
+		ON CONFLICT (key) DO UPDATE SET failures = excluded.failures, locked_until = excluded.locked_until, expires_at = excluded.expires_at`,
+		key, lockout.Failures, sqliteNullTime(lockedUntil), formatSQLiteTime(expiresAt))
+	if err != nil {
+		return 0, fmt.Errorf("failed to record login attempt: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to record login attempt: %w", err)
+	}
+	return 0, nil
+}
+
+func (s *SQLiteRateLimitStore) ResetFailures(ctx context.Context, key string) error {
+	ctx, cancel := s.timeouts.withTimeout(ctx, "rate_limit.reset_failures")
+	defer cancel()
+
+	if _, err := s.db.ExecContext(ctx, "DELETE FROM login_failures WHERE key = ?", key); err != nil {
+		return fmt.Errorf("failed to reset login failures: %w", err)
+	}
+	return nil
+}
+
+// scanSQLiteLockout reads a failed login record, returning the zero Lockout when there is none.
+func scanSQLiteLockout(row *sql.Row) (domain.Lockout, error) {
+	var l domain.Lockout
+	var lockedUntil sql.NullString
+	err := row.Scan(&l.Failures, &lockedUntil)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.Lockout{}, nil
+	}
+	if err != nil {
+		return domain.Lockout{}, fmt.Errorf("failed to find login failures: %w", err)
+	}
+	if lockedUntil.Valid {
+		if l.LockedUntil, err = parseSQLiteTime(lockedUntil.String); err != nil {
+			return domain.Lockout{}, fmt.Errorf("failed to decode login failures: %w", err)
+		}
+	}
+	return l, nil
+}
@@ -0,0 +1,66 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	domain "task_manager/Domain"
+)
+
+// SQLiteReminderRepository implements IReminderRepository using SQLite.
+type SQLiteReminderRepository struct {
+	db       *sql.DB
+	timeouts Timeouts
+}
+
+func NewSQLiteReminderRepository(db *sql.DB, timeouts Timeouts) IReminderRepository {
+	return &SQLiteReminderRepository{db: db, timeouts: timeouts}
+}
+
+func (r *SQLiteReminderRepository) Close() error {
+	return r.db.Close()
+}
+
+func (r *SQLiteReminderRepository) Claim(ctx context.Context, reminder domain.Reminder) (bool, error) {
+	ctx, cancel := r.timeouts.withTimeout(ctx, "reminder.claim")
+	defer cancel()
+
+	result, err := r.db.ExecContext(ctx, `INSERT INTO reminders (id, task_id, type, due_date, sent_at)
+		VALUES (?, ?, ?, ?, ?) ON CONFLICT (id) DO NOTHING`,
+		reminder.ID, reminder.TaskID, reminder.Type, formatSQLiteTime(reminder.DueDate), formatSQLiteTime(reminder.SentAt))
+	if err != nil {
+		return false, fmt.Errorf("failed to claim reminder: %w", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to claim reminder: %w", err)
+	}
+	return n == 1, nil
+}
+
+func (r *SQLiteReminderRepository) Release(ctx context.Context, id string) error {
+	ctx, cancel := r.timeouts.withTimeout(ctx, "reminder.release")
+	defer cancel()
+
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM reminders WHERE id = ?`, id); err != nil {
+		return fmt.Errorf("failed to release reminder: %w", err)
+	}
+	return nil
+}
+
+func (r *SQLiteReminderRepository) DeleteDueBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	ctx, cancel := r.timeouts.withTimeout(ctx, "reminder.delete_due_before")
+	defer cancel()
+
+	result, err := r.db.ExecContext(ctx, `DELETE FROM reminders WHERE due_date < ?`, formatSQLiteTime(cutoff))
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete reminders: %w", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete reminders: %w", err)
+	}
+	return n, nil
+}
@@ -0,0 +1,100 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	domain "task_manager/Domain"
+)
+
+// SQLiteRoleRepository implements IRoleRepository using SQLite.
+type SQLiteRoleRepository struct {
+	db       *sql.DB
+	timeouts Timeouts
+}
+
+func NewSQLiteRoleRepository(db *sql.DB, timeouts Timeouts) IRoleRepository {
+	return &SQLiteRoleRepository{db: db, timeouts: timeouts}
+}
+
+func (r *SQLiteRoleRepository) Close() error {
+	return r.db.Close()
+}
+
+func (r *SQLiteRoleRepository) GetByName(ctx context.Context, name string) (domain.Role, error) {
+	ctx, cancel := r.timeouts.withTimeout(ctx, "role.get_by_name")
+	defer cancel()
+
+	roles, err := r.query(ctx, "SELECT name, permissions FROM roles WHERE name = ?", name)
+	if err != nil {
+		return domain.Role{}, err
+	}
+	if len(roles) == 0 {
+		return domain.Role{}, ErrRoleNotFound
+	}
+	return roles[0], nil
+}
+
+func (r *SQLiteRoleRepository) List(ctx context.Context) ([]domain.Role, error) {
+	ctx, cancel := r.timeouts.withTimeout(ctx, "role.list")
+	defer cancel()
+
+	return r.query(ctx, "SELECT name, permissions FROM roles ORDER BY name")
+}
+
+func (r *SQLiteRoleRepository) Save(ctx context.Context, role domain.Role) error {
+	ctx, cancel := r.timeouts.withTimeout(ctx, "role.save")
+	defer cancel()
+
+	perms, err := json.Marshal(role.Permissions)
+	if err != nil {
+		return fmt.Errorf("failed to encode permissions: %w", err)
+	}
+	_, err = r.db.ExecContext(ctx, "INSERT INTO roles (name, permissions) VALUES (?, ?) ON CONFLICT (name) DO UPDATE SET permissions = excluded.permissions",
+		role.Name, string(perms))
+	if err != nil {
+		return fmt.Errorf("failed to save role: %w", err)
+	}
+	return nil
+}
+
+func (r *SQLiteRoleRepository) Delete(ctx context.Context, name string) error {
+	ctx, cancel := r.timeouts.withTimeout(ctx, "role.delete")
+	defer cancel()
+
+	res, err := r.db.ExecContext(ctx, "DELETE FROM roles WHERE name = ?", name)
+	if err != nil {
+		return fmt.Errorf("failed to delete role: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrRoleNotFound
+	}
+	return nil
+}
+
+func (r *SQLiteRoleRepository) query(ctx context.Context, stmt string, args ...interface{}) ([]domain.Role, error) {
+	rows, err := r.db.QueryContext(ctx, stmt, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find roles: %w", err)
+	}
+	defer rows.Close()
+
+	roles := []domain.Role{}
+	for rows.Next() {
+		var role domain.Role
+		var perms string
+		if err := rows.Scan(&role.Name, &perms); err != nil {
+			return nil, fmt.Errorf("failed to decode roles: %w", err)
+		}
+		if err := json.Unmarshal([]byte(perms), &role.Permissions); err != nil {
+			return nil, fmt.Errorf("failed to decode roles: %w", err)
+		}
+		roles = append(roles, role)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to decode roles: %w", err)
+	}
+	return roles, nil
+}
@@ -0,0 +1,533 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	domain "task_manager/Domain"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// SQLiteTaskRepository implements ITaskRepository using SQLite.
+// Searches use an in-process index built from the table by the first
+// search, so it only sees the writes made through this repository.
+type SQLiteTaskRepository struct {
+	db       *sql.DB
+	timeouts Timeouts
+	index    *searchIndex
+}
+
+func NewSQLiteTaskRepository(db *sql.DB, timeouts Timeouts) ITaskRepository {
+	return &SQLiteTaskRepository{db: db, timeouts: timeouts, index: newSearchIndex(false)}
+}
+
+const sqliteTaskColumns = "id, title, description, due_date, status, priority, tags, assignee_ids, parent_id, owner_id, version, recurrence, deleted_at, project_id"
+
+// sqliteLiveTask and sqliteDeletedTask select tasks outside and inside the trash.
+const (
+	sqliteLiveTask    = "deleted_at IS NULL"
+	sqliteDeletedTask = "deleted_at IS NOT NULL"
+)
+
+// sqliteTaskSortColumns maps TaskQuery sort fields to columns.
+var sqliteTaskSortColumns = map[string]string{
+	"due_date":   "due_date",
+	"title":      "title",
+	"status":     "status",
+	"deleted_at": "deleted_at",
+}
+
+func (r *SQLiteTaskRepository) List(ctx context.Context, q domain.TaskQuery) (domain.TaskPage, error) {
+	ctx, cancel := r.timeouts.withTimeout(ctx, "task.list")
+	defer cancel()
+
+	clause, args := sqliteTaskFilter(q)
+
+	var total int64
+	if err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM tasks"+clause, args...).Scan(&total); err != nil {
+		return domain.TaskPage{}, fmt.Errorf("failed to count tasks: %w", err)
+	}
+
+	stmt := "SELECT " + sqliteTaskColumns + " FROM tasks" + clause + sqliteTaskOrder(q) + " LIMIT ? OFFSET ?"
+	tasks, err := r.query(ctx, stmt, append(args, q.PageSize, q.Skip())...)
+	if err != nil {
+		return domain.TaskPage{}, err
+	}
+
+	return domain.NewTaskPage(q, tasks, total), nil
+}
+
+// Each reads the tasks one batch per query, each bounded by the task.each
+// timeout, so that the single connection is not held while fn runs. Each
+// batch starts after the sort key and ID of the last task read rather than
+// at an offset, so tasks written in between do not make it skip or repeat tasks.
+func (r *SQLiteTaskRepository) Each(ctx context.Context, q domain.TaskQuery, fn func(domain.Task) error) error {
+	clause, args := sqliteTaskFilter(q)
+	column, direction := sqliteTaskSort(q)
+	// The trash time of live tasks is NULL, which sorts like the empty string
+	key := "COALESCE(" + column + ", '')"
+	after := ">"
+	if direction == "DESC" {
+		after = "<"
+	}
+	order := fmt.Sprintf(" ORDER BY %s %s, id %s LIMIT ?", key, direction, direction)
+	stmt := "SELECT " + sqliteTaskColumns + " FROM tasks" + clause + order
+	batchArgs := append(args[:len(args):len(args)], taskBatchSize)
+	for {
+		batchCtx, cancel := r.timeouts.withTimeout(ctx, "task.each")
+		tasks, err := r.query(batchCtx, stmt, batchArgs...)
+		cancel()
+		if err != nil {
+			return err
+		}
+		for _, t := range tasks {
+			if err := fn(t); err != nil {
+				return err
+			}
+		}
+		if len(tasks) < taskBatchSize {
+			return nil
+		}
+		last := tasks[len(tasks)-1]
+		stmt = "SELECT " + sqliteTaskColumns + " FROM tasks" + clause + fmt.Sprintf(" AND (%s, id) %s (?, ?)", key, after) + order
+		batchArgs = append(args[:len(args):len(args)], sqliteTaskSortValue(column, last), last.ID, taskBatchSize)
+	}
+}
+
+// sqliteTaskSortValue returns the value of the sort column as stored for t,
+// with the empty string for NULL.
+func sqliteTaskSortValue(column string, t domain.Task) string {
+	switch column {
+	case "title":
+		return t.Title
+	case "status":
+		return t.Status
+	case "deleted_at":
+		if t.DeletedAt == nil {
+			return ""
+		}
+		return formatSQLiteTime(*t.DeletedAt)
+	default:
+		return formatSQLiteTime(t.DueDate)
+	}
+}
+
+// sqliteTaskFilter translates a task query into a WHERE clause and its arguments.
+func sqliteTaskFilter(q domain.TaskQuery) (string, []interface{}) {
+	where := []string{sqliteLiveTask}
+	if q.Deleted {
+		where[0] = sqliteDeletedTask
+	}
+	var args []interface{}
+	if q.OwnerID != "" {
+		where = append(where, "owner_id = ?")
+		args = append(args, q.OwnerID)
+	}
+	if q.ProjectID != "" {
+		where = append(where, "project_id = ?")
+		args = append(args, q.ProjectID)
+	}
+	if q.Status != "" {
+		where = append(where, "status = ?")
+		args = append(args, q.Status)
+	}
+	if q.ExcludeStatus != "" {
+		where = append(where, "status <> ?")
+		args = append(args, q.ExcludeStatus)
+	}
+	if !q.DueAfter.IsZero() {
+		where = append(where, "due_date >= ?")
+		args = append(args, formatSQLiteTime(q.DueAfter))
+	}
+	if !q.DueBefore.IsZero() {
+		where = append(where, "due_date <= ?")
+		args = append(args, formatSQLiteTime(q.DueBefore))
+	}
+	if q.Search != "" {
+		where = append(where, `title LIKE ? ESCAPE '\'`)
+		args = append(args, "%"+escapeLike(q.Search)+"%")
+	}
+	return " WHERE " + strings.Join(where, " AND "), args
+}
+
+// sqliteTaskOrder returns the ORDER BY clause of a task query.
+func sqliteTaskOrder(q domain.TaskQuery) string {
+	column, direction := sqliteTaskSort(q)
+	return fmt.Sprintf(" ORDER BY %s %s, id %s", column, direction, direction)
+}
+
+// sqliteTaskSort returns the column q sorts by and the direction.
+func sqliteTaskSort(q domain.TaskQuery) (column, direction string) {
+	column, ok := sqliteTaskSortColumns[q.SortBy]
+	if !ok {
+		column = "due_date"
+	}
+	direction = "ASC"
+	if q.SortDesc {
+		direction = "DESC"
+	}
+	return column, direction
+}
+
+// sqliteSearchBatch is how many candidates a search fetches per query.
+const sqliteSearchBatch = 500
+
+func (r *SQLiteTaskRepository) Search(ctx context.Context, s domain.TaskSearch) (domain.TaskSearchPage, error) {
+	ctx, cancel := r.timeouts.withTimeout(ctx, "task.search")
+	defer cancel()
+
+	ids, err := r.index.candidates(s, func() ([]domain.Task, error) {
+		return r.query(ctx, "SELECT "+sqliteTaskColumns+" FROM tasks")
+	})
+	if err != nil {
+		return domain.TaskSearchPage{}, err
+	}
+	var tasks []domain.Task
+	for len(ids) > 0 {
+		batch := ids[:min(len(ids), sqliteSearchBatch)]
+		ids = ids[len(batch):]
+		stmt := "SELECT " + sqliteTaskColumns + " FROM tasks WHERE " + sqliteLiveTask +
+			" AND id IN (?" + strings.Repeat(", ?", len(batch)-1) + ")"
+		args := make([]interface{}, 0, len(batch)+1)
+		for _, id := range batch {
+			args = append(args, id)
+		}
+		if s.OwnerID != "" {
+			stmt += " AND owner_id = ?"
+			args = append(args, s.OwnerID)
+		}
+		if s.ProjectID != "" {
+			stmt += " AND project_id = ?"
+			args = append(args, s.ProjectID)
+		}
+		found, err := r.query(ctx, stmt, args...)
+		if err != nil {
+			return domain.TaskSearchPage{}, err
+		}
+		tasks = append(tasks, found...)
+	}
+	return s.Rank(tasks), nil
+}
+
+func (r *SQLiteTaskRepository) GetChildren(ctx context.Context, parentID string) ([]domain.Task, error) {
+	ctx, cancel := r.timeouts.withTimeout(ctx, "task.get_children")
+	defer cancel()
+
+	return r.query(ctx, "SELECT "+sqliteTaskColumns+" FROM tasks WHERE "+sqliteLiveTask+" AND parent_id = ? ORDER BY id", parentID)
+}
+
+func (r *SQLiteTaskRepository) GetByID(ctx context.Context, id string) (domain.Task, error) {
+	ctx, cancel := r.timeouts.withTimeout(ctx, "task.get")
+	defer cancel()
+
+	return r.getOne(ctx, sqliteLiveTask, id)
+}
+
+func (r *SQLiteTaskRepository) GetDeleted(ctx context.Context, id string) (domain.Task, error) {
+	ctx, cancel := r.timeouts.withTimeout(ctx, "task.get_deleted")
+	defer cancel()
+
+	return r.getOne(ctx, sqliteDeletedTask, id)
+}
+
+// getOne returns the task with the ID if it also satisfies condition.
+func (r *SQLiteTaskRepository) getOne(ctx context.Context, condition, id string) (domain.Task, error) {
+	tasks, err := r.query(ctx, "SELECT "+sqliteTaskColumns+" FROM tasks WHERE "+condition+" AND id = ?", id)
+	if err != nil {
+		return domain.Task{}, err
+	}
+	if len(tasks) == 0 {
+		return domain.Task{}, ErrNotFound
+	}
+	return tasks[0], nil
+}
+
+func (r *SQLiteTaskRepository) Create(ctx context.Context, t domain.Task) (domain.Task, error) {
+	ctx, cancel := r.timeouts.withTimeout(ctx, "task.create")
+	defer cancel()
+
+	t, err := insertSQLiteTask(ctx, r.db, t)
+	if err == nil {
+		r.index.add(t)
+	}
+	return t, err
+}
+
+// sqliteExecer is implemented by *sql.DB and *sql.Tx.
+type sqliteExecer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+func insertSQLiteTask(ctx context.Context, db sqliteExecer, t domain.Task) (domain.Task, error) {
+	// Generate a new ID if not provided
+	if t.ID == "" {
+		t.ID = primitive.NewObjectID().Hex()
+	}
+	t.ProjectID = t.Project()
+	t.Version = 1
+
+	args, err := sqliteTaskArgs(t)
+	if err != nil {
+		return domain.Task{}, err
+	}
+	_, err = db.ExecContext(ctx, "INSERT INTO tasks ("+sqliteTaskColumns+") VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)", args...)
+	if err != nil {
+		return domain.Task{}, fmt.Errorf("failed to insert task: %w", err)
+	}
+
+	return t, nil
+}
+
+func (r *SQLiteTaskRepository) Update(ctx context.Context, id string, t domain.Task) (domain.Task, error) {
+	ctx, cancel := r.timeouts.withTimeout(ctx, "task.update")
+	defer cancel()
+
+	t, err := updateSQLiteTask(ctx, r.db, id, t)
+	if err == nil {
+		r.index.add(t)
+	}
+	return t, err
+}
+
+func updateSQLiteTask(ctx context.Context, db sqliteExecer, id string, t domain.Task) (domain.Task, error) {
+	t.ID = id
+	t.DeletedAt = nil
+	expected := t.Version
+	t.Version++
+	args, err := sqliteTaskArgs(t)
+	if err != nil {
+		return domain.Task{}, err
+	}
+	// Every column but the ID, deleted_at and the project, which the task keeps
+	values := args[1 : len(args)-2]
+	err = db.QueryRowContext(ctx, `UPDATE tasks SET title = ?, description = ?, due_date = ?, status = ?, priority = ?,
+		tags = ?, assignee_ids = ?, parent_id = ?, owner_id = ?, version = ?, recurrence = ? WHERE `+sqliteLiveTask+` AND id = ? AND version = ?
+		RETURNING project_id`,
+		append(values, id, expected)...).Scan(&t.ProjectID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.Task{}, missedSQLiteTask(ctx, db, id)
+	}
+	if err != nil {
+		return domain.Task{}, fmt.Errorf("failed to update task: %w", err)
+	}
+
+	return t, nil
+}
+
+func (r *SQLiteTaskRepository) Delete(ctx context.Context, id string) error {
+	ctx, cancel := r.timeouts.withTimeout(ctx, "task.delete")
+	defer cancel()
+
+	result, err := r.db.ExecContext(ctx, "UPDATE tasks SET deleted_at = ?, version = version + 1 WHERE "+sqliteLiveTask+" AND id = ?",
+		formatSQLiteTime(time.Now()), id)
+	if err != nil {
+		return fmt.Errorf("failed to delete task: %w", err)
+	}
+
+	if n, _ := result.RowsAffected(); n == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *SQLiteTaskRepository) Restore(ctx context.Context, id string) (domain.Task, error) {
+	ctx, cancel := r.timeouts.withTimeout(ctx, "task.restore")
+	defer cancel()
+
+	tasks, err := r.query(ctx, "UPDATE tasks SET deleted_at = NULL, version = version + 1 WHERE "+sqliteDeletedTask+" AND id = ? RETURNING "+sqliteTaskColumns, id)
+	if err != nil {
+		return domain.Task{}, fmt.Errorf("failed to restore task: %w", err)
+	}
+	if len(tasks) == 0 {
+		return domain.Task{}, ErrNotFound
+	}
+	return tasks[0], nil
+}
+
+func (r *SQLiteTaskRepository) Purge(ctx context.Context, id string) error {
+	ctx, cancel := r.timeouts.withTimeout(ctx, "task.purge")
+	defer cancel()
+
+	result, err := r.db.ExecContext(ctx, "DELETE FROM tasks WHERE "+sqliteDeletedTask+" AND id = ?", id)
+	if err != nil {
+		return fmt.Errorf("failed to purge task: %w", err)
+	}
+
+	if n, _ := result.RowsAffected(); n == 0 {
+		return ErrNotFound
+	}
+
+	r.index.remove(id)
+	return nil
+}
+
+func (r *SQLiteTaskRepository) PurgeDeletedBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	ctx, cancel := r.timeouts.withTimeout(ctx, "task.purge_deleted_before")
+	defer cancel()
+
+	result, err := r.db.ExecContext(ctx, "DELETE FROM tasks WHERE "+sqliteDeletedTask+" AND deleted_at < ?", formatSQLiteTime(cutoff))
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge tasks: %w", err)
+	}
+
+	n, _ := result.RowsAffected()
+	if n > 0 {
+		// Purged tasks are not known one by one, so the index is rebuilt
+		r.index.reset()
+	}
+	return n, nil
+}
+
+func (r *SQLiteTaskRepository) BulkWrite(ctx context.Context, writes []TaskWrite, atomic bool) ([]TaskWriteResult, error) {
+	ctx, cancel := r.timeouts.withTimeout(ctx, "task.bulk_write")
+	defer cancel()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// A failed statement changes nothing, so independent writes share the transaction
+	results := make([]TaskWriteResult, len(writes))
+	for i, w := range writes {
+		var err error
+		switch w.Op {
+		case WriteCreate:
+			results[i].Task, err = insertSQLiteTask(ctx, tx, w.Task)
+		case WriteUpdate:
+			results[i].Task, err = updateSQLiteTask(ctx, tx, w.Task.ID, w.Task)
+		case WriteDelete:
+			results[i].Task, err = deleteSQLiteTaskVersion(ctx, tx, w.Task.ID, w.Task.Version)
+		default:
+			err = fmt.Errorf("unknown task write %q", w.Op)
+		}
+		results[i].Err = err
+	}
+	if atomic && bulkFailed(results) {
+		abortBulk(results)
+		return results, nil
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit tasks: %w", err)
+	}
+	for i, w := range writes {
+		if w.Op != WriteDelete && results[i].Err == nil {
+			r.index.add(results[i].Task)
+		}
+	}
+	return results, nil
+}
+
+// deleteSQLiteTaskVersion moves the task to the trash if it is still at the version.
+func deleteSQLiteTaskVersion(ctx context.Context, db sqliteExecer, id string, version int64) (domain.Task, error) {
+	now := time.Now().UTC()
+	result, err := db.ExecContext(ctx, "UPDATE tasks SET deleted_at = ?, version = version + 1 WHERE "+sqliteLiveTask+" AND id = ? AND version = ?",
+		formatSQLiteTime(now), id, version)
+	if err != nil {
+		return domain.Task{}, fmt.Errorf("failed to delete task: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return domain.Task{}, missedSQLiteTask(ctx, db, id)
+	}
+	return domain.Task{ID: id, Version: version + 1, DeletedAt: &now}, nil
+}
+
+// missedSQLiteTask explains why a version-checked write to a live task matched no row.
+func missedSQLiteTask(ctx context.Context, db sqliteExecer, id string) error {
+	var exists bool
+	if err := db.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM tasks WHERE "+sqliteLiveTask+" AND id = ?)", id).Scan(&exists); err != nil {
+		return fmt.Errorf("failed to check task: %w", err)
+	}
+	if !exists {
+		return ErrNotFound
+	}
+	return ErrVersionConflict
+}
+
+func (r *SQLiteTaskRepository) Close() error {
+	return r.db.Close()
+}
+
+func (r *SQLiteTaskRepository) query(ctx context.Context, stmt string, args ...interface{}) ([]domain.Task, error) {
+	rows, err := r.db.QueryContext(ctx, stmt, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find tasks: %w", err)
+	}
+	defer rows.Close()
+
+	tasks := []domain.Task{}
+	for rows.Next() {
+		var t domain.Task
+		var due, tags, assignees, recurrence string
+		var deletedAt sql.NullString
+		if err := rows.Scan(&t.ID, &t.Title, &t.Description, &due, &t.Status, &t.Priority, &tags, &assignees, &t.ParentID, &t.OwnerID, &t.Version, &recurrence, &deletedAt, &t.ProjectID); err != nil {
+			return nil, fmt.Errorf("failed to decode tasks: %w", err)
+		}
+		if deletedAt.Valid {
+			at, err := parseSQLiteTime(deletedAt.String)
+			if err != nil {
+				return nil, fmt.Errorf("failed to decode tasks: %w", err)
+			}
+			t.DeletedAt = &at
+		}
+		if t.DueDate, err = parseSQLiteTime(due); err != nil {
+			return nil, fmt.Errorf("failed to decode tasks: %w", err)
+		}
+		if err := json.Unmarshal([]byte(tags), &t.Tags); err != nil {
+			return nil, fmt.Errorf("failed to decode tasks: %w", err)
+		}
+		if err := json.Unmarshal([]byte(assignees), &t.AssigneeIDs); err != nil {
+			return nil, fmt.Errorf("failed to decode tasks: %w", err)
+		}
+		if recurrence != "" {
+			if err := json.Unmarshal([]byte(recurrence), &t.Recurrence); err != nil {
+				return nil, fmt.Errorf("failed to decode tasks: %w", err)
+			}
+		}
+		tasks = append(tasks, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to decode tasks: %w", err)
+	}
+	return tasks, nil
+}
+
+// sqliteTaskArgs returns the task's values in sqliteTaskColumns order.
+func sqliteTaskArgs(t domain.Task) ([]interface{}, error) {
+	tags, err := json.Marshal(nonNilStrings(t.Tags))
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode tags: %w", err)
+	}
+	assignees, err := json.Marshal(nonNilStrings(t.AssigneeIDs))
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode assignees: %w", err)
+	}
+	var recurrence []byte
+	if t.Recurrence != nil {
+		if recurrence, err = json.Marshal(t.Recurrence); err != nil {
+			return nil, fmt.Errorf("failed to encode recurrence: %w", err)
+		}
+	}
+	var deletedAt interface{}
+	if t.DeletedAt != nil {
+		deletedAt = formatSQLiteTime(*t.DeletedAt)
+	}
+	return []interface{}{t.ID, t.Title, t.Description, formatSQLiteTime(t.DueDate), t.Status, t.Priority,
+		string(tags), string(assignees), t.ParentID, t.OwnerID, t.Version, string(recurrence), deletedAt, t.ProjectID}, nil
+}
+
+func nonNilStrings(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
@@ -0,0 +1,118 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	domain "task_manager/Domain"
+)
+
+// SQLiteTokenStore implements ITokenStore using SQLite.
+type SQLiteTokenStore struct {
+	db       *sql.DB
+	timeouts Timeouts
+}
+
+func NewSQLiteTokenStore(db *sql.DB, timeouts Timeouts) ITokenStore {
+	return &SQLiteTokenStore{db: db, timeouts: timeouts}
+}
+
+func (s *SQLiteTokenStore) Close() error {
+	return s.db.Close()
+}
+
+func (s *SQLiteTokenStore) SaveRefreshToken(ctx context.Context, rt domain.RefreshToken) error {
+	ctx, cancel := s.timeouts.withTimeout(ctx, "token.save_refresh_token")
+	defer cancel()
+
+	_, err := s.db.ExecContext(ctx, `INSERT INTO refresh_tokens (hash, family_id, user_id, username, expires_at, used, revoked, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		rt.Hash, rt.FamilyID, rt.UserID, rt.Username, formatSQLiteTime(rt.ExpiresAt), rt.Used, rt.Revoked, formatSQLiteTime(rt.CreatedAt))
+	if err != nil {
+		return fmt.Errorf("failed to save refresh token: %w", err)
+	}
+	return nil
+}
+
+func (s *SQLiteTokenStore) GetRefreshToken(ctx context.Context, hash string) (domain.RefreshToken, error) {
+	ctx, cancel := s.timeouts.withTimeout(ctx, "token.get_refresh_token")
+	defer cancel()
+
+	var rt domain.RefreshToken
+	var expires, created string
+	err := s.db.QueryRowContext(ctx, `SELECT hash, family_id, user_id, username, expires_at, used, revoked, created_at
+		FROM refresh_tokens WHERE hash = ?`, hash).
+		Scan(&rt.Hash, &rt.FamilyID, &rt.UserID, &rt.Username, &expires, &rt.Used, &rt.Revoked, &created)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.RefreshToken{}, ErrRefreshTokenNotFound
+		}
+		return domain.RefreshToken{}, fmt.Errorf("failed to find refresh token: %w", err)
+	}
+	if rt.ExpiresAt, err = parseSQLiteTime(expires); err != nil {
+		return domain.RefreshToken{}, fmt.Errorf("failed to decode refresh token: %w", err)
+	}
+	if rt.CreatedAt, err = parseSQLiteTime(created); err != nil {
+		return domain.RefreshToken{}, fmt.Errorf("failed to decode refresh token: %w", err)
+	}
+	return rt, nil
+}
+
+func (s *SQLiteTokenStore) UseRefreshToken(ctx context.Context, hash string) (domain.RefreshToken, error) {
+	ctx, cancel := s.timeouts.withTimeout(ctx, "token.use_refresh_token")
+	defer cancel()
+
+	res, err := s.db.ExecContext(ctx, "UPDATE refresh_tokens SET used = 1 WHERE hash = ? AND used = 0 AND revoked = 0", hash)
+	if err != nil {
+		return domain.RefreshToken{}, fmt.Errorf("failed to use refresh token: %w", err)
+	}
+	n, _ := res.RowsAffected()
+
+	rt, err := s.GetRefreshToken(ctx, hash)
+	if err != nil || n == 1 {
+		return rt, err
+	}
+	if rt.Revoked {
+		return rt, ErrRefreshTokenRevoked
+	}
+	return rt, ErrRefreshTokenReused
+}
+
+func (s *SQLiteTokenStore) RevokeFamily(ctx context.Context, familyID string) error {
+	ctx, cancel := s.timeouts.withTimeout(ctx, "token.revoke_family")
+	defer cancel()
+
+	if _, err := s.db.ExecContext(ctx, "UPDATE refresh_tokens SET revoked = 1 WHERE family_id = ?", familyID); err != nil {
+		return fmt.Errorf("failed to revoke token family: %w", err)
+	}
+	return nil
+}
+
+func (s *SQLiteTokenStore) RevokeAccessToken(ctx context.Context, jti string, expiresAt time.Time) error {
+	ctx, cancel := s.timeouts.withTimeout(ctx, "token.revoke_access_token")
+	defer cancel()
+
+	now := formatSQLiteTime(time.Now())
+	if _, err := s.db.ExecContext(ctx, "DELETE FROM revoked_tokens WHERE expires_at <= ?", now); err != nil {
+		return fmt.Errorf("failed to prune revoked tokens: %w", err)
+	}
+	_, err := s.db.ExecContext(ctx, "INSERT INTO revoked_tokens (jti, expires_at) VALUES (?, ?) ON CONFLICT (jti) DO UPDATE SET expires_at = excluded.expires_at",
+		jti, formatSQLiteTime(expiresAt))
+	if err != nil {
+		return fmt.Errorf("failed to revoke access token: %w", err)
+	}
+	return nil
+}
+
+func (s *SQLiteTokenStore) IsAccessTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	ctx, cancel := s.timeouts.withTimeout(ctx, "token.is_access_token_revoked")
+	defer cancel()
+
+	var cnt int
+	if err := s.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM revoked_tokens WHERE jti = ?", jti).Scan(&cnt); err != nil {
+		return false, fmt.Errorf("failed to check revocation: %w", err)
+	}
+	return cnt > 0, nil
+}
@@ -0,0 +1,161 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	domain "task_manager/Domain"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// SQLiteUserRepository implements IUserRepository using SQLite.
+type SQLiteUserRepository struct {
+	db       *sql.DB
+	timeouts Timeouts
+}
+
+func NewSQLiteUserRepository(db *sql.DB, timeouts Timeouts) IUserRepository {
+	return &SQLiteUserRepository{db: db, timeouts: timeouts}
+}
+
+func (r *SQLiteUserRepository) Close() error {
+	return r.db.Close()
+}
+
+func (r *SQLiteUserRepository) IsEmpty(ctx context.Context) (bool, error) {
+	ctx, cancel := r.timeouts.withTimeout(ctx, "user.is_empty")
+	defer cancel()
+
+	var cnt int
+	if err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM users").Scan(&cnt); err != nil {
+		return false, fmt.Errorf("count error: %w", err)
+	}
+	return cnt == 0, nil
+}
+
+func (r *SQLiteUserRepository) CreateUser(ctx context.Context, username, password string) (domain.User, error) {
+	ctx, cancel := r.timeouts.withTimeout(ctx, "user.create")
+	defer cancel()
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return domain.User{}, fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return domain.User{}, fmt.Errorf("failed to create user: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Check existing and count users in the same transaction
+	var existing, total int
+	err = tx.QueryRowContext(ctx, "SELECT COUNT(*) FILTER (WHERE username = ?), COUNT(*) FROM users", username).Scan(&existing, &total)
+	if err != nil {
+		return domain.User{}, fmt.Errorf("failed to check username: %w", err)
+	}
+	if existing > 0 {
+		return domain.User{}, ErrUsernameTaken
+	}
+
+	role := domain.RoleUser
+	if total == 0 {
+		role = domain.RoleAdmin
+	}
+
+	u := domain.User{
+		ID:           primitive.NewObjectID(),
+		Username:     username,
+		PasswordHash: string(hash),
+		Role:         role,
+		CreatedAt:    time.Now().UTC(),
+	}
+
+	_, err = tx.ExecContext(ctx, "INSERT INTO users (id, username, password_hash, role, created_at) VALUES (?, ?, ?, ?, ?)",
+		u.ID.Hex(), u.Username, u.PasswordHash, u.Role, formatSQLiteTime(u.CreatedAt))
+	if err != nil {
+		return domain.User{}, fmt.Errorf("failed to create user: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return domain.User{}, fmt.Errorf("failed to create user: %w", err)
+	}
+
+	u.PasswordHash = ""
+	return u, nil
+}
+
+func (r *SQLiteUserRepository) GetByUsername(ctx context.Context, username string) (domain.User, error) {
+	ctx, cancel := r.timeouts.withTimeout(ctx, "user.get_by_username")
+	defer cancel()
+
+	var u domain.User
+	var id, created string
+	err := r.db.QueryRowContext(ctx, "SELECT id, username, password_hash, role, created_at FROM users WHERE username = ?", username).
+		Scan(&id, &u.Username, &u.PasswordHash, &u.Role, &created)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.User{}, ErrUserNotFound
+		}
+		return domain.User{}, fmt.Errorf("failed to find user: %w", err)
+	}
+	if u.ID, err = primitive.ObjectIDFromHex(id); err != nil {
+		return domain.User{}, fmt.Errorf("failed to decode user: %w", err)
+	}
+	if u.CreatedAt, err = parseSQLiteTime(created); err != nil {
+		return domain.User{}, fmt.Errorf("failed to decode user: %w", err)
+	}
+	return u, nil
+}
+
+func (r *SQLiteUserRepository) GetByID(ctx context.Context, idHex string) (domain.User, error) {
+	ctx, cancel := r.timeouts.withTimeout(ctx, "user.get_by_id")
+	defer cancel()
+
+	var u domain.User
+	var created string
+	err := r.db.QueryRowContext(ctx, "SELECT username, role, created_at FROM users WHERE id = ?", idHex).
+		Scan(&u.Username, &u.Role, &created)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.User{}, ErrUserNotFound
+		}
+		return domain.User{}, fmt.Errorf("failed to find user: %w", err)
+	}
+	if u.ID, err = primitive.ObjectIDFromHex(idHex); err != nil {
+		return domain.User{}, fmt.Errorf("failed to decode user: %w", err)
+	}
+	if u.CreatedAt, err = parseSQLiteTime(created); err != nil {
+		return domain.User{}, fmt.Errorf("failed to decode user: %w", err)
+	}
+	return u, nil
+}
+
+func (r *SQLiteUserRepository) VerifyPassword(u domain.User, password string) bool {
+	err := bcrypt.CompareHashAndPassword([]byte(u.PasswordHash), []byte(password))
+	return err == nil
+}
+
+func (r *SQLiteUserRepository) PromoteUser(ctx context.Context, idHex string) error {
+	return r.SetRole(ctx, idHex, domain.RoleAdmin)
+}
+
+func (r *SQLiteUserRepository) SetRole(ctx context.Context, idHex, role string) error {
+	ctx, cancel := r.timeouts.withTimeout(ctx, "user.set_role")
+	defer cancel()
+
+	if _, err := primitive.ObjectIDFromHex(idHex); err != nil {
+		return ErrUserNotFound
+	}
+	res, err := r.db.ExecContext(ctx, "UPDATE users SET role = ? WHERE id = ?", role, idHex)
+	if err != nil {
+		return fmt.Errorf("failed to set role: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrUserNotFound
+	}
+	return nil
+}
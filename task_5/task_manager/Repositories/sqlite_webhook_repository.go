@@ -0,0 +1,249 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	domain "task_manager/Domain"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const sqliteWebhookColumns = "id, url, events, secret, created_by, created_at"
+
+const sqliteDeliveryColumns = `id, webhook_id, event_id, event_type, payload, status, attempts,
+	response_status, last_error, next_attempt_at, created_at, updated_at`
+
+// SQLiteWebhookRepository implements IWebhookRepository using SQLite.
+type SQLiteWebhookRepository struct {
+	db       *sql.DB
+	timeouts Timeouts
+}
+
+func NewSQLiteWebhookRepository(db *sql.DB, timeouts Timeouts) IWebhookRepository {
+	return &SQLiteWebhookRepository{db: db, timeouts: timeouts}
+}
+
+func (r *SQLiteWebhookRepository) Close() error {
+	return r.db.Close()
+}
+
+func (r *SQLiteWebhookRepository) Create(ctx context.Context, w domain.Webhook) (domain.Webhook, error) {
+	ctx, cancel := r.timeouts.withTimeout(ctx, "webhook.create")
+	defer cancel()
+
+	events, err := json.Marshal(w.Events)
+	if err != nil {
+		return domain.Webhook{}, fmt.Errorf("failed to encode webhook events: %w", err)
+	}
+	w.ID = primitive.NewObjectID().Hex()
+	_, err = r.db.ExecContext(ctx, `INSERT INTO webhooks (id, url, events, secret, created_by, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)`,
+		w.ID, w.URL, string(events), w.Secret, w.CreatedBy, formatSQLiteTime(w.CreatedAt))
+	if err != nil {
+		return domain.Webhook{}, fmt.Errorf("failed to insert webhook: %w", err)
+	}
+	return w, nil
+}
+
+func (r *SQLiteWebhookRepository) GetByID(ctx context.Context, id string) (domain.Webhook, error) {
+	ctx, cancel := r.timeouts.withTimeout(ctx, "webhook.get")
+	defer cancel()
+
+	webhooks, err := r.queryWebhooks(ctx, "SELECT "+sqliteWebhookColumns+" FROM webhooks WHERE id = ?", id)
+	if err != nil {
+		return domain.Webhook{}, err
+	}
+	if len(webhooks) == 0 {
+		return domain.Webhook{}, ErrWebhookNotFound
+	}
+	return webhooks[0], nil
+}
+
+func (r *SQLiteWebhookRepository) List(ctx context.Context) ([]domain.Webhook, error) {
+	ctx, cancel := r.timeouts.withTimeout(ctx, "webhook.list")
+	defer cancel()
+
+	return r.queryWebhooks(ctx, "SELECT "+sqliteWebhookColumns+" FROM webhooks ORDER BY id")
+}
+
+func (r *SQLiteWebhookRepository) Delete(ctx context.Context, id string) error {
+	ctx, cancel := r.timeouts.withTimeout(ctx, "webhook.delete")
+	defer cancel()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to delete webhook: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `DELETE FROM webhooks WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete webhook: %w", err)
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		return fmt.Errorf("failed to delete webhook: %w", err)
+	} else if n == 0 {
+		return ErrWebhookNotFound
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM webhook_deliveries WHERE webhook_id = ?`, id); err != nil {
+		return fmt.Errorf("failed to delete webhook deliveries: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to delete webhook: %w", err)
+	}
+	return nil
+}
+
+func (r *SQLiteWebhookRepository) CreateDelivery(ctx context.Context, d domain.WebhookDelivery) (domain.WebhookDelivery, error) {
+	ctx, cancel := r.timeouts.withTimeout(ctx, "webhook.create_delivery")
+	defer cancel()
+
+	d.ID = primitive.NewObjectID().Hex()
+	_, err := r.db.ExecContext(ctx, `INSERT INTO webhook_deliveries (`+sqliteDeliveryColumns+`)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`, sqliteDeliveryArgs(d)...)
+	if err != nil {
+		return domain.WebhookDelivery{}, fmt.Errorf("failed to insert delivery: %w", err)
+	}
+	return d, nil
+}
+
+func (r *SQLiteWebhookRepository) UpdateDelivery(ctx context.Context, d domain.WebhookDelivery) error {
+	ctx, cancel := r.timeouts.withTimeout(ctx, "webhook.update_delivery")
+	defer cancel()
+
+	args := sqliteDeliveryArgs(d)
+	result, err := r.db.ExecContext(ctx, `UPDATE webhook_deliveries SET webhook_id = ?, event_id = ?, event_type = ?,
+		payload = ?, status = ?, attempts = ?, response_status = ?, last_error = ?, next_attempt_at = ?,
+		created_at = ?, updated_at = ? WHERE id = ?`, append(args[1:], d.ID)...)
+	if err != nil {
+		return fmt.Errorf("failed to update delivery: %w", err)
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		return fmt.Errorf("failed to update delivery: %w", err)
+	} else if n == 0 {
+		return ErrDeliveryNotFound
+	}
+	return nil
+}
+
+func (r *SQLiteWebhookRepository) GetDelivery(ctx context.Context, id string) (domain.WebhookDelivery, error) {
+	ctx, cancel := r.timeouts.withTimeout(ctx, "webhook.get_delivery")
+	defer cancel()
+
+	deliveries, err := r.queryDeliveries(ctx, "SELECT "+sqliteDeliveryColumns+" FROM webhook_deliveries WHERE id = ?", id)
+	if err != nil {
+		return domain.WebhookDelivery{}, err
+	}
+	if len(deliveries) == 0 {
+		return domain.WebhookDelivery{}, ErrDeliveryNotFound
+	}
+	return deliveries[0], nil
+}
+
+func (r *SQLiteWebhookRepository) ListDeliveries(ctx context.Context, q domain.DeliveryQuery) (domain.DeliveryPage, error) {
+	ctx, cancel := r.timeouts.withTimeout(ctx, "webhook.list_deliveries")
+	defer cancel()
+
+	clause := " WHERE webhook_id = ?"
+	args := []interface{}{q.WebhookID}
+	if q.Status != "" {
+		clause += " AND status = ?"
+		args = append(args, q.Status)
+	}
+
+	var total int64
+	if err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM webhook_deliveries"+clause, args...).Scan(&total); err != nil {
+		return domain.DeliveryPage{}, fmt.Errorf("failed to count deliveries: %w", err)
+	}
+
+	deliveries, err := r.queryDeliveries(ctx, `SELECT `+sqliteDeliveryColumns+` FROM webhook_deliveries`+clause+
+		` ORDER BY created_at DESC, id DESC LIMIT ? OFFSET ?`, append(args, q.PageSize, q.Skip())...)
+	if err != nil {
+		return domain.DeliveryPage{}, err
+	}
+	return domain.NewDeliveryPage(q, deliveries, total), nil
+}
+
+func (r *SQLiteWebhookRepository) DueDeliveries(ctx context.Context, now time.Time, limit int) ([]domain.WebhookDelivery, error) {
+	ctx, cancel := r.timeouts.withTimeout(ctx, "webhook.due_deliveries")
+	defer cancel()
+
+	return r.queryDeliveries(ctx, `SELECT `+sqliteDeliveryColumns+` FROM webhook_deliveries
+		WHERE status = ? AND next_attempt_at <= ? ORDER BY next_attempt_at, id LIMIT ?`,
+		domain.DeliveryPending, formatSQLiteTime(now), limit)
+}
+
+func (r *SQLiteWebhookRepository) queryWebhooks(ctx context.Context, stmt string, args ...interface{}) ([]domain.Webhook, error) {
+	rows, err := r.db.QueryContext(ctx, stmt, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find webhooks: %w", err)
+	}
+	defer rows.Close()
+
+	webhooks := []domain.Webhook{}
+	for rows.Next() {
+		var w domain.Webhook
+		var events, created string
+		if err := rows.Scan(&w.ID, &w.URL, &events, &w.Secret, &w.CreatedBy, &created); err != nil {
+			return nil, fmt.Errorf("failed to decode webhooks: %w", err)
+		}
+		if err := json.Unmarshal([]byte(events), &w.Events); err != nil {
+			return nil, fmt.Errorf("failed to decode webhooks: %w", err)
+		}
+		if w.CreatedAt, err = parseSQLiteTime(created); err != nil {
+			return nil, fmt.Errorf("failed to decode webhooks: %w", err)
+		}
+		webhooks = append(webhooks, w)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to decode webhooks: %w", err)
+	}
+	return webhooks, nil
+}
+
+func (r *SQLiteWebhookRepository) queryDeliveries(ctx context.Context, stmt string, args ...interface{}) ([]domain.WebhookDelivery, error) {
+	rows, err := r.db.QueryContext(ctx, stmt, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	deliveries := []domain.WebhookDelivery{}
+	for rows.Next() {
+		var d domain.WebhookDelivery
+		var payload, next, created, updated string
+		err := rows.Scan(&d.ID, &d.WebhookID, &d.EventID, &d.EventType, &payload, &d.Status, &d.Attempts,
+			&d.ResponseStatus, &d.LastError, &next, &created, &updated)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode deliveries: %w", err)
+		}
+		d.Payload = json.RawMessage(payload)
+		if d.NextAttemptAt, err = parseSQLiteTime(next); err != nil {
+			return nil, fmt.Errorf("failed to decode deliveries: %w", err)
+		}
+		if d.CreatedAt, err = parseSQLiteTime(created); err != nil {
+			return nil, fmt.Errorf("failed to decode deliveries: %w", err)
+		}
+		if d.UpdatedAt, err = parseSQLiteTime(updated); err != nil {
+			return nil, fmt.Errorf("failed to decode deliveries: %w", err)
+		}
+		deliveries = append(deliveries, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to decode deliveries: %w", err)
+	}
+	return deliveries, nil
+}
+
+// sqliteDeliveryArgs returns the delivery's values in sqliteDeliveryColumns order.
+func sqliteDeliveryArgs(d domain.WebhookDelivery) []interface{} {
+	return []interface{}{
+		d.ID, d.WebhookID, d.EventID, d.EventType, string(d.Payload), d.Status, d.Attempts,
+		d.ResponseStatus, d.LastError, formatSQLiteTime(d.NextAttemptAt),
+		formatSQLiteTime(d.CreatedAt), formatSQLiteTime(d.UpdatedAt),
+	}
+}
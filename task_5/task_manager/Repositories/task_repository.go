@@ -4,7 +4,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"log"
+	"regexp"
+	"strings"
 	"time"
 
 	domain "task_manager/Domain"
@@ -16,126 +17,472 @@ import (
 )
 
 var (
-	ErrNotFound = errors.New("task not found")
+	ErrNotFound        error = domain.NewNotFoundError("task not found")
+	ErrVersionConflict error = domain.NewPreconditionFailedError("task was modified by another request")
+	// ErrBulkAborted is the result of the writes of an atomic bulk write
+	// that were not applied because another one failed.
+	ErrBulkAborted error = domain.NewConflictError("not applied because another operation failed")
 )
 
+// Kinds of writes in a bulk write.
+const (
+	WriteCreate = "create"
+	WriteUpdate = "update"
+	WriteDelete = "delete"
+)
+
+// TaskWrite is one write of a bulk write. Updates and deletes only apply
+// while the stored task is still at Task.Version; deletes only use the
+// task's ID and version.
+type TaskWrite struct {
+	Op   string
+	Task domain.Task
+}
+
+// TaskWriteResult is the outcome of one TaskWrite: the written task, or why it failed.
+type TaskWriteResult struct {
+	Task domain.Task
+	Err  error
+}
+
+// abortBulk replaces the successful results of a failed atomic bulk write with ErrBulkAborted.
+func abortBulk(results []TaskWriteResult) {
+	for i := range results {
+		if results[i].Err == nil {
+			results[i] = TaskWriteResult{Err: ErrBulkAborted}
+		}
+	}
+}
+
+func bulkFailed(results []TaskWriteResult) bool {
+	for _, r := range results {
+		if r.Err != nil {
+			return true
+		}
+	}
+	return false
+}
+
 // ITaskRepository defines the interface for task data access.
+// Deleted tasks stay in the trash until purged; every method except the
+// trash ones treats them as if they did not exist.
 type ITaskRepository interface {
-	GetAll() ([]domain.Task, error)
-	GetByID(id string) (domain.Task, error)
-	Create(t domain.Task) (domain.Task, error)
-	Update(id string, t domain.Task) (domain.Task, error)
-	Delete(id string) error
+	// List returns live tasks, or the trash when q.Deleted is set.
+	List(ctx context.Context, q domain.TaskQuery) (domain.TaskPage, error)
+	// Each calls fn with every task matching q, in q's sort order and
+	// ignoring its page, fetching them in batches instead of all at once.
+	// It stops at the first error fn returns and returns it.
+	Each(ctx context.Context, q domain.TaskQuery, fn func(domain.Task) error) error
+	// Search returns the page of live tasks matching every term of the
+	// search, ranked by TaskSearch.Rank.
+	Search(ctx context.Context, s domain.TaskSearch) (domain.TaskSearchPage, error)
+	GetByID(ctx context.Context, id string) (domain.Task, error)
+	// GetChildren returns the subtasks of the task.
+	GetChildren(ctx context.Context, parentID string) ([]domain.Task, error)
+	// Create stores a new task at version 1, in the default project
+	// unless the task names another.
+	Create(ctx context.Context, t domain.Task) (domain.Task, error)
+	// Update replaces the task only if its stored version still equals
+	// t.Version, and returns it with the version incremented. A stale
+	// version yields ErrVersionConflict. The task stays in its project.
+	Update(ctx context.Context, id string, t domain.Task) (domain.Task, error)
+	// Delete moves the task to the trash.
+	Delete(ctx context.Context, id string) error
+	// GetDeleted returns a task in the trash.
+	GetDeleted(ctx context.Context, id string) (domain.Task, error)
+	// Restore moves a task out of the trash and increments its version.
+	Restore(ctx context.Context, id string) (domain.Task, error)
+	// Purge permanently removes a task in the trash.
+	Purge(ctx context.Context, id string) error
+	// PurgeDeletedBefore permanently removes the tasks deleted before
+	// cutoff and returns how many were removed.
+	PurgeDeletedBefore(ctx context.Context, cutoff time.Time) (int64, error)
+	// BulkWrite applies the writes and returns their results in the same
+	// order. Each write succeeds or fails on its own unless atomic is set,
+	// in which case either every write is applied or none is. The error is
+	// reserved for failures of the bulk write as a whole.
+	BulkWrite(ctx context.Context, writes []TaskWrite, atomic bool) ([]TaskWriteResult, error)
 	Close() error
 }
 
 // MongoTaskRepository implements ITaskRepository using MongoDB.
 type MongoTaskRepository struct {
-	client     *mongo.Client
 	collection *mongo.Collection
+	timeouts   Timeouts
 }
 
-func NewMongoTaskRepository(uri string, dbName string, collectionName string) (ITaskRepository, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+func NewMongoTaskRepository(db *mongo.Database, collectionName string, timeouts Timeouts) (ITaskRepository, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), mongoConnectTimeout)
 	defer cancel()
 
-	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
+	collection := db.Collection(collectionName)
+
+	// Indexes backing the filtered and sorted task listings
+	_, err := collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "owner_id", Value: 1}, {Key: "due_date", Value: 1}}},
+		{Keys: bson.D{{Key: "project_id", Value: 1}, {Key: "due_date", Value: 1}}},
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "due_date", Value: 1}}},
+		{Keys: bson.D{{Key: "due_date", Value: 1}}},
+		{Keys: bson.D{{Key: "parent_id", Value: 1}}},
+		{Keys: bson.D{{Key: "deleted_at", Value: 1}}},
+		// Full-text search; words are not stemmed, as search matches them whole
+		{
+			Keys: bson.D{{Key: "title", Value: "text"}, {Key: "description", Value: "text"}},
+			Options: options.Index().
+				SetName("task_text").
+				SetWeights(bson.D{{Key: "title", Value: 3}, {Key: "description", Value: 1}}).
+				SetDefaultLanguage("none"),
+		},
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to connect to MongoDB: %v", err)
+		return nil, fmt.Errorf("failed to create task indexes: %w", err)
 	}
-
-	// Ping the database to verify connection
-	if err := client.Ping(ctx, nil); err != nil {
-		return nil, fmt.Errorf("failed to ping MongoDB: %v", err)
+	// Tasks stored before projects existed belong to the default project
+	_, err = collection.UpdateMany(ctx, bson.M{"project_id": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"project_id": domain.DefaultProjectID}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to assign tasks to the default project: %w", err)
 	}
 
-	log.Println("Connected to MongoDB")
-
-	collection := client.Database(dbName).Collection(collectionName)
-
 	return &MongoTaskRepository{
-		client:     client,
 		collection: collection,
+		timeouts:   timeouts,
 	}, nil
 }
 
-func (r *MongoTaskRepository) GetAll() ([]domain.Task, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+func (r *MongoTaskRepository) List(ctx context.Context, q domain.TaskQuery) (domain.TaskPage, error) {
+	ctx, cancel := r.timeouts.withTimeout(ctx, "task.list")
+	defer cancel()
+
+	filter := taskQueryFilter(q)
+
+	total, err := r.collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return domain.TaskPage{}, fmt.Errorf("failed to count tasks: %w", err)
+	}
+
+	direction := 1
+	if q.SortDesc {
+		direction = -1
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: q.SortBy, Value: direction}, {Key: "_id", Value: direction}}).
+		SetSkip(q.Skip()).
+		SetLimit(int64(q.PageSize))
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return domain.TaskPage{}, fmt.Errorf("failed to find tasks: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var tasks []domain.Task
+	if err = cursor.All(ctx, &tasks); err != nil {
+		return domain.TaskPage{}, fmt.Errorf("failed to decode tasks: %w", err)
+	}
+
+	return domain.NewTaskPage(q, tasks, total), nil
+}
+
+// taskBatchSize is how many tasks Each fetches at a time.
+const taskBatchSize = 500
+
+// Each bounds every fetch from the cursor by the task.each timeout rather
+// than the whole iteration, so slow consumers do not time out.
+func (r *MongoTaskRepository) Each(ctx context.Context, q domain.TaskQuery, fn func(domain.Task) error) error {
+	direction := 1
+	if q.SortDesc {
+		direction = -1
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: q.SortBy, Value: direction}, {Key: "_id", Value: direction}}).
+		SetBatchSize(taskBatchSize)
+
+	findCtx, cancel := r.timeouts.withTimeout(ctx, "task.each")
+	cursor, err := r.collection.Find(findCtx, taskQueryFilter(q), opts)
+	cancel()
+	if err != nil {
+		return fmt.Errorf("failed to find tasks: %w", err)
+	}
+	defer cursor.Close(context.WithoutCancel(ctx))
+
+	next := func() bool {
+		ctx, cancel := r.timeouts.withTimeout(ctx, "task.each")
+		defer cancel()
+		return cursor.Next(ctx)
+	}
+	for next() {
+		var t domain.Task
+		if err := cursor.Decode(&t); err != nil {
+			return fmt.Errorf("failed to decode tasks: %w", err)
+		}
+		if err := fn(t); err != nil {
+			return err
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return fmt.Errorf("failed to decode tasks: %w", err)
+	}
+	return nil
+}
+
+// maxSearchCandidates bounds how many tasks a search ranks.
+const maxSearchCandidates = 1000
+
+// Search narrows the tasks down with the text index, ranks the best
+// maxSearchCandidates of them and pages through those. Words and phrases
+// go to $text as phrases, which all have to match; prefixes, which $text
+// cannot match, become regular expressions. Without $text there is no
+// score to pick the best candidates by, so every match is ranked.
+func (r *MongoTaskRepository) Search(ctx context.Context, s domain.TaskSearch) (domain.TaskSearchPage, error) {
+	ctx, cancel := r.timeouts.withTimeout(ctx, "task.search")
 	defer cancel()
 
-	cursor, err := r.collection.Find(ctx, bson.M{})
+	filter := taskQueryFilter(domain.TaskQuery{OwnerID: s.OwnerID, ProjectID: s.ProjectID})
+	var phrases []string
+	var prefixes bson.A
+	for _, term := range s.Terms {
+		if !term.Prefix {
+			phrases = append(phrases, `"`+strings.Join(term.Words, " ")+`"`)
+			continue
+		}
+		pattern := bson.M{"$regex": `(^|\s)[[:punct:]]*` + regexp.QuoteMeta(strings.Join(term.Words, " ")), "$options": "i"}
+		prefixes = append(prefixes, bson.M{"$or": bson.A{bson.M{"title": pattern}, bson.M{"description": pattern}}})
+	}
+	if len(prefixes) > 0 {
+		filter["$and"] = prefixes
+	}
+	opts := options.Find()
+	if len(phrases) > 0 {
+		filter["$text"] = bson.M{"$search": strings.Join(phrases, " ")}
+		score := bson.M{"$meta": "textScore"}
+		opts.SetProjection(bson.M{"score": score}).SetSort(bson.M{"score": score}).SetLimit(maxSearchCandidates)
+	}
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
 	if err != nil {
-		return nil, fmt.Errorf("failed to find tasks: %v", err)
+		return domain.TaskSearchPage{}, fmt.Errorf("failed to search tasks: %w", err)
 	}
 	defer cursor.Close(ctx)
 
 	var tasks []domain.Task
 	if err = cursor.All(ctx, &tasks); err != nil {
-		return nil, fmt.Errorf("failed to decode tasks: %v", err)
+		return domain.TaskSearchPage{}, fmt.Errorf("failed to decode tasks: %w", err)
+	}
+	return s.Rank(tasks), nil
+}
+
+// taskQueryFilter translates a task query into a MongoDB filter.
+func taskQueryFilter(q domain.TaskQuery) bson.M {
+	filter := bson.M{"deleted_at": nil}
+	if q.Deleted {
+		filter["deleted_at"] = bson.M{"$ne": nil}
+	}
+	if q.OwnerID != "" {
+		filter["owner_id"] = q.OwnerID
+	}
+	if q.ProjectID != "" {
+		filter["project_id"] = q.ProjectID
+	}
+	status := bson.M{}
+	if q.Status != "" {
+		status["$eq"] = q.Status
+	}
+	if q.ExcludeStatus != "" {
+		status["$ne"] = q.ExcludeStatus
+	}
+	if len(status) > 0 {
+		filter["status"] = status
+	}
+	due := bson.M{}
+	if !q.DueAfter.IsZero() {
+		due["$gte"] = q.DueAfter
+	}
+	if !q.DueBefore.IsZero() {
+		due["$lte"] = q.DueBefore
+	}
+	if len(due) > 0 {
+		filter["due_date"] = due
+	}
+	if q.Search != "" {
+		filter["title"] = bson.M{"$regex": regexp.QuoteMeta(q.Search), "$options": "i"}
+	}
+	return filter
+}
+
+// liveTask restricts the filter to tasks that are not in the trash.
+// Tasks stored before soft deletion have no deleted_at field, which nil also matches.
+func liveTask(filter bson.M) bson.M {
+	filter["deleted_at"] = nil
+	return filter
+}
+
+// deletedTask restricts the filter to tasks in the trash.
+func deletedTask(filter bson.M) bson.M {
+	filter["deleted_at"] = bson.M{"$ne": nil}
+	return filter
+}
+
+func (r *MongoTaskRepository) GetChildren(ctx context.Context, parentID string) ([]domain.Task, error) {
+	ctx, cancel := r.timeouts.withTimeout(ctx, "task.get_children")
+	defer cancel()
+
+	cursor, err := r.collection.Find(ctx, liveTask(bson.M{"parent_id": parentID}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find subtasks: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	tasks := []domain.Task{}
+	if err = cursor.All(ctx, &tasks); err != nil {
+		return nil, fmt.Errorf("failed to decode subtasks: %w", err)
 	}
 
 	return tasks, nil
 }
 
-func (r *MongoTaskRepository) GetByID(id string) (domain.Task, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+func (r *MongoTaskRepository) GetByID(ctx context.Context, id string) (domain.Task, error) {
+	ctx, cancel := r.timeouts.withTimeout(ctx, "task.get")
 	defer cancel()
 
+	return r.findOne(ctx, liveTask(bson.M{"_id": id}))
+}
+
+func (r *MongoTaskRepository) GetDeleted(ctx context.Context, id string) (domain.Task, error) {
+	ctx, cancel := r.timeouts.withTimeout(ctx, "task.get_deleted")
+	defer cancel()
+
+	return r.findOne(ctx, deletedTask(bson.M{"_id": id}))
+}
+
+func (r *MongoTaskRepository) findOne(ctx context.Context, filter bson.M) (domain.Task, error) {
 	var task domain.Task
-	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&task)
+	err := r.collection.FindOne(ctx, filter).Decode(&task)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
 			return domain.Task{}, ErrNotFound
 		}
-		return domain.Task{}, fmt.Errorf("failed to find task: %v", err)
+		return domain.Task{}, fmt.Errorf("failed to find task: %w", err)
 	}
 
 	return task, nil
 }
 
-func (r *MongoTaskRepository) Create(t domain.Task) (domain.Task, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+func (r *MongoTaskRepository) Create(ctx context.Context, t domain.Task) (domain.Task, error) {
+	ctx, cancel := r.timeouts.withTimeout(ctx, "task.create")
 	defer cancel()
 
 	// Generate a new ID if not provided
 	if t.ID == "" {
 		t.ID = primitive.NewObjectID().Hex()
 	}
+	t.ProjectID = t.Project()
+	t.Version = 1
 
 	_, err := r.collection.InsertOne(ctx, t)
 	if err != nil {
-		return domain.Task{}, fmt.Errorf("failed to insert task: %v", err)
+		return domain.Task{}, fmt.Errorf("failed to insert task: %w", err)
 	}
 
 	return t, nil
 }
 
-func (r *MongoTaskRepository) Update(id string, t domain.Task) (domain.Task, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+func (r *MongoTaskRepository) Update(ctx context.Context, id string, t domain.Task) (domain.Task, error) {
+	ctx, cancel := r.timeouts.withTimeout(ctx, "task.update")
 	defer cancel()
 
 	t.ID = id
-	update := bson.M{"$set": t}
-
-	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, update)
+	filter, update, t := taskUpdate(t)
+	result, err := r.collection.UpdateOne(ctx, filter, update)
 	if err != nil {
-		return domain.Task{}, fmt.Errorf("failed to update task: %v", err)
+		return domain.Task{}, fmt.Errorf("failed to update task: %w", err)
 	}
 
 	if result.MatchedCount == 0 {
-		return domain.Task{}, ErrNotFound
+		n, err := r.collection.CountDocuments(ctx, liveTask(bson.M{"_id": id}))
+		if err != nil {
+			return domain.Task{}, fmt.Errorf("failed to update task: %w", err)
+		}
+		if n == 0 {
+			return domain.Task{}, ErrNotFound
+		}
+		return domain.Task{}, ErrVersionConflict
 	}
 
 	return t, nil
 }
 
-func (r *MongoTaskRepository) Delete(id string) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+// taskUpdate builds the filter and update that replace the stored task
+// while it is still at t.Version, and returns the task as it will be stored.
+func taskUpdate(t domain.Task) (bson.M, bson.M, domain.Task) {
+	t.DeletedAt = nil
+	filter := liveTask(bson.M{"_id": t.ID, "version": t.Version})
+	if t.Version == 0 {
+		// Tasks stored before versioning have no version field
+		filter["version"] = bson.M{"$in": bson.A{0, nil}}
+	}
+	t.Version++
+
+	// The stored project is kept, as $set leaves out the empty project ID
+	set := t
+	set.ProjectID = ""
+	update := bson.M{"$set": set}
+	// $set leaves out empty fields, so a removed parent or recurrence is cleared explicitly
+	unset := bson.M{}
+	if t.ParentID == "" {
+		unset["parent_id"] = ""
+	}
+	if t.Recurrence == nil {
+		unset["recurrence"] = ""
+	}
+	if len(unset) > 0 {
+		update["$unset"] = unset
+	}
+	return filter, update, t
+}
+
+func (r *MongoTaskRepository) Delete(ctx context.Context, id string) error {
+	ctx, cancel := r.timeouts.withTimeout(ctx, "task.delete")
+	defer cancel()
+
+	update := bson.M{"$set": bson.M{"deleted_at": time.Now().UTC()}, "$inc": bson.M{"version": 1}}
+	result, err := r.collection.UpdateOne(ctx, liveTask(bson.M{"_id": id}), update)
+	if err != nil {
+		return fmt.Errorf("failed to delete task: %w", err)
+	}
+
+	if result.MatchedCount == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *MongoTaskRepository) Restore(ctx context.Context, id string) (domain.Task, error) {
+	ctx, cancel := r.timeouts.withTimeout(ctx, "task.restore")
 	defer cancel()
 
-	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": id})
+	update := bson.M{"$unset": bson.M{"deleted_at": ""}, "$inc": bson.M{"version": 1}}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	var task domain.Task
+	err := r.collection.FindOneAndUpdate(ctx, deletedTask(bson.M{"_id": id}), update, opts).Decode(&task)
 	if err != nil {
-		return fmt.Errorf("failed to delete task: %v", err)
+		if err == mongo.ErrNoDocuments {
+			return domain.Task{}, ErrNotFound
+		}
+		return domain.Task{}, fmt.Errorf("failed to restore task: %w", err)
+	}
+
+	return task, nil
+}
+
+func (r *MongoTaskRepository) Purge(ctx context.Context, id string) error {
+	ctx, cancel := r.timeouts.withTimeout(ctx, "task.purge")
+	defer cancel()
+
+	result, err := r.collection.DeleteOne(ctx, deletedTask(bson.M{"_id": id}))
+	if err != nil {
+		return fmt.Errorf("failed to purge task: %w", err)
 	}
 
 	if result.DeletedCount == 0 {
@@ -145,9 +492,149 @@ func (r *MongoTaskRepository) Delete(id string) error {
 	return nil
 }
 
-func (r *MongoTaskRepository) Close() error {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+func (r *MongoTaskRepository) PurgeDeletedBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	ctx, cancel := r.timeouts.withTimeout(ctx, "task.purge_deleted_before")
 	defer cancel()
 
-	return r.client.Disconnect(ctx)
+	result, err := r.collection.DeleteMany(ctx, bson.M{"deleted_at": bson.M{"$ne": nil, "$lt": cutoff}})
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge tasks: %w", err)
+	}
+
+	return result.DeletedCount, nil
+}
+
+// BulkWrite sends the writes in one bulk write. Atomic bulk writes run in a
+// transaction, which needs a replica set or sharded cluster.
+func (r *MongoTaskRepository) BulkWrite(ctx context.Context, writes []TaskWrite, atomic bool) ([]TaskWriteResult, error) {
+	ctx, cancel := r.timeouts.withTimeout(ctx, "task.bulk_write")
+	defer cancel()
+
+	if !atomic {
+		return r.bulkWrite(ctx, writes, false)
+	}
+	session, err := r.collection.Database().Client().StartSession()
+	if err != nil {
+		return nil, fmt.Errorf("failed to start session: %w", err)
+	}
+	defer session.EndSession(ctx)
+
+	var results []TaskWriteResult
+	_, err = session.WithTransaction(ctx, func(sc mongo.SessionContext) (interface{}, error) {
+		if results, err = r.bulkWrite(sc, writes, true); err != nil {
+			return nil, err
+		}
+		if bulkFailed(results) {
+			return nil, ErrBulkAborted
+		}
+		return nil, nil
+	})
+	if errors.Is(err, ErrBulkAborted) {
+		abortBulk(results)
+		return results, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to write tasks: %w", err)
+	}
+	return results, nil
+}
+
+// bulkWrite runs the writes as one bulk write. An ordered bulk write stops
+// at the first failed write and reports the rest as ErrBulkAborted.
+func (r *MongoTaskRepository) bulkWrite(ctx context.Context, writes []TaskWrite, ordered bool) ([]TaskWriteResult, error) {
+	results := make([]TaskWriteResult, len(writes))
+	models := make([]mongo.WriteModel, len(writes))
+	now := time.Now().UTC()
+	for i, w := range writes {
+		t := w.Task
+		switch w.Op {
+		case WriteCreate:
+			if t.ID == "" {
+				t.ID = primitive.NewObjectID().Hex()
+			}
+			t.ProjectID = t.Project()
+			t.Version = 1
+			models[i] = mongo.NewInsertOneModel().SetDocument(t)
+		case WriteUpdate:
+			var filter, update bson.M
+			filter, update, t = taskUpdate(t)
+			models[i] = mongo.NewUpdateOneModel().SetFilter(filter).SetUpdate(update)
+		case WriteDelete:
+			filter := liveTask(bson.M{"_id": t.ID, "version": t.Version})
+			update := bson.M{"$set": bson.M{"deleted_at": now}, "$inc": bson.M{"version": 1}}
+			models[i] = mongo.NewUpdateOneModel().SetFilter(filter).SetUpdate(update)
+			t.Version++
+			t.DeletedAt = &now
+		default:
+			return nil, fmt.Errorf("unknown task write %q", w.Op)
+		}
+		results[i].Task = t
+	}
+
+	result, err := r.collection.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(ordered))
+	var bulkErr mongo.BulkWriteException
+	if err != nil && !errors.As(err, &bulkErr) {
+		return nil, fmt.Errorf("failed to write tasks: %w", err)
+	}
+	for _, writeErr := range bulkErr.WriteErrors {
+		results[writeErr.Index] = TaskWriteResult{Err: fmt.Errorf("failed to write task: %w", writeErr)}
+		if ordered {
+			for i := writeErr.Index + 1; i < len(results); i++ {
+				results[i] = TaskWriteResult{Err: ErrBulkAborted}
+			}
+		}
+	}
+
+	// The result only counts matches, so when some updates or deletes
+	// matched nothing, find out which from the versions now stored
+	var ids []string
+	for i, w := range writes {
+		if w.Op != WriteCreate && results[i].Err == nil {
+			ids = append(ids, w.Task.ID)
+		}
+	}
+	if len(ids) == 0 || (result != nil && result.MatchedCount == int64(len(ids))) {
+		return results, nil
+	}
+	return results, r.resolveBulkMatches(ctx, writes, results, ids)
+}
+
+// resolveBulkMatches marks the updates and deletes that left no trace in
+// the stored tasks as ErrNotFound or ErrVersionConflict.
+func (r *MongoTaskRepository) resolveBulkMatches(ctx context.Context, writes []TaskWrite, results []TaskWriteResult, ids []string) error {
+	cursor, err := r.collection.Find(ctx, bson.M{"_id": bson.M{"$in": ids}},
+		options.Find().SetProjection(bson.M{"version": 1, "deleted_at": 1}))
+	if err != nil {
+		return fmt.Errorf("failed to check written tasks: %w", err)
+	}
+	var stored []domain.Task
+	if err := cursor.All(ctx, &stored); err != nil {
+		return fmt.Errorf("failed to check written tasks: %w", err)
+	}
+	byID := make(map[string]domain.Task, len(stored))
+	for _, t := range stored {
+		byID[t.ID] = t
+	}
+
+	for i, w := range writes {
+		if w.Op == WriteCreate || results[i].Err != nil {
+			continue
+		}
+		t, ok := byID[w.Task.ID]
+		deleted := t.DeletedAt != nil
+		if ok && t.Version == results[i].Task.Version && deleted == (w.Op == WriteDelete) {
+			continue
+		}
+		if !ok || deleted {
+			results[i] = TaskWriteResult{Err: ErrNotFound}
+		} else {
+			results[i] = TaskWriteResult{Err: ErrVersionConflict}
+		}
+	}
+	return nil
+}
+
+// Close is a no-op; the shared client is disconnected by its owner.
+func (r *MongoTaskRepository) Close() error {
+	return nil
 }
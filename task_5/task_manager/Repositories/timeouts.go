@@ -0,0 +1,60 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DefaultOperationTimeout bounds repository operations without their own timeout.
+const DefaultOperationTimeout = 5 * time.Second
+
+// Timeouts bounds how long each repository operation may take.
+// Operations are named "<entity>.<operation>", for example "task.list" or "user.create".
+type Timeouts struct {
+	Default      time.Duration
+	PerOperation map[string]time.Duration
+}
+
+// DefaultTimeouts applies DefaultOperationTimeout to every operation.
+func DefaultTimeouts() Timeouts {
+	return Timeouts{Default: DefaultOperationTimeout}
+}
+
+// ParseTimeouts parses per-operation overrides such as "task.list=10s,user.create=2s".
+func ParseTimeouts(defaultTimeout time.Duration, overrides string) (Timeouts, error) {
+	t := Timeouts{Default: defaultTimeout, PerOperation: make(map[string]time.Duration)}
+	for _, entry := range strings.Split(overrides, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		op, value, ok := strings.Cut(entry, "=")
+		if !ok {
+			return Timeouts{}, fmt.Errorf("invalid timeout %q: expected operation=duration", entry)
+		}
+		d, err := time.ParseDuration(strings.TrimSpace(value))
+		if err != nil || d <= 0 {
+			return Timeouts{}, fmt.Errorf("invalid timeout for %s: %q", op, value)
+		}
+		t.PerOperation[strings.TrimSpace(op)] = d
+	}
+	return t, nil
+}
+
+// For returns the timeout of the named operation.
+func (t Timeouts) For(op string) time.Duration {
+	if d, ok := t.PerOperation[op]; ok {
+		return d
+	}
+	if t.Default > 0 {
+		return t.Default
+	}
+	return DefaultOperationTimeout
+}
+
+// withTimeout derives a context bounded by the operation's timeout.
+func (t Timeouts) withTimeout(ctx context.Context, op string) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(ctx, t.For(op))
+}
@@ -0,0 +1,162 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	domain "task_manager/Domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var (
+	ErrRefreshTokenNotFound = errors.New("refresh token not found")
+	ErrRefreshTokenReused   = errors.New("refresh token already used")
+	ErrRefreshTokenRevoked  = errors.New("refresh token revoked")
+)
+
+// IRevocationStore records revoked access tokens by their jti claim.
+type IRevocationStore interface {
+	RevokeAccessToken(ctx context.Context, jti string, expiresAt time.Time) error
+	IsAccessTokenRevoked(ctx context.Context, jti string) (bool, error)
+}
+
+// ITokenStore defines the interface for refresh token and revocation data access.
+type ITokenStore interface {
+	IRevocationStore
+	SaveRefreshToken(ctx context.Context, rt domain.RefreshToken) error
+	// UseRefreshToken atomically marks the token as used and returns it.
+	// A token that was already used is returned together with ErrRefreshTokenReused.
+	UseRefreshToken(ctx context.Context, hash string) (domain.RefreshToken, error)
+	GetRefreshToken(ctx context.Context, hash string) (domain.RefreshToken, error)
+	RevokeFamily(ctx context.Context, familyID string) error
+	Close() error
+}
+
+// MongoTokenStore implements ITokenStore using MongoDB.
+type MongoTokenStore struct {
+	refreshTokens *mongo.Collection
+	revokedTokens *mongo.Collection
+	timeouts      Timeouts
+}
+
+func NewMongoTokenStore(db *mongo.Database, timeouts Timeouts) (ITokenStore, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), mongoConnectTimeout)
+	defer cancel()
+
+	s := &MongoTokenStore{
+		refreshTokens: db.Collection("refresh_tokens"),
+		revokedTokens: db.Collection("revoked_tokens"),
+		timeouts:      timeouts,
+	}
+
+	// Expired entries are removed by MongoDB's TTL monitor
+	ttl := mongo.IndexModel{
+		Keys:    bson.D{{Key: "expires_at", Value: 1}},
+		Options: options.Index().SetExpireAfterSeconds(0),
+	}
+	if _, err := s.refreshTokens.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		ttl,
+		{Keys: bson.D{{Key: "family_id", Value: 1}}},
+	}); err != nil {
+		return nil, fmt.Errorf("failed to create refresh token indexes: %w", err)
+	}
+	if _, err := s.revokedTokens.Indexes().CreateOne(ctx, ttl); err != nil {
+		return nil, fmt.Errorf("failed to create revoked token index: %w", err)
+	}
+
+	return s, nil
+}
+
+// Close is a no-op; the shared client is disconnected by its owner.
+func (s *MongoTokenStore) Close() error {
+	return nil
+}
+
+func (s *MongoTokenStore) SaveRefreshToken(ctx context.Context, rt domain.RefreshToken) error {
+	ctx, cancel := s.timeouts.withTimeout(ctx, "token.save_refresh_token")
+	defer cancel()
+	if _, err := s.refreshTokens.InsertOne(ctx, rt); err != nil {
+		return fmt.Errorf("failed to save refresh token: %w", err)
+	}
+	return nil
+}
+
+func (s *MongoTokenStore) GetRefreshToken(ctx context.Context, hash string) (domain.RefreshToken, error) {
+	ctx, cancel := s.timeouts.withTimeout(ctx, "token.get_refresh_token")
+	defer cancel()
+	var rt domain.RefreshToken
+	if err := s.refreshTokens.FindOne(ctx, bson.M{"_id": hash}).Decode(&rt); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return domain.RefreshToken{}, ErrRefreshTokenNotFound
+		}
+		return domain.RefreshToken{}, fmt.Errorf("failed to find refresh token: %w", err)
+	}
+	return rt, nil
+}
+
+func (s *MongoTokenStore) UseRefreshToken(ctx context.Context, hash string) (domain.RefreshToken, error) {
+	ctx, cancel := s.timeouts.withTimeout(ctx, "token.use_refresh_token")
+	defer cancel()
+
+	var rt domain.RefreshToken
+	err := s.refreshTokens.FindOneAndUpdate(ctx,
+		bson.M{"_id": hash, "used": false, "revoked": false},
+		bson.M{"$set": bson.M{"used": true}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&rt)
+	if err == nil {
+		return rt, nil
+	}
+	if err != mongo.ErrNoDocuments {
+		return domain.RefreshToken{}, fmt.Errorf("failed to use refresh token: %w", err)
+	}
+
+	// Tell a missing token apart from one that can no longer be used
+	rt, err = s.GetRefreshToken(ctx, hash)
+	if err != nil {
+		return domain.RefreshToken{}, err
+	}
+	if rt.Revoked {
+		return rt, ErrRefreshTokenRevoked
+	}
+	return rt, ErrRefreshTokenReused
+}
+
+func (s *MongoTokenStore) RevokeFamily(ctx context.Context, familyID string) error {
+	ctx, cancel := s.timeouts.withTimeout(ctx, "token.revoke_family")
+	defer cancel()
+	_, err := s.refreshTokens.UpdateMany(ctx, bson.M{"family_id": familyID}, bson.M{"$set": bson.M{"revoked": true}})
+	if err != nil {
+		return fmt.Errorf("failed to revoke token family: %w", err)
+	}
+	return nil
+}
+
+func (s *MongoTokenStore) RevokeAccessToken(ctx context.Context, jti string, expiresAt time.Time) error {
+	ctx, cancel := s.timeouts.withTimeout(ctx, "token.revoke_access_token")
+	defer cancel()
+	_, err := s.revokedTokens.UpdateOne(ctx,
+		bson.M{"_id": jti},
+		bson.M{"$set": bson.M{"expires_at": expiresAt}},
+		options.Update().SetUpsert(true),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to revoke access token: %w", err)
+	}
+	return nil
+}
+
+func (s *MongoTokenStore) IsAccessTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	ctx, cancel := s.timeouts.withTimeout(ctx, "token.is_access_token_revoked")
+	defer cancel()
+	cnt, err := s.revokedTokens.CountDocuments(ctx, bson.M{"_id": jti})
+	if err != nil {
+		return false, fmt.Errorf("failed to check revocation: %w", err)
+	}
+	return cnt > 0, nil
+}
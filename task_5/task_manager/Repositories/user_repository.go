@@ -2,7 +2,6 @@ package repositories
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"time"
 
@@ -11,54 +10,43 @@ import (
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
-	"go.mongodb.org/mongo-driver/mongo/options"
 	"golang.org/x/crypto/bcrypt"
 )
 
 var (
-	ErrUserNotFound = errors.New("user not found")
+	ErrUserNotFound  error = domain.NewNotFoundError("user not found")
+	ErrUsernameTaken error = domain.NewConflictError("username already exists")
 )
 
 // IUserRepository defines the interface for user data access.
 type IUserRepository interface {
-	CreateUser(username, password string) (domain.User, error)
-	GetByUsername(username string) (domain.User, error)
+	CreateUser(ctx context.Context, username, password string) (domain.User, error)
+	GetByUsername(ctx context.Context, username string) (domain.User, error)
+	GetByID(ctx context.Context, idHex string) (domain.User, error)
 	VerifyPassword(u domain.User, password string) bool
-	PromoteUser(idHex string) error
+	PromoteUser(ctx context.Context, idHex string) error
+	SetRole(ctx context.Context, idHex, role string) error
 	Close() error
-	IsEmpty() (bool, error)
+	IsEmpty(ctx context.Context) (bool, error)
 }
 
 // MongoUserRepository implements IUserRepository using MongoDB.
 type MongoUserRepository struct {
-	client     *mongo.Client
 	collection *mongo.Collection
+	timeouts   Timeouts
 }
 
-func NewMongoUserRepository(uri, dbName, collectionName string) (IUserRepository, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
-	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
-	if err != nil {
-		return nil, fmt.Errorf("failed to connect to MongoDB: %w", err)
-	}
-	if err := client.Ping(ctx, nil); err != nil {
-		return nil, fmt.Errorf("failed to ping MongoDB: %w", err)
-	}
-
-	coll := client.Database(dbName).Collection(collectionName)
-	return &MongoUserRepository{client: client, collection: coll}, nil
+func NewMongoUserRepository(db *mongo.Database, collectionName string, timeouts Timeouts) IUserRepository {
+	return &MongoUserRepository{collection: db.Collection(collectionName), timeouts: timeouts}
 }
 
+// Close is a no-op; the shared client is disconnected by its owner.
 func (r *MongoUserRepository) Close() error {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-	return r.client.Disconnect(ctx)
+	return nil
 }
 
-func (r *MongoUserRepository) IsEmpty() (bool, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+func (r *MongoUserRepository) IsEmpty(ctx context.Context) (bool, error) {
+	ctx, cancel := r.timeouts.withTimeout(ctx, "user.is_empty")
 	defer cancel()
 	cnt, err := r.collection.CountDocuments(ctx, bson.M{})
 	if err != nil {
@@ -67,15 +55,15 @@ func (r *MongoUserRepository) IsEmpty() (bool, error) {
 	return cnt == 0, nil
 }
 
-func (r *MongoUserRepository) CreateUser(username, password string) (domain.User, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+func (r *MongoUserRepository) CreateUser(ctx context.Context, username, password string) (domain.User, error) {
+	ctx, cancel := r.timeouts.withTimeout(ctx, "user.create")
 	defer cancel()
 
 	// Check existing
 	var existing domain.User
 	err := r.collection.FindOne(ctx, bson.M{"username": username}).Decode(&existing)
 	if err == nil {
-		return domain.User{}, fmt.Errorf("username already exists")
+		return domain.User{}, ErrUsernameTaken
 	}
 	if err != nil && err != mongo.ErrNoDocuments {
 		return domain.User{}, fmt.Errorf("failed to check username: %w", err)
@@ -86,13 +74,13 @@ func (r *MongoUserRepository) CreateUser(username, password string) (domain.User
 		return domain.User{}, fmt.Errorf("failed to hash password: %w", err)
 	}
 
-	role := "user"
-	empty, err := r.IsEmpty()
+	role := domain.RoleUser
+	empty, err := r.IsEmpty(ctx)
 	if err != nil {
 		return domain.User{}, err
 	}
 	if empty {
-		role = "admin"
+		role = domain.RoleAdmin
 	}
 
 	u := domain.User{
@@ -110,8 +98,8 @@ func (r *MongoUserRepository) CreateUser(username, password string) (domain.User
 	return u, nil
 }
 
-func (r *MongoUserRepository) GetByUsername(username string) (domain.User, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+func (r *MongoUserRepository) GetByUsername(ctx context.Context, username string) (domain.User, error) {
+	ctx, cancel := r.timeouts.withTimeout(ctx, "user.get_by_username")
 	defer cancel()
 	var u domain.User
 	if err := r.collection.FindOne(ctx, bson.M{"username": username}).Decode(&u); err != nil {
@@ -123,21 +111,43 @@ func (r *MongoUserRepository) GetByUsername(username string) (domain.User, error
 	return u, nil
 }
 
+func (r *MongoUserRepository) GetByID(ctx context.Context, idHex string) (domain.User, error) {
+	ctx, cancel := r.timeouts.withTimeout(ctx, "user.get_by_id")
+	defer cancel()
+	id, err := primitive.ObjectIDFromHex(idHex)
+	if err != nil {
+		return domain.User{}, ErrUserNotFound
+	}
+	var u domain.User
+	if err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&u); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return domain.User{}, ErrUserNotFound
+		}
+		return domain.User{}, fmt.Errorf("failed to find user: %w", err)
+	}
+	u.PasswordHash = ""
+	return u, nil
+}
+
 func (r *MongoUserRepository) VerifyPassword(u domain.User, password string) bool {
 	err := bcrypt.CompareHashAndPassword([]byte(u.PasswordHash), []byte(password))
 	return err == nil
 }
 
-func (r *MongoUserRepository) PromoteUser(idHex string) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+func (r *MongoUserRepository) PromoteUser(ctx context.Context, idHex string) error {
+	return r.SetRole(ctx, idHex, domain.RoleAdmin)
+}
+
+func (r *MongoUserRepository) SetRole(ctx context.Context, idHex, role string) error {
+	ctx, cancel := r.timeouts.withTimeout(ctx, "user.set_role")
 	defer cancel()
 	id, err := primitive.ObjectIDFromHex(idHex)
 	if err != nil {
-		return fmt.Errorf("invalid id: %w", err)
+		return ErrUserNotFound
 	}
-	res, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{"role": "admin"}})
+	res, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{"role": role}})
 	if err != nil {
-		return fmt.Errorf("failed to promote: %w", err)
+		return fmt.Errorf("failed to set role: %w", err)
 	}
 	if res.MatchedCount == 0 {
 		return ErrUserNotFound
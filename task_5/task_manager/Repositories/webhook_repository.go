@@ -0,0 +1,225 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	domain "task_manager/Domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var (
+	ErrWebhookNotFound  error = domain.NewNotFoundError("webhook not found")
+	ErrDeliveryNotFound error = domain.NewNotFoundError("delivery not found")
+)
+
+// IWebhookRepository defines the interface for webhooks and their delivery log.
+type IWebhookRepository interface {
+	// Create stores a new webhook, assigning its ID.
+	Create(ctx context.Context, w domain.Webhook) (domain.Webhook, error)
+	GetByID(ctx context.Context, id string) (domain.Webhook, error)
+	// List returns every webhook, oldest first.
+	List(ctx context.Context) ([]domain.Webhook, error)
+	// Delete removes the webhook together with its deliveries.
+	Delete(ctx context.Context, id string) error
+
+	// CreateDelivery stores a new delivery, assigning its ID.
+	CreateDelivery(ctx context.Context, d domain.WebhookDelivery) (domain.WebhookDelivery, error)
+	// UpdateDelivery replaces a stored delivery.
+	UpdateDelivery(ctx context.Context, d domain.WebhookDelivery) error
+	GetDelivery(ctx context.Context, id string) (domain.WebhookDelivery, error)
+	// ListDeliveries returns a page of a webhook's deliveries, newest first.
+	ListDeliveries(ctx context.Context, q domain.DeliveryQuery) (domain.DeliveryPage, error)
+	// DueDeliveries returns up to limit pending deliveries whose next attempt
+	// is due at now, the longest overdue first.
+	DueDeliveries(ctx context.Context, now time.Time, limit int) ([]domain.WebhookDelivery, error)
+	Close() error
+}
+
+// MongoWebhookRepository implements IWebhookRepository using MongoDB.
+type MongoWebhookRepository struct {
+	webhooks   *mongo.Collection
+	deliveries *mongo.Collection
+	timeouts   Timeouts
+}
+
+func NewMongoWebhookRepository(db *mongo.Database, timeouts Timeouts) (IWebhookRepository, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), mongoConnectTimeout)
+	defer cancel()
+
+	deliveries := db.Collection("webhook_deliveries")
+
+	// Indexes backing the retry queue and the delivery log
+	_, err := deliveries.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "next_attempt_at", Value: 1}}},
+		{Keys: bson.D{{Key: "webhook_id", Value: 1}, {Key: "created_at", Value: -1}}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create webhook delivery indexes: %w", err)
+	}
+
+	return &MongoWebhookRepository{
+		webhooks:   db.Collection("webhooks"),
+		deliveries: deliveries,
+		timeouts:   timeouts,
+	}, nil
+}
+
+// Close is a no-op; the shared client is disconnected by its owner.
+func (r *MongoWebhookRepository) Close() error {
+	return nil
+}
+
+func (r *MongoWebhookRepository) Create(ctx context.Context, w domain.Webhook) (domain.Webhook, error) {
+	ctx, cancel := r.timeouts.withTimeout(ctx, "webhook.create")
+	defer cancel()
+
+	w.ID = primitive.NewObjectID().Hex()
+	if _, err := r.webhooks.InsertOne(ctx, w); err != nil {
+		return domain.Webhook{}, fmt.Errorf("failed to insert webhook: %w", err)
+	}
+	return w, nil
+}
+
+func (r *MongoWebhookRepository) GetByID(ctx context.Context, id string) (domain.Webhook, error) {
+	ctx, cancel := r.timeouts.withTimeout(ctx, "webhook.get")
+	defer cancel()
+
+	var w domain.Webhook
+	if err := r.webhooks.FindOne(ctx, bson.M{"_id": id}).Decode(&w); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return domain.Webhook{}, ErrWebhookNotFound
+		}
+		return domain.Webhook{}, fmt.Errorf("failed to find webhook: %w", err)
+	}
+	return w, nil
+}
+
+func (r *MongoWebhookRepository) List(ctx context.Context) ([]domain.Webhook, error) {
+	ctx, cancel := r.timeouts.withTimeout(ctx, "webhook.list")
+	defer cancel()
+
+	cursor, err := r.webhooks.Find(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find webhooks: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	webhooks := []domain.Webhook{}
+	if err := cursor.All(ctx, &webhooks); err != nil {
+		return nil, fmt.Errorf("failed to decode webhooks: %w", err)
+	}
+	return webhooks, nil
+}
+
+func (r *MongoWebhookRepository) Delete(ctx context.Context, id string) error {
+	ctx, cancel := r.timeouts.withTimeout(ctx, "webhook.delete")
+	defer cancel()
+
+	result, err := r.webhooks.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return fmt.Errorf("failed to delete webhook: %w", err)
+	}
+	if result.DeletedCount == 0 {
+		return ErrWebhookNotFound
+	}
+	if _, err := r.deliveries.DeleteMany(ctx, bson.M{"webhook_id": id}); err != nil {
+		return fmt.Errorf("failed to delete webhook deliveries: %w", err)
+	}
+	return nil
+}
+
+func (r *MongoWebhookRepository) CreateDelivery(ctx context.Context, d domain.WebhookDelivery) (domain.WebhookDelivery, error) {
+	ctx, cancel := r.timeouts.withTimeout(ctx, "webhook.create_delivery")
+	defer cancel()
+
+	d.ID = primitive.NewObjectID().Hex()
+	if _, err := r.deliveries.InsertOne(ctx, d); err != nil {
+		return domain.WebhookDelivery{}, fmt.Errorf("failed to insert delivery: %w", err)
+	}
+	return d, nil
+}
+
+func (r *MongoWebhookRepository) UpdateDelivery(ctx context.Context, d domain.WebhookDelivery) error {
+	ctx, cancel := r.timeouts.withTimeout(ctx, "webhook.update_delivery")
+	defer cancel()
+
+	result, err := r.deliveries.ReplaceOne(ctx, bson.M{"_id": d.ID}, d)
+	if err != nil {
+		return fmt.Errorf("failed to update delivery: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return ErrDeliveryNotFound
+	}
+	return nil
+}
+
+func (r *MongoWebhookRepository) GetDelivery(ctx context.Context, id string) (domain.WebhookDelivery, error) {
+	ctx, cancel := r.timeouts.withTimeout(ctx, "webhook.get_delivery")
+	defer cancel()
+
+	var d domain.WebhookDelivery
+	if err := r.deliveries.FindOne(ctx, bson.M{"_id": id}).Decode(&d); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return domain.WebhookDelivery{}, ErrDeliveryNotFound
+		}
+		return domain.WebhookDelivery{}, fmt.Errorf("failed to find delivery: %w", err)
+	}
+	return d, nil
+}
+
+func (r *MongoWebhookRepository) ListDeliveries(ctx context.Context, q domain.DeliveryQuery) (domain.DeliveryPage, error) {
+	ctx, cancel := r.timeouts.withTimeout(ctx, "webhook.list_deliveries")
+	defer cancel()
+
+	filter := bson.M{"webhook_id": q.WebhookID}
+	if q.Status != "" {
+		filter["status"] = q.Status
+	}
+	total, err := r.deliveries.CountDocuments(ctx, filter)
+	if err != nil {
+		return domain.DeliveryPage{}, fmt.Errorf("failed to count deliveries: %w", err)
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}).
+		SetSkip(q.Skip()).
+		SetLimit(int64(q.PageSize))
+	cursor, err := r.deliveries.Find(ctx, filter, opts)
+	if err != nil {
+		return domain.DeliveryPage{}, fmt.Errorf("failed to find deliveries: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var deliveries []domain.WebhookDelivery
+	if err := cursor.All(ctx, &deliveries); err != nil {
+		return domain.DeliveryPage{}, fmt.Errorf("failed to decode deliveries: %w", err)
+	}
+	return domain.NewDeliveryPage(q, deliveries, total), nil
+}
+
+func (r *MongoWebhookRepository) DueDeliveries(ctx context.Context, now time.Time, limit int) ([]domain.WebhookDelivery, error) {
+	ctx, cancel := r.timeouts.withTimeout(ctx, "webhook.due_deliveries")
+	defer cancel()
+
+	filter := bson.M{"status": domain.DeliveryPending, "next_attempt_at": bson.M{"$lte": now}}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "next_attempt_at", Value: 1}, {Key: "_id", Value: 1}}).
+		SetLimit(int64(limit))
+	cursor, err := r.deliveries.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find deliveries: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	deliveries := []domain.WebhookDelivery{}
+	if err := cursor.All(ctx, &deliveries); err != nil {
+		return nil, fmt.Errorf("failed to decode deliveries: %w", err)
+	}
+	return deliveries, nil
+}
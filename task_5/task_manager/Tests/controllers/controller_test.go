@@ -2,14 +2,20 @@ package controllers_test
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"fmt"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
 	"task_manager/Delivery/controllers"
 	domain "task_manager/Domain"
+	infrastructure "task_manager/Infrastructure"
+	repositories "task_manager/Repositories"
 	"task_manager/Tests/mocks"
 	usecases "task_manager/Usecases"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
@@ -19,59 +25,244 @@ import (
 func TestController_ListTasks(t *testing.T) {
 	mockTaskRepo := new(mocks.MockTaskRepository)
 	mockUserRepo := new(mocks.MockUserRepository)
-	taskUsecases := usecases.NewTaskUsecases(mockTaskRepo)
-	userUsecases := usecases.NewUserUsecases(mockUserRepo)
-	ctrl := controllers.NewController(taskUsecases, userUsecases, nil) // jwt not needed for this test
+	taskUsecases := usecases.NewTaskUsecases(mockTaskRepo, mockUserRepo, repositories.NewMemoryAuditRepository(), domain.DefaultWorkflow())
+	userUsecases := usecases.NewUserUsecases(mockUserRepo, repositories.NewMemoryAuditRepository())
+	ctrl := controllers.NewController(taskUsecases, userUsecases, nil, nil, nil, nil, nil, nil, nil) // jwt not needed for this test
 
-	tasks := []domain.Task{{ID: "1", Title: "Task1"}}
-	mockTaskRepo.On("GetAll").Return(tasks, nil)
+	tasks := []domain.Task{{ID: "1", Title: "Task1", OwnerID: "u1"}}
+	page := domain.TaskPage{Tasks: tasks, Total: 1, Page: 1, PageSize: domain.DefaultPageSize}
+	mockTaskRepo.On("List", mock.MatchedBy(func(q domain.TaskQuery) bool {
+		return q.OwnerID == "u1"
+	})).Return(page, nil)
 
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("GET", "/tasks", nil)
+	c.Set("user_id", "u1")
+	c.Set("role", "user")
 	ctrl.ListTasks(c)
 
 	assert.Equal(t, http.StatusOK, w.Code)
-	var response map[string][]domain.Task
+	var response domain.TaskPage
 	json.Unmarshal(w.Body.Bytes(), &response)
-	assert.Equal(t, tasks, response["data"])
+	assert.Equal(t, page, response)
 	mockTaskRepo.AssertExpectations(t)
 }
 
+func TestController_ListTasks_QueryParams(t *testing.T) {
+	mockTaskRepo := new(mocks.MockTaskRepository)
+	mockUserRepo := new(mocks.MockUserRepository)
+	taskUsecases := usecases.NewTaskUsecases(mockTaskRepo, mockUserRepo, repositories.NewMemoryAuditRepository(), domain.DefaultWorkflow())
+	userUsecases := usecases.NewUserUsecases(mockUserRepo, repositories.NewMemoryAuditRepository())
+	ctrl := controllers.NewController(taskUsecases, userUsecases, nil, nil, nil, nil, nil, nil, nil)
+
+	mockTaskRepo.On("List", mock.MatchedBy(func(q domain.TaskQuery) bool {
+		return q.Status == "pending" && q.Search == "milk" && q.SortBy == "title" && q.SortDesc &&
+			q.Page == 2 && q.PageSize == 5 && q.DueAfter.Equal(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	})).Return(domain.TaskPage{Page: 2, PageSize: 5}, nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("GET", "/tasks?status=pending&q=milk&sort=title&order=desc&page=2&page_size=5&due_after=2025-01-01T00:00:00Z", nil)
+	c.Set("user_id", "u1")
+	c.Set("role", "user")
+	ctrl.ListTasks(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockTaskRepo.AssertExpectations(t)
+}
+
+func TestController_ListTasks_InvalidSort(t *testing.T) {
+	mockTaskRepo := new(mocks.MockTaskRepository)
+	mockUserRepo := new(mocks.MockUserRepository)
+	taskUsecases := usecases.NewTaskUsecases(mockTaskRepo, mockUserRepo, repositories.NewMemoryAuditRepository(), domain.DefaultWorkflow())
+	userUsecases := usecases.NewUserUsecases(mockUserRepo, repositories.NewMemoryAuditRepository())
+	ctrl := controllers.NewController(taskUsecases, userUsecases, nil, nil, nil, nil, nil, nil, nil)
+
+	r := gin.New()
+	r.Use(infrastructure.ErrorHandler())
+	r.GET("/tasks", ctrl.ListTasks)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/tasks?sort=password", nil))
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	var response infrastructure.ErrorResponse
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, string(domain.CodeValidation), response.Error.Code)
+	assert.Equal(t, []domain.FieldError{{Field: "sort", Message: "invalid sort field"}}, response.Error.Fields)
+	assert.NotEmpty(t, response.Error.RequestID)
+	mockTaskRepo.AssertNotCalled(t, "List", mock.Anything)
+}
+
+func TestController_SearchTasks(t *testing.T) {
+	mockTaskRepo := new(mocks.MockTaskRepository)
+	taskUsecases := usecases.NewTaskUsecases(mockTaskRepo, nil, repositories.NewMemoryAuditRepository(), domain.DefaultWorkflow())
+	ctrl := controllers.NewController(taskUsecases, nil, nil, nil, nil, nil, nil, nil, nil)
+
+	hits := []domain.SearchHit{{Task: domain.Task{ID: "1", Title: "Buy milk"}, Score: 3, Highlights: map[string]string{"title": "Buy <mark>milk</mark>"}}}
+	mockTaskRepo.On("Search", mock.MatchedBy(func(s domain.TaskSearch) bool {
+		return s.OwnerID == "u1" && len(s.Terms) == 2 && s.Terms[1].Prefix && s.Page == 2
+	})).Return(domain.TaskSearchPage{Hits: hits, Total: 1, Page: 2, PageSize: domain.DefaultPageSize}, nil)
+
+	r := gin.New()
+	r.Use(infrastructure.ErrorHandler(), func(c *gin.Context) { c.Set("user_id", "u1"); c.Set("role", "user") })
+	r.GET("/tasks/search", ctrl.SearchTasks)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/tasks/search?q=milk+fre*&page=2", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response domain.TaskSearchPage
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, hits, response.Hits)
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/tasks/search?q=", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockTaskRepo.AssertNumberOfCalls(t, "Search", 1)
+}
+
+func TestController_GetTask_NotFound(t *testing.T) {
+	mockTaskRepo := new(mocks.MockTaskRepository)
+	mockUserRepo := new(mocks.MockUserRepository)
+	taskUsecases := usecases.NewTaskUsecases(mockTaskRepo, mockUserRepo, repositories.NewMemoryAuditRepository(), domain.DefaultWorkflow())
+	userUsecases := usecases.NewUserUsecases(mockUserRepo, repositories.NewMemoryAuditRepository())
+	ctrl := controllers.NewController(taskUsecases, userUsecases, nil, nil, nil, nil, nil, nil, nil)
+
+	mockTaskRepo.On("GetByID", "missing").Return(domain.Task{}, fmt.Errorf("lookup: %w", repositories.ErrNotFound))
+
+	r := gin.New()
+	r.Use(infrastructure.ErrorHandler())
+	r.GET("/tasks/:id", ctrl.GetTask)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/tasks/missing", nil)
+	req.Header.Set(infrastructure.RequestIDHeader, "req-42")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	var response infrastructure.ErrorResponse
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, string(domain.CodeNotFound), response.Error.Code)
+	assert.Equal(t, "task not found", response.Error.Message)
+	assert.Equal(t, "req-42", response.Error.RequestID)
+	assert.Equal(t, "req-42", w.Header().Get(infrastructure.RequestIDHeader))
+}
+
 func TestController_GetTask(t *testing.T) {
 	mockTaskRepo := new(mocks.MockTaskRepository)
 	mockUserRepo := new(mocks.MockUserRepository)
-	taskUsecases := usecases.NewTaskUsecases(mockTaskRepo)
-	userUsecases := usecases.NewUserUsecases(mockUserRepo)
-	ctrl := controllers.NewController(taskUsecases, userUsecases, nil)
+	taskUsecases := usecases.NewTaskUsecases(mockTaskRepo, mockUserRepo, repositories.NewMemoryAuditRepository(), domain.DefaultWorkflow())
+	userUsecases := usecases.NewUserUsecases(mockUserRepo, repositories.NewMemoryAuditRepository())
+	ctrl := controllers.NewController(taskUsecases, userUsecases, nil, nil, nil, nil, nil, nil, nil)
 
-	task := domain.Task{ID: "1", Title: "Task1"}
+	task := domain.Task{ID: "1", Title: "Task1", OwnerID: "u1", Version: 2}
 	mockTaskRepo.On("GetByID", "1").Return(task, nil)
 
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
+	c.Set("user_id", "u1")
+	c.Set("role", "user")
+	c.Request = httptest.NewRequest("GET", "/tasks/1", nil)
 	c.Params = gin.Params{{Key: "id", Value: "1"}}
 	ctrl.GetTask(c)
 
 	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `"2"`, w.Header().Get("ETag"))
 	var response map[string]domain.Task
 	json.Unmarshal(w.Body.Bytes(), &response)
 	assert.Equal(t, task, response["data"])
 	mockTaskRepo.AssertExpectations(t)
 }
 
+// patchRouter serves PATCH /tasks/:id as user u1.
+func patchRouter(ctrl *controllers.Controller) *gin.Engine {
+	r := gin.New()
+	r.Use(infrastructure.ErrorHandler())
+	r.PATCH("/tasks/:id", func(c *gin.Context) {
+		c.Set("user_id", "u1")
+		c.Set("role", "user")
+		ctrl.PatchTask(c)
+	})
+	return r
+}
+
+func TestController_PatchTask(t *testing.T) {
+	mockTaskRepo := new(mocks.MockTaskRepository)
+	mockUserRepo := new(mocks.MockUserRepository)
+	taskUsecases := usecases.NewTaskUsecases(mockTaskRepo, mockUserRepo, repositories.NewMemoryAuditRepository(), domain.DefaultWorkflow())
+	ctrl := controllers.NewController(taskUsecases, nil, nil, nil, nil, nil, nil, nil, nil)
+
+	existing := domain.Task{ID: "1", Title: "Old", Description: "Keep me", Status: "pending", Priority: "high",
+		Tags: []string{"backend"}, DueDate: time.Now().Add(time.Hour), OwnerID: "u1", Version: 3}
+	mockTaskRepo.On("GetByID", "1").Return(existing, nil)
+	mockTaskRepo.On("GetChildren", "1").Return([]domain.Task{}, nil)
+	mockTaskRepo.On("Update", "1", mock.MatchedBy(func(t domain.Task) bool {
+		return t.Title == "New" && t.Description == "Keep me" && t.Priority == "high" &&
+			len(t.Tags) == 0 && t.Version == 3
+	})).Return(domain.Task{ID: "1", Title: "New", Description: "Keep me", Status: "pending", OwnerID: "u1", Version: 4}, nil)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("PATCH", "/tasks/1", bytes.NewBufferString(`{"title":"New","tags":null}`))
+	req.Header.Set("Content-Type", "application/merge-patch+json")
+	req.Header.Set("If-Match", `"3"`)
+	patchRouter(ctrl).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `"4"`, w.Header().Get("ETag"))
+	mockTaskRepo.AssertExpectations(t)
+}
+
+func TestController_PatchTask_StaleIfMatch(t *testing.T) {
+	mockTaskRepo := new(mocks.MockTaskRepository)
+	taskUsecases := usecases.NewTaskUsecases(mockTaskRepo, new(mocks.MockUserRepository), repositories.NewMemoryAuditRepository(), domain.DefaultWorkflow())
+	ctrl := controllers.NewController(taskUsecases, nil, nil, nil, nil, nil, nil, nil, nil)
+
+	mockTaskRepo.On("GetByID", "1").Return(domain.Task{ID: "1", Title: "Old", Status: "pending", OwnerID: "u1", Version: 5}, nil)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("PATCH", "/tasks/1", bytes.NewBufferString(`{"title":"New"}`))
+	req.Header.Set("If-Match", `"4"`)
+	patchRouter(ctrl).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusPreconditionFailed, w.Code)
+	var response infrastructure.ErrorResponse
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, string(domain.CodePreconditionFailed), response.Error.Code)
+	mockTaskRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestController_PatchTask_UnknownField(t *testing.T) {
+	mockTaskRepo := new(mocks.MockTaskRepository)
+	taskUsecases := usecases.NewTaskUsecases(mockTaskRepo, new(mocks.MockUserRepository), repositories.NewMemoryAuditRepository(), domain.DefaultWorkflow())
+	ctrl := controllers.NewController(taskUsecases, nil, nil, nil, nil, nil, nil, nil, nil)
+
+	mockTaskRepo.On("GetByID", "1").Return(domain.Task{ID: "1", Title: "Old", Status: "pending", OwnerID: "u1", Version: 1}, nil)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("PATCH", "/tasks/1", bytes.NewBufferString(`{"owner_id":"u2"}`))
+	patchRouter(ctrl).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockTaskRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
 func TestController_CreateTask(t *testing.T) {
 	mockTaskRepo := new(mocks.MockTaskRepository)
 	mockUserRepo := new(mocks.MockUserRepository)
-	taskUsecases := usecases.NewTaskUsecases(mockTaskRepo)
-	userUsecases := usecases.NewUserUsecases(mockUserRepo)
-	ctrl := controllers.NewController(taskUsecases, userUsecases, nil)
+	taskUsecases := usecases.NewTaskUsecases(mockTaskRepo, mockUserRepo, repositories.NewMemoryAuditRepository(), domain.DefaultWorkflow())
+	userUsecases := usecases.NewUserUsecases(mockUserRepo, repositories.NewMemoryAuditRepository())
+	ctrl := controllers.NewController(taskUsecases, userUsecases, nil, nil, nil, nil, nil, nil, nil)
 
-	created := domain.Task{ID: "1", Title: "New Task", Status: "pending"}
-	mockTaskRepo.On("Create", mock.AnythingOfType("domain.Task")).Return(created, nil)
+	created := domain.Task{ID: "1", Title: "New Task", Status: "pending", OwnerID: "u1"}
+	mockTaskRepo.On("Create", mock.MatchedBy(func(t domain.Task) bool {
+		return t.OwnerID == "u1"
+	})).Return(created, nil)
 
 	body := map[string]interface{}{
-		"title":  "New Task",
-		"status": "pending",
+		"title":    "New Task",
+		"status":   "pending",
+		"due_date": time.Now().Add(time.Hour),
 	}
 	jsonBody, _ := json.Marshal(body)
 
@@ -79,11 +270,14 @@ func TestController_CreateTask(t *testing.T) {
 	c, _ := gin.CreateTestContext(w)
 	c.Request = httptest.NewRequest("POST", "/tasks", bytes.NewBuffer(jsonBody))
 	c.Request.Header.Set("Content-Type", "application/json")
+	c.Set("user_id", "u1")
+	c.Set("role", "user")
 	ctrl.CreateTask(c)
 
 	assert.Equal(t, http.StatusCreated, w.Code)
 	var response map[string]domain.Task
 	json.Unmarshal(w.Body.Bytes(), &response)
+	created.NextStatuses = []string{"in_progress", "completed"}
 	assert.Equal(t, created, response["data"])
 	mockTaskRepo.AssertExpectations(t)
 }
@@ -91,9 +285,9 @@ func TestController_CreateTask(t *testing.T) {
 func TestController_Register(t *testing.T) {
 	mockTaskRepo := new(mocks.MockTaskRepository)
 	mockUserRepo := new(mocks.MockUserRepository)
-	taskUsecases := usecases.NewTaskUsecases(mockTaskRepo)
-	userUsecases := usecases.NewUserUsecases(mockUserRepo)
-	ctrl := controllers.NewController(taskUsecases, userUsecases, nil)
+	taskUsecases := usecases.NewTaskUsecases(mockTaskRepo, mockUserRepo, repositories.NewMemoryAuditRepository(), domain.DefaultWorkflow())
+	userUsecases := usecases.NewUserUsecases(mockUserRepo, repositories.NewMemoryAuditRepository())
+	ctrl := controllers.NewController(taskUsecases, userUsecases, nil, nil, nil, nil, nil, nil, nil)
 
 	user := domain.User{Username: "user", Role: "user"}
 	mockUserRepo.On("CreateUser", "user", "pass").Return(user, nil)
@@ -116,3 +310,212 @@ func TestController_Register(t *testing.T) {
 	assert.Equal(t, user, response["data"])
 	mockUserRepo.AssertExpectations(t)
 }
+
+func TestController_BulkTasks(t *testing.T) {
+	taskRepo := repositories.NewMemoryTaskRepository()
+	taskUsecases := usecases.NewTaskUsecases(taskRepo, nil, repositories.NewMemoryAuditRepository(), domain.DefaultWorkflow())
+	ctrl := controllers.NewController(taskUsecases, nil, nil, nil, nil, nil, nil, nil, nil)
+	role, _ := domain.BuiltInRole(domain.RoleUser)
+	existing, err := taskRepo.Create(context.Background(), domain.Task{Title: "Old", Description: "Keep me", Status: "pending",
+		Priority: "medium", DueDate: time.Now().Add(time.Hour), OwnerID: "u1"})
+	assert.NoError(t, err)
+
+	r := gin.New()
+	r.Use(infrastructure.ErrorHandler())
+	r.POST("/tasks/bulk", func(c *gin.Context) {
+		c.Set("user_id", "u1")
+		c.Set("role", role.Name)
+		c.Set("permissions", role.Permissions)
+		ctrl.BulkTasks(c)
+	})
+
+	body := fmt.Sprintf(`{"operations":[
+		{"op":"create","task":{"title":"New","due_date":%q}},
+		{"op":"update","id":%q,"version":1,"task":{"title":"New title"}},
+		{"op":"delete","id":%q},
+		{"op":"create","task":{"title":""}}
+	]}`, time.Now().Add(time.Hour).Format(time.RFC3339), existing.ID, existing.ID)
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("POST", "/tasks/bulk", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusMultiStatus, w.Code)
+	var response struct {
+		Data []struct {
+			Status int                       `json:"status"`
+			Data   *domain.Task              `json:"data"`
+			Error  *infrastructure.ErrorBody `json:"error"`
+		} `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	if !assert.Len(t, response.Data, 4) {
+		return
+	}
+	assert.Equal(t, http.StatusCreated, response.Data[0].Status)
+	assert.Equal(t, "New", response.Data[0].Data.Title)
+	assert.Equal(t, http.StatusOK, response.Data[1].Status)
+	assert.Equal(t, "New title", response.Data[1].Data.Title)
+	assert.Equal(t, "Keep me", response.Data[1].Data.Description)
+	assert.Equal(t, http.StatusForbidden, response.Data[2].Status)
+	assert.Equal(t, string(domain.CodeForbidden), response.Data[2].Error.Code)
+	assert.Equal(t, http.StatusBadRequest, response.Data[3].Status)
+	assert.Equal(t, string(domain.CodeValidation), response.Data[3].Error.Code)
+}
+
+func TestController_Comments(t *testing.T) {
+	taskRepo := repositories.NewMemoryTaskRepository()
+	taskUsecases := usecases.NewTaskUsecases(taskRepo, nil, repositories.NewMemoryAuditRepository(), domain.DefaultWorkflow())
+	commentUsecases := usecases.NewCommentUsecases(repositories.NewMemoryCommentRepository(), taskRepo, repositories.NewMemoryUserRepository())
+	ctrl := controllers.NewController(taskUsecases, nil, nil, nil, nil, commentUsecases, nil, nil, nil)
+	role, _ := domain.BuiltInRole(domain.RoleUser)
+	task, err := taskRepo.Create(context.Background(), domain.Task{Title: "Plan", Status: "pending", DueDate: time.Now().Add(time.Hour), OwnerID: "u1"})
+	assert.NoError(t, err)
+
+	r := gin.New()
+	r.Use(infrastructure.ErrorHandler(), func(c *gin.Context) {
+		c.Set("user_id", c.GetHeader("X-User"))
+		c.Set("role", role.Name)
+		c.Set("permissions", role.Permissions)
+	})
+	r.GET("/tasks/:id/comments", ctrl.ListComments)
+	r.POST("/tasks/:id/comments", ctrl.AddComment)
+	r.PATCH("/tasks/:id/comments/:comment_id", ctrl.EditComment)
+	r.DELETE("/tasks/:id/comments/:comment_id", ctrl.DeleteComment)
+	do := func(method, path, user, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(method, path, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-User", user)
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := do("POST", "/tasks/"+task.ID+"/comments", "u1", `{"body":"First"}`)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var created map[string]domain.Comment
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	comment := created["data"]
+	assert.Equal(t, "u1", comment.AuthorID)
+
+	assert.Equal(t, http.StatusBadRequest, do("POST", "/tasks/"+task.ID+"/comments", "u1", `{}`).Code)
+	assert.Equal(t, http.StatusNotFound, do("POST", "/tasks/"+task.ID+"/comments", "u2", `{"body":"Hi"}`).Code)
+
+	w = do("PATCH", "/tasks/"+task.ID+"/comments/"+comment.ID, "u1", `{"body":"First, edited"}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = do("GET", "/tasks/"+task.ID+"/comments?page_size=10", "u1", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	var page domain.CommentPage
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+	if assert.Len(t, page.Comments, 1) {
+		assert.Equal(t, "First, edited", page.Comments[0].Body)
+		assert.NotNil(t, page.Comments[0].EditedAt)
+	}
+
+	assert.Equal(t, http.StatusNoContent, do("DELETE", "/tasks/"+task.ID+"/comments/"+comment.ID, "u1", "").Code)
+	assert.Equal(t, http.StatusNotFound, do("DELETE", "/tasks/"+task.ID+"/comments/"+comment.ID, "u1", "").Code)
+}
+
+func TestController_Attachments(t *testing.T) {
+	taskRepo := repositories.NewMemoryTaskRepository()
+	taskUsecases := usecases.NewTaskUsecases(taskRepo, nil, repositories.NewMemoryAuditRepository(), domain.DefaultWorkflow())
+	config := usecases.DefaultAttachmentConfig()
+	config.MaxSize = 1 << 10
+	attachmentUsecases := usecases.NewAttachmentUsecases(repositories.NewMemoryAttachmentRepository(), repositories.NewMemoryBlobStore(), taskRepo, config)
+	ctrl := controllers.NewController(taskUsecases, nil, nil, nil, nil, nil, attachmentUsecases, nil, nil)
+	role, _ := domain.BuiltInRole(domain.RoleUser)
+	task, err := taskRepo.Create(context.Background(), domain.Task{Title: "Plan", Status: "pending", DueDate: time.Now().Add(time.Hour), OwnerID: "u1"})
+	assert.NoError(t, err)
+
+	r := gin.New()
+	r.Use(infrastructure.ErrorHandler(), func(c *gin.Context) {
+		c.Set("user_id", "u1")
+		c.Set("role", role.Name)
+		c.Set("permissions", role.Permissions)
+	})
+	r.POST("/tasks/:id/attachments", ctrl.UploadAttachment)
+	r.GET("/tasks/:id/attachments/:attachment_id", ctrl.DownloadAttachment)
+	upload := func(filename string, content []byte) *httptest.ResponseRecorder {
+		var body bytes.Buffer
+		form := multipart.NewWriter(&body)
+		part, _ := form.CreateFormFile("file", filename)
+		part.Write(content)
+		form.Close()
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest("POST", "/tasks/"+task.ID+"/attachments", &body)
+		req.Header.Set("Content-Type", form.FormDataContentType())
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := upload("report notes.txt", []byte("hello"))
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var created map[string]domain.Attachment
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	attachment := created["data"]
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/tasks/"+task.ID+"/attachments/"+attachment.ID, nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "hello", w.Body.String())
+	assert.Equal(t, "text/plain; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Equal(t, `attachment; filename="report notes.txt"`, w.Header().Get("Content-Disposition"))
+	assert.Equal(t, "nosniff", w.Header().Get("X-Content-Type-Options"))
+	assert.Equal(t, "sha-256=:LPJNul+wow4m6DsqxbninhsWHlwfp0JecwQzYpOLmCQ=:", w.Header().Get("Content-Digest"))
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, upload("big.txt", bytes.Repeat([]byte("x"), 2<<10)).Code)
+	assert.Equal(t, http.StatusUnsupportedMediaType, upload("tool.exe", []byte("MZ\x90\x00")).Code)
+}
+
+func TestController_Projects(t *testing.T) {
+	userRepo := repositories.NewMemoryUserRepository()
+	alice, _ := userRepo.CreateUser(context.Background(), "alice", "password123")
+	bob, _ := userRepo.CreateUser(context.Background(), "bob", "password123")
+	projectUsecases := usecases.NewProjectUsecases(repositories.NewMemoryProjectRepository(), userRepo, repositories.NewMemoryAuditRepository())
+	ctrl := controllers.NewController(nil, nil, nil, nil, nil, nil, nil, projectUsecases, nil)
+	role, _ := domain.BuiltInRole(domain.RoleUser)
+
+	r := gin.New()
+	r.Use(infrastructure.ErrorHandler(), func(c *gin.Context) {
+		c.Set("user_id", c.GetHeader("X-User"))
+		c.Set("role", role.Name)
+		c.Set("permissions", role.Permissions)
+	})
+	r.GET("/projects", ctrl.ListProjects)
+	r.POST("/projects", ctrl.CreateProject)
+	r.GET("/projects/:pid", ctrl.GetProject)
+	r.PUT("/projects/:pid/members/:user_id", ctrl.SetProjectMember)
+	r.DELETE("/projects/:pid/members/:user_id", ctrl.RemoveProjectMember)
+	do := func(method, path, user, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(method, path, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-User", user)
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := do("POST", "/projects", alice.ID.Hex(), `{"name":"Launch"}`)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var created map[string]domain.Project
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	project := created["data"]
+	assert.Equal(t, []domain.ProjectMember{{UserID: alice.ID.Hex(), Role: domain.ProjectRoleManager}}, project.Members)
+
+	assert.Equal(t, http.StatusBadRequest, do("POST", "/projects", alice.ID.Hex(), `{}`).Code)
+	assert.Equal(t, http.StatusNotFound, do("GET", "/projects/"+project.ID, bob.ID.Hex(), "").Code)
+
+	memberPath := "/projects/" + project.ID + "/members/" + bob.ID.Hex()
+	assert.Equal(t, http.StatusOK, do("PUT", memberPath, alice.ID.Hex(), `{"role":"viewer"}`).Code)
+	assert.Equal(t, http.StatusForbidden, do("PUT", memberPath, bob.ID.Hex(), `{"role":"manager"}`).Code)
+
+	w = do("GET", "/projects", bob.ID.Hex(), "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	var list map[string][]domain.Project
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+	assert.Len(t, list["data"], 1)
+
+	assert.Equal(t, http.StatusConflict, do("DELETE", "/projects/"+project.ID+"/members/"+alice.ID.Hex(), alice.ID.Hex(), "").Code)
+	assert.Equal(t, http.StatusNoContent, do("DELETE", memberPath, alice.ID.Hex(), "").Code)
+}
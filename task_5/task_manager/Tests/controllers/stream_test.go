@@ -0,0 +1,96 @@
+package controllers_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"task_manager/Delivery/controllers"
+	domain "task_manager/Domain"
+	infrastructure "task_manager/Infrastructure"
+	repositories "task_manager/Repositories"
+	"task_manager/Tests/mocks"
+	usecases "task_manager/Usecases"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/websocket"
+)
+
+// streamServer serves GET /tasks/stream as user u1 and publishes two events
+// on its task usecases: one about another user's task, then one about u1's.
+func streamServer(t *testing.T) *httptest.Server {
+	taskUsecases := usecases.NewTaskUsecases(new(mocks.MockTaskRepository), new(mocks.MockUserRepository), repositories.NewMemoryAuditRepository(), domain.DefaultWorkflow())
+	ctrl := controllers.NewController(taskUsecases, nil, nil, nil, nil, nil, nil, nil, nil)
+	taskUsecases.Events().Publish(domain.TaskEvent{Type: domain.EventTaskCreated, Task: &domain.Task{ID: "hidden", OwnerID: "u2"}})
+	taskUsecases.Events().Publish(domain.TaskEvent{Type: domain.EventTaskCreated, Task: &domain.Task{ID: "1", Title: "Task", Status: domain.StatusPending, OwnerID: "u1"}})
+
+	r := gin.New()
+	r.Use(infrastructure.ErrorHandler())
+	r.GET("/tasks/stream", func(c *gin.Context) {
+		c.Set("user_id", "u1")
+		c.Set("role", "user")
+		ctrl.StreamTasks(c)
+	})
+	server := httptest.NewServer(r)
+	t.Cleanup(func() {
+		taskUsecases.CloseStreams()
+		server.Close()
+	})
+	return server
+}
+
+func TestController_StreamTasks_SSE(t *testing.T) {
+	server := streamServer(t)
+
+	req, _ := http.NewRequest("GET", server.URL+"/tasks/stream", nil)
+	req.Header.Set("Last-Event-ID", "1") // resume after the first event
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	reader := bufio.NewReader(resp.Body)
+	var lines []string
+	for len(lines) < 3 {
+		line, err := reader.ReadString('\n')
+		require.NoError(t, err)
+		lines = append(lines, strings.TrimSuffix(line, "\n"))
+	}
+	assert.Equal(t, "id: 2", lines[0])
+	assert.Equal(t, "event: task.created", lines[1])
+
+	var event domain.TaskEvent
+	require.NoError(t, json.Unmarshal([]byte(strings.TrimPrefix(lines[2], "data: ")), &event))
+	assert.Equal(t, int64(2), event.ID)
+	assert.Equal(t, "1", event.Task.ID)
+}
+
+func TestController_StreamTasks_WebSocket(t *testing.T) {
+	server := streamServer(t)
+
+	ws, err := websocket.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/tasks/stream?last_event_id=1", "", server.URL)
+	require.NoError(t, err)
+	defer ws.Close()
+
+	var event domain.TaskEvent
+	require.NoError(t, websocket.JSON.Receive(ws, &event))
+	assert.Equal(t, int64(2), event.ID)
+	assert.Equal(t, domain.EventTaskCreated, event.Type)
+	assert.Equal(t, "1", event.Task.ID)
+}
+
+func TestController_StreamTasks_InvalidLastEventID(t *testing.T) {
+	server := streamServer(t)
+
+	resp, err := http.Get(server.URL + "/tasks/stream?last_event_id=abc")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
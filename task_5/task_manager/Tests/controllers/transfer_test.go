@@ -0,0 +1,104 @@
+package controllers_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"task_manager/Delivery/controllers"
+	domain "task_manager/Domain"
+	infrastructure "task_manager/Infrastructure"
+	repositories "task_manager/Repositories"
+	usecases "task_manager/Usecases"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func transferRouter(taskRepo repositories.ITaskRepository) *gin.Engine {
+	taskUsecases := usecases.NewTaskUsecases(taskRepo, nil, repositories.NewMemoryAuditRepository(), domain.DefaultWorkflow())
+	ctrl := controllers.NewController(taskUsecases, nil, nil, nil, nil, nil, nil, nil, nil)
+	role, _ := domain.BuiltInRole(domain.RoleUser)
+	actor := func(c *gin.Context) {
+		c.Set("user_id", "u1")
+		c.Set("role", role.Name)
+		c.Set("permissions", role.Permissions)
+	}
+	r := gin.New()
+	r.Use(infrastructure.ErrorHandler(), actor)
+	r.GET("/tasks/export", ctrl.ExportTasks)
+	r.POST("/tasks/import", ctrl.ImportTasks)
+	return r
+}
+
+func TestController_ExportTasks(t *testing.T) {
+	taskRepo := repositories.NewMemoryTaskRepository()
+	due := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	for _, task := range []domain.Task{
+		{Title: "Mine", Status: "pending", Priority: "high", DueDate: due, OwnerID: "u1"},
+		{Title: "Not mine", Status: "pending", Priority: "high", DueDate: due, OwnerID: "u2"},
+	} {
+		_, err := taskRepo.Create(context.Background(), task)
+		require.NoError(t, err)
+	}
+
+	w := httptest.NewRecorder()
+	transferRouter(taskRepo).ServeHTTP(w, httptest.NewRequest("GET", "/tasks/export?format=ics", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/calendar; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Equal(t, `attachment; filename="tasks.ics"`, w.Header().Get("Content-Disposition"))
+	assert.Contains(t, w.Body.String(), "SUMMARY:Mine\r\nDUE:20250601T120000Z\r\n")
+	assert.NotContains(t, w.Body.String(), "Not mine")
+
+	w = httptest.NewRecorder()
+	transferRouter(taskRepo).ServeHTTP(w, httptest.NewRequest("GET", "/tasks/export?format=xml", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Type"), "application/json")
+}
+
+func TestController_ImportTasks(t *testing.T) {
+	taskRepo := repositories.NewMemoryTaskRepository()
+	due := time.Now().Add(24 * time.Hour).UTC().Format(time.RFC3339)
+	body := "title,due_date,priority\n" +
+		"Write report," + due + ",high\n" +
+		"," + due + ",low\n"
+
+	w := httptest.NewRecorder()
+	transferRouter(taskRepo).ServeHTTP(w, httptest.NewRequest("POST", "/tasks/import?format=csv&dry_run=true", strings.NewReader(body)))
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response struct {
+		Data struct {
+			DryRun   bool `json:"dry_run"`
+			Valid    int  `json:"valid"`
+			Imported int  `json:"imported"`
+			Errors   []struct {
+				Line  int                      `json:"line"`
+				Error infrastructure.ErrorBody `json:"error"`
+			} `json:"errors"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.True(t, response.Data.DryRun)
+	assert.Equal(t, 1, response.Data.Valid)
+	require.Len(t, response.Data.Errors, 1)
+	assert.Equal(t, 3, response.Data.Errors[0].Line)
+	assert.Equal(t, "title", response.Data.Errors[0].Error.Fields[0].Field)
+
+	w = httptest.NewRecorder()
+	transferRouter(taskRepo).ServeHTTP(w, httptest.NewRequest("POST", "/tasks/import?format=csv", strings.NewReader(body)))
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+
+	w = httptest.NewRecorder()
+	body = strings.TrimSuffix(body, ","+due+",low\n")
+	transferRouter(taskRepo).ServeHTTP(w, httptest.NewRequest("POST", "/tasks/import?format=csv", strings.NewReader(body)))
+	assert.Equal(t, http.StatusCreated, w.Code)
+	page, err := taskRepo.List(context.Background(), domain.TaskQuery{Page: 1, PageSize: 10})
+	require.NoError(t, err)
+	require.Len(t, page.Tasks, 1)
+	assert.Equal(t, "Write report", page.Tasks[0].Title)
+	assert.Equal(t, "u1", page.Tasks[0].OwnerID)
+}
@@ -1,10 +1,16 @@
 package infrastructure_test
 
 import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
 	d "task_manager/Domain"
 	infrastructure "task_manager/Infrastructure"
 	"testing"
+	"time"
 
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/stretchr/testify/assert"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
@@ -26,3 +32,118 @@ func TestJWTInvalidToken(t *testing.T) {
 	_, err := jwtSvc.ValidateToken("invalid-token")
 	assert.Error(t, err)
 }
+
+func TestJWTAsymmetricAlgorithms(t *testing.T) {
+	for _, alg := range []string{infrastructure.AlgorithmRS256, infrastructure.AlgorithmEdDSA} {
+		jwtSvc, err := infrastructure.NewJWTServiceFromConfig(infrastructure.JWTConfig{Algorithm: alg})
+		assert.NoError(t, err)
+
+		user := d.User{ID: primitive.NewObjectID(), Username: "u1", Role: "user"}
+		token, err := jwtSvc.GenerateToken(user)
+		assert.NoError(t, err)
+
+		parsed, _, err := jwt.NewParser().ParseUnverified(token, jwt.MapClaims{})
+		assert.NoError(t, err)
+		assert.Equal(t, alg, parsed.Method.Alg())
+		assert.NotEmpty(t, parsed.Header["kid"])
+
+		claims, err := jwtSvc.ValidateToken(token)
+		assert.NoError(t, err)
+		assert.Equal(t, user.Username, claims["usr"])
+
+		jwks := jwtSvc.JWKS()
+		assert.Len(t, jwks.Keys, 1)
+		assert.Equal(t, parsed.Header["kid"], jwks.Keys[0].Kid)
+		assert.Equal(t, alg, jwks.Keys[0].Alg)
+	}
+}
+
+func TestJWTKeyRotation(t *testing.T) {
+	jwtSvc, err := infrastructure.NewJWTServiceFromConfig(infrastructure.JWTConfig{Algorithm: infrastructure.AlgorithmEdDSA})
+	assert.NoError(t, err)
+
+	user := d.User{ID: primitive.NewObjectID(), Username: "u1", Role: "user"}
+	oldToken, err := jwtSvc.GenerateToken(user)
+	assert.NoError(t, err)
+
+	assert.NoError(t, jwtSvc.RotateKey())
+	newToken, err := jwtSvc.GenerateToken(user)
+	assert.NoError(t, err)
+
+	// Tokens signed by the retired key stay valid until they expire
+	_, err = jwtSvc.ValidateToken(oldToken)
+	assert.NoError(t, err)
+	_, err = jwtSvc.ValidateToken(newToken)
+	assert.NoError(t, err)
+	assert.Len(t, jwtSvc.JWKS().Keys, 2)
+}
+
+func TestJWTHMACKeysAreNotPublished(t *testing.T) {
+	jwtSvc := infrastructure.NewJWTService("test-secret")
+	assert.Empty(t, jwtSvc.JWKS().Keys)
+	assert.Error(t, jwtSvc.RotateKey())
+}
+
+func TestJWTRejectsWrongIssuerAndAudience(t *testing.T) {
+	issuer, err := infrastructure.NewJWTServiceFromConfig(infrastructure.JWTConfig{Secret: "test-secret", Issuer: "other"})
+	assert.NoError(t, err)
+	audience, err := infrastructure.NewJWTServiceFromConfig(infrastructure.JWTConfig{Secret: "test-secret", Audience: "other"})
+	assert.NoError(t, err)
+	jwtSvc := infrastructure.NewJWTService("test-secret")
+
+	user := d.User{ID: primitive.NewObjectID(), Username: "u1", Role: "user"}
+	for _, svc := range []*infrastructure.JWTService{issuer, audience} {
+		token, err := svc.GenerateToken(user)
+		assert.NoError(t, err)
+		_, err = jwtSvc.ValidateToken(token)
+		assert.Error(t, err)
+	}
+}
+
+func TestJWTRejectsMissingNotBefore(t *testing.T) {
+	jwtSvc := infrastructure.NewJWTService("test-secret")
+	now := time.Now()
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub": "u1",
+		"iss": infrastructure.DefaultIssuer,
+		"aud": infrastructure.DefaultAudience,
+		"iat": now.Unix(),
+		"exp": now.Add(time.Minute).Unix(),
+	}).SignedString([]byte("test-secret"))
+	assert.NoError(t, err)
+
+	_, err = jwtSvc.ValidateToken(token)
+	assert.Error(t, err)
+}
+
+func TestJWTRejectsAlgorithmMismatch(t *testing.T) {
+	rsaSvc, err := infrastructure.NewJWTServiceFromConfig(infrastructure.JWTConfig{Algorithm: infrastructure.AlgorithmRS256})
+	assert.NoError(t, err)
+	hmacSvc := infrastructure.NewJWTService("test-secret")
+
+	token, err := hmacSvc.GenerateToken(d.User{ID: primitive.NewObjectID(), Username: "u1", Role: "user"})
+	assert.NoError(t, err)
+	_, err = rsaSvc.ValidateToken(token)
+	assert.Error(t, err)
+}
+
+func TestJWTConfigRequiresSecret(t *testing.T) {
+	_, err := infrastructure.NewJWTServiceFromConfig(infrastructure.JWTConfig{Algorithm: infrastructure.AlgorithmHS256})
+	assert.Error(t, err)
+	_, err = infrastructure.NewJWTServiceFromConfig(infrastructure.JWTConfig{Algorithm: "none"})
+	assert.Error(t, err)
+}
+
+func TestParsePrivateKeyPEM(t *testing.T) {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	assert.NoError(t, err)
+
+	parsed, err := infrastructure.ParsePrivateKeyPEM(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+	assert.NoError(t, err)
+	assert.Equal(t, key, parsed)
+
+	_, err = infrastructure.ParsePrivateKeyPEM([]byte("not a key"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,135 @@
+package infrastructure_test
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strings"
+	d "task_manager/Domain"
+	infrastructure "task_manager/Infrastructure"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSMTPServer accepts one SMTP session and reports the envelope and message it received.
+type fakeSMTPServer struct {
+	addr     string
+	received chan fakeMail
+}
+
+type fakeMail struct {
+	from string
+	to   []string
+	data string
+}
+
+func newFakeSMTPServer(t *testing.T) *fakeSMTPServer {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	s := &fakeSMTPServer{addr: listener.Addr().String(), received: make(chan fakeMail, 1)}
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		s.serve(conn)
+	}()
+	return s
+}
+
+func (s *fakeSMTPServer) serve(conn net.Conn) {
+	r := bufio.NewReader(conn)
+	reply := func(line string) { conn.Write([]byte(line + "\r\n")) }
+	var mail fakeMail
+
+	reply("220 localhost ESMTP")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		switch verb := strings.ToUpper(strings.SplitN(line, " ", 2)[0]); verb {
+		case "EHLO", "HELO":
+			reply("250 localhost")
+		case "MAIL":
+			mail.from = strings.Trim(strings.TrimPrefix(line, "MAIL FROM:"), "<>")
+			reply("250 OK")
+		case "RCPT":
+			mail.to = append(mail.to, strings.Trim(strings.TrimPrefix(line, "RCPT TO:"), "<>"))
+			reply("250 OK")
+		case "DATA":
+			reply("354 End data with <CR><LF>.<CR><LF>")
+			var data strings.Builder
+			for {
+				line, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if line == ".\r\n" {
+					break
+				}
+				data.WriteString(line)
+			}
+			mail.data = data.String()
+			reply("250 OK")
+		case "QUIT":
+			reply("221 Bye")
+			s.received <- mail
+			return
+		default:
+			reply("502 Command not implemented")
+		}
+	}
+}
+
+func TestSMTPNotifier_Notify(t *testing.T) {
+	server := newFakeSMTPServer(t)
+	notifier, err := infrastructure.NewSMTPNotifier(infrastructure.SMTPConfig{
+		Addr:    server.addr,
+		From:    "tasks@example.com",
+		To:      []string{"ops@example.com", " ", "lead@example.com"},
+		Timeout: time.Second,
+	})
+	require.NoError(t, err)
+
+	due := time.Date(2030, 1, 2, 15, 4, 5, 0, time.UTC)
+	event := d.TaskEvent{Type: d.EventTaskOverdue, Task: &d.Task{ID: "t1", Title: "Ship\r\nrelease", Status: d.StatusPending, DueDate: due, OwnerID: "u1"}}
+	require.NoError(t, notifier.Notify(context.Background(), event))
+
+	mail := <-server.received
+	assert.Equal(t, "tasks@example.com", mail.from)
+	assert.Equal(t, []string{"ops@example.com", "lead@example.com"}, mail.to)
+	assert.Contains(t, mail.data, "Subject: Task overdue: Ship release\r\n")
+	assert.Contains(t, mail.data, "To: ops@example.com, lead@example.com\r\n")
+	assert.Contains(t, mail.data, "ID: t1\r\n")
+	assert.Contains(t, mail.data, "Due: 2030-01-02T15:04:05Z\r\n")
+}
+
+func TestNewSMTPNotifier_Invalid(t *testing.T) {
+	for _, config := range []infrastructure.SMTPConfig{
+		{Addr: "localhost", From: "a@example.com", To: []string{"b@example.com"}},
+		{Addr: "localhost:25", To: []string{"b@example.com"}},
+		{Addr: "localhost:25", From: "a@example.com", To: []string{""}},
+	} {
+		_, err := infrastructure.NewSMTPNotifier(config)
+		assert.Error(t, err)
+	}
+}
+
+func TestSMTPNotifier_Unreachable(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := listener.Addr().String()
+	listener.Close()
+
+	notifier, err := infrastructure.NewSMTPNotifier(infrastructure.SMTPConfig{Addr: addr, From: "a@example.com", To: []string{"b@example.com"}})
+	require.NoError(t, err)
+	assert.Error(t, notifier.Notify(context.Background(), d.TaskEvent{Type: d.EventTaskDueSoon}))
+}
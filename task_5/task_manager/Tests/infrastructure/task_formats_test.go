@@ -0,0 +1,188 @@
+package infrastructure_test
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	d "task_manager/Domain"
+	infrastructure "task_manager/Infrastructure"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func exportTasks(t *testing.T, format string, tasks ...d.Task) string {
+	var buf bytes.Buffer
+	enc, err := infrastructure.NewTaskEncoder(format, &buf, d.DefaultWorkflow())
+	require.NoError(t, err)
+	for _, task := range tasks {
+		require.NoError(t, enc.Encode(task))
+	}
+	require.NoError(t, enc.Close())
+	return buf.String()
+}
+
+func importTasks(t *testing.T, format, input string) []infrastructure.TaskRecord {
+	dec, err := infrastructure.NewTaskDecoder(format, strings.NewReader(input), d.DefaultWorkflow())
+	require.NoError(t, err)
+	var records []infrastructure.TaskRecord
+	for {
+		record, err := dec.Next()
+		if err == io.EOF {
+			return records
+		}
+		require.NoError(t, err)
+		records = append(records, record)
+	}
+}
+
+func TestTaskFormats_RoundTrip(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+	tasks := []d.Task{
+		{ID: "1", Title: "Plan, then ship; \"quoted\"", Description: "Line one\nLine two\\", Status: d.StatusInProgress,
+			Priority: d.PriorityUrgent, Tags: []string{"work", "q3"}, AssigneeIDs: []string{"u2"},
+			DueDate: time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC), OwnerID: "u1", Version: 3},
+		{ID: "2", Title: strings.Repeat("Long title with ümlauts ", 6), Status: d.StatusPending, Priority: d.PriorityLow,
+			ParentID: "1", DueDate: time.Date(2025, 6, 2, 9, 0, 0, 0, berlin)},
+		{ID: "3", Title: "Water the plants", Status: d.StatusCompleted, Priority: d.PriorityMedium,
+			DueDate: time.Date(2025, 6, 3, 9, 0, 0, 0, berlin), Recurrence: &d.Recurrence{Rule: "FREQ=WEEKLY", Timezone: "Europe/Berlin"}},
+	}
+
+	for _, format := range []string{infrastructure.FormatCSV, infrastructure.FormatJSONL, infrastructure.FormatICS} {
+		t.Run(format, func(t *testing.T) {
+			records := importTasks(t, format, exportTasks(t, format, tasks...))
+			require.Len(t, records, len(tasks))
+			for i, record := range records {
+				require.NoError(t, record.Err)
+				want, got := tasks[i], record.Task
+				assert.Equal(t, want.Title, got.Title)
+				assert.Equal(t, want.Description, got.Description)
+				assert.True(t, want.DueDate.Equal(got.DueDate), "got %s, want %s", got.DueDate, want.DueDate)
+				assert.Equal(t, want.Status, got.Status)
+				assert.Equal(t, want.Priority, got.Priority)
+				assert.Equal(t, want.Tags, got.Tags)
+				assert.Equal(t, want.ParentID, got.ParentID)
+				assert.Equal(t, want.Recurrence, got.Recurrence)
+				if format != infrastructure.FormatICS {
+					assert.Equal(t, want.AssigneeIDs, got.AssigneeIDs)
+				}
+			}
+		})
+	}
+}
+
+func TestTaskFormats_Empty(t *testing.T) {
+	assert.Equal(t, "id,title,description,due_date,status,priority,tags,assignee_ids,parent_id,recurrence,timezone,owner_id,version\n",
+		exportTasks(t, infrastructure.FormatCSV))
+	assert.Empty(t, exportTasks(t, infrastructure.FormatJSONL))
+	ics := exportTasks(t, infrastructure.FormatICS)
+	assert.True(t, strings.HasPrefix(ics, "BEGIN:VCALENDAR\r\n"))
+	assert.True(t, strings.HasSuffix(ics, "END:VCALENDAR\r\n"))
+	assert.Empty(t, importTasks(t, infrastructure.FormatICS, ics))
+}
+
+func TestCSVEncoder_EscapesFormulas(t *testing.T) {
+	task := d.Task{ID: "1", Title: "=HYPERLINK(\"http://evil.example\")", Description: "+1 more", Status: d.StatusPending,
+		Priority: d.PriorityLow, Tags: []string{"-urgent", "work"}, AssigneeIDs: []string{"@u2"},
+		DueDate: time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)}
+	csv := exportTasks(t, infrastructure.FormatCSV, task)
+	assert.Contains(t, csv, `"'=HYPERLINK(""http://evil.example"")",'+1 more,`)
+	assert.Contains(t, csv, `,"'-urgent,work",'@u2,`)
+
+	// Tabs, carriage returns and cells that already start with an apostrophe are escaped too
+	for _, title := range []string{"\tcmd", "\rcmd", "'=quoted", "'plain", "plain"} {
+		task.Title = title
+		records := importTasks(t, infrastructure.FormatCSV, exportTasks(t, infrastructure.FormatCSV, task))
+		require.Len(t, records, 1)
+		assert.Equal(t, title, records[0].Task.Title)
+	}
+	records := importTasks(t, infrastructure.FormatCSV, csv)
+	require.Len(t, records, 1)
+	assert.Equal(t, `=HYPERLINK("http://evil.example")`, records[0].Task.Title)
+	assert.Equal(t, "+1 more", records[0].Task.Description)
+	assert.Equal(t, []string{"-urgent", "work"}, records[0].Task.Tags)
+	assert.Equal(t, []string{"@u2"}, records[0].Task.AssigneeIDs)
+}
+
+func TestICSEncoder_FoldsLongLines(t *testing.T) {
+	ics := exportTasks(t, infrastructure.FormatICS, d.Task{ID: "1", Title: strings.Repeat("ü", 100), Status: d.StatusPending})
+	for _, line := range strings.Split(strings.TrimSuffix(ics, "\r\n"), "\r\n") {
+		assert.LessOrEqual(t, len(line), 75, line)
+	}
+	assert.Contains(t, ics, "STATUS:NEEDS-ACTION\r\n")
+}
+
+func TestICSDecoder_ForeignCalendar(t *testing.T) {
+	ics := "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n" +
+		"BEGIN:VEVENT\r\nSUMMARY:Not a task\r\nEND:VEVENT\r\n" +
+		"BEGIN:VTODO\r\nUID:abc@example.com\r\nSUMMARY:Call the \r\n bank\r\nDUE;VALUE=DATE:20250601\r\n" +
+		"STATUS:COMPLETED\r\nPRIORITY:2\r\nCATEGORIES:Finance,Home\\,Garden\r\n" +
+		"BEGIN:VALARM\r\nACTION:DISPLAY\r\nDESCRIPTION:Reminder\r\nEND:VALARM\r\nEND:VTODO\r\n" +
+		"BEGIN:VTODO\r\nSUMMARY:Bad date\r\nDUE:tomorrow\r\nEND:VTODO\r\n" +
+		"END:VCALENDAR\r\n"
+
+	records := importTasks(t, infrastructure.FormatICS, ics)
+	require.Len(t, records, 2)
+	assert.Equal(t, 6, records[0].Line)
+	assert.NoError(t, records[0].Err)
+	assert.Equal(t, "Call the bank", records[0].Task.Title)
+	assert.Empty(t, records[0].Task.Description)
+	assert.Equal(t, time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC), records[0].Task.DueDate)
+	assert.Equal(t, d.StatusCompleted, records[0].Task.Status)
+	assert.Equal(t, d.PriorityHigh, records[0].Task.Priority)
+	assert.Equal(t, []string{"Finance", "Home,Garden"}, records[0].Task.Tags)
+
+	assert.Equal(t, 19, records[1].Line)
+	var domainErr *d.Error
+	require.ErrorAs(t, records[1].Err, &domainErr)
+	assert.Equal(t, "due_date", domainErr.Fields[0].Field)
+}
+
+func TestCSVDecoder_ErrorsByLine(t *testing.T) {
+	csv := "title,due_date,tags\n" +
+		"Good,2025-06-01,\"a, b\"\n" +
+		"Bad date,next week,\n" +
+		"Too,many,fields,here\n" +
+		"\"Multi\nline\",2025-06-01T10:00:00+02:00,\n"
+
+	records := importTasks(t, infrastructure.FormatCSV, csv)
+	require.Len(t, records, 4)
+	assert.NoError(t, records[0].Err)
+	assert.Equal(t, []string{"a", "b"}, records[0].Task.Tags)
+	assert.Equal(t, 3, records[1].Line)
+	assert.Error(t, records[1].Err)
+	assert.Equal(t, 4, records[2].Line)
+	assert.Error(t, records[2].Err)
+	assert.Equal(t, 5, records[3].Line)
+	assert.Equal(t, "Multi\nline", records[3].Task.Title)
+	assert.True(t, time.Date(2025, 6, 1, 8, 0, 0, 0, time.UTC).Equal(records[3].Task.DueDate))
+}
+
+func TestTaskDecoders_MalformedInput(t *testing.T) {
+	for _, tt := range []struct{ format, input string }{
+		{infrastructure.FormatCSV, "title,color\nA,red\n"},
+		{infrastructure.FormatCSV, "title\n\"unterminated\n"},
+		{infrastructure.FormatICS, "BEGIN:VTODO\r\nEND:VTODO\r\n"},
+		{infrastructure.FormatICS, "BEGIN:VCALENDAR\r\nBEGIN:VTODO\r\nSUMMARY:Open\r\n"},
+	} {
+		dec, err := infrastructure.NewTaskDecoder(tt.format, strings.NewReader(tt.input), d.DefaultWorkflow())
+		require.NoError(t, err)
+		_, err = dec.Next()
+		var domainErr *d.Error
+		assert.ErrorAs(t, err, &domainErr, tt.input)
+	}
+
+	_, err := infrastructure.NewTaskDecoder("xlsx", strings.NewReader(""), d.DefaultWorkflow())
+	assert.Error(t, err)
+}
+
+func TestJSONLDecoder(t *testing.T) {
+	records := importTasks(t, infrastructure.FormatJSONL, "{\"title\":\"A\",\"due_date\":\"2025-06-01T00:00:00Z\"}\n\n{not json}\n")
+	require.Len(t, records, 2)
+	assert.Equal(t, "A", records[0].Task.Title)
+	assert.Equal(t, 3, records[1].Line)
+	assert.Error(t, records[1].Err)
+}
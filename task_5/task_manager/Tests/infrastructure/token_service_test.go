@@ -0,0 +1,92 @@
+package infrastructure_test
+
+import (
+	"context"
+	d "task_manager/Domain"
+	infrastructure "task_manager/Infrastructure"
+	repositories "task_manager/Repositories"
+	"task_manager/Tests/mocks"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func newTokenService(user d.User) (*infrastructure.TokenService, repositories.ITokenStore) {
+	store := repositories.NewMemoryTokenStore()
+	userRepo := new(mocks.MockUserRepository)
+	userRepo.On("GetByUsername", user.Username).Return(user, nil)
+	return infrastructure.NewTokenService(infrastructure.NewJWTService("test-secret"), store, userRepo), store
+}
+
+func TestTokenService_IssueAndRefresh(t *testing.T) {
+	user := d.User{ID: primitive.NewObjectID(), Username: "u1", Role: "user"}
+	svc, _ := newTokenService(user)
+
+	pair, err := svc.Issue(context.Background(), user)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, pair.AccessToken)
+	assert.NotEmpty(t, pair.RefreshToken)
+	assert.Equal(t, int64(infrastructure.AccessTokenTTL.Seconds()), pair.ExpiresIn)
+
+	next, err := svc.Refresh(context.Background(), pair.RefreshToken)
+	assert.NoError(t, err)
+	assert.NotEqual(t, pair.RefreshToken, next.RefreshToken)
+	assert.NotEqual(t, pair.AccessToken, next.AccessToken)
+}
+
+func TestTokenService_ReuseRevokesFamily(t *testing.T) {
+	user := d.User{ID: primitive.NewObjectID(), Username: "u1", Role: "user"}
+	svc, _ := newTokenService(user)
+
+	pair, err := svc.Issue(context.Background(), user)
+	assert.NoError(t, err)
+	next, err := svc.Refresh(context.Background(), pair.RefreshToken)
+	assert.NoError(t, err)
+
+	// Replaying the rotated token revokes every token in the family
+	_, err = svc.Refresh(context.Background(), pair.RefreshToken)
+	assert.ErrorIs(t, err, infrastructure.ErrInvalidRefreshToken)
+	_, err = svc.Refresh(context.Background(), next.RefreshToken)
+	assert.ErrorIs(t, err, infrastructure.ErrInvalidRefreshToken)
+}
+
+func TestTokenService_RefreshUnknownToken(t *testing.T) {
+	user := d.User{ID: primitive.NewObjectID(), Username: "u1", Role: "user"}
+	svc, _ := newTokenService(user)
+
+	_, err := svc.Refresh(context.Background(), "unknown")
+	assert.ErrorIs(t, err, infrastructure.ErrInvalidRefreshToken)
+}
+
+func TestTokenService_Logout(t *testing.T) {
+	user := d.User{ID: primitive.NewObjectID(), Username: "u1", Role: "user"}
+	svc, store := newTokenService(user)
+
+	pair, err := svc.Issue(context.Background(), user)
+	assert.NoError(t, err)
+
+	err = svc.Logout(context.Background(), user.ID.Hex(), "access-jti", pair.RefreshToken)
+	assert.NoError(t, err)
+
+	revoked, err := store.IsAccessTokenRevoked(context.Background(), "access-jti")
+	assert.NoError(t, err)
+	assert.True(t, revoked)
+
+	_, err = svc.Refresh(context.Background(), pair.RefreshToken)
+	assert.ErrorIs(t, err, infrastructure.ErrInvalidRefreshToken)
+}
+
+func TestTokenService_LogoutOtherUsersToken(t *testing.T) {
+	user := d.User{ID: primitive.NewObjectID(), Username: "u1", Role: "user"}
+	svc, _ := newTokenService(user)
+
+	pair, err := svc.Issue(context.Background(), user)
+	assert.NoError(t, err)
+
+	err = svc.Logout(context.Background(), primitive.NewObjectID().Hex(), "access-jti", pair.RefreshToken)
+	assert.ErrorIs(t, err, infrastructure.ErrInvalidRefreshToken)
+
+	_, err = svc.Refresh(context.Background(), pair.RefreshToken)
+	assert.NoError(t, err)
+}
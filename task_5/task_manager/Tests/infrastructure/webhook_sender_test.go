@@ -0,0 +1,48 @@
+package infrastructure_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	d "task_manager/Domain"
+	infrastructure "task_manager/Infrastructure"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSignWebhookPayload(t *testing.T) {
+	signature := infrastructure.SignWebhookPayload("secret", "1700000000", []byte(`{"a":1}`))
+	assert.Equal(t, "sha256=49f24e537407743fa4a0242bb63b94b9a47ee99cbbe071ccd8a22550ae411686", signature)
+}
+
+func TestWebhookSender_Send(t *testing.T) {
+	var got *http.Request
+	status := http.StatusNoContent
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	sender := infrastructure.NewWebhookSender(time.Second)
+	hook := d.Webhook{URL: server.URL, Secret: "secret"}
+	delivery := d.WebhookDelivery{ID: "d1", EventType: d.EventTaskDeleted, Payload: json.RawMessage(`{"a":1}`)}
+
+	code, err := sender.Send(context.Background(), hook, delivery)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, code)
+	assert.Equal(t, http.MethodPost, got.Method)
+	assert.Equal(t, "application/json", got.Header.Get("Content-Type"))
+	assert.Equal(t, "d1", got.Header.Get(infrastructure.WebhookDeliveryHeader))
+	assert.Equal(t, d.EventTaskDeleted, got.Header.Get(infrastructure.WebhookEventHeader))
+	timestamp := got.Header.Get(infrastructure.WebhookTimestampHeader)
+	assert.Equal(t, infrastructure.SignWebhookPayload("secret", timestamp, delivery.Payload), got.Header.Get(infrastructure.WebhookSignatureHeader))
+
+	status = http.StatusGone
+	code, err = sender.Send(context.Background(), hook, delivery)
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusGone, code)
+}
@@ -1,22 +1,45 @@
 package middleware_test
 
 import (
+	"context"
 	"net/http"
 	"net/http/httptest"
 	domain "task_manager/Domain"
 	infrastructure "task_manager/Infrastructure"
+	repositories "task_manager/Repositories"
+	usecases "task_manager/Usecases"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+func newAuthMiddleware(jwtSvc *infrastructure.JWTService, store repositories.IRevocationStore, users repositories.IUserRepository) *infrastructure.AuthMiddleware {
+	roles := usecases.NewRoleUsecases(repositories.NewMemoryRoleRepository(), users, nil)
+	return infrastructure.NewAuthMiddleware(jwtSvc, store, roles)
+}
+
+// userToken stores a user with the role and issues an access token for it.
+func userToken(t *testing.T, jwtSvc *infrastructure.JWTService, users repositories.IUserRepository, role string) (domain.User, string) {
+	user, err := users.CreateUser(context.Background(), primitive.NewObjectID().Hex(), "password123")
+	require.NoError(t, err)
+	require.NoError(t, users.SetRole(context.Background(), user.ID.Hex(), role))
+	user.Role = role
+	token, err := jwtSvc.GenerateToken(user)
+	require.NoError(t, err)
+	return user, token
+}
+
 func TestAuthMiddleware_NoAuthorization(t *testing.T) {
 	jwtSvc := infrastructure.NewJWTService("secret")
-	authMW := infrastructure.NewAuthMiddleware(jwtSvc)
+	users := repositories.NewMemoryUserRepository()
+	authMW := newAuthMiddleware(jwtSvc, repositories.NewMemoryTokenStore(), users)
 
 	r := gin.New()
+	r.Use(infrastructure.ErrorHandler())
 	r.Use(authMW.AuthRequired())
 	r.GET("/test", func(c *gin.Context) { c.Status(200) })
 
@@ -29,9 +52,11 @@ func TestAuthMiddleware_NoAuthorization(t *testing.T) {
 
 func TestAuthMiddleware_InvalidToken(t *testing.T) {
 	jwtSvc := infrastructure.NewJWTService("secret")
-	authMW := infrastructure.NewAuthMiddleware(jwtSvc)
+	users := repositories.NewMemoryUserRepository()
+	authMW := newAuthMiddleware(jwtSvc, repositories.NewMemoryTokenStore(), users)
 
 	r := gin.New()
+	r.Use(infrastructure.ErrorHandler())
 	r.Use(authMW.AuthRequired())
 	r.GET("/test", func(c *gin.Context) { c.Status(200) })
 
@@ -45,9 +70,11 @@ func TestAuthMiddleware_InvalidToken(t *testing.T) {
 
 func TestAuthMiddleware_ValidToken(t *testing.T) {
 	jwtSvc := infrastructure.NewJWTService("secret")
-	authMW := infrastructure.NewAuthMiddleware(jwtSvc)
+	users := repositories.NewMemoryUserRepository()
+	authMW := newAuthMiddleware(jwtSvc, repositories.NewMemoryTokenStore(), users)
 
 	r := gin.New()
+	r.Use(infrastructure.ErrorHandler())
 	r.Use(authMW.AuthRequired())
 	r.GET("/test", func(c *gin.Context) {
 		userID, _ := c.Get("user_id")
@@ -56,7 +83,7 @@ func TestAuthMiddleware_ValidToken(t *testing.T) {
 	})
 
 	// Assume a valid token is generated
-	token, _ := jwtSvc.GenerateToken(domain.User{ID: primitive.NewObjectID(), Username: "user", Role: "user"})
+	_, token := userToken(t, jwtSvc, users, domain.RoleUser)
 	req := httptest.NewRequest("GET", "/test", nil)
 	req.Header.Set("Authorization", "Bearer "+token)
 	w := httptest.NewRecorder()
@@ -65,16 +92,75 @@ func TestAuthMiddleware_ValidToken(t *testing.T) {
 	assert.Equal(t, http.StatusOK, w.Code)
 }
 
+func TestAuthMiddleware_RevokedToken(t *testing.T) {
+	jwtSvc := infrastructure.NewJWTService("secret")
+	store := repositories.NewMemoryTokenStore()
+	users := repositories.NewMemoryUserRepository()
+	authMW := newAuthMiddleware(jwtSvc, store, users)
+
+	r := gin.New()
+	r.Use(infrastructure.ErrorHandler())
+	r.Use(authMW.AuthRequired())
+	r.GET("/test", func(c *gin.Context) { c.Status(200) })
+
+	_, token := userToken(t, jwtSvc, users, domain.RoleUser)
+	claims, err := jwtSvc.ValidateToken(token)
+	assert.NoError(t, err)
+	store.RevokeAccessToken(context.Background(), claims["jti"].(string), time.Now().Add(time.Minute))
+
+	req := httptest.NewRequest("GET", "/test", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestAuthMiddleware_RoleChangesApplyToIssuedTokens(t *testing.T) {
+	jwtSvc := infrastructure.NewJWTService("secret")
+	users := repositories.NewMemoryUserRepository()
+	authMW := newAuthMiddleware(jwtSvc, repositories.NewMemoryTokenStore(), users)
+
+	r := gin.New()
+	r.Use(infrastructure.ErrorHandler())
+	r.Use(authMW.AuthRequired())
+	r.GET("/audit", authMW.RequirePermission(domain.PermAuditRead), func(c *gin.Context) {
+		c.String(200, c.GetString("role"))
+	})
+
+	admin, token := userToken(t, jwtSvc, users, domain.RoleAdmin)
+	send := func(token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/audit", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := send(token)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, domain.RoleAdmin, w.Body.String())
+	// A demoted admin loses the permissions at once, despite the token's role claim
+	require.NoError(t, users.SetRole(context.Background(), admin.ID.Hex(), domain.RoleUser))
+	assert.Equal(t, http.StatusForbidden, send(token).Code)
+
+	// Tokens of unknown users are refused
+	ghost, _ := jwtSvc.GenerateToken(domain.User{ID: primitive.NewObjectID(), Username: "ghost", Role: domain.RoleAdmin})
+	assert.Equal(t, http.StatusUnauthorized, send(ghost).Code)
+}
+
 func TestAdminRequired_UserRole(t *testing.T) {
 	jwtSvc := infrastructure.NewJWTService("secret")
-	authMW := infrastructure.NewAuthMiddleware(jwtSvc)
+	users := repositories.NewMemoryUserRepository()
+	authMW := newAuthMiddleware(jwtSvc, repositories.NewMemoryTokenStore(), users)
 
 	r := gin.New()
+	r.Use(infrastructure.ErrorHandler())
 	r.Use(authMW.AuthRequired())
 	r.Use(authMW.AdminRequired())
 	r.GET("/admin", func(c *gin.Context) { c.Status(200) })
 
-	token, _ := jwtSvc.GenerateToken(domain.User{ID: primitive.NewObjectID(), Username: "user", Role: "user"})
+	_, token := userToken(t, jwtSvc, users, domain.RoleUser)
 	req := httptest.NewRequest("GET", "/admin", nil)
 	req.Header.Set("Authorization", "Bearer "+token)
 	w := httptest.NewRecorder()
@@ -85,14 +171,16 @@ func TestAdminRequired_UserRole(t *testing.T) {
 
 func TestAdminRequired_AdminRole(t *testing.T) {
 	jwtSvc := infrastructure.NewJWTService("secret")
-	authMW := infrastructure.NewAuthMiddleware(jwtSvc)
+	users := repositories.NewMemoryUserRepository()
+	authMW := newAuthMiddleware(jwtSvc, repositories.NewMemoryTokenStore(), users)
 
 	r := gin.New()
+	r.Use(infrastructure.ErrorHandler())
 	r.Use(authMW.AuthRequired())
 	r.Use(authMW.AdminRequired())
 	r.GET("/admin", func(c *gin.Context) { c.Status(200) })
 
-	token, _ := jwtSvc.GenerateToken(domain.User{ID: primitive.NewObjectID(), Username: "admin", Role: "admin"})
+	_, token := userToken(t, jwtSvc, users, domain.RoleAdmin)
 	req := httptest.NewRequest("GET", "/admin", nil)
 	req.Header.Set("Authorization", "Bearer "+token)
 	w := httptest.NewRecorder()
@@ -100,3 +188,108 @@ func TestAdminRequired_AdminRole(t *testing.T) {
 
 	assert.Equal(t, http.StatusOK, w.Code)
 }
+
+func TestRequirePermission(t *testing.T) {
+	jwtSvc := infrastructure.NewJWTService("secret")
+	users := repositories.NewMemoryUserRepository()
+	authMW := newAuthMiddleware(jwtSvc, repositories.NewMemoryTokenStore(), users)
+
+	r := gin.New()
+	r.Use(infrastructure.ErrorHandler())
+	r.Use(authMW.AuthRequired())
+	r.GET("/tasks", authMW.RequirePermission(domain.PermTasksRead), func(c *gin.Context) { c.Status(200) })
+	r.DELETE("/tasks", authMW.RequirePermission(domain.PermTasksDelete), func(c *gin.Context) { c.Status(200) })
+
+	cases := []struct {
+		role   string
+		method string
+		want   int
+	}{
+		{domain.RoleUser, "GET", http.StatusOK},
+		{domain.RoleUser, "DELETE", http.StatusForbidden},
+		{domain.RoleAdmin, "DELETE", http.StatusOK},
+		{"unknown", "GET", http.StatusForbidden},
+	}
+	for _, tc := range cases {
+		_, token := userToken(t, jwtSvc, users, tc.role)
+		req := httptest.NewRequest(tc.method, "/tasks", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, tc.want, w.Code, "%s %s", tc.role, tc.method)
+	}
+}
+
+func TestRequirePermission_CustomRoleChangesApplyImmediately(t *testing.T) {
+	jwtSvc := infrastructure.NewJWTService("secret")
+	users := repositories.NewMemoryUserRepository()
+	roles := usecases.NewRoleUsecases(repositories.NewMemoryRoleRepository(), users, nil)
+	authMW := infrastructure.NewAuthMiddleware(jwtSvc, repositories.NewMemoryTokenStore(), roles)
+
+	r := gin.New()
+	r.Use(infrastructure.ErrorHandler())
+	r.Use(authMW.AuthRequired())
+	r.DELETE("/tasks", authMW.RequirePermission(domain.PermTasksDelete), func(c *gin.Context) { c.Status(200) })
+
+	_, err := roles.SaveRole(context.Background(), domain.Role{Name: "reviewer", Permissions: []string{domain.PermTasksRead}})
+	assert.NoError(t, err)
+	_, token := userToken(t, jwtSvc, users, "reviewer")
+
+	send := func() int {
+		req := httptest.NewRequest("DELETE", "/tasks", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusForbidden, send())
+	_, err = roles.SaveRole(context.Background(), domain.Role{Name: "reviewer", Permissions: []string{domain.PermTasksRead, domain.PermTasksDelete}})
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, send())
+}
+
+func TestProjectScope(t *testing.T) {
+	jwtSvc := infrastructure.NewJWTService("secret")
+	userRepo := repositories.NewMemoryUserRepository()
+	authMW := newAuthMiddleware(jwtSvc, repositories.NewMemoryTokenStore(), userRepo)
+	manager, _ := userRepo.CreateUser(context.Background(), "manager", "password123")
+	viewer, _ := userRepo.CreateUser(context.Background(), "viewer", "password123")
+	outsider, _ := userRepo.CreateUser(context.Background(), "outsider", "password123")
+	projects := usecases.NewProjectUsecases(repositories.NewMemoryProjectRepository(), userRepo, repositories.NewMemoryAuditRepository())
+	project, err := projects.CreateProject(context.Background(), domain.Actor{UserID: manager.ID.Hex()}, usecases.ProjectInput{
+		Name:    "Launch",
+		Members: []domain.ProjectMember{{UserID: viewer.ID.Hex(), Role: domain.ProjectRoleViewer}},
+	})
+	assert.NoError(t, err)
+
+	r := gin.New()
+	r.Use(infrastructure.ErrorHandler())
+	scoped := r.Group("/projects/:pid", authMW.AuthRequired(), infrastructure.ProjectScope(projects))
+	scoped.GET("/tasks", authMW.RequirePermission(domain.PermTasksRead), func(c *gin.Context) {
+		assert.Equal(t, project.ID, c.GetString("project_id"))
+		c.Status(200)
+	})
+	scoped.POST("/tasks", authMW.RequirePermission(domain.PermTasksCreate), func(c *gin.Context) { c.Status(201) })
+
+	send := func(user domain.User, method string) int {
+		token, _ := jwtSvc.GenerateToken(domain.User{ID: user.ID, Username: user.Username, Role: domain.RoleUser})
+		req := httptest.NewRequest(method, "/projects/"+project.ID+"/tasks", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	// The project role replaces the permissions of the global role
+	assert.Equal(t, http.StatusOK, send(viewer, "GET"))
+	assert.Equal(t, http.StatusForbidden, send(viewer, "POST"))
+	assert.Equal(t, http.StatusCreated, send(manager, "POST"))
+	assert.Equal(t, http.StatusNotFound, send(outsider, "GET"))
+
+	_, err = projects.ArchiveProject(context.Background(), domain.Actor{UserID: manager.ID.Hex()}, project.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, send(manager, "GET"))
+	assert.Equal(t, http.StatusConflict, send(manager, "POST"))
+}
@@ -0,0 +1,85 @@
+package middleware_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	domain "task_manager/Domain"
+	infrastructure "task_manager/Infrastructure"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func serveError(err error) (*httptest.ResponseRecorder, infrastructure.ErrorResponse) {
+	r := gin.New()
+	r.Use(infrastructure.ErrorHandler())
+	r.GET("/test", func(c *gin.Context) { c.Error(err) })
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/test", nil))
+
+	var response infrastructure.ErrorResponse
+	json.Unmarshal(w.Body.Bytes(), &response)
+	return w, response
+}
+
+func TestErrorHandler_MapsDomainErrors(t *testing.T) {
+	cases := []struct {
+		err    error
+		status int
+		code   domain.ErrorCode
+	}{
+		{domain.NewNotFoundError("task not found"), http.StatusNotFound, domain.CodeNotFound},
+		{domain.NewConflictError("username already exists"), http.StatusConflict, domain.CodeConflict},
+		{domain.NewValidationError("invalid"), http.StatusBadRequest, domain.CodeValidation},
+		{domain.NewUnauthorizedError("invalid credentials"), http.StatusUnauthorized, domain.CodeUnauthorized},
+		{domain.NewForbiddenError("nope"), http.StatusForbidden, domain.CodeForbidden},
+		// Wrapped errors keep their code
+		{fmt.Errorf("update: %w", domain.NewNotFoundError("task not found")), http.StatusNotFound, domain.CodeNotFound},
+	}
+
+	for _, tc := range cases {
+		w, response := serveError(tc.err)
+		assert.Equal(t, tc.status, w.Code, tc.err.Error())
+		assert.Equal(t, string(tc.code), response.Error.Code)
+		assert.NotEmpty(t, response.Error.RequestID)
+		assert.Equal(t, response.Error.RequestID, w.Header().Get(infrastructure.RequestIDHeader))
+	}
+}
+
+func TestErrorHandler_ValidationFields(t *testing.T) {
+	fields := []domain.FieldError{{Field: "title", Message: "title is required"}}
+	w, response := serveError(domain.NewValidationError("title is required", fields...))
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, fields, response.Error.Fields)
+}
+
+func TestErrorHandler_HidesInternalErrors(t *testing.T) {
+	w, response := serveError(errors.New("connection refused: 10.0.0.5:27017"))
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Equal(t, infrastructure.CodeInternal, response.Error.Code)
+	assert.NotContains(t, response.Error.Message, "10.0.0.5")
+}
+
+func TestErrorHandler_Timeout(t *testing.T) {
+	w, response := serveError(fmt.Errorf("failed to find tasks: %w", context.DeadlineExceeded))
+
+	assert.Equal(t, http.StatusGatewayTimeout, w.Code)
+	assert.Equal(t, infrastructure.CodeTimeout, response.Error.Code)
+}
+
+func TestErrorHandler_RetryAfter(t *testing.T) {
+	w, response := serveError(domain.NewRateLimitedError("too many requests", 1500*time.Millisecond))
+
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, string(domain.CodeRateLimited), response.Error.Code)
+	assert.Equal(t, "2", w.Header().Get("Retry-After"))
+}
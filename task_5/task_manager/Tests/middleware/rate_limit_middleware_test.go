@@ -0,0 +1,81 @@
+package middleware_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	domain "task_manager/Domain"
+	infrastructure "task_manager/Infrastructure"
+	repositories "task_manager/Repositories"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRateLimits(t *testing.T) {
+	limits, err := infrastructure.ParseRateLimits(infrastructure.DefaultRateLimits(), " login.ip=3/1s, register.ip=0,register.username=2/1h ")
+	require.NoError(t, err)
+	assert.Equal(t, domain.RateLimit{Burst: 3, Period: time.Second}, limits["login.ip"])
+	assert.False(t, limits["register.ip"].Enabled())
+	assert.Equal(t, domain.RateLimit{Burst: 2, Period: time.Hour}, limits["register.username"])
+	assert.Equal(t, infrastructure.DefaultRateLimits()["login.username"], limits["login.username"])
+	// The defaults are left alone
+	assert.True(t, infrastructure.DefaultRateLimits()["register.ip"].Enabled())
+
+	for _, spec := range []string{"login.ip", "login.ip=5", "login.ip=0/1m", "login.ip=5/-1m", "login.email=5/1m"} {
+		_, err := infrastructure.ParseRateLimits(nil, spec)
+		assert.Error(t, err, spec)
+	}
+}
+
+func TestRateLimiter_Limit(t *testing.T) {
+	limiter := infrastructure.NewRateLimiter(repositories.NewMemoryRateLimitStore(), infrastructure.RateLimits{
+		"login.ip":       {Burst: 3, Period: time.Minute},
+		"login.username": {Burst: 2, Period: time.Minute},
+	})
+
+	r := gin.New()
+	r.Use(infrastructure.ErrorHandler())
+	r.POST("/login", limiter.Limit("login"), func(c *gin.Context) {
+		var input struct {
+			Username string `json:"username"`
+		}
+		// The handler still reads the whole body
+		require.NoError(t, c.ShouldBindJSON(&input))
+		c.String(http.StatusOK, input.Username)
+	})
+	r.POST("/refresh", limiter.Limit("refresh"), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	send := func(path, ip, username string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", path, bytes.NewBufferString(`{"username":"`+username+`","password":"x"}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.RemoteAddr = ip + ":1234"
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := send("/login", "10.0.0.1", "alice")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "alice", w.Body.String())
+	assert.Equal(t, http.StatusOK, send("/login", "10.0.0.2", "alice").Code)
+	// The account's bucket is empty, whichever address the request comes from
+	w = send("/login", "10.0.0.3", "alice")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "30", w.Header().Get("Retry-After"))
+
+	assert.Equal(t, http.StatusOK, send("/login", "10.0.0.1", "bob").Code)
+	assert.Equal(t, http.StatusOK, send("/login", "10.0.0.1", "carol").Code)
+	// The address's bucket is empty, whichever account is tried
+	w = send("/login", "10.0.0.1", "dave")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "20", w.Header().Get("Retry-After"))
+
+	// Routes without limits are not throttled
+	for i := 0; i < 5; i++ {
+		assert.Equal(t, http.StatusOK, send("/refresh", "10.0.0.1", "alice").Code)
+	}
+}
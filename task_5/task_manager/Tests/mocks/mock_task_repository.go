@@ -1,7 +1,11 @@
 package mocks
 
 import (
+	"context"
+	"time"
+
 	domain "task_manager/Domain"
+	repositories "task_manager/Repositories"
 
 	"github.com/stretchr/testify/mock"
 )
@@ -10,15 +14,33 @@ type MockTaskRepository struct {
 	mock.Mock
 }
 
-func (m *MockTaskRepository) GetAll() ([]domain.Task, error) {
-	args := m.Called()
+func (m *MockTaskRepository) List(ctx context.Context, q domain.TaskQuery) (domain.TaskPage, error) {
+	args := m.Called(q)
+	if page, ok := args.Get(0).(domain.TaskPage); ok {
+		return page, args.Error(1)
+	}
+	return domain.TaskPage{}, args.Error(1)
+}
+
+// Each calls fn with the tasks the expectation returns.
+func (m *MockTaskRepository) Each(ctx context.Context, q domain.TaskQuery, fn func(domain.Task) error) error {
+	args := m.Called(q)
 	if tasks, ok := args.Get(0).([]domain.Task); ok {
-		return tasks, args.Error(1)
+		for _, t := range tasks {
+			if err := fn(t); err != nil {
+				return err
+			}
+		}
 	}
-	return nil, args.Error(1)
+	return args.Error(1)
+}
+
+func (m *MockTaskRepository) Search(ctx context.Context, s domain.TaskSearch) (domain.TaskSearchPage, error) {
+	args := m.Called(s)
+	return args.Get(0).(domain.TaskSearchPage), args.Error(1)
 }
 
-func (m *MockTaskRepository) GetByID(id string) (domain.Task, error) {
+func (m *MockTaskRepository) GetByID(ctx context.Context, id string) (domain.Task, error) {
 	args := m.Called(id)
 	if t, ok := args.Get(0).(domain.Task); ok {
 		return t, args.Error(1)
@@ -26,7 +48,15 @@ func (m *MockTaskRepository) GetByID(id string) (domain.Task, error) {
 	return domain.Task{}, args.Error(1)
 }
 
-func (m *MockTaskRepository) Create(t domain.Task) (domain.Task, error) {
+func (m *MockTaskRepository) GetChildren(ctx context.Context, parentID string) ([]domain.Task, error) {
+	args := m.Called(parentID)
+	if tasks, ok := args.Get(0).([]domain.Task); ok {
+		return tasks, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockTaskRepository) Create(ctx context.Context, t domain.Task) (domain.Task, error) {
 	args := m.Called(t)
 	if created, ok := args.Get(0).(domain.Task); ok {
 		return created, args.Error(1)
@@ -34,7 +64,7 @@ func (m *MockTaskRepository) Create(t domain.Task) (domain.Task, error) {
 	return domain.Task{}, args.Error(1)
 }
 
-func (m *MockTaskRepository) Update(id string, t domain.Task) (domain.Task, error) {
+func (m *MockTaskRepository) Update(ctx context.Context, id string, t domain.Task) (domain.Task, error) {
 	args := m.Called(id, t)
 	if updated, ok := args.Get(0).(domain.Task); ok {
 		return updated, args.Error(1)
@@ -42,11 +72,45 @@ func (m *MockTaskRepository) Update(id string, t domain.Task) (domain.Task, erro
 	return domain.Task{}, args.Error(1)
 }
 
-func (m *MockTaskRepository) Delete(id string) error {
+func (m *MockTaskRepository) Delete(ctx context.Context, id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockTaskRepository) GetDeleted(ctx context.Context, id string) (domain.Task, error) {
+	args := m.Called(id)
+	if t, ok := args.Get(0).(domain.Task); ok {
+		return t, args.Error(1)
+	}
+	return domain.Task{}, args.Error(1)
+}
+
+func (m *MockTaskRepository) Restore(ctx context.Context, id string) (domain.Task, error) {
+	args := m.Called(id)
+	if t, ok := args.Get(0).(domain.Task); ok {
+		return t, args.Error(1)
+	}
+	return domain.Task{}, args.Error(1)
+}
+
+func (m *MockTaskRepository) Purge(ctx context.Context, id string) error {
 	args := m.Called(id)
 	return args.Error(0)
 }
 
+func (m *MockTaskRepository) PurgeDeletedBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	args := m.Called(cutoff)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockTaskRepository) BulkWrite(ctx context.Context, writes []repositories.TaskWrite, atomic bool) ([]repositories.TaskWriteResult, error) {
+	args := m.Called(writes, atomic)
+	if results, ok := args.Get(0).([]repositories.TaskWriteResult); ok {
+		return results, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
 func (m *MockTaskRepository) Close() error {
 	return nil
 }
@@ -1,6 +1,8 @@
 package mocks
 
 import (
+	"context"
+
 	domain "task_manager/Domain"
 
 	"github.com/stretchr/testify/mock"
@@ -10,7 +12,7 @@ type MockUserRepository struct {
 	mock.Mock
 }
 
-func (m *MockUserRepository) CreateUser(username, password string) (domain.User, error) {
+func (m *MockUserRepository) CreateUser(ctx context.Context, username, password string) (domain.User, error) {
 	args := m.Called(username, password)
 	if u, ok := args.Get(0).(domain.User); ok {
 		return u, args.Error(1)
@@ -18,7 +20,7 @@ func (m *MockUserRepository) CreateUser(username, password string) (domain.User,
 	return domain.User{}, args.Error(1)
 }
 
-func (m *MockUserRepository) GetByUsername(username string) (domain.User, error) {
+func (m *MockUserRepository) GetByUsername(ctx context.Context, username string) (domain.User, error) {
 	args := m.Called(username)
 	if u, ok := args.Get(0).(domain.User); ok {
 		return u, args.Error(1)
@@ -26,21 +28,34 @@ func (m *MockUserRepository) GetByUsername(username string) (domain.User, error)
 	return domain.User{}, args.Error(1)
 }
 
+func (m *MockUserRepository) GetByID(ctx context.Context, idHex string) (domain.User, error) {
+	args := m.Called(idHex)
+	if u, ok := args.Get(0).(domain.User); ok {
+		return u, args.Error(1)
+	}
+	return domain.User{}, args.Error(1)
+}
+
 func (m *MockUserRepository) VerifyPassword(u domain.User, password string) bool {
 	args := m.Called(u, password)
 	return args.Bool(0)
 }
 
-func (m *MockUserRepository) PromoteUser(idHex string) error {
+func (m *MockUserRepository) PromoteUser(ctx context.Context, idHex string) error {
 	args := m.Called(idHex)
 	return args.Error(0)
 }
 
+func (m *MockUserRepository) SetRole(ctx context.Context, idHex, role string) error {
+	args := m.Called(idHex, role)
+	return args.Error(0)
+}
+
 func (m *MockUserRepository) Close() error {
 	return nil
 }
 
-func (m *MockUserRepository) IsEmpty() (bool, error) {
+func (m *MockUserRepository) IsEmpty(ctx context.Context) (bool, error) {
 	args := m.Called()
 	return args.Bool(0), args.Error(1)
 }
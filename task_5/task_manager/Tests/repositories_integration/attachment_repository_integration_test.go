@@ -0,0 +1,189 @@
+package repositories_integration_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	domain "task_manager/Domain"
+	repositories "task_manager/Repositories"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+// AttachmentRepositoryConformanceSuite is the contract every IAttachmentRepository must satisfy.
+type AttachmentRepositoryConformanceSuite struct {
+	suite.Suite
+	open func(t *testing.T) repositories.IAttachmentRepository
+	repo repositories.IAttachmentRepository
+}
+
+func (s *AttachmentRepositoryConformanceSuite) SetupTest() {
+	s.repo = s.open(s.T())
+}
+
+func (s *AttachmentRepositoryConformanceSuite) createAttachment(taskID, filename string, at time.Time) domain.Attachment {
+	a, err := s.repo.Create(context.Background(), domain.Attachment{
+		TaskID:      taskID,
+		UploaderID:  "uploader",
+		Filename:    filename,
+		ContentType: "text/plain; charset=utf-8",
+		Size:        5,
+		SHA256:      "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824",
+		BlobKey:     "key-" + filename,
+		CreatedAt:   at,
+	})
+	s.Require().NoError(err)
+	return a
+}
+
+func (s *AttachmentRepositoryConformanceSuite) TestCreateGetDelete() {
+	ctx := context.Background()
+	created := s.createAttachment("task-1", "notes.txt", time.Now().UTC().Truncate(time.Millisecond))
+	assert.NotEmpty(s.T(), created.ID)
+
+	got, err := s.repo.GetByID(ctx, created.ID)
+	s.Require().NoError(err)
+	assert.True(s.T(), created.CreatedAt.Equal(got.CreatedAt))
+	got.CreatedAt = created.CreatedAt
+	assert.Equal(s.T(), created, got)
+
+	s.Require().NoError(s.repo.Delete(ctx, created.ID))
+	_, err = s.repo.GetByID(ctx, created.ID)
+	assert.ErrorIs(s.T(), err, repositories.ErrAttachmentNotFound)
+	assert.ErrorIs(s.T(), s.repo.Delete(ctx, created.ID), repositories.ErrAttachmentNotFound)
+}
+
+func (s *AttachmentRepositoryConformanceSuite) TestListByTaskAndTaskIDs() {
+	ctx := context.Background()
+	start := time.Now().UTC().Truncate(time.Millisecond)
+	s.createAttachment("task-1", "b.txt", start.Add(time.Second))
+	s.createAttachment("task-1", "a.txt", start)
+	s.createAttachment("task-2", "c.txt", start)
+
+	attachments, err := s.repo.ListByTask(ctx, "task-1")
+	s.Require().NoError(err)
+	s.Require().Len(attachments, 2)
+	assert.Equal(s.T(), "a.txt", attachments[0].Filename)
+	assert.Equal(s.T(), "b.txt", attachments[1].Filename)
+
+	attachments, err = s.repo.ListByTask(ctx, "task-3")
+	s.Require().NoError(err)
+	assert.NotNil(s.T(), attachments)
+	assert.Empty(s.T(), attachments)
+
+	ids, err := s.repo.TaskIDs(ctx)
+	s.Require().NoError(err)
+	assert.ElementsMatch(s.T(), []string{"task-1", "task-2"}, ids)
+}
+
+func (s *AttachmentRepositoryConformanceSuite) TestCreate_LimitsAttachmentsPerTask() {
+	ctx := context.Background()
+	start := time.Now().UTC().Truncate(time.Millisecond)
+	for i := 0; i < domain.MaxAttachmentsPerTask-2; i++ {
+		s.createAttachment("task-1", fmt.Sprintf("%d.txt", i), start)
+	}
+
+	// Concurrent uploads cannot both take the last place
+	var wg sync.WaitGroup
+	var created atomic.Int32
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, err := s.repo.Create(ctx, domain.Attachment{TaskID: "task-1", Filename: fmt.Sprintf("race-%d.txt", i), BlobKey: fmt.Sprintf("race-%d", i), CreatedAt: start})
+			if err == nil {
+				created.Add(1)
+			} else {
+				assert.ErrorIs(s.T(), err, repositories.ErrTooManyAttachments)
+			}
+		}(i)
+	}
+	wg.Wait()
+	assert.Equal(s.T(), int32(2), created.Load())
+
+	attachments, err := s.repo.ListByTask(ctx, "task-1")
+	s.Require().NoError(err)
+	assert.Len(s.T(), attachments, domain.MaxAttachmentsPerTask)
+
+	// Other tasks are not affected, and deleting frees a place
+	s.createAttachment("task-2", "other.txt", start)
+	s.Require().NoError(s.repo.Delete(ctx, attachments[0].ID))
+	s.createAttachment("task-1", "again.txt", start)
+}
+
+func TestAttachmentRepositoryConformance(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, b backend) {
+		suite.Run(t, &AttachmentRepositoryConformanceSuite{open: b.attachments})
+	})
+}
+
+// BlobStoreConformanceSuite is the contract every IBlobStore must satisfy.
+type BlobStoreConformanceSuite struct {
+	suite.Suite
+	open  func(t *testing.T) repositories.IBlobStore
+	store repositories.IBlobStore
+}
+
+func (s *BlobStoreConformanceSuite) SetupTest() {
+	s.store = s.open(s.T())
+}
+
+func (s *BlobStoreConformanceSuite) read(key string) []byte {
+	r, err := s.store.Open(context.Background(), key)
+	s.Require().NoError(err)
+	defer r.Close()
+	content, err := io.ReadAll(r)
+	s.Require().NoError(err)
+	return content
+}
+
+func (s *BlobStoreConformanceSuite) TestPutOpenDelete() {
+	ctx := context.Background()
+	// Larger than a GridFS chunk, so that content spans several
+	content := bytes.Repeat([]byte("0123456789abcdef"), 20000)
+	s.Require().NoError(s.store.Put(ctx, "blob-1", bytes.NewReader(content)))
+	s.Require().NoError(s.store.Put(ctx, "blob_2", bytes.NewReader(nil)))
+
+	assert.Equal(s.T(), content, s.read("blob-1"))
+	assert.Empty(s.T(), s.read("blob_2"))
+
+	s.Require().NoError(s.store.Delete(ctx, "blob-1"))
+	_, err := s.store.Open(ctx, "blob-1")
+	assert.ErrorIs(s.T(), err, repositories.ErrBlobNotFound)
+	assert.ErrorIs(s.T(), s.store.Delete(ctx, "blob-1"), repositories.ErrBlobNotFound)
+}
+
+func (s *BlobStoreConformanceSuite) TestRejectsInvalidKeys() {
+	for _, key := range []string{"", "../escape", "a/b", "a.b"} {
+		assert.Error(s.T(), s.store.Put(context.Background(), key, bytes.NewReader([]byte("x"))), key)
+	}
+}
+
+func (s *BlobStoreConformanceSuite) TestFailedPutKeepsNothing() {
+	ctx := context.Background()
+	failing := io.MultiReader(bytes.NewReader([]byte("partial")), errReader{})
+	assert.Error(s.T(), s.store.Put(ctx, "broken", failing))
+	_, err := s.store.Open(ctx, "broken")
+	assert.ErrorIs(s.T(), err, repositories.ErrBlobNotFound)
+}
+
+// errReader fails every read.
+type errReader struct{}
+
+func (errReader) Read([]byte) (int, error) {
+	return 0, errors.New("read failed")
+}
+
+func TestBlobStoreConformance(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, b backend) {
+		suite.Run(t, &BlobStoreConformanceSuite{open: b.blobs})
+	})
+}
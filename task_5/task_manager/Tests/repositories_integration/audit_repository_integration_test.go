@@ -0,0 +1,85 @@
+package repositories_integration_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	domain "task_manager/Domain"
+	repositories "task_manager/Repositories"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+// AuditRepositoryConformanceSuite is the contract every IAuditRepository must satisfy.
+type AuditRepositoryConformanceSuite struct {
+	suite.Suite
+	open func(t *testing.T) repositories.IAuditRepository
+	repo repositories.IAuditRepository
+}
+
+func (s *AuditRepositoryConformanceSuite) SetupTest() {
+	s.repo = s.open(s.T())
+}
+
+func (s *AuditRepositoryConformanceSuite) appendEntry(actorID, action, targetID string, at time.Time) {
+	entry := domain.NewAuditEntry(domain.Actor{UserID: actorID, Role: domain.RoleUser}, action, domain.AuditTargetTask, targetID,
+		domain.DiffField("title", "Old", "New"))
+	entry.CreatedAt = at
+	s.Require().NoError(s.repo.Append(context.Background(), entry))
+}
+
+func (s *AuditRepositoryConformanceSuite) TestAppendAndList() {
+	start := time.Now().UTC().Truncate(time.Millisecond)
+	s.appendEntry("u1", domain.AuditTaskCreate, "t1", start)
+	s.appendEntry("u1", domain.AuditTaskUpdate, "t1", start.Add(time.Second))
+
+	page, err := s.repo.List(context.Background(), domain.AuditQuery{Page: 1, PageSize: 10})
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), int64(2), page.Total)
+	assert.Len(s.T(), page.Entries, 2)
+
+	newest := page.Entries[0]
+	assert.NotEmpty(s.T(), newest.ID)
+	assert.Equal(s.T(), domain.AuditTaskUpdate, newest.Action)
+	assert.Equal(s.T(), "u1", newest.ActorID)
+	assert.Equal(s.T(), domain.RoleUser, newest.ActorRole)
+	assert.Equal(s.T(), domain.AuditTargetTask, newest.TargetType)
+	assert.Equal(s.T(), "t1", newest.TargetID)
+	assert.WithinDuration(s.T(), start.Add(time.Second), newest.CreatedAt, time.Millisecond)
+	assert.Equal(s.T(), []domain.FieldChange{{Field: "title", Old: json.RawMessage(`"Old"`), New: json.RawMessage(`"New"`)}}, newest.Changes)
+}
+
+func (s *AuditRepositoryConformanceSuite) TestListFilters() {
+	start := time.Now().UTC().Truncate(time.Millisecond)
+	s.appendEntry("u1", domain.AuditTaskCreate, "t1", start)
+	s.appendEntry("u2", domain.AuditTaskUpdate, "t1", start.Add(time.Second))
+	s.appendEntry("u2", domain.AuditTaskDelete, "t2", start.Add(2*time.Second))
+
+	page, err := s.repo.List(context.Background(), domain.AuditQuery{ActorID: "u2", Page: 1, PageSize: 10})
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), int64(2), page.Total)
+
+	page, err = s.repo.List(context.Background(), domain.AuditQuery{TargetType: domain.AuditTargetTask, TargetID: "t1", Page: 1, PageSize: 10})
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), int64(2), page.Total)
+
+	page, err = s.repo.List(context.Background(), domain.AuditQuery{Action: domain.AuditTaskDelete, Page: 1, PageSize: 10})
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), int64(1), page.Total)
+
+	page, err = s.repo.List(context.Background(), domain.AuditQuery{Since: start.Add(time.Second), Page: 1, PageSize: 1})
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), int64(2), page.Total)
+	assert.Len(s.T(), page.Entries, 1)
+	assert.Equal(s.T(), "t2", page.Entries[0].TargetID)
+	assert.Equal(s.T(), 2, page.NextPage)
+}
+
+func TestAuditRepositoryConformance(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, b backend) {
+		suite.Run(t, &AuditRepositoryConformanceSuite{open: b.audit})
+	})
+}
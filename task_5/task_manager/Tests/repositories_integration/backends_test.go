@@ -0,0 +1,261 @@
+package repositories_integration_test
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	domain "task_manager/Domain"
+	repositories "task_manager/Repositories"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// backend opens empty repositories of one storage implementation.
+type backend struct {
+	name      string
+	external  bool // needs a running MongoDB
+	tasks     func(t *testing.T) repositories.ITaskRepository
+	users     func(t *testing.T) repositories.IUserRepository
+	roles     func(t *testing.T) repositories.IRoleRepository
+	tokens    func(t *testing.T) repositories.ITokenStore
+	audit     func(t *testing.T) repositories.IAuditRepository
+	webhooks  func(t *testing.T) repositories.IWebhookRepository
+	reminders func(t *testing.T) repositories.IReminderRepository
+	comments  func(t *testing.T) repositories.ICommentRepository
+	// attachments and blobs are the attachment stores used with the backend
+	attachments func(t *testing.T) repositories.IAttachmentRepository
+	blobs       func(t *testing.T) repositories.IBlobStore
+	projects    func(t *testing.T) repositories.IProjectRepository
+	limits      func(t *testing.T) repositories.IRateLimitStore
+}
+
+func backends() []backend {
+	return []backend{
+		{
+			name:   "memory",
+			tasks:  func(*testing.T) repositories.ITaskRepository { return repositories.NewMemoryTaskRepository() },
+			users:  func(*testing.T) repositories.IUserRepository { return repositories.NewMemoryUserRepository() },
+			roles:  func(*testing.T) repositories.IRoleRepository { return repositories.NewMemoryRoleRepository() },
+			tokens: func(*testing.T) repositories.ITokenStore { return repositories.NewMemoryTokenStore() },
+			audit:  func(*testing.T) repositories.IAuditRepository { return repositories.NewMemoryAuditRepository() },
+			webhooks: func(*testing.T) repositories.IWebhookRepository {
+				return repositories.NewMemoryWebhookRepository()
+			},
+			reminders: func(*testing.T) repositories.IReminderRepository {
+				return repositories.NewMemoryReminderRepository()
+			},
+			comments: func(*testing.T) repositories.ICommentRepository {
+				return repositories.NewMemoryCommentRepository()
+			},
+			attachments: func(*testing.T) repositories.IAttachmentRepository {
+				return repositories.NewMemoryAttachmentRepository()
+			},
+			blobs: func(*testing.T) repositories.IBlobStore { return repositories.NewMemoryBlobStore() },
+			projects: func(*testing.T) repositories.IProjectRepository {
+				return repositories.NewMemoryProjectRepository()
+			},
+			limits: func(*testing.T) repositories.IRateLimitStore { return repositories.NewMemoryRateLimitStore() },
+		},
+		{
+			name: "sqlite",
+			tasks: func(t *testing.T) repositories.ITaskRepository {
+				return repositories.NewSQLiteTaskRepository(openSQLite(t), repositories.DefaultTimeouts())
+			},
+			users: func(t *testing.T) repositories.IUserRepository {
+				return repositories.NewSQLiteUserRepository(openSQLite(t), repositories.DefaultTimeouts())
+			},
+			roles: func(t *testing.T) repositories.IRoleRepository {
+				return repositories.NewSQLiteRoleRepository(openSQLite(t), repositories.DefaultTimeouts())
+			},
+			tokens: func(t *testing.T) repositories.ITokenStore {
+				return repositories.NewSQLiteTokenStore(openSQLite(t), repositories.DefaultTimeouts())
+			},
+			audit: func(t *testing.T) repositories.IAuditRepository {
+				return repositories.NewSQLiteAuditRepository(openSQLite(t), repositories.DefaultTimeouts())
+			},
+			webhooks: func(t *testing.T) repositories.IWebhookRepository {
+				return repositories.NewSQLiteWebhookRepository(openSQLite(t), repositories.DefaultTimeouts())
+			},
+			reminders: func(t *testing.T) repositories.IReminderRepository {
+				return repositories.NewSQLiteReminderRepository(openSQLite(t), repositories.DefaultTimeouts())
+			},
+			comments: func(t *testing.T) repositories.ICommentRepository {
+				return repositories.NewSQLiteCommentRepository(openSQLite(t), repositories.DefaultTimeouts())
+			},
+			attachments: func(t *testing.T) repositories.IAttachmentRepository {
+				return repositories.NewSQLiteAttachmentRepository(openSQLite(t), repositories.DefaultTimeouts())
+			},
+			blobs: func(t *testing.T) repositories.IBlobStore {
+				store, err := repositories.NewFileBlobStore(t.TempDir())
+				require.NoError(t, err)
+				return store
+			},
+			projects: func(t *testing.T) repositories.IProjectRepository {
+				return repositories.NewSQLiteProjectRepository(openSQLite(t), repositories.DefaultTimeouts())
+			},
+			limits: func(t *testing.T) repositories.IRateLimitStore {
+				return repositories.NewSQLiteRateLimitStore(openSQLite(t), repositories.DefaultTimeouts())
+			},
+		},
+		{
+			name:     "mongo",
+			external: true,
+			tasks: func(t *testing.T) repositories.ITaskRepository {
+				db := openMongo(t)
+				clearMongoCollection(t, db, "tasks_test")
+				repo, err := repositories.NewMongoTaskRepository(db, "tasks_test", repositories.DefaultTimeouts())
+				require.NoError(t, err)
+				return repo
+			},
+			users: func(t *testing.T) repositories.IUserRepository {
+				db := openMongo(t)
+				clearMongoCollection(t, db, "users_test")
+				return repositories.NewMongoUserRepository(db, "users_test", repositories.DefaultTimeouts())
+			},
+			roles: func(t *testing.T) repositories.IRoleRepository {
+				db := openMongo(t)
+				clearMongoCollection(t, db, "roles_test")
+				return repositories.NewMongoRoleRepository(db, "roles_test", repositories.DefaultTimeouts())
+			},
+			tokens: func(t *testing.T) repositories.ITokenStore {
+				db := openMongo(t)
+				clearMongoCollection(t, db, "refresh_tokens")
+				clearMongoCollection(t, db, "revoked_tokens")
+				store, err := repositories.NewMongoTokenStore(db, repositories.DefaultTimeouts())
+				require.NoError(t, err)
+				return store
+			},
+			audit: func(t *testing.T) repositories.IAuditRepository {
+				db := openMongo(t)
+				clearMongoCollection(t, db, "audit_log_test")
+				repo, err := repositories.NewMongoAuditRepository(db, "audit_log_test", repositories.DefaultTimeouts())
+				require.NoError(t, err)
+				return repo
+			},
+			webhooks: func(t *testing.T) repositories.IWebhookRepository {
+				db := openMongo(t)
+				clearMongoCollection(t, db, "webhooks")
+				clearMongoCollection(t, db, "webhook_deliveries")
+				repo, err := repositories.NewMongoWebhookRepository(db, repositories.DefaultTimeouts())
+				require.NoError(t, err)
+				return repo
+			},
+			reminders: func(t *testing.T) repositories.IReminderRepository {
+				db := openMongo(t)
+				clearMongoCollection(t, db, "reminders_test")
+				repo, err := repositories.NewMongoReminderRepository(db, "reminders_test", repositories.DefaultTimeouts())
+				require.NoError(t, err)
+				return repo
+			},
+			comments: func(t *testing.T) repositories.ICommentRepository {
+				db := openMongo(t)
+				clearMongoCollection(t, db, "comments_test")
+				repo, err := repositories.NewMongoCommentRepository(db, "comments_test", repositories.DefaultTimeouts())
+				require.NoError(t, err)
+				return repo
+			},
+			attachments: func(t *testing.T) repositories.IAttachmentRepository {
+				db := openMongo(t)
+				clearMongoCollection(t, db, "attachments_test")
+				repo, err := repositories.NewMongoAttachmentRepository(db, "attachments_test", repositories.DefaultTimeouts())
+				require.NoError(t, err)
+				return repo
+			},
+			blobs: func(t *testing.T) repositories.IBlobStore {
+				db := openMongo(t)
+				clearMongoCollection(t, db, "blobs_test.files")
+				clearMongoCollection(t, db, "blobs_test.chunks")
+				store, err := repositories.NewGridFSBlobStore(db, "blobs_test")
+				require.NoError(t, err)
+				return store
+			},
+			projects: func(t *testing.T) repositories.IProjectRepository {
+				db := openMongo(t)
+				clearMongoCollection(t, db, "projects_test")
+				repo, err := repositories.NewMongoProjectRepository(db, "projects_test", repositories.DefaultTimeouts())
+				require.NoError(t, err)
+				return repo
+			},
+			limits: func(t *testing.T) repositories.IRateLimitStore {
+				db := openMongo(t)
+				clearMongoCollection(t, db, "rate_limits")
+				clearMongoCollection(t, db, "login_failures")
+				store, err := repositories.NewMongoRateLimitStore(db, repositories.DefaultTimeouts())
+				require.NoError(t, err)
+				return store
+			},
+		},
+	}
+}
+
+// forEachBackend runs the test once per backend, skipping MongoDB when SKIP_INTEGRATION=true.
+func forEachBackend(t *testing.T, run func(t *testing.T, b backend)) {
+	for _, b := range backends() {
+		t.Run(b.name, func(t *testing.T) {
+			if b.external && os.Getenv("SKIP_INTEGRATION") == "true" {
+				t.Skip("Skipping integration tests")
+			}
+			run(t, b)
+		})
+	}
+}
+
+// TestTaskUpdate_ClearsParent checks that every backend stores a removed
+// parent, since MongoDB leaves empty fields out of an update.
+func TestTaskUpdate_ClearsParent(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, b backend) {
+		ctx := context.Background()
+		repo := b.tasks(t)
+		parent, err := repo.Create(ctx, domain.Task{Title: "Parent", Status: "pending"})
+		require.NoError(t, err)
+		child, err := repo.Create(ctx, domain.Task{Title: "Child", Status: "pending", ParentID: parent.ID})
+		require.NoError(t, err)
+
+		child.ParentID = ""
+		_, err = repo.Update(ctx, child.ID, child)
+		require.NoError(t, err)
+		fetched, err := repo.GetByID(ctx, child.ID)
+		require.NoError(t, err)
+		assert.Empty(t, fetched.ParentID)
+
+		children, err := repo.GetChildren(ctx, parent.ID)
+		require.NoError(t, err)
+		assert.Empty(t, children)
+	})
+}
+
+func openSQLite(t *testing.T) *sql.DB {
+	db, err := repositories.OpenSQLite(filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func mongoURI() string {
+	if uri := os.Getenv("MONGODB_URI"); uri != "" {
+		return uri
+	}
+	return "mongodb://localhost:27017"
+}
+
+// openMongo connects a client shared by the repositories of one test.
+func openMongo(t *testing.T) *mongo.Database {
+	client, err := repositories.OpenMongo(repositories.MongoConfig{URI: mongoURI()})
+	require.NoError(t, err)
+	t.Cleanup(func() { client.Disconnect(context.Background()) })
+	return client.Database("taskmanager_test")
+}
+
+func clearMongoCollection(t *testing.T, db *mongo.Database, name string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	_, err := db.Collection(name).DeleteMany(ctx, bson.M{})
+	require.NoError(t, err)
+}
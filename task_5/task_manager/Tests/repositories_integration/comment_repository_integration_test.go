@@ -0,0 +1,100 @@
+package repositories_integration_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	domain "task_manager/Domain"
+	repositories "task_manager/Repositories"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+// CommentRepositoryConformanceSuite is the contract every ICommentRepository must satisfy.
+type CommentRepositoryConformanceSuite struct {
+	suite.Suite
+	open func(t *testing.T) repositories.ICommentRepository
+	repo repositories.ICommentRepository
+}
+
+func (s *CommentRepositoryConformanceSuite) SetupTest() {
+	s.repo = s.open(s.T())
+}
+
+func (s *CommentRepositoryConformanceSuite) createComment(taskID, body string, at time.Time) domain.Comment {
+	c, err := s.repo.Create(context.Background(), domain.Comment{
+		TaskID:    taskID,
+		AuthorID:  "author",
+		Body:      body,
+		Mentions:  []domain.Mention{},
+		CreatedAt: at,
+	})
+	s.Require().NoError(err)
+	return c
+}
+
+func (s *CommentRepositoryConformanceSuite) TestCreateGetUpdateDelete() {
+	ctx := context.Background()
+	created := s.createComment("task-1", "First", time.Now().UTC().Truncate(time.Millisecond))
+	assert.NotEmpty(s.T(), created.ID)
+
+	got, err := s.repo.GetByID(ctx, created.ID)
+	s.Require().NoError(err)
+	assert.Equal(s.T(), "First", got.Body)
+	assert.True(s.T(), created.CreatedAt.Equal(got.CreatedAt))
+	assert.Nil(s.T(), got.EditedAt)
+	assert.Empty(s.T(), got.Mentions)
+
+	edited := time.Now().UTC().Truncate(time.Millisecond)
+	got.Body = "Edited, @bob"
+	got.Mentions = []domain.Mention{{UserID: "u2", Username: "bob"}}
+	got.EditedAt = &edited
+	s.Require().NoError(s.repo.Update(ctx, got))
+
+	got, err = s.repo.GetByID(ctx, created.ID)
+	s.Require().NoError(err)
+	assert.Equal(s.T(), "Edited, @bob", got.Body)
+	assert.Equal(s.T(), []domain.Mention{{UserID: "u2", Username: "bob"}}, got.Mentions)
+	s.Require().NotNil(got.EditedAt)
+	assert.True(s.T(), edited.Equal(*got.EditedAt))
+	assert.Equal(s.T(), "task-1", got.TaskID)
+
+	s.Require().NoError(s.repo.Delete(ctx, created.ID))
+	_, err = s.repo.GetByID(ctx, created.ID)
+	assert.ErrorIs(s.T(), err, repositories.ErrCommentNotFound)
+	assert.ErrorIs(s.T(), s.repo.Delete(ctx, created.ID), repositories.ErrCommentNotFound)
+	assert.ErrorIs(s.T(), s.repo.Update(ctx, got), repositories.ErrCommentNotFound)
+}
+
+func (s *CommentRepositoryConformanceSuite) TestList() {
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i, body := range []string{"one", "two", "three"} {
+		s.createComment("task-1", body, base.Add(time.Duration(i)*time.Minute))
+	}
+	s.createComment("task-2", "elsewhere", base)
+
+	q := domain.CommentQuery{TaskID: "task-1", PageSize: 2}
+	s.Require().NoError(q.Normalize())
+	page, err := s.repo.List(context.Background(), q)
+	s.Require().NoError(err)
+	assert.Equal(s.T(), int64(3), page.Total)
+	assert.Equal(s.T(), 2, page.NextPage)
+	s.Require().Len(page.Comments, 2)
+	assert.Equal(s.T(), "one", page.Comments[0].Body)
+	assert.Equal(s.T(), "two", page.Comments[1].Body)
+
+	q.Page = 2
+	page, err = s.repo.List(context.Background(), q)
+	s.Require().NoError(err)
+	s.Require().Len(page.Comments, 1)
+	assert.Equal(s.T(), "three", page.Comments[0].Body)
+	assert.Zero(s.T(), page.NextPage)
+}
+
+func TestCommentRepositoryConformance(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, b backend) {
+		suite.Run(t, &CommentRepositoryConformanceSuite{open: b.comments})
+	})
+}
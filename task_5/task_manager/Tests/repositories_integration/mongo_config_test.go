@@ -0,0 +1,21 @@
+package repositories_integration_test
+
+import (
+	"testing"
+
+	repositories "task_manager/Repositories"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOpenMongo_InvalidConfig(t *testing.T) {
+	for name, cfg := range map[string]repositories.MongoConfig{
+		"read preference": {URI: mongoURI(), ReadPreference: "fastest"},
+		"write concern":   {URI: mongoURI(), WriteConcern: "most"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := repositories.OpenMongo(cfg)
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package repositories_integration_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	domain "task_manager/Domain"
+	repositories "task_manager/Repositories"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+// ProjectRepositoryConformanceSuite is the contract every IProjectRepository must satisfy.
+type ProjectRepositoryConformanceSuite struct {
+	suite.Suite
+	open func(t *testing.T) repositories.IProjectRepository
+	repo repositories.IProjectRepository
+}
+
+func (s *ProjectRepositoryConformanceSuite) SetupTest() {
+	s.repo = s.open(s.T())
+}
+
+func (s *ProjectRepositoryConformanceSuite) createProject(name string, at time.Time, members ...domain.ProjectMember) domain.Project {
+	p, err := s.repo.Create(context.Background(), domain.Project{
+		Name:      name,
+		Members:   members,
+		CreatedBy: "creator",
+		CreatedAt: at,
+	})
+	s.Require().NoError(err)
+	return p
+}
+
+func (s *ProjectRepositoryConformanceSuite) TestCreateGetUpdate() {
+	ctx := context.Background()
+	created := s.createProject("Launch", time.Now().UTC().Truncate(time.Millisecond),
+		domain.ProjectMember{UserID: "u1", Role: domain.ProjectRoleManager})
+	assert.NotEmpty(s.T(), created.ID)
+
+	got, err := s.repo.GetByID(ctx, created.ID)
+	s.Require().NoError(err)
+	assert.Equal(s.T(), "Launch", got.Name)
+	assert.Equal(s.T(), "creator", got.CreatedBy)
+	assert.True(s.T(), created.CreatedAt.Equal(got.CreatedAt))
+	assert.Equal(s.T(), []domain.ProjectMember{{UserID: "u1", Role: domain.ProjectRoleManager}}, got.Members)
+	assert.Nil(s.T(), got.ArchivedAt)
+
+	archived := time.Now().UTC().Truncate(time.Millisecond)
+	got.Name = "Launch v2"
+	got.Description = "Second attempt"
+	got.SetMember("u2", domain.ProjectRoleViewer)
+	got.ArchivedAt = &archived
+	s.Require().NoError(s.repo.Update(ctx, got))
+
+	got, err = s.repo.GetByID(ctx, created.ID)
+	s.Require().NoError(err)
+	assert.Equal(s.T(), "Launch v2", got.Name)
+	assert.Equal(s.T(), "Second attempt", got.Description)
+	assert.Len(s.T(), got.Members, 2)
+	s.Require().NotNil(got.ArchivedAt)
+	assert.True(s.T(), archived.Equal(*got.ArchivedAt))
+
+	got.ArchivedAt = nil
+	s.Require().NoError(s.repo.Update(ctx, got))
+	got, err = s.repo.GetByID(ctx, created.ID)
+	s.Require().NoError(err)
+	assert.Nil(s.T(), got.ArchivedAt)
+}
+
+func (s *ProjectRepositoryConformanceSuite) TestNotFound() {
+	ctx := context.Background()
+	_, err := s.repo.GetByID(ctx, "missing")
+	assert.ErrorIs(s.T(), err, repositories.ErrProjectNotFound)
+	assert.ErrorIs(s.T(), s.repo.Update(ctx, domain.Project{ID: "missing", Name: "x"}), repositories.ErrProjectNotFound)
+}
+
+func (s *ProjectRepositoryConformanceSuite) TestList() {
+	ctx := context.Background()
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	s.createProject("Second", base.Add(time.Hour),
+		domain.ProjectMember{UserID: "u1", Role: domain.ProjectRoleMember},
+		domain.ProjectMember{UserID: "u2", Role: domain.ProjectRoleManager})
+	s.createProject("First", base, domain.ProjectMember{UserID: "u1", Role: domain.ProjectRoleManager})
+	s.createProject("Other", base.Add(2*time.Hour), domain.ProjectMember{UserID: "u3", Role: domain.ProjectRoleManager})
+
+	all, err := s.repo.List(ctx, "")
+	s.Require().NoError(err)
+	s.Require().Len(all, 3)
+	assert.Equal(s.T(), "First", all[0].Name)
+	assert.Equal(s.T(), "Second", all[1].Name)
+	assert.Equal(s.T(), "Other", all[2].Name)
+
+	mine, err := s.repo.List(ctx, "u1")
+	s.Require().NoError(err)
+	s.Require().Len(mine, 2)
+	assert.Equal(s.T(), "First", mine[0].Name)
+	assert.Equal(s.T(), "Second", mine[1].Name)
+
+	none, err := s.repo.List(ctx, "nobody")
+	s.Require().NoError(err)
+	assert.Empty(s.T(), none)
+}
+
+func TestProjectRepositoryConformance(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, b backend) {
+		suite.Run(t, &ProjectRepositoryConformanceSuite{open: b.projects})
+	})
+}
@@ -0,0 +1,113 @@
+package repositories_integration_test
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	domain "task_manager/Domain"
+	repositories "task_manager/Repositories"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+// RateLimitStoreConformanceSuite is the contract every IRateLimitStore must satisfy.
+type RateLimitStoreConformanceSuite struct {
+	suite.Suite
+	open  func(t *testing.T) repositories.IRateLimitStore
+	store repositories.IRateLimitStore
+}
+
+func (s *RateLimitStoreConformanceSuite) SetupTest() {
+	s.store = s.open(s.T())
+}
+
+func (s *RateLimitStoreConformanceSuite) TestTake() {
+	ctx := context.Background()
+	limit := domain.RateLimit{Burst: 2, Period: time.Minute}
+	now := time.Now().UTC().Truncate(time.Millisecond)
+
+	for i := 0; i < 2; i++ {
+		wait, err := s.store.Take(ctx, "login.ip:1.2.3.4", limit, now)
+		s.Require().NoError(err)
+		assert.Zero(s.T(), wait)
+	}
+	wait, err := s.store.Take(ctx, "login.ip:1.2.3.4", limit, now)
+	s.Require().NoError(err)
+	assert.Equal(s.T(), 30*time.Second, wait)
+
+	// Other keys have their own bucket
+	wait, err = s.store.Take(ctx, "login.ip:5.6.7.8", limit, now)
+	s.Require().NoError(err)
+	assert.Zero(s.T(), wait)
+
+	// A token is regained every 30 seconds
+	wait, err = s.store.Take(ctx, "login.ip:1.2.3.4", limit, now.Add(20*time.Second))
+	s.Require().NoError(err)
+	assert.InDelta(s.T(), float64(10*time.Second), float64(wait), float64(time.Millisecond))
+	wait, err = s.store.Take(ctx, "login.ip:1.2.3.4", limit, now.Add(30*time.Second))
+	s.Require().NoError(err)
+	assert.Zero(s.T(), wait)
+}
+
+func (s *RateLimitStoreConformanceSuite) TestAttempt() {
+	ctx := context.Background()
+	policy := domain.LockoutPolicy{Threshold: 2, Delay: time.Minute, MaxDelay: 3 * time.Minute, Window: 10 * time.Minute}
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	attempt := func(key string, at time.Time) time.Duration {
+		wait, err := s.store.Attempt(ctx, key, policy, at)
+		s.Require().NoError(err)
+		return wait
+	}
+
+	// The attempt that reaches the threshold is let through and locks the key
+	assert.Zero(s.T(), attempt("login:alice", now))
+	assert.Zero(s.T(), attempt("login:alice", now))
+	assert.Equal(s.T(), time.Minute, attempt("login:alice", now))
+	assert.Equal(s.T(), 30*time.Second, attempt("login:alice", now.Add(30*time.Second)))
+	// Refused attempts are not counted, so the lock does not grow while it lasts
+	assert.Zero(s.T(), attempt("login:alice", now.Add(time.Minute)))
+	assert.Equal(s.T(), 2*time.Minute, attempt("login:alice", now.Add(time.Minute)))
+	assert.Zero(s.T(), attempt("login:bob", now))
+
+	// Failures are forgotten a window after the lockout ends
+	later := now.Add(13*time.Minute + time.Second)
+	assert.Zero(s.T(), attempt("login:alice", later))
+	assert.Zero(s.T(), attempt("login:alice", later))
+	assert.Equal(s.T(), time.Minute, attempt("login:alice", later))
+
+	s.Require().NoError(s.store.ResetFailures(ctx, "login:alice"))
+	assert.Zero(s.T(), attempt("login:alice", later))
+	assert.Zero(s.T(), attempt("login:alice", later))
+	s.Require().NoError(s.store.ResetFailures(ctx, "login:nobody"))
+}
+
+func (s *RateLimitStoreConformanceSuite) TestAttempt_Concurrent() {
+	ctx := context.Background()
+	policy := domain.LockoutPolicy{Threshold: 3, Delay: time.Minute, MaxDelay: time.Hour, Window: time.Hour}
+	now := time.Now().UTC().Truncate(time.Millisecond)
+
+	var wg sync.WaitGroup
+	var allowed atomic.Int32
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			wait, err := s.store.Attempt(ctx, "login:alice", policy, now)
+			if assert.NoError(s.T(), err) && wait == 0 {
+				allowed.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(s.T(), int32(policy.Threshold), allowed.Load())
+}
+
+func TestRateLimitStoreConformance(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, b backend) {
+		suite.Run(t, &RateLimitStoreConformanceSuite{open: b.limits})
+	})
+}
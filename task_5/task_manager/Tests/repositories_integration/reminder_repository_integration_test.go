@@ -0,0 +1,76 @@
+package repositories_integration_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	domain "task_manager/Domain"
+	repositories "task_manager/Repositories"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+// ReminderRepositoryConformanceSuite is the contract every IReminderRepository must satisfy.
+type ReminderRepositoryConformanceSuite struct {
+	suite.Suite
+	open func(t *testing.T) repositories.IReminderRepository
+	repo repositories.IReminderRepository
+}
+
+func (s *ReminderRepositoryConformanceSuite) SetupTest() {
+	s.repo = s.open(s.T())
+}
+
+func (s *ReminderRepositoryConformanceSuite) TestClaimOnce() {
+	ctx := context.Background()
+	due := time.Now().UTC().Truncate(time.Millisecond)
+	reminder := domain.NewReminder(domain.Task{ID: "t1", DueDate: due}, domain.EventTaskOverdue, time.Now().UTC())
+
+	claimed, err := s.repo.Claim(ctx, reminder)
+	assert.NoError(s.T(), err)
+	assert.True(s.T(), claimed)
+	claimed, err = s.repo.Claim(ctx, reminder)
+	assert.NoError(s.T(), err)
+	assert.False(s.T(), claimed)
+
+	// A new due date is a new reminder
+	moved := domain.NewReminder(domain.Task{ID: "t1", DueDate: due.Add(time.Hour)}, domain.EventTaskOverdue, time.Now().UTC())
+	claimed, err = s.repo.Claim(ctx, moved)
+	assert.NoError(s.T(), err)
+	assert.True(s.T(), claimed)
+
+	assert.NoError(s.T(), s.repo.Release(ctx, reminder.ID))
+	claimed, err = s.repo.Claim(ctx, reminder)
+	assert.NoError(s.T(), err)
+	assert.True(s.T(), claimed)
+}
+
+func (s *ReminderRepositoryConformanceSuite) TestDeleteDueBefore() {
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	old := domain.NewReminder(domain.Task{ID: "t1", DueDate: now.Add(-48 * time.Hour)}, domain.EventTaskOverdue, now)
+	recent := domain.NewReminder(domain.Task{ID: "t2", DueDate: now.Add(-time.Hour)}, domain.EventTaskOverdue, now)
+	for _, r := range []domain.Reminder{old, recent} {
+		_, err := s.repo.Claim(ctx, r)
+		s.Require().NoError(err)
+	}
+
+	n, err := s.repo.DeleteDueBefore(ctx, now.Add(-24*time.Hour))
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), int64(1), n)
+
+	claimed, err := s.repo.Claim(ctx, old)
+	assert.NoError(s.T(), err)
+	assert.True(s.T(), claimed)
+	claimed, err = s.repo.Claim(ctx, recent)
+	assert.NoError(s.T(), err)
+	assert.False(s.T(), claimed)
+}
+
+func TestReminderRepositoryConformance(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, b backend) {
+		suite.Run(t, &ReminderRepositoryConformanceSuite{open: b.reminders})
+	})
+}
@@ -0,0 +1,65 @@
+package repositories_integration_test
+
+import (
+	"context"
+	"testing"
+
+	domain "task_manager/Domain"
+	repositories "task_manager/Repositories"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+// RoleRepositoryConformanceSuite is the contract every IRoleRepository must satisfy.
+type RoleRepositoryConformanceSuite struct {
+	suite.Suite
+	open func(t *testing.T) repositories.IRoleRepository
+	repo repositories.IRoleRepository
+}
+
+func (s *RoleRepositoryConformanceSuite) SetupTest() {
+	s.repo = s.open(s.T())
+}
+
+func (s *RoleRepositoryConformanceSuite) TestSaveAndGet() {
+	role := domain.Role{Name: "reviewer", Permissions: []string{domain.PermTasksRead}}
+	assert.NoError(s.T(), s.repo.Save(context.Background(), role))
+
+	found, err := s.repo.GetByName(context.Background(), "reviewer")
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), role, found)
+
+	role.Permissions = append(role.Permissions, domain.PermTasksManage)
+	assert.NoError(s.T(), s.repo.Save(context.Background(), role))
+
+	found, err = s.repo.GetByName(context.Background(), "reviewer")
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), role.Permissions, found.Permissions)
+}
+
+func (s *RoleRepositoryConformanceSuite) TestList() {
+	assert.NoError(s.T(), s.repo.Save(context.Background(), domain.Role{Name: "b", Permissions: []string{}}))
+	assert.NoError(s.T(), s.repo.Save(context.Background(), domain.Role{Name: "a", Permissions: []string{}}))
+
+	roles, err := s.repo.List(context.Background())
+	assert.NoError(s.T(), err)
+	s.Require().Len(roles, 2)
+	assert.Equal(s.T(), "a", roles[0].Name)
+	assert.Equal(s.T(), "b", roles[1].Name)
+}
+
+func (s *RoleRepositoryConformanceSuite) TestDelete() {
+	assert.NoError(s.T(), s.repo.Save(context.Background(), domain.Role{Name: "reviewer", Permissions: []string{}}))
+	assert.NoError(s.T(), s.repo.Delete(context.Background(), "reviewer"))
+
+	_, err := s.repo.GetByName(context.Background(), "reviewer")
+	assert.ErrorIs(s.T(), err, repositories.ErrRoleNotFound)
+	assert.ErrorIs(s.T(), s.repo.Delete(context.Background(), "reviewer"), repositories.ErrRoleNotFound)
+}
+
+func TestRoleRepositoryConformance(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, b backend) {
+		suite.Run(t, &RoleRepositoryConformanceSuite{open: b.roles})
+	})
+}
@@ -2,63 +2,32 @@ package repositories_integration_test
 
 import (
 	"context"
-	"os"
+	"errors"
+	"fmt"
 	"testing"
 	"time"
 
 	domain "task_manager/Domain"
 	repositories "task_manager/Repositories"
+	usecases "task_manager/Usecases"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
-	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
-	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
-type TaskRepositoryIntegrationSuite struct {
+// TaskRepositoryConformanceSuite is the contract every ITaskRepository must satisfy.
+type TaskRepositoryConformanceSuite struct {
 	suite.Suite
-	repo       repositories.ITaskRepository
-	client     *mongo.Client
-	collection *mongo.Collection
+	open func(t *testing.T) repositories.ITaskRepository
+	repo repositories.ITaskRepository
 }
 
-func (s *TaskRepositoryIntegrationSuite) SetupSuite() {
-	mongoURI := os.Getenv("MONGODB_URI")
-	if mongoURI == "" {
-		mongoURI = "mongodb://localhost:27017"
-	}
-
-	repo, err := repositories.NewMongoTaskRepository(mongoURI, "taskmanager_test", "tasks_test")
-	s.Require().NoError(err)
-	s.repo = repo
-
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-	client, err := mongo.Connect(ctx, options.Client().ApplyURI(mongoURI))
-	s.Require().NoError(err)
-	s.client = client
-	s.collection = client.Database("taskmanager_test").Collection("tasks_test")
-}
-
-func (s *TaskRepositoryIntegrationSuite) TearDownSuite() {
-	if s.repo != nil {
-		s.repo.Close()
-	}
-	if s.client != nil {
-		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-		defer cancel()
-		s.client.Disconnect(ctx)
-	}
-}
-
-func (s *TaskRepositoryIntegrationSuite) SetupTest() {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-	s.collection.DeleteMany(ctx, bson.M{})
+func (s *TaskRepositoryConformanceSuite) SetupTest() {
+	s.repo = s.open(s.T())
 }
 
-func (s *TaskRepositoryIntegrationSuite) TestCreateAndGetTask() {
+func (s *TaskRepositoryConformanceSuite) TestCreateAndGetTask() {
 	task := domain.Task{
 		Title:       "Integration Test Task",
 		Description: "Testing MongoDB integration",
@@ -66,69 +35,584 @@ func (s *TaskRepositoryIntegrationSuite) TestCreateAndGetTask() {
 		DueDate:     time.Now().Add(24 * time.Hour),
 	}
 
-	created, err := s.repo.Create(task)
+	created, err := s.repo.Create(context.Background(), task)
 	assert.NoError(s.T(), err)
 	assert.NotEmpty(s.T(), created.ID)
 	assert.Equal(s.T(), task.Title, created.Title)
 
-	fetched, err := s.repo.GetByID(created.ID)
+	fetched, err := s.repo.GetByID(context.Background(), created.ID)
 	assert.NoError(s.T(), err)
 	assert.Equal(s.T(), created.ID, fetched.ID)
 	assert.Equal(s.T(), task.Title, fetched.Title)
+	assert.Equal(s.T(), task.Description, fetched.Description)
+	assert.WithinDuration(s.T(), task.DueDate, fetched.DueDate, time.Millisecond)
+}
+
+func (s *TaskRepositoryConformanceSuite) TestCreateAndGetTask_RichFields() {
+	created, err := s.repo.Create(context.Background(), domain.Task{
+		Title:       "Rich",
+		Status:      "pending",
+		Priority:    domain.PriorityHigh,
+		Tags:        []string{"backend", "urgent"},
+		AssigneeIDs: []string{"u1", "u2"},
+	})
+	assert.NoError(s.T(), err)
+
+	fetched, err := s.repo.GetByID(context.Background(), created.ID)
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), domain.PriorityHigh, fetched.Priority)
+	assert.Equal(s.T(), []string{"backend", "urgent"}, fetched.Tags)
+	assert.Equal(s.T(), []string{"u1", "u2"}, fetched.AssigneeIDs)
+	assert.Empty(s.T(), fetched.ParentID)
+}
+
+func (s *TaskRepositoryConformanceSuite) TestUpdate_Recurrence() {
+	ctx := context.Background()
+	recurrence := &domain.Recurrence{Rule: "FREQ=WEEKLY;BYDAY=MO,FR", Timezone: "Europe/Berlin"}
+	created, err := s.repo.Create(ctx, domain.Task{Title: "Recurring", Status: "pending", Recurrence: recurrence})
+	assert.NoError(s.T(), err)
+
+	fetched, err := s.repo.GetByID(ctx, created.ID)
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), recurrence, fetched.Recurrence)
+
+	// Removing the recurrence clears it from storage
+	fetched.Recurrence = nil
+	_, err = s.repo.Update(ctx, created.ID, fetched)
+	assert.NoError(s.T(), err)
+	fetched, err = s.repo.GetByID(ctx, created.ID)
+	assert.NoError(s.T(), err)
+	assert.Nil(s.T(), fetched.Recurrence)
+}
+
+func (s *TaskRepositoryConformanceSuite) TestBulkWrite() {
+	ctx := context.Background()
+	kept, err := s.repo.Create(ctx, domain.Task{Title: "Kept", Status: "pending"})
+	assert.NoError(s.T(), err)
+	removed, err := s.repo.Create(ctx, domain.Task{Title: "Removed", Status: "pending"})
+	assert.NoError(s.T(), err)
+
+	kept.Status = "completed"
+	stale := removed
+	stale.Version = 7
+	results, err := s.repo.BulkWrite(ctx, []repositories.TaskWrite{
+		{Op: repositories.WriteCreate, Task: domain.Task{Title: "New", Status: "pending"}},
+		{Op: repositories.WriteUpdate, Task: kept},
+		{Op: repositories.WriteUpdate, Task: stale},
+		{Op: repositories.WriteDelete, Task: removed},
+		{Op: repositories.WriteDelete, Task: domain.Task{ID: "nonexistent-id", Version: 1}},
+	}, false)
+	assert.NoError(s.T(), err)
+	if !assert.Len(s.T(), results, 5) {
+		return
+	}
+
+	assert.NoError(s.T(), results[0].Err)
+	assert.NotEmpty(s.T(), results[0].Task.ID)
+	assert.Equal(s.T(), int64(1), results[0].Task.Version)
+	assert.NoError(s.T(), results[1].Err)
+	assert.Equal(s.T(), int64(2), results[1].Task.Version)
+	assert.ErrorIs(s.T(), results[2].Err, repositories.ErrVersionConflict)
+	assert.NoError(s.T(), results[3].Err)
+	assert.ErrorIs(s.T(), results[4].Err, repositories.ErrNotFound)
+
+	created, err := s.repo.GetByID(ctx, results[0].Task.ID)
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), "New", created.Title)
+	updated, err := s.repo.GetByID(ctx, kept.ID)
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), "completed", updated.Status)
+	deleted, err := s.repo.GetDeleted(ctx, removed.ID)
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), int64(2), deleted.Version)
+}
+
+func (s *TaskRepositoryConformanceSuite) TestBulkWrite_Atomic() {
+	ctx := context.Background()
+	task, err := s.repo.Create(ctx, domain.Task{Title: "Task", Status: "pending"})
+	assert.NoError(s.T(), err)
+
+	task.Status = "completed"
+	results, err := s.repo.BulkWrite(ctx, []repositories.TaskWrite{
+		{Op: repositories.WriteCreate, Task: domain.Task{Title: "New", Status: "pending"}},
+		{Op: repositories.WriteUpdate, Task: task},
+		{Op: repositories.WriteDelete, Task: domain.Task{ID: "nonexistent-id", Version: 1}},
+	}, true)
+	var cmdErr mongo.CommandError
+	if errors.As(err, &cmdErr) && cmdErr.Code == mongoIllegalOperation {
+		s.T().Skip("MongoDB server does not support transactions")
+	}
+	assert.NoError(s.T(), err)
+	if !assert.Len(s.T(), results, 3) {
+		return
+	}
+	assert.ErrorIs(s.T(), results[0].Err, repositories.ErrBulkAborted)
+	assert.ErrorIs(s.T(), results[1].Err, repositories.ErrBulkAborted)
+	assert.ErrorIs(s.T(), results[2].Err, repositories.ErrNotFound)
+
+	// Nothing was written
+	all := domain.TaskQuery{SortBy: "due_date", Page: 1, PageSize: 10}
+	page, err := s.repo.List(ctx, all)
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), page.Tasks, 1)
+	stored, err := s.repo.GetByID(ctx, task.ID)
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), "pending", stored.Status)
+	assert.Equal(s.T(), int64(1), stored.Version)
+
+	results, err = s.repo.BulkWrite(ctx, []repositories.TaskWrite{
+		{Op: repositories.WriteCreate, Task: domain.Task{Title: "New", Status: "pending"}},
+		{Op: repositories.WriteUpdate, Task: stored},
+	}, true)
+	assert.NoError(s.T(), err)
+	assert.NoError(s.T(), results[0].Err)
+	assert.NoError(s.T(), results[1].Err)
+	page, err = s.repo.List(ctx, all)
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), page.Tasks, 2)
+}
+
+// mongoIllegalOperation is the error code of transactions on a standalone server.
+const mongoIllegalOperation = 20
+
+func (s *TaskRepositoryConformanceSuite) TestGetChildren() {
+	parent, err := s.repo.Create(context.Background(), domain.Task{Title: "Parent", Status: "pending"})
+	assert.NoError(s.T(), err)
+	_, err = s.repo.Create(context.Background(), domain.Task{Title: "Child", Status: "pending", ParentID: parent.ID})
+	assert.NoError(s.T(), err)
+	_, err = s.repo.Create(context.Background(), domain.Task{Title: "Unrelated", Status: "pending"})
+	assert.NoError(s.T(), err)
+
+	children, err := s.repo.GetChildren(context.Background(), parent.ID)
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), children, 1)
+	assert.Equal(s.T(), "Child", children[0].Title)
+	assert.Equal(s.T(), parent.ID, children[0].ParentID)
+
+	children, err = s.repo.GetChildren(context.Background(), "nonexistent-id")
+	assert.NoError(s.T(), err)
+	assert.Empty(s.T(), children)
 }
 
-func (s *TaskRepositoryIntegrationSuite) TestGetAllTasks() {
-	task1 := domain.Task{Title: "Task 1", Status: "pending"}
-	task2 := domain.Task{Title: "Task 2", Status: "completed"}
+func (s *TaskRepositoryConformanceSuite) TestListTasks() {
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i, title := range []string{"Buy milk", "Buy bread", "Walk dog", "Buy eggs"} {
+		_, err := s.repo.Create(context.Background(), domain.Task{
+			Title:   title,
+			Status:  "pending",
+			DueDate: base.Add(time.Duration(i) * 24 * time.Hour),
+			OwnerID: "owner-1",
+		})
+		assert.NoError(s.T(), err)
+	}
 
-	_, err := s.repo.Create(task1)
+	q := domain.TaskQuery{OwnerID: "owner-1", Search: "buy", SortDesc: true, PageSize: 2}
+	s.Require().NoError(q.Normalize())
+	page, err := s.repo.List(context.Background(), q)
 	assert.NoError(s.T(), err)
-	_, err = s.repo.Create(task2)
+	assert.Equal(s.T(), int64(3), page.Total)
+	assert.Equal(s.T(), 2, page.NextPage)
+	s.Require().Len(page.Tasks, 2)
+	assert.Equal(s.T(), "Buy eggs", page.Tasks[0].Title)
+	assert.Equal(s.T(), "Buy bread", page.Tasks[1].Title)
+
+	q.Page = 2
+	page, err = s.repo.List(context.Background(), q)
 	assert.NoError(s.T(), err)
+	assert.Zero(s.T(), page.NextPage)
+	s.Require().Len(page.Tasks, 1)
+	assert.Equal(s.T(), "Buy milk", page.Tasks[0].Title)
+
+	q = domain.TaskQuery{DueAfter: base.Add(24 * time.Hour), DueBefore: base.Add(48 * time.Hour)}
+	s.Require().NoError(q.Normalize())
+	page, err = s.repo.List(context.Background(), q)
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), int64(2), page.Total)
+}
 
-	tasks, err := s.repo.GetAll()
+func (s *TaskRepositoryConformanceSuite) TestEach() {
+	ctx := context.Background()
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i, title := range []string{"Buy milk", "Walk dog", "Buy eggs", "Buy bread"} {
+		task, err := s.repo.Create(ctx, domain.Task{Title: title, Status: "pending", DueDate: base.AddDate(0, 0, i), OwnerID: "owner-1"})
+		s.Require().NoError(err)
+		if title == "Buy bread" {
+			s.Require().NoError(s.repo.Delete(ctx, task.ID))
+		}
+	}
+
+	q := domain.TaskQuery{OwnerID: "owner-1", Search: "buy", SortDesc: true, PageSize: 1}
+	s.Require().NoError(q.Normalize())
+	var titles []string
+	err := s.repo.Each(ctx, q, func(t domain.Task) error {
+		titles = append(titles, t.Title)
+		return nil
+	})
 	assert.NoError(s.T(), err)
-	assert.Len(s.T(), tasks, 2)
+	assert.Equal(s.T(), []string{"Buy eggs", "Buy milk"}, titles)
+
+	stop := errors.New("stop")
+	calls := 0
+	err = s.repo.Each(ctx, q, func(t domain.Task) error {
+		calls++
+		return stop
+	})
+	assert.ErrorIs(s.T(), err, stop)
+	assert.Equal(s.T(), 1, calls)
+}
+
+func (s *TaskRepositoryConformanceSuite) TestEach_ConcurrentWrites() {
+	ctx := context.Background()
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	// More than one batch
+	writes := make([]repositories.TaskWrite, 600)
+	for i := range writes {
+		writes[i] = repositories.TaskWrite{Op: repositories.WriteCreate,
+			Task: domain.Task{Title: fmt.Sprintf("Task %d", i), Status: "pending", DueDate: base.Add(time.Duration(i) * time.Minute)}}
+	}
+	results, err := s.repo.BulkWrite(ctx, writes, false)
+	s.Require().NoError(err)
+	for _, result := range results {
+		s.Require().NoError(result.Err)
+	}
+
+	// each reads every task, calling write once the first one has been read
+	each := func(desc bool, write func(first domain.Task)) map[string]int {
+		q := domain.TaskQuery{SortBy: "due_date", SortDesc: desc}
+		s.Require().NoError(q.Normalize())
+		seen := make(map[string]int)
+		err := s.repo.Each(ctx, q, func(t domain.Task) error {
+			if seen[t.ID]++; len(seen) == 1 {
+				write(t)
+			}
+			return nil
+		})
+		s.Require().NoError(err)
+		return seen
+	}
+
+	// Removing a task that was read must not skip one that was not
+	seen := each(false, func(first domain.Task) { s.Require().NoError(s.repo.Delete(ctx, first.ID)) })
+	for _, result := range results {
+		assert.Equal(s.T(), 1, seen[result.Task.ID], result.Task.Title)
+	}
+	_, err = s.repo.Restore(ctx, results[0].Task.ID)
+	s.Require().NoError(err)
+
+	// Adding a task ahead of the ones read must not repeat one
+	seen = each(true, func(domain.Task) {
+		_, err := s.repo.Create(ctx, domain.Task{Title: "Added", Status: "pending", DueDate: base.AddDate(1, 0, 0)})
+		s.Require().NoError(err)
+	})
+	assert.Len(s.T(), seen, len(results))
+	for _, result := range results {
+		assert.Equal(s.T(), 1, seen[result.Task.ID], result.Task.Title)
+	}
 }
 
-func (s *TaskRepositoryIntegrationSuite) TestUpdateTask() {
+func (s *TaskRepositoryConformanceSuite) TestSearch() {
+	ctx := context.Background()
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	ids := make(map[string]string)
+	for i, task := range []domain.Task{
+		{Title: "Buy milk", Description: "From the corner shop", OwnerID: "owner-1"},
+		{Title: "Groceries", Description: "Remember to buy milk and eggs", OwnerID: "owner-1"},
+		{Title: "Milk the cows", OwnerID: "owner-2"},
+		{Title: "Buy bread", OwnerID: "owner-1"},
+		{Title: "Buying spree", OwnerID: "owner-1"},
+	} {
+		task.Status, task.DueDate = "pending", base.AddDate(0, 0, i)
+		created, err := s.repo.Create(ctx, task)
+		s.Require().NoError(err)
+		ids[task.Title] = created.ID
+	}
+	s.Require().NoError(s.repo.Delete(ctx, ids["Buy bread"]))
+
+	search := func(text, ownerID string, pageSize int) domain.TaskSearchPage {
+		q := domain.TaskSearch{Text: text, OwnerID: ownerID, PageSize: pageSize}
+		s.Require().NoError(q.Normalize())
+		page, err := s.repo.Search(ctx, q)
+		s.Require().NoError(err)
+		return page
+	}
+	titles := func(page domain.TaskSearchPage) []string {
+		titles := []string{}
+		for _, hit := range page.Hits {
+			titles = append(titles, hit.Task.Title)
+		}
+		return titles
+	}
+
+	page := search("milk", "owner-1", 0)
+	assert.Equal(s.T(), []string{"Buy milk", "Groceries"}, titles(page))
+	assert.Equal(s.T(), "Buy <mark>milk</mark>", page.Hits[0].Highlights["title"])
+	assert.Equal(s.T(), "Remember to buy <mark>milk</mark> and eggs", page.Hits[1].Highlights["description"])
+	assert.Equal(s.T(), []string{"Buy milk", "Groceries"}, titles(search(`"buy milk"`, "", 0)))
+	assert.Equal(s.T(), []string{"Milk the cows"}, titles(search("milk cows", "", 0)))
+
+	page = search("buy*", "owner-1", 2)
+	assert.Equal(s.T(), []string{"Buy milk", "Buying spree"}, titles(page))
+	assert.Equal(s.T(), int64(3), page.Total)
+	assert.Equal(s.T(), 2, page.NextPage)
+
+	// Later writes are searchable
+	groceries, err := s.repo.GetByID(ctx, ids["Groceries"])
+	s.Require().NoError(err)
+	groceries.Description = "Eggs only"
+	_, err = s.repo.Update(ctx, groceries.ID, groceries)
+	s.Require().NoError(err)
+	_, err = s.repo.Create(ctx, domain.Task{Title: "Oat milk", Status: "pending", DueDate: base, OwnerID: "owner-1"})
+	s.Require().NoError(err)
+	assert.Equal(s.T(), []string{"Buy milk", "Oat milk"}, titles(search("milk", "owner-1", 0)))
+}
+
+func (s *TaskRepositoryConformanceSuite) TestProjects() {
+	ctx := context.Background()
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	unscoped, err := s.repo.Create(ctx, domain.Task{Title: "Buy milk", Status: "pending", DueDate: base})
+	s.Require().NoError(err)
+	assert.Equal(s.T(), domain.DefaultProjectID, unscoped.ProjectID)
+	scoped, err := s.repo.Create(ctx, domain.Task{Title: "Buy eggs", Status: "pending", DueDate: base, ProjectID: "p1"})
+	s.Require().NoError(err)
+
+	q := domain.TaskQuery{ProjectID: "p1"}
+	s.Require().NoError(q.Normalize())
+	page, err := s.repo.List(ctx, q)
+	s.Require().NoError(err)
+	s.Require().Len(page.Tasks, 1)
+	assert.Equal(s.T(), scoped.ID, page.Tasks[0].ID)
+	assert.Equal(s.T(), "p1", page.Tasks[0].ProjectID)
+
+	q.ProjectID = domain.DefaultProjectID
+	page, err = s.repo.List(ctx, q)
+	s.Require().NoError(err)
+	s.Require().Len(page.Tasks, 1)
+	assert.Equal(s.T(), unscoped.ID, page.Tasks[0].ID)
+
+	search := domain.TaskSearch{Text: "buy", ProjectID: "p1"}
+	s.Require().NoError(search.Normalize())
+	hits, err := s.repo.Search(ctx, search)
+	s.Require().NoError(err)
+	s.Require().Len(hits.Hits, 1)
+	assert.Equal(s.T(), scoped.ID, hits.Hits[0].Task.ID)
+
+	// Updates never move a task to another project
+	scoped.ProjectID = "p2"
+	updated, err := s.repo.Update(ctx, scoped.ID, scoped)
+	s.Require().NoError(err)
+	assert.Equal(s.T(), "p1", updated.ProjectID)
+	got, err := s.repo.GetByID(ctx, scoped.ID)
+	s.Require().NoError(err)
+	assert.Equal(s.T(), "p1", got.ProjectID)
+}
+
+func (s *TaskRepositoryConformanceSuite) TestListTasks_ExcludeStatus() {
+	for _, status := range []string{"pending", "completed", "in_progress"} {
+		_, err := s.repo.Create(context.Background(), domain.Task{Title: status, Status: status})
+		assert.NoError(s.T(), err)
+	}
+
+	q := domain.TaskQuery{ExcludeStatus: "completed"}
+	s.Require().NoError(q.Normalize())
+	page, err := s.repo.List(context.Background(), q)
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), int64(2), page.Total)
+
+	q = domain.TaskQuery{Status: "pending", ExcludeStatus: "completed"}
+	s.Require().NoError(q.Normalize())
+	page, err = s.repo.List(context.Background(), q)
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), int64(1), page.Total)
+}
+
+func (s *TaskRepositoryConformanceSuite) TestUpdateTask() {
 	task := domain.Task{Title: "Original", Status: "pending"}
-	created, err := s.repo.Create(task)
+	created, err := s.repo.Create(context.Background(), task)
 	assert.NoError(s.T(), err)
 
-	updated, err := s.repo.Update(created.ID, domain.Task{
-		Title:  "Updated",
-		Status: "completed",
+	assert.Equal(s.T(), int64(1), created.Version)
+
+	updated, err := s.repo.Update(context.Background(), created.ID, domain.Task{
+		Title:   "Updated",
+		Status:  "completed",
+		Version: created.Version,
 	})
 	assert.NoError(s.T(), err)
 	assert.Equal(s.T(), "Updated", updated.Title)
+	assert.Equal(s.T(), int64(2), updated.Version)
 
-	fetched, err := s.repo.GetByID(created.ID)
+	fetched, err := s.repo.GetByID(context.Background(), created.ID)
 	assert.NoError(s.T(), err)
 	assert.Equal(s.T(), "Updated", fetched.Title)
 	assert.Equal(s.T(), "completed", fetched.Status)
+	assert.Equal(s.T(), int64(2), fetched.Version)
+}
+
+func (s *TaskRepositoryConformanceSuite) TestUpdate_StaleVersion() {
+	created, err := s.repo.Create(context.Background(), domain.Task{Title: "Original", Status: "pending"})
+	assert.NoError(s.T(), err)
+
+	_, err = s.repo.Update(context.Background(), created.ID, domain.Task{Title: "First", Status: "pending", Version: created.Version})
+	assert.NoError(s.T(), err)
+
+	// A second writer that read the same version must not overwrite the first
+	_, err = s.repo.Update(context.Background(), created.ID, domain.Task{Title: "Second", Status: "pending", Version: created.Version})
+	assert.ErrorIs(s.T(), err, repositories.ErrVersionConflict)
+
+	fetched, err := s.repo.GetByID(context.Background(), created.ID)
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), "First", fetched.Title)
 }
 
-func (s *TaskRepositoryIntegrationSuite) TestDeleteTask() {
+func (s *TaskRepositoryConformanceSuite) TestDeleteTask() {
 	task := domain.Task{Title: "To Delete", Status: "pending"}
-	created, err := s.repo.Create(task)
+	created, err := s.repo.Create(context.Background(), task)
 	assert.NoError(s.T(), err)
 
-	err = s.repo.Delete(created.ID)
+	err = s.repo.Delete(context.Background(), created.ID)
 	assert.NoError(s.T(), err)
 
-	_, err = s.repo.GetByID(created.ID)
-	assert.Error(s.T(), err)
+	_, err = s.repo.GetByID(context.Background(), created.ID)
+	assert.ErrorIs(s.T(), err, repositories.ErrNotFound)
+
+	err = s.repo.Delete(context.Background(), created.ID)
+	assert.ErrorIs(s.T(), err, repositories.ErrNotFound)
 }
 
-func (s *TaskRepositoryIntegrationSuite) TestGetByID_NotFound() {
-	_, err := s.repo.GetByID("nonexistent-id")
-	assert.Error(s.T(), err)
+func (s *TaskRepositoryConformanceSuite) TestDelete_MovesToTrash() {
+	ctx := context.Background()
+	parent, err := s.repo.Create(ctx, domain.Task{Title: "Parent", Status: "pending", OwnerID: "u1"})
+	assert.NoError(s.T(), err)
+	child, err := s.repo.Create(ctx, domain.Task{Title: "Child", Status: "pending", OwnerID: "u1", ParentID: parent.ID})
+	assert.NoError(s.T(), err)
+	assert.NoError(s.T(), s.repo.Delete(ctx, child.ID))
+
+	// Hidden from every live read
+	owned, err := s.repo.List(ctx, domain.TaskQuery{OwnerID: "u1", SortBy: "due_date", Page: 1, PageSize: 10})
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), owned.Tasks, 1)
+	children, err := s.repo.GetChildren(ctx, parent.ID)
+	assert.NoError(s.T(), err)
+	assert.Empty(s.T(), children)
+	page, err := s.repo.List(ctx, domain.TaskQuery{SortBy: "due_date", Page: 1, PageSize: 10})
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), int64(1), page.Total)
+	_, err = s.repo.Update(ctx, child.ID, domain.Task{Title: "Changed", Status: "pending", Version: child.Version})
+	assert.ErrorIs(s.T(), err, repositories.ErrNotFound)
+
+	// Visible in the trash
+	trash, err := s.repo.List(ctx, domain.TaskQuery{Deleted: true, SortBy: "deleted_at", SortDesc: true, Page: 1, PageSize: 10})
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), int64(1), trash.Total)
+	assert.Equal(s.T(), child.ID, trash.Tasks[0].ID)
+	assert.NotNil(s.T(), trash.Tasks[0].DeletedAt)
+
+	deleted, err := s.repo.GetDeleted(ctx, child.ID)
+	assert.NoError(s.T(), err)
+	assert.NotNil(s.T(), deleted.DeletedAt)
+	assert.Equal(s.T(), child.Version+1, deleted.Version)
+	_, err = s.repo.GetDeleted(ctx, parent.ID)
+	assert.ErrorIs(s.T(), err, repositories.ErrNotFound)
 }
 
-func TestTaskRepositoryIntegrationSuite(t *testing.T) {
-	if os.Getenv("SKIP_INTEGRATION") == "true" {
-		t.Skip("Skipping integration tests")
-	}
-	suite.Run(t, new(TaskRepositoryIntegrationSuite))
+func (s *TaskRepositoryConformanceSuite) TestRestore() {
+	ctx := context.Background()
+	created, err := s.repo.Create(ctx, domain.Task{Title: "Restore Me", Status: "pending"})
+	assert.NoError(s.T(), err)
+	assert.NoError(s.T(), s.repo.Delete(ctx, created.ID))
+
+	restored, err := s.repo.Restore(ctx, created.ID)
+	assert.NoError(s.T(), err)
+	assert.Nil(s.T(), restored.DeletedAt)
+	assert.Equal(s.T(), "Restore Me", restored.Title)
+	assert.Equal(s.T(), created.Version+2, restored.Version)
+
+	fetched, err := s.repo.GetByID(ctx, created.ID)
+	assert.NoError(s.T(), err)
+	assert.Nil(s.T(), fetched.DeletedAt)
+
+	_, err = s.repo.Restore(ctx, created.ID)
+	assert.ErrorIs(s.T(), err, repositories.ErrNotFound)
+}
+
+func (s *TaskRepositoryConformanceSuite) TestRestore_DetachesFromPurgedParent() {
+	ctx := context.Background()
+	tu := usecases.NewTaskUsecases(s.repo, repositories.NewMemoryUserRepository(), repositories.NewMemoryAuditRepository(), domain.DefaultWorkflow())
+	role, _ := domain.BuiltInRole(domain.RoleAdmin)
+	actor := domain.Actor{UserID: "u1", Role: role.Name, Permissions: role.Permissions}
+
+	due := time.Now().Add(time.Hour)
+	parent, err := tu.CreateTask(ctx, actor, usecases.TaskInput{Title: "Parent", DueDate: due})
+	s.Require().NoError(err)
+	child, err := tu.CreateTask(ctx, actor, usecases.TaskInput{Title: "Child", DueDate: due, ParentID: parent.ID})
+	s.Require().NoError(err)
+	s.Require().NoError(tu.DeleteTask(ctx, actor, child.ID))
+	s.Require().NoError(tu.DeleteTask(ctx, actor, parent.ID))
+	s.Require().NoError(tu.PurgeTask(ctx, actor, parent.ID))
+
+	restored, err := tu.RestoreTask(ctx, actor, child.ID)
+	s.Require().NoError(err)
+	assert.Empty(s.T(), restored.ParentID)
+
+	fetched, err := s.repo.GetByID(ctx, child.ID)
+	s.Require().NoError(err)
+	assert.Empty(s.T(), fetched.ParentID)
+}
+
+func (s *TaskRepositoryConformanceSuite) TestPurge() {
+	ctx := context.Background()
+	created, err := s.repo.Create(ctx, domain.Task{Title: "Purge Me", Status: "pending"})
+	assert.NoError(s.T(), err)
+
+	// Only tasks in the trash can be purged
+	assert.ErrorIs(s.T(), s.repo.Purge(ctx, created.ID), repositories.ErrNotFound)
+
+	assert.NoError(s.T(), s.repo.Delete(ctx, created.ID))
+	assert.NoError(s.T(), s.repo.Purge(ctx, created.ID))
+
+	_, err = s.repo.GetDeleted(ctx, created.ID)
+	assert.ErrorIs(s.T(), err, repositories.ErrNotFound)
+	_, err = s.repo.Restore(ctx, created.ID)
+	assert.ErrorIs(s.T(), err, repositories.ErrNotFound)
+}
+
+func (s *TaskRepositoryConformanceSuite) TestPurgeDeletedBefore() {
+	ctx := context.Background()
+	live, err := s.repo.Create(ctx, domain.Task{Title: "Live", Status: "pending"})
+	assert.NoError(s.T(), err)
+	old, err := s.repo.Create(ctx, domain.Task{Title: "Old", Status: "pending"})
+	assert.NoError(s.T(), err)
+	assert.NoError(s.T(), s.repo.Delete(ctx, old.ID))
+	cutoff := time.Now().Add(time.Second)
+
+	n, err := s.repo.PurgeDeletedBefore(ctx, cutoff)
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), int64(1), n)
+
+	_, err = s.repo.GetDeleted(ctx, old.ID)
+	assert.ErrorIs(s.T(), err, repositories.ErrNotFound)
+	_, err = s.repo.GetByID(ctx, live.ID)
+	assert.NoError(s.T(), err)
+
+	// Recently deleted tasks are kept
+	assert.NoError(s.T(), s.repo.Delete(ctx, live.ID))
+	n, err = s.repo.PurgeDeletedBefore(ctx, time.Now().Add(-time.Hour))
+	assert.NoError(s.T(), err)
+	assert.Zero(s.T(), n)
+}
+
+func (s *TaskRepositoryConformanceSuite) TestGetByID_NotFound() {
+	_, err := s.repo.GetByID(context.Background(), "nonexistent-id")
+	assert.ErrorIs(s.T(), err, repositories.ErrNotFound)
+}
+
+func (s *TaskRepositoryConformanceSuite) TestUpdate_NotFound() {
+	_, err := s.repo.Update(context.Background(), "nonexistent-id", domain.Task{Title: "Missing", Status: "pending"})
+	assert.ErrorIs(s.T(), err, repositories.ErrNotFound)
+}
+
+func TestTaskRepositoryConformance(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, b backend) {
+		suite.Run(t, &TaskRepositoryConformanceSuite{open: b.tasks})
+	})
 }
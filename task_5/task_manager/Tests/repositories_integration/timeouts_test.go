@@ -0,0 +1,43 @@
+package repositories_integration_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	domain "task_manager/Domain"
+	repositories "task_manager/Repositories"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTimeouts(t *testing.T) {
+	timeouts, err := repositories.ParseTimeouts(3*time.Second, "task.list=10s, user.create=2s")
+	require.NoError(t, err)
+
+	assert.Equal(t, 10*time.Second, timeouts.For("task.list"))
+	assert.Equal(t, 2*time.Second, timeouts.For("user.create"))
+	assert.Equal(t, 3*time.Second, timeouts.For("task.get"))
+}
+
+func TestParseTimeouts_Invalid(t *testing.T) {
+	for _, overrides := range []string{"task.list", "task.list=soon", "task.list=-1s"} {
+		_, err := repositories.ParseTimeouts(time.Second, overrides)
+		assert.Error(t, err, overrides)
+	}
+}
+
+func TestTimeouts_DefaultWhenUnset(t *testing.T) {
+	assert.Equal(t, repositories.DefaultOperationTimeout, repositories.Timeouts{}.For("task.list"))
+}
+
+func TestSQLiteTaskRepository_CanceledContext(t *testing.T) {
+	repo := repositories.NewSQLiteTaskRepository(openSQLite(t), repositories.DefaultTimeouts())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := repo.List(ctx, domain.TaskQuery{SortBy: "due_date", Page: 1, PageSize: 10})
+	assert.ErrorIs(t, err, context.Canceled)
+}
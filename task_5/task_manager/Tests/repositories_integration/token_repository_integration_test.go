@@ -0,0 +1,86 @@
+package repositories_integration_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	domain "task_manager/Domain"
+	repositories "task_manager/Repositories"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+// TokenStoreConformanceSuite is the contract every ITokenStore must satisfy.
+type TokenStoreConformanceSuite struct {
+	suite.Suite
+	open  func(t *testing.T) repositories.ITokenStore
+	store repositories.ITokenStore
+}
+
+func (s *TokenStoreConformanceSuite) SetupTest() {
+	s.store = s.open(s.T())
+}
+
+func (s *TokenStoreConformanceSuite) refreshToken(hash, family string) domain.RefreshToken {
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	return domain.RefreshToken{
+		Hash:      hash,
+		FamilyID:  family,
+		UserID:    "user-1",
+		Username:  "u1",
+		ExpiresAt: now.Add(time.Hour),
+		CreatedAt: now,
+	}
+}
+
+func (s *TokenStoreConformanceSuite) TestUseRefreshTokenOnce() {
+	rt := s.refreshToken("h1", "f1")
+	assert.NoError(s.T(), s.store.SaveRefreshToken(context.Background(), rt))
+
+	used, err := s.store.UseRefreshToken(context.Background(), "h1")
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), "f1", used.FamilyID)
+	assert.Equal(s.T(), "u1", used.Username)
+	assert.True(s.T(), used.Used)
+	assert.WithinDuration(s.T(), rt.ExpiresAt, used.ExpiresAt, time.Millisecond)
+
+	used, err = s.store.UseRefreshToken(context.Background(), "h1")
+	assert.ErrorIs(s.T(), err, repositories.ErrRefreshTokenReused)
+	assert.Equal(s.T(), "f1", used.FamilyID)
+
+	_, err = s.store.UseRefreshToken(context.Background(), "missing")
+	assert.ErrorIs(s.T(), err, repositories.ErrRefreshTokenNotFound)
+}
+
+func (s *TokenStoreConformanceSuite) TestRevokeFamily() {
+	assert.NoError(s.T(), s.store.SaveRefreshToken(context.Background(), s.refreshToken("h1", "f1")))
+	assert.NoError(s.T(), s.store.SaveRefreshToken(context.Background(), s.refreshToken("h2", "f1")))
+	assert.NoError(s.T(), s.store.SaveRefreshToken(context.Background(), s.refreshToken("h3", "f2")))
+
+	assert.NoError(s.T(), s.store.RevokeFamily(context.Background(), "f1"))
+
+	_, err := s.store.UseRefreshToken(context.Background(), "h2")
+	assert.ErrorIs(s.T(), err, repositories.ErrRefreshTokenRevoked)
+	_, err = s.store.UseRefreshToken(context.Background(), "h3")
+	assert.NoError(s.T(), err)
+}
+
+func (s *TokenStoreConformanceSuite) TestRevokeAccessToken() {
+	revoked, err := s.store.IsAccessTokenRevoked(context.Background(), "jti-1")
+	assert.NoError(s.T(), err)
+	assert.False(s.T(), revoked)
+
+	assert.NoError(s.T(), s.store.RevokeAccessToken(context.Background(), "jti-1", time.Now().Add(time.Minute)))
+
+	revoked, err = s.store.IsAccessTokenRevoked(context.Background(), "jti-1")
+	assert.NoError(s.T(), err)
+	assert.True(s.T(), revoked)
+}
+
+func TestTokenStoreConformance(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, b backend) {
+		suite.Run(t, &TokenStoreConformanceSuite{open: b.tokens})
+	})
+}
@@ -2,131 +2,145 @@ package repositories_integration_test
 
 import (
 	"context"
-	"os"
 	"testing"
-	"time"
 
+	domain "task_manager/Domain"
 	repositories "task_manager/Repositories"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
-	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
-	"go.mongodb.org/mongo-driver/mongo"
-	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
-type UserRepositoryIntegrationSuite struct {
+// UserRepositoryConformanceSuite is the contract every IUserRepository must satisfy.
+type UserRepositoryConformanceSuite struct {
 	suite.Suite
-	repo       repositories.IUserRepository
-	client     *mongo.Client
-	collection *mongo.Collection
+	open func(t *testing.T) repositories.IUserRepository
+	repo repositories.IUserRepository
 }
 
-func (s *UserRepositoryIntegrationSuite) SetupSuite() {
-	mongoURI := os.Getenv("MONGODB_URI")
-	if mongoURI == "" {
-		mongoURI = "mongodb://localhost:27017"
-	}
-
-	repo, err := repositories.NewMongoUserRepository(mongoURI, "taskmanager_test", "users_test")
-	s.Require().NoError(err)
-	s.repo = repo
-
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-	client, err := mongo.Connect(ctx, options.Client().ApplyURI(mongoURI))
-	s.Require().NoError(err)
-	s.client = client
-	s.collection = client.Database("taskmanager_test").Collection("users_test")
+func (s *UserRepositoryConformanceSuite) SetupTest() {
+	s.repo = s.open(s.T())
 }
 
-func (s *UserRepositoryIntegrationSuite) TearDownSuite() {
-	if s.repo != nil {
-		s.repo.Close()
-	}
-	if s.client != nil {
-		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-		defer cancel()
-		s.client.Disconnect(ctx)
-	}
-}
-
-func (s *UserRepositoryIntegrationSuite) SetupTest() {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-	s.collection.DeleteMany(ctx, bson.M{})
-}
-
-func (s *UserRepositoryIntegrationSuite) TestCreateUser_FirstUserIsAdmin() {
-	user, err := s.repo.CreateUser("firstuser", "password123")
+func (s *UserRepositoryConformanceSuite) TestCreateUser_FirstUserIsAdmin() {
+	user, err := s.repo.CreateUser(context.Background(), "firstuser", "password123")
 	assert.NoError(s.T(), err)
 	assert.NotEqual(s.T(), primitive.NilObjectID, user.ID)
 	assert.Equal(s.T(), "firstuser", user.Username)
 	assert.Equal(s.T(), "admin", user.Role)
 }
 
-func (s *UserRepositoryIntegrationSuite) TestCreateUser_SubsequentUserIsRegular() {
-	_, err := s.repo.CreateUser("admin", "password123")
+func (s *UserRepositoryConformanceSuite) TestCreateUser_SubsequentUserIsRegular() {
+	_, err := s.repo.CreateUser(context.Background(), "admin", "password123")
 	assert.NoError(s.T(), err)
 
-	user, err := s.repo.CreateUser("regularuser", "password123")
+	user, err := s.repo.CreateUser(context.Background(), "regularuser", "password123")
 	assert.NoError(s.T(), err)
 	assert.Equal(s.T(), "user", user.Role)
 }
 
-func (s *UserRepositoryIntegrationSuite) TestCreateUser_DuplicateUsername() {
-	_, err := s.repo.CreateUser("duplicate", "password123")
+func (s *UserRepositoryConformanceSuite) TestCreateUser_DuplicateUsername() {
+	_, err := s.repo.CreateUser(context.Background(), "duplicate", "password123")
 	assert.NoError(s.T(), err)
 
-	_, err = s.repo.CreateUser("duplicate", "password456")
-	assert.Error(s.T(), err)
+	_, err = s.repo.CreateUser(context.Background(), "duplicate", "password456")
+	assert.ErrorIs(s.T(), err, repositories.ErrUsernameTaken)
 }
 
-func (s *UserRepositoryIntegrationSuite) TestGetByUsername() {
-	created, err := s.repo.CreateUser("findme", "password123")
+func (s *UserRepositoryConformanceSuite) TestSetRole_InvalidID() {
+	err := s.repo.SetRole(context.Background(), "not-an-id", domain.RoleAdmin)
+	assert.ErrorIs(s.T(), err, repositories.ErrUserNotFound)
+}
+
+func (s *UserRepositoryConformanceSuite) TestGetByUsername() {
+	created, err := s.repo.CreateUser(context.Background(), "findme", "password123")
 	assert.NoError(s.T(), err)
 
-	found, err := s.repo.GetByUsername("findme")
+	found, err := s.repo.GetByUsername(context.Background(), "findme")
 	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), created.ID, found.ID)
 	assert.Equal(s.T(), created.Username, found.Username)
+	assert.Equal(s.T(), created.Role, found.Role)
+}
+
+func (s *UserRepositoryConformanceSuite) TestGetByID() {
+	created, err := s.repo.CreateUser(context.Background(), "byid", "password123")
+	assert.NoError(s.T(), err)
+
+	found, err := s.repo.GetByID(context.Background(), created.ID.Hex())
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), "byid", found.Username)
+	assert.Empty(s.T(), found.PasswordHash)
+
+	_, err = s.repo.GetByID(context.Background(), "not-an-id")
+	assert.ErrorIs(s.T(), err, repositories.ErrUserNotFound)
 }
 
-func (s *UserRepositoryIntegrationSuite) TestGetByUsername_NotFound() {
-	_, err := s.repo.GetByUsername("nonexistent")
-	assert.Error(s.T(), err)
+func (s *UserRepositoryConformanceSuite) TestGetByUsername_NotFound() {
+	_, err := s.repo.GetByUsername(context.Background(), "nonexistent")
+	assert.ErrorIs(s.T(), err, repositories.ErrUserNotFound)
 }
 
-func (s *UserRepositoryIntegrationSuite) TestVerifyPassword() {
-	_, err := s.repo.CreateUser("verifyuser", "correctpassword")
+func (s *UserRepositoryConformanceSuite) TestVerifyPassword() {
+	_, err := s.repo.CreateUser(context.Background(), "verifyuser", "correctpassword")
 	assert.NoError(s.T(), err)
 
-	user, err := s.repo.GetByUsername("verifyuser")
+	user, err := s.repo.GetByUsername(context.Background(), "verifyuser")
 	assert.NoError(s.T(), err)
 
 	assert.True(s.T(), s.repo.VerifyPassword(user, "correctpassword"))
 	assert.False(s.T(), s.repo.VerifyPassword(user, "wrongpassword"))
 }
 
-func (s *UserRepositoryIntegrationSuite) TestPromoteUser() {
-	_, err := s.repo.CreateUser("admin", "password123")
+func (s *UserRepositoryConformanceSuite) TestPromoteUser() {
+	_, err := s.repo.CreateUser(context.Background(), "admin", "password123")
 	assert.NoError(s.T(), err)
 
-	user, err := s.repo.CreateUser("topromote", "password123")
+	user, err := s.repo.CreateUser(context.Background(), "topromote", "password123")
 	assert.NoError(s.T(), err)
 	assert.Equal(s.T(), "user", user.Role)
 
-	err = s.repo.PromoteUser(user.ID.Hex())
+	err = s.repo.PromoteUser(context.Background(), user.ID.Hex())
 	assert.NoError(s.T(), err)
 
-	promoted, err := s.repo.GetByUsername("topromote")
+	promoted, err := s.repo.GetByUsername(context.Background(), "topromote")
 	assert.NoError(s.T(), err)
 	assert.Equal(s.T(), "admin", promoted.Role)
 }
 
-func TestUserRepositoryIntegrationSuite(t *testing.T) {
-	if os.Getenv("SKIP_INTEGRATION") == "true" {
-		t.Skip("Skipping integration tests")
-	}
-	suite.Run(t, new(UserRepositoryIntegrationSuite))
+func (s *UserRepositoryConformanceSuite) TestSetRole() {
+	_, err := s.repo.CreateUser(context.Background(), "admin", "password123")
+	assert.NoError(s.T(), err)
+	user, err := s.repo.CreateUser(context.Background(), "reviewer", "password123")
+	assert.NoError(s.T(), err)
+
+	err = s.repo.SetRole(context.Background(), user.ID.Hex(), "reviewer")
+	assert.NoError(s.T(), err)
+
+	found, err := s.repo.GetByUsername(context.Background(), "reviewer")
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), "reviewer", found.Role)
+
+	err = s.repo.SetRole(context.Background(), primitive.NewObjectID().Hex(), "user")
+	assert.ErrorIs(s.T(), err, repositories.ErrUserNotFound)
+}
+
+func (s *UserRepositoryConformanceSuite) TestIsEmpty() {
+	empty, err := s.repo.IsEmpty(context.Background())
+	assert.NoError(s.T(), err)
+	assert.True(s.T(), empty)
+
+	_, err = s.repo.CreateUser(context.Background(), "someone", "password123")
+	assert.NoError(s.T(), err)
+
+	empty, err = s.repo.IsEmpty(context.Background())
+	assert.NoError(s.T(), err)
+	assert.False(s.T(), empty)
+}
+
+func TestUserRepositoryConformance(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, b backend) {
+		suite.Run(t, &UserRepositoryConformanceSuite{open: b.users})
+	})
 }
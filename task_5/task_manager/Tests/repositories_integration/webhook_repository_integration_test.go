@@ -0,0 +1,159 @@
+package repositories_integration_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	domain "task_manager/Domain"
+	repositories "task_manager/Repositories"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+// WebhookRepositoryConformanceSuite is the contract every IWebhookRepository must satisfy.
+type WebhookRepositoryConformanceSuite struct {
+	suite.Suite
+	open func(t *testing.T) repositories.IWebhookRepository
+	repo repositories.IWebhookRepository
+}
+
+func (s *WebhookRepositoryConformanceSuite) SetupTest() {
+	s.repo = s.open(s.T())
+}
+
+func (s *WebhookRepositoryConformanceSuite) createWebhook(url string) domain.Webhook {
+	hook, err := s.repo.Create(context.Background(), domain.Webhook{
+		URL:       url,
+		Events:    []string{domain.EventTaskCreated, domain.EventTaskDeleted},
+		Secret:    "secret",
+		CreatedBy: "admin",
+		CreatedAt: time.Now().UTC().Truncate(time.Millisecond),
+	})
+	s.Require().NoError(err)
+	return hook
+}
+
+func (s *WebhookRepositoryConformanceSuite) createDelivery(webhookID string, eventID int64, status string, at time.Time) domain.WebhookDelivery {
+	d, err := s.repo.CreateDelivery(context.Background(), domain.WebhookDelivery{
+		WebhookID:     webhookID,
+		EventID:       eventID,
+		EventType:     domain.EventTaskCreated,
+		Payload:       json.RawMessage(`{"id":1}`),
+		Status:        status,
+		NextAttemptAt: at,
+		CreatedAt:     at,
+		UpdatedAt:     at,
+	})
+	s.Require().NoError(err)
+	return d
+}
+
+func (s *WebhookRepositoryConformanceSuite) TestCreateGetListDelete() {
+	ctx := context.Background()
+	hook := s.createWebhook("https://example.com/hook")
+	s.createWebhook("https://example.com/other")
+	assert.NotEmpty(s.T(), hook.ID)
+
+	got, err := s.repo.GetByID(ctx, hook.ID)
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), "https://example.com/hook", got.URL)
+	assert.Equal(s.T(), []string{domain.EventTaskCreated, domain.EventTaskDeleted}, got.Events)
+	assert.Equal(s.T(), "secret", got.Secret)
+	assert.Equal(s.T(), "admin", got.CreatedBy)
+	assert.WithinDuration(s.T(), hook.CreatedAt, got.CreatedAt, time.Millisecond)
+
+	webhooks, err := s.repo.List(ctx)
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), webhooks, 2)
+	assert.Equal(s.T(), hook.ID, webhooks[0].ID)
+
+	delivery := s.createDelivery(hook.ID, 1, domain.DeliveryPending, time.Now().UTC())
+	assert.NoError(s.T(), s.repo.Delete(ctx, hook.ID))
+	_, err = s.repo.GetByID(ctx, hook.ID)
+	assert.ErrorIs(s.T(), err, repositories.ErrWebhookNotFound)
+	_, err = s.repo.GetDelivery(ctx, delivery.ID)
+	assert.ErrorIs(s.T(), err, repositories.ErrDeliveryNotFound)
+	assert.ErrorIs(s.T(), s.repo.Delete(ctx, hook.ID), repositories.ErrWebhookNotFound)
+}
+
+func (s *WebhookRepositoryConformanceSuite) TestUpdateDelivery() {
+	ctx := context.Background()
+	hook := s.createWebhook("https://example.com/hook")
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	d := s.createDelivery(hook.ID, 7, domain.DeliveryPending, now)
+
+	d.Status = domain.DeliveryFailed
+	d.Attempts = 3
+	d.ResponseStatus = 503
+	d.LastError = "endpoint responded with 503"
+	d.UpdatedAt = now.Add(time.Minute)
+	assert.NoError(s.T(), s.repo.UpdateDelivery(ctx, d))
+
+	got, err := s.repo.GetDelivery(ctx, d.ID)
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), hook.ID, got.WebhookID)
+	assert.Equal(s.T(), int64(7), got.EventID)
+	assert.Equal(s.T(), domain.EventTaskCreated, got.EventType)
+	assert.JSONEq(s.T(), `{"id":1}`, string(got.Payload))
+	assert.Equal(s.T(), domain.DeliveryFailed, got.Status)
+	assert.Equal(s.T(), 3, got.Attempts)
+	assert.Equal(s.T(), 503, got.ResponseStatus)
+	assert.Equal(s.T(), "endpoint responded with 503", got.LastError)
+	assert.WithinDuration(s.T(), now.Add(time.Minute), got.UpdatedAt, time.Millisecond)
+
+	d.ID = "000000000000000000000000"
+	assert.ErrorIs(s.T(), s.repo.UpdateDelivery(ctx, d), repositories.ErrDeliveryNotFound)
+}
+
+func (s *WebhookRepositoryConformanceSuite) TestListDeliveries() {
+	ctx := context.Background()
+	hook := s.createWebhook("https://example.com/hook")
+	other := s.createWebhook("https://example.com/other")
+	start := time.Now().UTC().Truncate(time.Millisecond)
+	s.createDelivery(hook.ID, 1, domain.DeliverySucceeded, start)
+	s.createDelivery(hook.ID, 2, domain.DeliveryFailed, start.Add(time.Second))
+	s.createDelivery(hook.ID, 3, domain.DeliveryPending, start.Add(2*time.Second))
+	s.createDelivery(other.ID, 4, domain.DeliveryPending, start)
+
+	page, err := s.repo.ListDeliveries(ctx, domain.DeliveryQuery{WebhookID: hook.ID, Page: 1, PageSize: 2})
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), int64(3), page.Total)
+	assert.Len(s.T(), page.Deliveries, 2)
+	assert.Equal(s.T(), int64(3), page.Deliveries[0].EventID)
+	assert.Equal(s.T(), 2, page.NextPage)
+
+	page, err = s.repo.ListDeliveries(ctx, domain.DeliveryQuery{WebhookID: hook.ID, Status: domain.DeliveryFailed, Page: 1, PageSize: 10})
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), int64(1), page.Total)
+	assert.Equal(s.T(), int64(2), page.Deliveries[0].EventID)
+}
+
+func (s *WebhookRepositoryConformanceSuite) TestDueDeliveries() {
+	ctx := context.Background()
+	hook := s.createWebhook("https://example.com/hook")
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	s.createDelivery(hook.ID, 1, domain.DeliveryPending, now.Add(-time.Second))
+	s.createDelivery(hook.ID, 2, domain.DeliveryPending, now.Add(-time.Minute))
+	s.createDelivery(hook.ID, 3, domain.DeliveryPending, now.Add(time.Minute))
+	s.createDelivery(hook.ID, 4, domain.DeliverySucceeded, now.Add(-time.Hour))
+
+	due, err := s.repo.DueDeliveries(ctx, now, 10)
+	assert.NoError(s.T(), err)
+	if assert.Len(s.T(), due, 2) {
+		assert.Equal(s.T(), int64(2), due[0].EventID)
+		assert.Equal(s.T(), int64(1), due[1].EventID)
+	}
+
+	due, err = s.repo.DueDeliveries(ctx, now, 1)
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), due, 1)
+}
+
+func TestWebhookRepositoryConformance(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, b backend) {
+		suite.Run(t, &WebhookRepositoryConformanceSuite{open: b.webhooks})
+	})
+}
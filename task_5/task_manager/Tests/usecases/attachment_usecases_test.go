@@ -0,0 +1,186 @@
+package usecases_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	domain "task_manager/Domain"
+	repositories "task_manager/Repositories"
+	usecases "task_manager/Usecases"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// pngHeader is enough of a PNG file for its type to be detected.
+var pngHeader = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+type attachmentFixture struct {
+	au       *usecases.AttachmentUsecases
+	tu       *usecases.TaskUsecases
+	taskRepo repositories.ITaskRepository
+	repo     repositories.IAttachmentRepository
+	blobs    repositories.IBlobStore
+	task     domain.Task
+}
+
+func newAttachmentFixture(t *testing.T, config usecases.AttachmentConfig) attachmentFixture {
+	f := attachmentFixture{
+		taskRepo: repositories.NewMemoryTaskRepository(),
+		repo:     repositories.NewMemoryAttachmentRepository(),
+		blobs:    repositories.NewMemoryBlobStore(),
+	}
+	f.au = usecases.NewAttachmentUsecases(f.repo, f.blobs, f.taskRepo, config)
+	f.tu = usecases.NewTaskUsecases(f.taskRepo, nil, repositories.NewMemoryAuditRepository(), domain.DefaultWorkflow())
+	f.tu.AddPurgeListener(f.au)
+	var err error
+	f.task, err = f.taskRepo.Create(context.Background(), domain.Task{Title: "Design", Status: "pending", DueDate: time.Now().Add(time.Hour), OwnerID: owner.UserID})
+	require.NoError(t, err)
+	return f
+}
+
+func (f attachmentFixture) upload(filename string, content []byte, checksum string) (domain.Attachment, error) {
+	return f.au.UploadAttachment(context.Background(), owner, f.task.ID, usecases.AttachmentUpload{
+		Filename: filename,
+		Content:  bytes.NewReader(content),
+		SHA256:   checksum,
+	})
+}
+
+func errorCode(t *testing.T, err error) domain.ErrorCode {
+	var domainErr *domain.Error
+	require.ErrorAs(t, err, &domainErr)
+	return domainErr.Code
+}
+
+func TestUploadAttachment(t *testing.T) {
+	f := newAttachmentFixture(t, usecases.DefaultAttachmentConfig())
+	content := append(pngHeader, bytes.Repeat([]byte{0}, 1000)...)
+	sum := sha256.Sum256(content)
+
+	attachment, err := f.upload(`C:\Users\me\screen"shot.png`, content, hex.EncodeToString(sum[:]))
+	require.NoError(t, err)
+	assert.Equal(t, "screenshot.png", attachment.Filename)
+	assert.Equal(t, "image/png", attachment.ContentType)
+	assert.Equal(t, int64(len(content)), attachment.Size)
+	assert.Equal(t, hex.EncodeToString(sum[:]), attachment.SHA256)
+	assert.Equal(t, owner.UserID, attachment.UploaderID)
+
+	got, r, err := f.au.OpenAttachment(context.Background(), owner, f.task.ID, attachment.ID)
+	require.NoError(t, err)
+	defer r.Close()
+	downloaded, err := io.ReadAll(r)
+	require.NoError(t, err)
+	assert.Equal(t, content, downloaded)
+	assert.Equal(t, attachment, got)
+
+	list, err := f.au.ListAttachments(context.Background(), owner, f.task.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []domain.Attachment{attachment}, list)
+}
+
+func TestUploadAttachment_DetectsType(t *testing.T) {
+	f := newAttachmentFixture(t, usecases.DefaultAttachmentConfig())
+
+	// The extension only refines types that look alike
+	csv, err := f.upload("data.csv", []byte("a,b\n1,2\n"), "")
+	require.NoError(t, err)
+	assert.Equal(t, "text/csv; charset=utf-8", csv.ContentType)
+
+	_, err = f.upload("page.png", []byte("<html><script>alert(1)</script></html>"), "")
+	assert.Equal(t, domain.CodeUnsupportedMedia, errorCode(t, err))
+
+	_, err = f.upload("program.exe", []byte("MZ\x90\x00\x03\x00\x00\x00"), "")
+	assert.Equal(t, domain.CodeUnsupportedMedia, errorCode(t, err))
+}
+
+func TestUploadAttachment_Limits(t *testing.T) {
+	config := usecases.DefaultAttachmentConfig()
+	config.MaxSize = 1024
+	f := newAttachmentFixture(t, config)
+
+	_, err := f.upload("big.txt", []byte(strings.Repeat("x", 1025)), "")
+	assert.Equal(t, domain.CodeTooLarge, errorCode(t, err))
+
+	_, err = f.upload("empty.txt", nil, "")
+	assert.Equal(t, domain.CodeValidation, errorCode(t, err))
+
+	_, err = f.upload("notes.txt", []byte("hello"), "not-a-checksum")
+	assert.Equal(t, domain.CodeValidation, errorCode(t, err))
+
+	sum := sha256.Sum256([]byte("something else"))
+	_, err = f.upload("notes.txt", []byte("hello"), hex.EncodeToString(sum[:]))
+	assert.Equal(t, domain.CodeValidation, errorCode(t, err))
+
+	// Rejected uploads leave nothing behind
+	list, err := f.au.ListAttachments(context.Background(), owner, f.task.ID)
+	require.NoError(t, err)
+	assert.Empty(t, list)
+}
+
+func TestAttachments_FollowTaskVisibility(t *testing.T) {
+	f := newAttachmentFixture(t, usecases.DefaultAttachmentConfig())
+	attachment, err := f.upload("notes.txt", []byte("hello"), "")
+	require.NoError(t, err)
+
+	_, _, err = f.au.OpenAttachment(context.Background(), other, f.task.ID, attachment.ID)
+	assert.ErrorIs(t, err, repositories.ErrNotFound)
+	_, err = f.au.UploadAttachment(context.Background(), other, f.task.ID, usecases.AttachmentUpload{Filename: "x.txt", Content: strings.NewReader("x")})
+	assert.ErrorIs(t, err, repositories.ErrNotFound)
+	assert.ErrorIs(t, f.au.DeleteAttachment(context.Background(), other, f.task.ID, attachment.ID), repositories.ErrNotFound)
+
+	// Attachments are only reachable through their own task
+	elsewhere, err := f.taskRepo.Create(context.Background(), domain.Task{Title: "Elsewhere", OwnerID: owner.UserID})
+	require.NoError(t, err)
+	_, _, err = f.au.OpenAttachment(context.Background(), owner, elsewhere.ID, attachment.ID)
+	assert.ErrorIs(t, err, repositories.ErrAttachmentNotFound)
+
+	_, r, err := f.au.OpenAttachment(context.Background(), admin, f.task.ID, attachment.ID)
+	require.NoError(t, err)
+	r.Close()
+
+	require.NoError(t, f.au.DeleteAttachment(context.Background(), owner, f.task.ID, attachment.ID))
+	_, err = f.blobs.Open(context.Background(), attachment.BlobKey)
+	assert.ErrorIs(t, err, repositories.ErrBlobNotFound)
+}
+
+func TestAttachments_RemovedWithPurgedTasks(t *testing.T) {
+	f := newAttachmentFixture(t, usecases.DefaultAttachmentConfig())
+	attachment, err := f.upload("notes.txt", []byte("hello"), "")
+	require.NoError(t, err)
+
+	// Deleted tasks keep their attachments so that they can be restored
+	require.NoError(t, f.tu.DeleteTask(context.Background(), owner, f.task.ID))
+	_, err = f.repo.GetByID(context.Background(), attachment.ID)
+	require.NoError(t, err)
+
+	require.NoError(t, f.tu.PurgeTask(context.Background(), owner, f.task.ID))
+	_, err = f.repo.GetByID(context.Background(), attachment.ID)
+	assert.ErrorIs(t, err, repositories.ErrAttachmentNotFound)
+	_, err = f.blobs.Open(context.Background(), attachment.BlobKey)
+	assert.ErrorIs(t, err, repositories.ErrBlobNotFound)
+
+	// Tasks purged by the trash sweeper are cleaned up afterwards
+	expired, err := f.taskRepo.Create(context.Background(), domain.Task{Title: "Old", Status: "pending", OwnerID: owner.UserID})
+	require.NoError(t, err)
+	kept, err := f.taskRepo.Create(context.Background(), domain.Task{Title: "Kept", Status: "pending", OwnerID: owner.UserID})
+	require.NoError(t, err)
+	for _, task := range []domain.Task{expired, kept} {
+		_, err := f.au.UploadAttachment(context.Background(), owner, task.ID, usecases.AttachmentUpload{Filename: "a.txt", Content: strings.NewReader("a")})
+		require.NoError(t, err)
+	}
+	require.NoError(t, f.tu.DeleteTask(context.Background(), owner, expired.ID))
+	n, err := f.tu.PurgeExpired(context.Background(), -time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), n)
+
+	ids, err := f.repo.TaskIDs(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{kept.ID}, ids)
+}
@@ -0,0 +1,96 @@
+package usecases_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	domain "task_manager/Domain"
+	repositories "task_manager/Repositories"
+	usecases "task_manager/Usecases"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newCommentUsecases(t *testing.T) (*usecases.CommentUsecases, repositories.ITaskRepository, domain.Task, domain.User) {
+	taskRepo := repositories.NewMemoryTaskRepository()
+	userRepo := repositories.NewMemoryUserRepository()
+	task, err := taskRepo.Create(context.Background(), domain.Task{Title: "Plan", Status: "pending", DueDate: time.Now().Add(time.Hour), OwnerID: owner.UserID})
+	require.NoError(t, err)
+	bob, err := userRepo.CreateUser(context.Background(), "bob", "password123")
+	require.NoError(t, err)
+	return usecases.NewCommentUsecases(repositories.NewMemoryCommentRepository(), taskRepo, userRepo), taskRepo, task, bob
+}
+
+func TestParseMentions(t *testing.T) {
+	assert.Equal(t, []string{"bob", "alice.smith", "ünal"},
+		domain.ParseMentions("@bob, can you ask @alice.smith? (cc @ünal.) Thanks @bob. Mail me at me@example.com"))
+	assert.Empty(t, domain.ParseMentions("no mentions @ all"))
+}
+
+func TestAddComment_ResolvesMentions(t *testing.T) {
+	cu, _, task, bob := newCommentUsecases(t)
+
+	comment, err := cu.AddComment(context.Background(), owner, task.ID, "  Over to you @bob, and @nobody  ")
+	require.NoError(t, err)
+	assert.Equal(t, "Over to you @bob, and @nobody", comment.Body)
+	assert.Equal(t, owner.UserID, comment.AuthorID)
+	assert.Equal(t, []domain.Mention{{UserID: bob.ID.Hex(), Username: "bob"}}, comment.Mentions)
+
+	page, err := cu.ListComments(context.Background(), owner, domain.CommentQuery{TaskID: task.ID})
+	require.NoError(t, err)
+	require.Len(t, page.Comments, 1)
+	assert.Equal(t, comment.ID, page.Comments[0].ID)
+}
+
+func TestAddComment_Validation(t *testing.T) {
+	cu, _, task, _ := newCommentUsecases(t)
+
+	for _, body := range []string{"   ", strings.Repeat("x", domain.MaxCommentLength+1)} {
+		_, err := cu.AddComment(context.Background(), owner, task.ID, body)
+		var domainErr *domain.Error
+		require.ErrorAs(t, err, &domainErr)
+		assert.Equal(t, "body", domainErr.Fields[0].Field)
+	}
+
+	// Tasks the actor cannot access are not found
+	_, err := cu.AddComment(context.Background(), other, task.ID, "Hello")
+	assert.ErrorIs(t, err, repositories.ErrNotFound)
+	_, err = cu.ListComments(context.Background(), other, domain.CommentQuery{TaskID: task.ID})
+	assert.ErrorIs(t, err, repositories.ErrNotFound)
+}
+
+func TestEditAndDeleteComment_OnlyAuthorOrManager(t *testing.T) {
+	cu, taskRepo, task, bob := newCommentUsecases(t)
+	comment, err := cu.AddComment(context.Background(), admin, task.ID, "Written by an admin")
+	require.NoError(t, err)
+
+	_, err = cu.EditComment(context.Background(), owner, task.ID, comment.ID, "Hijacked")
+	var domainErr *domain.Error
+	require.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, domain.CodeForbidden, domainErr.Code)
+	require.ErrorAs(t, cu.DeleteComment(context.Background(), owner, task.ID, comment.ID), &domainErr)
+	assert.Equal(t, domain.CodeForbidden, domainErr.Code)
+
+	mine, err := cu.AddComment(context.Background(), owner, task.ID, "Mine")
+	require.NoError(t, err)
+	edited, err := cu.EditComment(context.Background(), owner, task.ID, mine.ID, "Mine, for @bob")
+	require.NoError(t, err)
+	assert.Equal(t, "Mine, for @bob", edited.Body)
+	assert.Equal(t, []domain.Mention{{UserID: bob.ID.Hex(), Username: "bob"}}, edited.Mentions)
+	assert.NotNil(t, edited.EditedAt)
+
+	// The comment has to belong to the task in the path
+	elsewhere, err := taskRepo.Create(context.Background(), domain.Task{Title: "Elsewhere", OwnerID: owner.UserID})
+	require.NoError(t, err)
+	_, err = cu.EditComment(context.Background(), owner, elsewhere.ID, mine.ID, "Moved")
+	assert.ErrorIs(t, err, repositories.ErrCommentNotFound)
+
+	// Admins manage every comment
+	require.NoError(t, cu.DeleteComment(context.Background(), admin, task.ID, mine.ID))
+	page, err := cu.ListComments(context.Background(), owner, domain.CommentQuery{TaskID: task.ID})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), page.Total)
+}
@@ -0,0 +1,216 @@
+package usecases_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	domain "task_manager/Domain"
+	repositories "task_manager/Repositories"
+	usecases "task_manager/Usecases"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newProjectUsecases returns project usecases whose user repository knows
+// the manager and member actors it also returns.
+func newProjectUsecases(t *testing.T) (*usecases.ProjectUsecases, repositories.IAuditRepository, domain.Actor, domain.Actor) {
+	userRepo := repositories.NewMemoryUserRepository()
+	alice, err := userRepo.CreateUser(context.Background(), "alice", "password123")
+	require.NoError(t, err)
+	bob, err := userRepo.CreateUser(context.Background(), "bob", "password123")
+	require.NoError(t, err)
+	auditRepo := repositories.NewMemoryAuditRepository()
+	pu := usecases.NewProjectUsecases(repositories.NewMemoryProjectRepository(), userRepo, auditRepo)
+	return pu, auditRepo, actorWithRole(alice.ID.Hex(), domain.RoleUser), actorWithRole(bob.ID.Hex(), domain.RoleUser)
+}
+
+func TestCreateProject_CreatorBecomesManager(t *testing.T) {
+	pu, auditRepo, alice, bob := newProjectUsecases(t)
+
+	project, err := pu.CreateProject(context.Background(), alice, usecases.ProjectInput{
+		Name:    "  Launch  ",
+		Members: []domain.ProjectMember{{UserID: bob.UserID, Role: domain.ProjectRoleViewer}},
+	})
+	require.NoError(t, err)
+	assert.NotEmpty(t, project.ID)
+	assert.Equal(t, "Launch", project.Name)
+	role, ok := project.MemberRole(alice.UserID)
+	assert.True(t, ok)
+	assert.Equal(t, domain.ProjectRoleManager, role)
+	role, _ = project.MemberRole(bob.UserID)
+	assert.Equal(t, domain.ProjectRoleViewer, role)
+
+	entries, err := auditRepo.List(context.Background(), domain.AuditQuery{Action: domain.AuditProjectCreate, Page: 1, PageSize: 10})
+	require.NoError(t, err)
+	assert.Len(t, entries.Entries, 1)
+}
+
+func TestCreateProject_Validation(t *testing.T) {
+	pu, _, alice, _ := newProjectUsecases(t)
+
+	for _, input := range []usecases.ProjectInput{
+		{Name: "   "},
+		{Name: "Launch", Members: []domain.ProjectMember{{UserID: "ghost", Role: domain.ProjectRoleMember}}},
+		{Name: "Launch", Members: []domain.ProjectMember{{UserID: alice.UserID, Role: "owner"}}},
+	} {
+		_, err := pu.CreateProject(context.Background(), alice, input)
+		var domainErr *domain.Error
+		require.ErrorAs(t, err, &domainErr)
+		assert.Equal(t, domain.CodeValidation, domainErr.Code)
+	}
+}
+
+func TestProjects_VisibleToMembersAndManagers(t *testing.T) {
+	pu, _, alice, bob := newProjectUsecases(t)
+	project, err := pu.CreateProject(context.Background(), alice, usecases.ProjectInput{Name: "Launch"})
+	require.NoError(t, err)
+
+	projects, err := pu.ListProjects(context.Background(), bob)
+	require.NoError(t, err)
+	assert.Empty(t, projects)
+	_, err = pu.GetProject(context.Background(), bob, project.ID)
+	assert.ErrorIs(t, err, repositories.ErrProjectNotFound)
+
+	projects, err = pu.ListProjects(context.Background(), admin)
+	require.NoError(t, err)
+	assert.Len(t, projects, 1)
+	_, err = pu.GetProject(context.Background(), admin, project.ID)
+	assert.NoError(t, err)
+}
+
+func TestSetAndRemoveMember(t *testing.T) {
+	pu, auditRepo, alice, bob := newProjectUsecases(t)
+	project, err := pu.CreateProject(context.Background(), alice, usecases.ProjectInput{Name: "Launch"})
+	require.NoError(t, err)
+
+	project, err = pu.SetMember(context.Background(), alice, project.ID, bob.UserID, domain.ProjectRoleMember)
+	require.NoError(t, err)
+	role, _ := project.MemberRole(bob.UserID)
+	assert.Equal(t, domain.ProjectRoleMember, role)
+
+	// Members who are not managers cannot change the members
+	_, err = pu.SetMember(context.Background(), bob, project.ID, bob.UserID, domain.ProjectRoleManager)
+	var domainErr *domain.Error
+	require.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, domain.CodeForbidden, domainErr.Code)
+
+	_, err = pu.SetMember(context.Background(), alice, project.ID, "ghost", domain.ProjectRoleMember)
+	assert.ErrorIs(t, err, repositories.ErrUserNotFound)
+	_, err = pu.SetMember(context.Background(), alice, project.ID, bob.UserID, "owner")
+	require.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, domain.CodeValidation, domainErr.Code)
+
+	// The last manager can neither step down nor leave
+	_, err = pu.SetMember(context.Background(), alice, project.ID, alice.UserID, domain.ProjectRoleMember)
+	assert.ErrorIs(t, err, usecases.ErrLastManager)
+	assert.ErrorIs(t, pu.RemoveMember(context.Background(), alice, project.ID, alice.UserID), usecases.ErrLastManager)
+
+	require.NoError(t, pu.RemoveMember(context.Background(), alice, project.ID, bob.UserID))
+	assert.ErrorIs(t, pu.RemoveMember(context.Background(), alice, project.ID, bob.UserID), usecases.ErrMemberNotFound)
+
+	entries, err := auditRepo.List(context.Background(), domain.AuditQuery{TargetType: domain.AuditTargetProject, TargetID: project.ID, Page: 1, PageSize: 10})
+	require.NoError(t, err)
+	assert.Len(t, entries.Entries, 3)
+}
+
+func TestArchiveProject_BlocksMemberChanges(t *testing.T) {
+	pu, _, alice, bob := newProjectUsecases(t)
+	project, err := pu.CreateProject(context.Background(), alice, usecases.ProjectInput{Name: "Launch"})
+	require.NoError(t, err)
+
+	archived, err := pu.ArchiveProject(context.Background(), admin, project.ID)
+	require.NoError(t, err)
+	assert.True(t, archived.IsArchived())
+	_, err = pu.SetMember(context.Background(), alice, project.ID, bob.UserID, domain.ProjectRoleMember)
+	assert.ErrorIs(t, err, usecases.ErrProjectArchived)
+
+	access, err := pu.ProjectAccess(context.Background(), alice, project.ID)
+	require.NoError(t, err)
+	assert.True(t, access.ReadOnly)
+
+	restored, err := pu.UnarchiveProject(context.Background(), admin, project.ID)
+	require.NoError(t, err)
+	assert.Nil(t, restored.ArchivedAt)
+	_, err = pu.SetMember(context.Background(), alice, project.ID, bob.UserID, domain.ProjectRoleMember)
+	assert.NoError(t, err)
+}
+
+func TestProjectAccess(t *testing.T) {
+	pu, _, alice, bob := newProjectUsecases(t)
+	project, err := pu.CreateProject(context.Background(), alice, usecases.ProjectInput{
+		Name:    "Launch",
+		Members: []domain.ProjectMember{{UserID: bob.UserID, Role: domain.ProjectRoleViewer}},
+	})
+	require.NoError(t, err)
+
+	// The default project keeps the actor's own permissions
+	access, err := pu.ProjectAccess(context.Background(), bob, domain.DefaultProjectID)
+	require.NoError(t, err)
+	assert.Equal(t, bob.Permissions, access.Permissions)
+
+	access, err = pu.ProjectAccess(context.Background(), bob, project.ID)
+	require.NoError(t, err)
+	assert.Equal(t, project.ID, access.ProjectID)
+	assert.Equal(t, domain.ProjectRolePermissions(domain.ProjectRoleViewer), access.Permissions)
+	assert.False(t, access.ReadOnly)
+
+	access, err = pu.ProjectAccess(context.Background(), admin, project.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.ProjectRolePermissions(domain.ProjectRoleManager), access.Permissions)
+
+	_, err = pu.ProjectAccess(context.Background(), other, project.ID)
+	assert.ErrorIs(t, err, repositories.ErrProjectNotFound)
+}
+
+func TestTasks_ScopedToProject(t *testing.T) {
+	tu := usecases.NewTaskUsecases(repositories.NewMemoryTaskRepository(), repositories.NewMemoryUserRepository(), repositories.NewMemoryAuditRepository(), domain.DefaultWorkflow())
+	inA := domain.Actor{UserID: owner.UserID, ProjectID: "a", Permissions: domain.ProjectRolePermissions(domain.ProjectRoleMember)}
+	inB := domain.Actor{UserID: owner.UserID, ProjectID: "b", Permissions: domain.ProjectRolePermissions(domain.ProjectRoleManager)}
+
+	task, err := tu.CreateTask(context.Background(), inA, usecases.TaskInput{Title: "Plan", DueDate: time.Now().Add(time.Hour)})
+	require.NoError(t, err)
+	assert.Equal(t, "a", task.ProjectID)
+
+	_, err = tu.GetTaskByID(context.Background(), inB, task.ID)
+	assert.ErrorIs(t, err, repositories.ErrNotFound)
+	_, err = tu.GetTaskByID(context.Background(), owner, task.ID)
+	assert.ErrorIs(t, err, repositories.ErrNotFound)
+	page, err := tu.ListTasks(context.Background(), inB, domain.TaskQuery{})
+	require.NoError(t, err)
+	assert.Empty(t, page.Tasks)
+
+	page, err = tu.ListTasks(context.Background(), inA, domain.TaskQuery{})
+	require.NoError(t, err)
+	require.Len(t, page.Tasks, 1)
+	assert.Equal(t, task.ID, page.Tasks[0].ID)
+}
+
+func TestTasks_ViewerSeesButCannotChange(t *testing.T) {
+	tu := usecases.NewTaskUsecases(repositories.NewMemoryTaskRepository(), repositories.NewMemoryUserRepository(), repositories.NewMemoryAuditRepository(), domain.DefaultWorkflow())
+	member := domain.Actor{UserID: owner.UserID, ProjectID: "a", Permissions: domain.ProjectRolePermissions(domain.ProjectRoleMember)}
+	viewer := domain.Actor{UserID: other.UserID, ProjectID: "a", Permissions: domain.ProjectRolePermissions(domain.ProjectRoleViewer)}
+
+	task, err := tu.CreateTask(context.Background(), member, usecases.TaskInput{Title: "Plan", DueDate: time.Now().Add(time.Hour)})
+	require.NoError(t, err)
+
+	// Viewers see every task of the project
+	_, err = tu.GetTaskByID(context.Background(), viewer, task.ID)
+	require.NoError(t, err)
+	page, err := tu.ListTasks(context.Background(), viewer, domain.TaskQuery{})
+	require.NoError(t, err)
+	assert.Len(t, page.Tasks, 1)
+
+	// but cannot change another member's task
+	_, err = tu.UpdateTask(context.Background(), viewer, task.ID, usecases.TaskInput{Title: "Taken over", DueDate: task.DueDate})
+	var domainErr *domain.Error
+	require.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, domain.CodeForbidden, domainErr.Code)
+	require.ErrorAs(t, tu.DeleteTask(context.Background(), viewer, task.ID), &domainErr)
+	assert.Equal(t, domain.CodeForbidden, domainErr.Code)
+
+	got, err := tu.GetTaskByID(context.Background(), member, task.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Plan", got.Title)
+}
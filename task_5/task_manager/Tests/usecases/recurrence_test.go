@@ -0,0 +1,261 @@
+package usecases_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	domain "task_manager/Domain"
+	repositories "task_manager/Repositories"
+	usecases "task_manager/Usecases"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecurrenceNext(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+	past := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		rule     string
+		timezone string
+		due      time.Time
+		want     time.Time
+		wantRule string
+	}{
+		{"daily", "daily", "", time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC), time.Date(2025, 3, 2, 9, 0, 0, 0, time.UTC), "FREQ=DAILY"},
+		{"every other day", "FREQ=DAILY;INTERVAL=2", "", time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC), time.Date(2025, 3, 3, 9, 0, 0, 0, time.UTC), "FREQ=DAILY;INTERVAL=2"},
+		{"weekly", "weekly", "", time.Date(2025, 3, 5, 9, 0, 0, 0, time.UTC), time.Date(2025, 3, 12, 9, 0, 0, 0, time.UTC), "FREQ=WEEKLY"},
+		{"later weekday in the week", "FREQ=WEEKLY;BYDAY=FR,MO", "", time.Date(2025, 3, 3, 9, 0, 0, 0, time.UTC), time.Date(2025, 3, 7, 9, 0, 0, 0, time.UTC), "FREQ=WEEKLY;BYDAY=MO,FR"},
+		{"first weekday of a later week", "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,FR", "", time.Date(2025, 3, 7, 9, 0, 0, 0, time.UTC), time.Date(2025, 3, 17, 9, 0, 0, 0, time.UTC), "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,FR"},
+		{"week starting on sunday", "FREQ=WEEKLY;INTERVAL=2;BYDAY=SU,SA;WKST=SU", "", time.Date(2025, 3, 8, 9, 0, 0, 0, time.UTC), time.Date(2025, 3, 16, 9, 0, 0, 0, time.UTC), "FREQ=WEEKLY;INTERVAL=2;BYDAY=SU,SA;WKST=SU"},
+		{"monthly skips short months", "monthly", "", time.Date(2025, 1, 31, 9, 0, 0, 0, time.UTC), time.Date(2025, 3, 31, 9, 0, 0, 0, time.UTC), "FREQ=MONTHLY"},
+		{"last day of the month", "FREQ=MONTHLY;BYMONTHDAY=-1", "", time.Date(2025, 1, 31, 9, 0, 0, 0, time.UTC), time.Date(2025, 2, 28, 9, 0, 0, 0, time.UTC), "FREQ=MONTHLY;BYMONTHDAY=-1"},
+		{"several days of the month", "FREQ=MONTHLY;BYMONTHDAY=15,1", "", time.Date(2025, 1, 15, 9, 0, 0, 0, time.UTC), time.Date(2025, 2, 1, 9, 0, 0, 0, time.UTC), "FREQ=MONTHLY;BYMONTHDAY=1,15"},
+		{"yearly on a leap day", "yearly", "", time.Date(2024, 2, 29, 9, 0, 0, 0, time.UTC), time.Date(2028, 2, 29, 9, 0, 0, 0, time.UTC), "FREQ=YEARLY"},
+		{"count is used up", "FREQ=DAILY;COUNT=3", "", time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC), time.Date(2025, 3, 2, 9, 0, 0, 0, time.UTC), "FREQ=DAILY;COUNT=2"},
+		// 09:00 in Berlin is 08:00 UTC before the switch to summer time and 07:00 UTC after it
+		{"local time kept across DST", "daily", "Europe/Berlin", time.Date(2025, 3, 29, 9, 0, 0, 0, berlin), time.Date(2025, 3, 30, 7, 0, 0, 0, time.UTC), "FREQ=DAILY"},
+		{"weekly across DST", "FREQ=WEEKLY", "Europe/Berlin", time.Date(2025, 10, 20, 9, 0, 0, 0, berlin), time.Date(2025, 10, 27, 8, 0, 0, 0, time.UTC), "FREQ=WEEKLY"},
+		{"local day differs from UTC day", "FREQ=WEEKLY;BYDAY=MO", "Europe/Berlin", time.Date(2025, 3, 3, 0, 30, 0, 0, berlin), time.Date(2025, 3, 9, 23, 30, 0, 0, time.UTC), "FREQ=WEEKLY;BYDAY=MO"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := domain.Recurrence{Rule: tt.rule, Timezone: tt.timezone}
+			require.NoError(t, r.Validate())
+			next, due, ok := r.Next(tt.due, past)
+			require.True(t, ok)
+			assert.True(t, tt.want.Equal(due), "got %s, want %s", due, tt.want)
+			assert.Equal(t, tt.wantRule, next.Rule)
+			assert.Equal(t, tt.timezone, next.Timezone)
+		})
+	}
+}
+
+// nextOccurrences follows the series of task for n occurrences.
+func nextOccurrences(t *testing.T, task domain.Task, n int) []time.Time {
+	t.Helper()
+	past := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	var dues []time.Time
+	for i := 0; i < n; i++ {
+		task.Normalize()
+		require.NoError(t, task.Recurrence.Validate())
+		next, due, ok := task.Recurrence.Next(task.DueDate, past)
+		require.True(t, ok)
+		task.Recurrence, task.DueDate = &next, due
+		dues = append(dues, due)
+	}
+	return dues
+}
+
+func TestRecurrenceNext_DSTGap(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+	// 02:30 does not exist on 2026-03-08, when clocks jump from 02:00 EST to 03:00 EDT
+	task := domain.Task{
+		DueDate:    time.Date(2026, 3, 7, 2, 30, 0, 0, newYork),
+		Recurrence: &domain.Recurrence{Rule: "daily", Timezone: "America/New_York"},
+	}
+	dues := nextOccurrences(t, task, 3)
+
+	// The gap moves the occurrence forward by an hour, and the next day is back at 02:30
+	assert.True(t, time.Date(2026, 3, 8, 7, 30, 0, 0, time.UTC).Equal(dues[0]), dues[0])
+	assert.Equal(t, "03:30", dues[0].In(newYork).Format("15:04"))
+	for _, due := range dues[1:] {
+		assert.Equal(t, "02:30 EDT", due.In(newYork).Format("15:04 MST"))
+	}
+	assert.True(t, time.Date(2026, 3, 10, 6, 30, 0, 0, time.UTC).Equal(dues[2]), dues[2])
+}
+
+func TestRecurrenceNext_DSTOverlap(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+	// 01:30 happens twice on 2026-11-01, when clocks fall back from 02:00 EDT to 01:00 EST
+	task := domain.Task{
+		DueDate:    time.Date(2026, 10, 31, 1, 30, 0, 0, newYork),
+		Recurrence: &domain.Recurrence{Rule: "FREQ=DAILY", Timezone: "America/New_York"},
+	}
+	dues := nextOccurrences(t, task, 2)
+
+	// The first of the two 01:30s is used
+	assert.True(t, time.Date(2026, 11, 1, 5, 30, 0, 0, time.UTC).Equal(dues[0]), dues[0])
+	assert.Equal(t, "01:30 EDT", dues[0].In(newYork).Format("15:04 MST"))
+	assert.Equal(t, "01:30 EST", dues[1].In(newYork).Format("15:04 MST"))
+}
+
+func TestRecurrenceNext_MonthlyAndYearlyKeepTimeOfDay(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+	// 02:30 does not exist on 2026-03-29 in Berlin
+	for rule, due := range map[string]time.Time{
+		"FREQ=MONTHLY;BYMONTHDAY=29": time.Date(2026, 1, 29, 2, 30, 0, 0, berlin),
+		"FREQ=YEARLY":                time.Date(2025, 3, 29, 2, 30, 0, 0, berlin),
+	} {
+		task := domain.Task{DueDate: due, Recurrence: &domain.Recurrence{Rule: rule, Timezone: "Europe/Berlin"}}
+		dues := nextOccurrences(t, task, 2)
+		assert.Equal(t, "2026-03-29 03:30", dues[0].In(berlin).Format("2006-01-02 15:04"), rule)
+		assert.Equal(t, "02:30", dues[1].In(berlin).Format("15:04"), rule)
+	}
+}
+
+func TestRecurrence_TimeOfDayFollowsDueDate(t *testing.T) {
+	task := domain.Task{
+		DueDate:    time.Date(2026, 3, 7, 9, 0, 0, 0, time.UTC),
+		Recurrence: &domain.Recurrence{Rule: "daily", TimeOfDay: "02:30:00"},
+	}
+	// A due date that is not the series' occurrence on its day moves the series
+	task.Normalize()
+	assert.Equal(t, "09:00:00", task.Recurrence.TimeOfDay)
+
+	assert.Error(t, domain.Recurrence{Rule: "daily", TimeOfDay: "25:00"}.Validate())
+}
+
+func TestRecurrenceNext_SkipsMissedOccurrences(t *testing.T) {
+	r := domain.Recurrence{Rule: "FREQ=DAILY;COUNT=10"}
+	due := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+	next, nextDue, ok := r.Next(due, due.Add(72*time.Hour))
+	require.True(t, ok)
+	assert.Equal(t, time.Date(2025, 3, 5, 9, 0, 0, 0, time.UTC), nextDue)
+	assert.Equal(t, "FREQ=DAILY;COUNT=6", next.Rule)
+}
+
+func TestRecurrenceNext_SeriesEnds(t *testing.T) {
+	due := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+	for _, rule := range []string{"FREQ=DAILY;COUNT=1", "FREQ=DAILY;UNTIL=20250301T235959Z", "FREQ=WEEKLY;UNTIL=20250307"} {
+		_, _, ok := domain.Recurrence{Rule: rule}.Next(due, due)
+		assert.False(t, ok, rule)
+	}
+	// A date UNTIL includes the whole day in the rule's time zone
+	_, _, ok := domain.Recurrence{Rule: "FREQ=DAILY;UNTIL=20250302", Timezone: "America/New_York"}.Next(due, due)
+	assert.True(t, ok)
+}
+
+func TestRecurrenceValidate(t *testing.T) {
+	for _, r := range []domain.Recurrence{
+		{Rule: ""},
+		{Rule: "hourly"},
+		{Rule: "FREQ=HOURLY"},
+		{Rule: "INTERVAL=2"},
+		{Rule: "FREQ=DAILY;INTERVAL=0"},
+		{Rule: "FREQ=DAILY;FREQ=WEEKLY"},
+		{Rule: "FREQ=DAILY;BYDAY=MO"},
+		{Rule: "FREQ=WEEKLY;BYDAY=1MO"},
+		{Rule: "FREQ=WEEKLY;BYMONTHDAY=1"},
+		{Rule: "FREQ=MONTHLY;BYMONTHDAY=32"},
+		{Rule: "FREQ=DAILY;COUNT=2;UNTIL=20250101"},
+		{Rule: "FREQ=DAILY;UNTIL=tomorrow"},
+		{Rule: "FREQ=DAILY;BYHOUR=9"},
+		{Rule: "daily", Timezone: "Mars/Olympus_Mons"},
+	} {
+		err := r.Validate()
+		var domainErr *domain.Error
+		if assert.ErrorAs(t, err, &domainErr, "%+v", r) {
+			assert.Equal(t, "recurrence", domainErr.Fields[0].Field)
+		}
+	}
+	assert.NoError(t, domain.Recurrence{Rule: "rrule:freq=weekly;byday=mo,we", Timezone: "America/New_York"}.Validate())
+}
+
+func newRecurringTask(t *testing.T, tu *usecases.TaskUsecases, recurrence *domain.Recurrence) domain.Task {
+	task, err := tu.CreateTask(context.Background(), owner, usecases.TaskInput{
+		Title:      "Water the plants",
+		DueDate:    time.Now().Add(time.Hour).Truncate(time.Second),
+		Tags:       []string{"home"},
+		Recurrence: recurrence,
+	})
+	require.NoError(t, err)
+	return task
+}
+
+func TestUpdateTask_CompletingRecurringTaskCreatesNext(t *testing.T) {
+	taskRepo := repositories.NewMemoryTaskRepository()
+	auditRepo := repositories.NewMemoryAuditRepository()
+	tu := usecases.NewTaskUsecases(taskRepo, nil, auditRepo, domain.DefaultWorkflow())
+	task := newRecurringTask(t, tu, &domain.Recurrence{Rule: "weekly"})
+	timeOfDay := task.DueDate.UTC().Format("15:04:05")
+	assert.Equal(t, &domain.Recurrence{Rule: "FREQ=WEEKLY", TimeOfDay: timeOfDay}, task.Recurrence)
+
+	completed, err := tu.UpdateTask(context.Background(), owner, task.ID, usecases.TaskInput{
+		Title: task.Title, DueDate: task.DueDate, Tags: task.Tags, Status: domain.StatusCompleted, Recurrence: task.Recurrence,
+	})
+	require.NoError(t, err)
+	assert.Nil(t, completed.Recurrence)
+
+	page, err := tu.ListTasks(context.Background(), owner, domain.TaskQuery{Status: domain.StatusPending})
+	require.NoError(t, err)
+	require.Len(t, page.Tasks, 1)
+	next := page.Tasks[0]
+	assert.NotEqual(t, task.ID, next.ID)
+	assert.Equal(t, task.Title, next.Title)
+	assert.Equal(t, []string{"home"}, next.Tags)
+	assert.True(t, task.DueDate.UTC().AddDate(0, 0, 7).Equal(next.DueDate))
+	assert.Equal(t, &domain.Recurrence{Rule: "FREQ=WEEKLY", TimeOfDay: timeOfDay}, next.Recurrence)
+
+	history, err := auditRepo.List(context.Background(), domain.AuditQuery{TargetID: next.ID, Page: 1, PageSize: 10})
+	require.NoError(t, err)
+	require.Len(t, history.Entries, 1)
+	assert.Equal(t, domain.AuditTaskCreate, history.Entries[0].Action)
+}
+
+func TestUpdateTask_RecurrenceEnds(t *testing.T) {
+	taskRepo := repositories.NewMemoryTaskRepository()
+	tu := usecases.NewTaskUsecases(taskRepo, nil, repositories.NewMemoryAuditRepository(), domain.DefaultWorkflow())
+	task := newRecurringTask(t, tu, &domain.Recurrence{Rule: "FREQ=DAILY;COUNT=1"})
+
+	_, err := tu.UpdateTask(context.Background(), owner, task.ID, usecases.TaskInput{
+		Title: task.Title, DueDate: task.DueDate, Status: domain.StatusCompleted, Recurrence: task.Recurrence,
+	})
+	require.NoError(t, err)
+	tasks := storedTasks(t, taskRepo)
+	assert.Len(t, tasks, 1)
+}
+
+func TestUpdateTask_StaleVersionDiscardsNextOccurrence(t *testing.T) {
+	taskRepo := repositories.NewMemoryTaskRepository()
+	tu := usecases.NewTaskUsecases(taskRepo, nil, repositories.NewMemoryAuditRepository(), domain.DefaultWorkflow())
+	task := newRecurringTask(t, tu, &domain.Recurrence{Rule: "daily"})
+	_, err := taskRepo.Update(context.Background(), task.ID, task)
+	require.NoError(t, err)
+
+	_, err = tu.UpdateTask(context.Background(), owner, task.ID, usecases.TaskInput{
+		Title: task.Title, DueDate: task.DueDate, Status: domain.StatusCompleted, Recurrence: task.Recurrence, Version: task.Version,
+	})
+	assert.ErrorIs(t, err, repositories.ErrVersionConflict)
+	tasks := storedTasks(t, taskRepo)
+	assert.Len(t, tasks, 1)
+}
+
+func TestCreateTask_RecurringSubtask(t *testing.T) {
+	tu := usecases.NewTaskUsecases(repositories.NewMemoryTaskRepository(), nil, repositories.NewMemoryAuditRepository(), domain.DefaultWorkflow())
+	parent := newRecurringTask(t, tu, nil)
+
+	_, err := tu.CreateTask(context.Background(), owner, usecases.TaskInput{
+		Title: "Subtask", DueDate: time.Now().Add(time.Hour), ParentID: parent.ID, Recurrence: &domain.Recurrence{Rule: "daily"},
+	})
+	var domainErr *domain.Error
+	require.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, []domain.FieldError{{Field: "recurrence", Message: "subtasks cannot recur"}}, domainErr.Fields)
+}
@@ -0,0 +1,124 @@
+package usecases_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	domain "task_manager/Domain"
+	repositories "task_manager/Repositories"
+	usecases "task_manager/Usecases"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingNotifier keeps the events it is sent, failing while err is set.
+type recordingNotifier struct {
+	mu     sync.Mutex
+	events []domain.TaskEvent
+	err    error
+}
+
+func (n *recordingNotifier) Notify(ctx context.Context, event domain.TaskEvent) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.err != nil {
+		return n.err
+	}
+	n.events = append(n.events, event)
+	return nil
+}
+
+func newTestReminders(t *testing.T) (*usecases.ReminderUsecases, repositories.ITaskRepository, *recordingNotifier) {
+	taskRepo := repositories.NewMemoryTaskRepository()
+	notifier := &recordingNotifier{}
+	config := usecases.ReminderConfig{Lead: time.Hour, Lookback: 24 * time.Hour}
+	ru := usecases.NewReminderUsecases(taskRepo, repositories.NewMemoryReminderRepository(), domain.DefaultWorkflow(), notifier, config)
+	return ru, taskRepo, notifier
+}
+
+func createDueTask(t *testing.T, repo repositories.ITaskRepository, title, status string, due time.Time) domain.Task {
+	task, err := repo.Create(context.Background(), domain.Task{Title: title, Status: status, DueDate: due, OwnerID: owner.UserID})
+	require.NoError(t, err)
+	return task
+}
+
+func TestSendReminders(t *testing.T) {
+	ru, taskRepo, notifier := newTestReminders(t)
+	now := time.Now().UTC()
+	soon := createDueTask(t, taskRepo, "Soon", domain.StatusPending, now.Add(30*time.Minute))
+	late := createDueTask(t, taskRepo, "Late", domain.StatusInProgress, now.Add(-time.Hour))
+	createDueTask(t, taskRepo, "Done", domain.StatusCompleted, now.Add(-time.Hour))
+	createDueTask(t, taskRepo, "Later", domain.StatusPending, now.Add(2*time.Hour))
+	createDueTask(t, taskRepo, "Long overdue", domain.StatusPending, now.Add(-48*time.Hour))
+
+	sent, err := ru.SendReminders(context.Background(), now)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, sent)
+	require.Len(t, notifier.events, 2)
+	assert.Equal(t, domain.EventTaskOverdue, notifier.events[0].Type)
+	assert.Equal(t, late.ID, notifier.events[0].Task.ID)
+	assert.Equal(t, domain.EventTaskDueSoon, notifier.events[1].Type)
+	assert.Equal(t, soon.ID, notifier.events[1].Task.ID)
+
+	// Each reminder is sent once
+	sent, err = ru.SendReminders(context.Background(), now.Add(time.Minute))
+	assert.NoError(t, err)
+	assert.Zero(t, sent)
+
+	// Once due, the due-soon task becomes overdue
+	sent, err = ru.SendReminders(context.Background(), now.Add(31*time.Minute))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, sent)
+	assert.Equal(t, domain.EventTaskOverdue, notifier.events[2].Type)
+	assert.Equal(t, soon.ID, notifier.events[2].Task.ID)
+}
+
+func TestSendReminders_NewDueDateRearms(t *testing.T) {
+	ru, taskRepo, notifier := newTestReminders(t)
+	now := time.Now().UTC()
+	task := createDueTask(t, taskRepo, "Task", domain.StatusPending, now.Add(-time.Minute))
+	_, err := ru.SendReminders(context.Background(), now)
+	require.NoError(t, err)
+
+	task.DueDate = now.Add(-30 * time.Second)
+	_, err = taskRepo.Update(context.Background(), task.ID, task)
+	require.NoError(t, err)
+	sent, err := ru.SendReminders(context.Background(), now)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, sent)
+	assert.Len(t, notifier.events, 2)
+}
+
+func TestSendReminders_RetriesFailedNotification(t *testing.T) {
+	ru, taskRepo, notifier := newTestReminders(t)
+	now := time.Now().UTC()
+	createDueTask(t, taskRepo, "Task", domain.StatusPending, now.Add(-time.Minute))
+
+	notifier.err = errors.New("mail server down")
+	sent, err := ru.SendReminders(context.Background(), now)
+	assert.NoError(t, err)
+	assert.Zero(t, sent)
+
+	notifier.err = nil
+	sent, err = ru.SendReminders(context.Background(), now)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, sent)
+}
+
+func TestListTasks_Overdue(t *testing.T) {
+	taskRepo := repositories.NewMemoryTaskRepository()
+	tu := usecases.NewTaskUsecases(taskRepo, nil, repositories.NewMemoryAuditRepository(), domain.DefaultWorkflow())
+	now := time.Now().UTC()
+	late := createDueTask(t, taskRepo, "Late", domain.StatusPending, now.Add(-time.Hour))
+	createDueTask(t, taskRepo, "Done", domain.StatusCompleted, now.Add(-time.Hour))
+	createDueTask(t, taskRepo, "Upcoming", domain.StatusPending, now.Add(time.Hour))
+
+	page, err := tu.ListTasks(context.Background(), owner, domain.TaskQuery{Overdue: true})
+	assert.NoError(t, err)
+	require.Len(t, page.Tasks, 1)
+	assert.Equal(t, late.ID, page.Tasks[0].ID)
+}
@@ -0,0 +1,79 @@
+package usecases_test
+
+import (
+	"context"
+	domain "task_manager/Domain"
+	repositories "task_manager/Repositories"
+	"task_manager/Tests/mocks"
+	usecases "task_manager/Usecases"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestListRoles_IncludesBuiltIns(t *testing.T) {
+	ru := usecases.NewRoleUsecases(repositories.NewMemoryRoleRepository(), nil, repositories.NewMemoryAuditRepository())
+
+	_, err := ru.SaveRole(context.Background(), domain.Role{Name: "reviewer", Permissions: []string{domain.PermTasksRead}})
+	assert.NoError(t, err)
+
+	roles, err := ru.ListRoles(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, roles, 3)
+	assert.Equal(t, domain.RoleAdmin, roles[0].Name)
+	assert.True(t, roles[0].BuiltIn)
+	assert.Equal(t, "reviewer", roles[2].Name)
+	assert.False(t, roles[2].BuiltIn)
+}
+
+func TestSaveRole_Validation(t *testing.T) {
+	ru := usecases.NewRoleUsecases(repositories.NewMemoryRoleRepository(), nil, repositories.NewMemoryAuditRepository())
+
+	_, err := ru.SaveRole(context.Background(), domain.Role{Name: domain.RoleAdmin})
+	assert.ErrorIs(t, err, usecases.ErrBuiltInRole)
+
+	_, err = ru.SaveRole(context.Background(), domain.Role{Name: "reviewer", Permissions: []string{"tasks:explode"}})
+	assert.Error(t, err)
+
+	_, err = ru.SaveRole(context.Background(), domain.Role{Name: "Bad Name"})
+	assert.Error(t, err)
+}
+
+func TestPermissionsForRole(t *testing.T) {
+	ru := usecases.NewRoleUsecases(repositories.NewMemoryRoleRepository(), nil, repositories.NewMemoryAuditRepository())
+
+	perms, err := ru.PermissionsForRole(context.Background(), domain.RoleAdmin)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, domain.AllPermissions, perms)
+
+	perms, err = ru.PermissionsForRole(context.Background(), "missing")
+	assert.NoError(t, err)
+	assert.Empty(t, perms)
+}
+
+func TestDeleteRole(t *testing.T) {
+	ru := usecases.NewRoleUsecases(repositories.NewMemoryRoleRepository(), nil, repositories.NewMemoryAuditRepository())
+
+	assert.ErrorIs(t, ru.DeleteRole(context.Background(), domain.RoleUser), usecases.ErrBuiltInRole)
+	assert.ErrorIs(t, ru.DeleteRole(context.Background(), "missing"), repositories.ErrRoleNotFound)
+
+	_, err := ru.SaveRole(context.Background(), domain.Role{Name: "reviewer"})
+	assert.NoError(t, err)
+	assert.NoError(t, ru.DeleteRole(context.Background(), "reviewer"))
+}
+
+func TestAssignRole(t *testing.T) {
+	userRepo := new(mocks.MockUserRepository)
+	ru := usecases.NewRoleUsecases(repositories.NewMemoryRoleRepository(), userRepo, repositories.NewMemoryAuditRepository())
+
+	userRepo.On("GetByID", "user-id").Return(domain.User{Role: domain.RoleUser}, nil)
+	userRepo.On("SetRole", "user-id", domain.RoleAdmin).Return(nil)
+
+	assert.NoError(t, ru.AssignRole(context.Background(), admin, "user-id", domain.RoleAdmin))
+	assert.ErrorIs(t, ru.AssignRole(context.Background(), admin, "user-id", "missing"), repositories.ErrRoleNotFound)
+	assert.ErrorIs(t, ru.AssignRole(context.Background(), admin, admin.UserID, domain.RoleUser), usecases.ErrOwnRoleChange)
+	userRepo.AssertNumberOfCalls(t, "SetRole", 1)
+	userRepo.AssertCalled(t, "SetRole", "user-id", domain.RoleAdmin)
+	userRepo.AssertNotCalled(t, "SetRole", mock.Anything, domain.RoleUser)
+}
@@ -0,0 +1,134 @@
+package usecases_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	domain "task_manager/Domain"
+	repositories "task_manager/Repositories"
+	usecases "task_manager/Usecases"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newBulkUsecases() (*usecases.TaskUsecases, repositories.ITaskRepository, repositories.IAuditRepository) {
+	taskRepo := repositories.NewMemoryTaskRepository()
+	auditRepo := repositories.NewMemoryAuditRepository()
+	return usecases.NewTaskUsecases(taskRepo, nil, auditRepo, domain.DefaultWorkflow()), taskRepo, auditRepo
+}
+
+func TestBulkTasks(t *testing.T) {
+	tu, taskRepo, auditRepo := newBulkUsecases()
+	due := time.Now().Add(time.Hour)
+	task, err := tu.CreateTask(context.Background(), owner, usecases.TaskInput{Title: "Task", DueDate: due})
+	require.NoError(t, err)
+	removed, err := tu.CreateTask(context.Background(), admin, usecases.TaskInput{Title: "Removed", DueDate: due})
+	require.NoError(t, err)
+
+	results, err := tu.BulkTasks(context.Background(), admin, []usecases.BulkOperation{
+		{Op: usecases.BulkCreate, Input: usecases.TaskInput{Title: "New", DueDate: due}},
+		{Op: usecases.BulkCreate, Input: usecases.TaskInput{Title: "Invalid", DueDate: due, Status: "unknown"}},
+		{Op: usecases.BulkUpdate, ID: task.ID, Input: usecases.TaskInput{Title: "Renamed", DueDate: due, Version: task.Version}},
+		{Op: usecases.BulkDelete, ID: removed.ID},
+		{Op: usecases.BulkDelete, ID: "missing"},
+	}, false)
+	require.NoError(t, err)
+	require.Len(t, results, 5)
+
+	assert.NoError(t, results[0].Err)
+	assert.Equal(t, "New", results[0].Task.Title)
+	assert.Equal(t, admin.UserID, results[0].Task.OwnerID)
+	var domainErr *domain.Error
+	require.ErrorAs(t, results[1].Err, &domainErr)
+	assert.Equal(t, domain.CodeValidation, domainErr.Code)
+	assert.NoError(t, results[2].Err)
+	assert.Equal(t, "Renamed", results[2].Task.Title)
+	assert.Equal(t, int64(2), results[2].Task.Version)
+	assert.NotEmpty(t, results[2].Task.NextStatuses)
+	assert.NoError(t, results[3].Err)
+	assert.NotNil(t, results[3].Task.DeletedAt)
+	assert.ErrorIs(t, results[4].Err, repositories.ErrNotFound)
+
+	tasks := storedTasks(t, taskRepo)
+	assert.Len(t, tasks, 2)
+
+	history, err := auditRepo.List(context.Background(), domain.AuditQuery{TargetID: task.ID, Page: 1, PageSize: 10})
+	require.NoError(t, err)
+	require.Len(t, history.Entries, 2)
+	assert.Equal(t, domain.AuditTaskUpdate, history.Entries[0].Action)
+}
+
+func TestBulkTasks_PermissionPerOperation(t *testing.T) {
+	tu, _, _ := newBulkUsecases()
+	due := time.Now().Add(time.Hour)
+	task, err := tu.CreateTask(context.Background(), owner, usecases.TaskInput{Title: "Task", DueDate: due})
+	require.NoError(t, err)
+	others, err := tu.CreateTask(context.Background(), other, usecases.TaskInput{Title: "Other", DueDate: due})
+	require.NoError(t, err)
+
+	results, err := tu.BulkTasks(context.Background(), owner, []usecases.BulkOperation{
+		{Op: usecases.BulkCreate, Input: usecases.TaskInput{Title: "New", DueDate: due}},
+		{Op: usecases.BulkDelete, ID: task.ID},
+		{Op: usecases.BulkUpdate, ID: others.ID, Input: usecases.TaskInput{Title: "Mine now", DueDate: due}},
+	}, false)
+	require.NoError(t, err)
+	assert.NoError(t, results[0].Err)
+	var domainErr *domain.Error
+	require.ErrorAs(t, results[1].Err, &domainErr)
+	assert.Equal(t, domain.CodeForbidden, domainErr.Code)
+	assert.ErrorIs(t, results[2].Err, repositories.ErrNotFound)
+}
+
+func TestBulkTasks_Atomic(t *testing.T) {
+	tu, taskRepo, auditRepo := newBulkUsecases()
+	due := time.Now().Add(time.Hour)
+	task, err := tu.CreateTask(context.Background(), owner, usecases.TaskInput{Title: "Task", DueDate: due})
+	require.NoError(t, err)
+
+	results, err := tu.BulkTasks(context.Background(), owner, []usecases.BulkOperation{
+		{Op: usecases.BulkCreate, Input: usecases.TaskInput{Title: "New", DueDate: due}},
+		{Op: usecases.BulkUpdate, ID: task.ID, Input: usecases.TaskInput{Title: "Renamed", DueDate: due}},
+		{Op: usecases.BulkCreate, Input: usecases.TaskInput{DueDate: due}},
+	}, true)
+	require.NoError(t, err)
+	assert.ErrorIs(t, results[0].Err, repositories.ErrBulkAborted)
+	assert.ErrorIs(t, results[1].Err, repositories.ErrBulkAborted)
+	var domainErr *domain.Error
+	require.ErrorAs(t, results[2].Err, &domainErr)
+	assert.Equal(t, domain.CodeValidation, domainErr.Code)
+
+	tasks := storedTasks(t, taskRepo)
+	require.Len(t, tasks, 1)
+	assert.Equal(t, "Task", tasks[0].Title)
+	history, err := auditRepo.List(context.Background(), domain.AuditQuery{TargetID: task.ID, Page: 1, PageSize: 10})
+	require.NoError(t, err)
+	assert.Len(t, history.Entries, 1)
+}
+
+func TestBulkTasks_CompletesRecurringTask(t *testing.T) {
+	tu, taskRepo, _ := newBulkUsecases()
+	task := newRecurringTask(t, tu, &domain.Recurrence{Rule: "daily"})
+
+	results, err := tu.BulkTasks(context.Background(), owner, []usecases.BulkOperation{{
+		Op: usecases.BulkUpdate, ID: task.ID,
+		Input: usecases.TaskInput{Title: task.Title, DueDate: task.DueDate, Status: domain.StatusCompleted, Recurrence: task.Recurrence},
+	}}, true)
+	require.NoError(t, err)
+	require.NoError(t, results[0].Err)
+	assert.Nil(t, results[0].Task.Recurrence)
+
+	tasks := storedTasks(t, taskRepo)
+	assert.Len(t, tasks, 2)
+}
+
+func TestBulkTasks_OperationCount(t *testing.T) {
+	tu, _, _ := newBulkUsecases()
+	for _, ops := range [][]usecases.BulkOperation{nil, make([]usecases.BulkOperation, usecases.MaxBulkOperations+1)} {
+		_, err := tu.BulkTasks(context.Background(), owner, ops, false)
+		var domainErr *domain.Error
+		require.ErrorAs(t, err, &domainErr)
+		assert.Equal(t, "operations", domainErr.Fields[0].Field)
+	}
+}
@@ -0,0 +1,106 @@
+package usecases_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	domain "task_manager/Domain"
+	repositories "task_manager/Repositories"
+	"task_manager/Tests/mocks"
+	usecases "task_manager/Usecases"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func nextEvent(t *testing.T, stream *usecases.TaskStream) domain.TaskEvent {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	event, err := stream.Next(ctx)
+	assert.NoError(t, err)
+	return event
+}
+
+func TestStreamTasks_PublishesChanges(t *testing.T) {
+	mockRepo := new(mocks.MockTaskRepository)
+	tu := usecases.NewTaskUsecases(mockRepo, new(mocks.MockUserRepository), repositories.NewMemoryAuditRepository(), domain.DefaultWorkflow())
+	stream := tu.StreamTasks(owner, 0)
+	defer stream.Close()
+
+	created := domain.Task{ID: "1", Title: "Task", Status: domain.StatusPending, OwnerID: owner.UserID, Version: 1}
+	mockRepo.On("Create", mock.Anything).Return(created, nil)
+	_, err := tu.CreateTask(context.Background(), owner, usecases.TaskInput{Title: "Task", DueDate: time.Now().Add(time.Hour)})
+	assert.NoError(t, err)
+
+	event := nextEvent(t, stream)
+	assert.Equal(t, int64(1), event.ID)
+	assert.Equal(t, domain.EventTaskCreated, event.Type)
+	assert.Equal(t, "1", event.Task.ID)
+	assert.Equal(t, []string{domain.StatusInProgress, domain.StatusCompleted}, event.Task.NextStatuses)
+}
+
+func TestStreamTasks_OnlyVisibleTasks(t *testing.T) {
+	tu := usecases.NewTaskUsecases(new(mocks.MockTaskRepository), new(mocks.MockUserRepository), repositories.NewMemoryAuditRepository(), domain.DefaultWorkflow())
+	ownerStream := tu.StreamTasks(owner, 0)
+	defer ownerStream.Close()
+	adminStream := tu.StreamTasks(admin, 0)
+	defer adminStream.Close()
+
+	tu.Events().Publish(domain.TaskEvent{Type: domain.EventTaskUpdated, Task: &domain.Task{ID: "1", OwnerID: other.UserID}})
+	tu.Events().Publish(domain.TaskEvent{Type: domain.EventTaskUpdated, Task: &domain.Task{ID: "2", OwnerID: owner.UserID}})
+
+	assert.Equal(t, "2", nextEvent(t, ownerStream).Task.ID)
+	assert.Equal(t, "1", nextEvent(t, adminStream).Task.ID)
+	assert.Equal(t, "2", nextEvent(t, adminStream).Task.ID)
+}
+
+func TestStreamTasks_Resume(t *testing.T) {
+	tu := usecases.NewTaskUsecases(new(mocks.MockTaskRepository), new(mocks.MockUserRepository), repositories.NewMemoryAuditRepository(), domain.DefaultWorkflow())
+	for _, id := range []string{"1", "2", "3"} {
+		tu.Events().Publish(domain.TaskEvent{Type: domain.EventTaskUpdated, Task: &domain.Task{ID: id, OwnerID: owner.UserID}})
+	}
+
+	stream := tu.StreamTasks(owner, 1)
+	defer stream.Close()
+	assert.False(t, stream.Reset())
+	assert.Equal(t, int64(2), nextEvent(t, stream).ID)
+	assert.Equal(t, int64(3), nextEvent(t, stream).ID)
+
+	// Live events follow the replayed ones
+	tu.Events().Publish(domain.TaskEvent{Type: domain.EventTaskDeleted, Task: &domain.Task{ID: "1", OwnerID: owner.UserID}})
+	event := nextEvent(t, stream)
+	assert.Equal(t, int64(4), event.ID)
+	assert.Equal(t, domain.EventTaskDeleted, event.Type)
+}
+
+func TestEventBus_ResumeAfterLostHistory(t *testing.T) {
+	bus := usecases.NewEventBus(1)
+	for _, id := range []string{"1", "2", "3"} {
+		bus.Publish(domain.TaskEvent{Type: domain.EventTaskUpdated, Task: &domain.Task{ID: id}})
+	}
+
+	// Event 2 is no longer kept, so only event 3 is replayed
+	sub := bus.Subscribe(1)
+	defer sub.Close()
+	assert.True(t, sub.Reset())
+	event, err := sub.Next(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), event.ID)
+
+	// IDs from before a restart are unknown
+	unknown := bus.Subscribe(42)
+	defer unknown.Close()
+	assert.True(t, unknown.Reset())
+}
+
+func TestEventBus_Close(t *testing.T) {
+	bus := usecases.NewEventBus(10)
+	sub := bus.Subscribe(0)
+	bus.Close()
+
+	_, err := sub.Next(context.Background())
+	assert.ErrorIs(t, err, usecases.ErrStreamClosed)
+	_, err = bus.Subscribe(0).Next(context.Background())
+	assert.ErrorIs(t, err, usecases.ErrStreamClosed)
+}
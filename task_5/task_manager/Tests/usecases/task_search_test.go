@@ -0,0 +1,88 @@
+package usecases_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	domain "task_manager/Domain"
+	repositories "task_manager/Repositories"
+	usecases "task_manager/Usecases"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenize(t *testing.T) {
+	text := `"Don't" forget: the e-mail (ASAP)!`
+	var words []string
+	for _, token := range domain.Tokenize(text) {
+		words = append(words, token.Word)
+	}
+	assert.Equal(t, []string{"dont", "forget", "the", "email", "asap"}, words)
+
+	tokens := domain.Tokenize(text)
+	assert.Equal(t, `Don't`, text[tokens[0].Start:tokens[0].End])
+	assert.Equal(t, "ASAP", text[tokens[4].Start:tokens[4].End])
+}
+
+func TestTaskSearch_Normalize(t *testing.T) {
+	s := domain.TaskSearch{Text: `Report "Quarterly  sales" draft* "unclosed quote`}
+	require.NoError(t, s.Normalize())
+	assert.Equal(t, []domain.SearchTerm{
+		{Words: []string{"report"}},
+		{Words: []string{"quarterly", "sales"}},
+		{Words: []string{"draft"}, Prefix: true},
+		{Words: []string{"unclosed", "quote"}},
+	}, s.Terms)
+	assert.Equal(t, 1, s.Page)
+	assert.Equal(t, domain.DefaultPageSize, s.PageSize)
+
+	for _, text := range []string{"", `  "" ?!`, "a*", strings.Repeat("word ", domain.MaxSearchTerms+1)} {
+		s := domain.TaskSearch{Text: text}
+		var domainErr *domain.Error
+		require.ErrorAs(t, s.Normalize(), &domainErr, text)
+		assert.Equal(t, "q", domainErr.Fields[0].Field)
+	}
+}
+
+func TestTaskSearch_Match(t *testing.T) {
+	s := domain.TaskSearch{Text: `"release notes" pub*`}
+	require.NoError(t, s.Normalize())
+
+	description := strings.Repeat("Lorem ipsum dolor sit amet. ", 5) + "Write the release notes & publish them <today>. " + strings.Repeat("Consectetur adipiscing elit. ", 5)
+	hit, ok := s.Match(domain.Task{Title: "Publish release notes", Description: description})
+	require.True(t, ok)
+	assert.Equal(t, 3*2+2+3*0.5+0.5, hit.Score)
+	assert.Equal(t, "<mark>Publish</mark> <mark>release notes</mark>", hit.Highlights["title"])
+	snippet := hit.Highlights["description"]
+	assert.True(t, strings.HasPrefix(snippet, "…"), snippet)
+	assert.True(t, strings.HasSuffix(snippet, "…"), snippet)
+	assert.Contains(t, snippet, "Write the <mark>release notes</mark> &amp; <mark>publish</mark> them &lt;today&gt;.")
+
+	// Every term has to match, and phrases only match in order
+	_, ok = s.Match(domain.Task{Title: "Publish notes", Description: "Release day"})
+	assert.False(t, ok)
+	_, ok = s.Match(domain.Task{Title: "Notes on the release", Description: "Publish soon"})
+	assert.False(t, ok)
+}
+
+func TestSearchTasks_ScopedToOwner(t *testing.T) {
+	tu := usecases.NewTaskUsecases(repositories.NewMemoryTaskRepository(), nil, repositories.NewMemoryAuditRepository(), domain.DefaultWorkflow())
+	due := time.Now().Add(time.Hour)
+	for _, actor := range []domain.Actor{owner, other} {
+		_, err := tu.CreateTask(context.Background(), actor, usecases.TaskInput{Title: "Plan the offsite", DueDate: due})
+		require.NoError(t, err)
+	}
+
+	page, err := tu.SearchTasks(context.Background(), owner, domain.TaskSearch{Text: "offsite"})
+	require.NoError(t, err)
+	require.Len(t, page.Hits, 1)
+	assert.Equal(t, owner.UserID, page.Hits[0].Task.OwnerID)
+	assert.NotEmpty(t, page.Hits[0].Task.NextStatuses)
+
+	page, err = tu.SearchTasks(context.Background(), admin, domain.TaskSearch{Text: "offsite"})
+	require.NoError(t, err)
+	assert.Len(t, page.Hits, 2)
+}
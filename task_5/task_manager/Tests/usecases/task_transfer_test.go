@@ -0,0 +1,177 @@
+package usecases_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	domain "task_manager/Domain"
+	infrastructure "task_manager/Infrastructure"
+	repositories "task_manager/Repositories"
+	usecases "task_manager/Usecases"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportTasks_ScopedToOwner(t *testing.T) {
+	tu := usecases.NewTaskUsecases(repositories.NewMemoryTaskRepository(), nil, repositories.NewMemoryAuditRepository(), domain.DefaultWorkflow())
+	due := time.Now().Add(time.Hour)
+	for _, actor := range []domain.Actor{owner, other, owner} {
+		_, err := tu.CreateTask(context.Background(), actor, usecases.TaskInput{Title: "Task of " + actor.UserID, DueDate: due})
+		require.NoError(t, err)
+	}
+
+	export := func(actor domain.Actor) []domain.Task {
+		var tasks []domain.Task
+		err := tu.ExportTasks(context.Background(), actor, domain.TaskQuery{}, func(task domain.Task) error {
+			tasks = append(tasks, task)
+			return nil
+		})
+		require.NoError(t, err)
+		return tasks
+	}
+	tasks := export(owner)
+	assert.Len(t, tasks, 2)
+	for _, task := range tasks {
+		assert.Equal(t, owner.UserID, task.OwnerID)
+	}
+	assert.Len(t, export(admin), 3)
+
+	err := tu.ExportTasks(context.Background(), owner, domain.TaskQuery{SortBy: "owner_id"}, func(domain.Task) error { return nil })
+	assert.Error(t, err)
+}
+
+func TestImportTasks(t *testing.T) {
+	taskRepo := repositories.NewMemoryTaskRepository()
+	auditRepo := repositories.NewMemoryAuditRepository()
+	tu := usecases.NewTaskUsecases(taskRepo, nil, auditRepo, domain.DefaultWorkflow())
+	due := time.Now().Add(time.Hour)
+	records := []usecases.ImportRecord{
+		{Line: 2, Input: usecases.TaskInput{Title: "First", DueDate: due, Tags: []string{"Imported"}}},
+		{Line: 3, Input: usecases.TaskInput{Title: "Second", DueDate: due, Status: domain.StatusCompleted}},
+	}
+
+	report, err := tu.ImportTasks(context.Background(), owner, records, false)
+	require.NoError(t, err)
+	assert.Equal(t, usecases.ImportReport{Valid: 2, Imported: 2}, report)
+
+	tasks := storedTasks(t, taskRepo)
+	require.Len(t, tasks, 2)
+	for _, task := range tasks {
+		assert.Equal(t, owner.UserID, task.OwnerID)
+		history, err := auditRepo.List(context.Background(), domain.AuditQuery{TargetID: task.ID, Page: 1, PageSize: 10})
+		require.NoError(t, err)
+		assert.Len(t, history.Entries, 1)
+	}
+}
+
+func TestImportTasks_ErrorsByLine(t *testing.T) {
+	taskRepo := repositories.NewMemoryTaskRepository()
+	tu := usecases.NewTaskUsecases(taskRepo, nil, repositories.NewMemoryAuditRepository(), domain.DefaultWorkflow())
+	due := time.Now().Add(time.Hour)
+	unreadable := domain.NewValidationError("invalid JSON")
+	records := []usecases.ImportRecord{
+		{Line: 1, Input: usecases.TaskInput{Title: "Valid", DueDate: due}},
+		{Line: 2, Input: usecases.TaskInput{DueDate: due}},
+		{Line: 3, Input: usecases.TaskInput{Title: "Unknown priority", DueDate: due, Priority: "someday"}},
+		{Line: 4, Err: unreadable},
+		{Line: 5, Input: usecases.TaskInput{Title: "Orphan", DueDate: due, ParentID: "missing"}},
+	}
+
+	for _, dryRun := range []bool{true, false} {
+		report, err := tu.ImportTasks(context.Background(), owner, records, dryRun)
+		require.NoError(t, err)
+		assert.Equal(t, dryRun, report.DryRun)
+		assert.Equal(t, 1, report.Valid)
+		assert.Zero(t, report.Imported)
+		require.Len(t, report.Errors, 4)
+		fields := make(map[int]string)
+		for _, e := range report.Errors {
+			var domainErr *domain.Error
+			require.ErrorAs(t, e.Err, &domainErr)
+			if len(domainErr.Fields) > 0 {
+				fields[e.Line] = domainErr.Fields[0].Field
+			}
+		}
+		assert.Equal(t, map[int]string{2: "title", 3: "priority", 5: "parent_id"}, fields)
+		assert.True(t, errors.Is(report.Errors[2].Err, unreadable))
+	}
+
+	// Nothing is imported while any line is invalid
+	tasks := storedTasks(t, taskRepo)
+	assert.Empty(t, tasks)
+}
+
+func TestImportTasks_RoundTripsPastDueTasks(t *testing.T) {
+	taskRepo := repositories.NewMemoryTaskRepository()
+	tu := usecases.NewTaskUsecases(taskRepo, nil, repositories.NewMemoryAuditRepository(), domain.DefaultWorkflow())
+	// Overdue and completed tasks cannot be created through CreateTask any more
+	past := time.Now().Add(-48 * time.Hour).UTC().Truncate(time.Second)
+	for _, task := range []domain.Task{
+		{Title: "Overdue", DueDate: past, Status: domain.StatusPending, Priority: domain.PriorityHigh, OwnerID: owner.UserID},
+		{Title: "Done last week", DueDate: past.AddDate(0, 0, -5), Status: domain.StatusCompleted, Priority: domain.PriorityLow, OwnerID: owner.UserID},
+	} {
+		_, err := taskRepo.Create(context.Background(), task)
+		require.NoError(t, err)
+	}
+	export := func() []domain.Task {
+		var tasks []domain.Task
+		require.NoError(t, tu.ExportTasks(context.Background(), owner, domain.TaskQuery{SortBy: "due_date"}, func(task domain.Task) error {
+			tasks = append(tasks, task)
+			return nil
+		}))
+		return tasks
+	}
+
+	var buf bytes.Buffer
+	enc, err := infrastructure.NewTaskEncoder(infrastructure.FormatCSV, &buf, domain.DefaultWorkflow())
+	require.NoError(t, err)
+	exported := export()
+	for _, task := range exported {
+		require.NoError(t, enc.Encode(task))
+	}
+	require.NoError(t, enc.Close())
+
+	dec, err := infrastructure.NewTaskDecoder(infrastructure.FormatCSV, &buf, domain.DefaultWorkflow())
+	require.NoError(t, err)
+	var records []usecases.ImportRecord
+	for {
+		record, err := dec.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		task := record.Task
+		records = append(records, usecases.ImportRecord{Line: record.Line, Err: record.Err, Input: usecases.TaskInput{
+			Title: task.Title, Description: task.Description, DueDate: task.DueDate, Status: task.Status,
+			Priority: task.Priority, Tags: task.Tags, AssigneeIDs: task.AssigneeIDs, ParentID: task.ParentID,
+		}})
+	}
+
+	report, err := tu.ImportTasks(context.Background(), owner, records, false)
+	require.NoError(t, err)
+	assert.Empty(t, report.Errors)
+	assert.Equal(t, 2, report.Imported)
+
+	tasks := export()
+	require.Len(t, tasks, 4)
+	for i, want := range exported {
+		for _, got := range tasks[2*i : 2*i+2] {
+			assert.Equal(t, want.Title, got.Title)
+			assert.Equal(t, want.Status, got.Status)
+			assert.True(t, want.DueDate.Equal(got.DueDate), "got %s, want %s", got.DueDate, want.DueDate)
+		}
+	}
+}
+
+func TestImportTasks_TooManyTasks(t *testing.T) {
+	tu := usecases.NewTaskUsecases(repositories.NewMemoryTaskRepository(), nil, repositories.NewMemoryAuditRepository(), domain.DefaultWorkflow())
+	_, err := tu.ImportTasks(context.Background(), owner, make([]usecases.ImportRecord, usecases.MaxImportTasks+1), true)
+	var domainErr *domain.Error
+	require.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, domain.CodeValidation, domainErr.Code)
+}
@@ -1,7 +1,10 @@
 package usecases_test
 
 import (
+	"context"
+	"encoding/json"
 	domain "task_manager/Domain"
+	repositories "task_manager/Repositories"
 	"task_manager/Tests/mocks"
 	usecases "task_manager/Usecases"
 	"testing"
@@ -9,76 +12,490 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
+var (
+	admin = actorWithRole("admin-id", domain.RoleAdmin)
+	owner = actorWithRole("owner-id", domain.RoleUser)
+	other = actorWithRole("other-id", domain.RoleUser)
+)
+
+func actorWithRole(userID, roleName string) domain.Actor {
+	role, _ := domain.BuiltInRole(roleName)
+	return domain.Actor{UserID: userID, Role: role.Name, Permissions: role.Permissions}
+}
+
+// storedTasks returns the live tasks in repo, in due date order.
+func storedTasks(t *testing.T, repo repositories.ITaskRepository) []domain.Task {
+	t.Helper()
+	page, err := repo.List(context.Background(), domain.TaskQuery{SortBy: "due_date", Page: 1, PageSize: domain.MaxPageSize})
+	require.NoError(t, err)
+	return page.Tasks
+}
+
 func TestGetAllTasks(t *testing.T) {
 	mockRepo := new(mocks.MockTaskRepository)
-	tu := usecases.NewTaskUsecases(mockRepo)
+	tu := usecases.NewTaskUsecases(mockRepo, new(mocks.MockUserRepository), repositories.NewMemoryAuditRepository(), domain.DefaultWorkflow())
 
 	tasks := []domain.Task{{ID: "1", Title: "Task1"}}
-	mockRepo.On("GetAll").Return(tasks, nil)
+	mockRepo.On("Each", domain.TaskQuery{
+		ProjectID: domain.DefaultProjectID,
+		SortBy:    "due_date",
+		Page:      1,
+		PageSize:  domain.DefaultPageSize,
+	}).Return(tasks, nil)
 
-	result, err := tu.GetAllTasks()
+	result, err := tu.GetAllTasks(context.Background(), admin)
 	assert.NoError(t, err)
 	assert.Equal(t, tasks, result)
 	mockRepo.AssertExpectations(t)
 }
 
+func TestGetAllTasks_UserSeesOwnTasks(t *testing.T) {
+	mockRepo := new(mocks.MockTaskRepository)
+	tu := usecases.NewTaskUsecases(mockRepo, new(mocks.MockUserRepository), repositories.NewMemoryAuditRepository(), domain.DefaultWorkflow())
+
+	tasks := []domain.Task{{ID: "1", Title: "Task1", OwnerID: owner.UserID}}
+	mockRepo.On("Each", domain.TaskQuery{
+		OwnerID:   owner.UserID,
+		ProjectID: domain.DefaultProjectID,
+		SortBy:    "due_date",
+		Page:      1,
+		PageSize:  domain.DefaultPageSize,
+	}).Return(tasks, nil)
+
+	result, err := tu.GetAllTasks(context.Background(), owner)
+	assert.NoError(t, err)
+	assert.Equal(t, tasks, result)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestListTasks_ScopedToOwner(t *testing.T) {
+	mockRepo := new(mocks.MockTaskRepository)
+	tu := usecases.NewTaskUsecases(mockRepo, new(mocks.MockUserRepository), repositories.NewMemoryAuditRepository(), domain.DefaultWorkflow())
+
+	page := domain.TaskPage{Tasks: []domain.Task{{ID: "1", OwnerID: owner.UserID}}, Total: 1, Page: 1, PageSize: domain.DefaultPageSize}
+	mockRepo.On("List", domain.TaskQuery{
+		OwnerID:   owner.UserID,
+		ProjectID: domain.DefaultProjectID,
+		SortBy:    "due_date",
+		Page:      1,
+		PageSize:  domain.DefaultPageSize,
+	}).Return(page, nil)
+
+	result, err := tu.ListTasks(context.Background(), owner, domain.TaskQuery{OwnerID: other.UserID})
+	assert.NoError(t, err)
+	assert.Equal(t, page, result)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestListTasks_InvalidPageSize(t *testing.T) {
+	mockRepo := new(mocks.MockTaskRepository)
+	tu := usecases.NewTaskUsecases(mockRepo, new(mocks.MockUserRepository), repositories.NewMemoryAuditRepository(), domain.DefaultWorkflow())
+
+	_, err := tu.ListTasks(context.Background(), admin, domain.TaskQuery{PageSize: domain.MaxPageSize + 1})
+	assert.Error(t, err)
+	assert.Equal(t, "invalid page size", err.Error())
+}
+
 func TestGetTaskByID(t *testing.T) {
 	mockRepo := new(mocks.MockTaskRepository)
-	tu := usecases.NewTaskUsecases(mockRepo)
+	tu := usecases.NewTaskUsecases(mockRepo, new(mocks.MockUserRepository), repositories.NewMemoryAuditRepository(), domain.DefaultWorkflow())
 
-	task := domain.Task{ID: "1", Title: "Task1"}
+	task := domain.Task{ID: "1", Title: "Task1", OwnerID: owner.UserID}
 	mockRepo.On("GetByID", "1").Return(task, nil)
 
-	result, err := tu.GetTaskByID("1")
+	result, err := tu.GetTaskByID(context.Background(), owner, "1")
 	assert.NoError(t, err)
 	assert.Equal(t, task, result)
 	mockRepo.AssertExpectations(t)
 }
 
+func TestGetTaskByID_OtherUsersTask(t *testing.T) {
+	mockRepo := new(mocks.MockTaskRepository)
+	tu := usecases.NewTaskUsecases(mockRepo, new(mocks.MockUserRepository), repositories.NewMemoryAuditRepository(), domain.DefaultWorkflow())
+
+	task := domain.Task{ID: "1", Title: "Task1", OwnerID: owner.UserID}
+	mockRepo.On("GetByID", "1").Return(task, nil)
+
+	_, err := tu.GetTaskByID(context.Background(), other, "1")
+	assert.Equal(t, "task not found", err.Error())
+
+	result, err := tu.GetTaskByID(context.Background(), admin, "1")
+	assert.NoError(t, err)
+	assert.Equal(t, task, result)
+}
+
 func TestCreateTask_Valid(t *testing.T) {
 	mockRepo := new(mocks.MockTaskRepository)
-	tu := usecases.NewTaskUsecases(mockRepo)
+	tu := usecases.NewTaskUsecases(mockRepo, new(mocks.MockUserRepository), repositories.NewMemoryAuditRepository(), domain.DefaultWorkflow())
 
-	created := domain.Task{ID: "1", Title: "New Task", Status: "pending"}
-	mockRepo.On("Create", mock.AnythingOfType("domain.Task")).Return(created, nil)
+	created := domain.Task{ID: "1", Title: "New Task", Status: "pending", OwnerID: owner.UserID}
+	mockRepo.On("Create", mock.MatchedBy(func(t domain.Task) bool {
+		return t.OwnerID == owner.UserID
+	})).Return(created, nil)
 
-	result, err := tu.CreateTask("New Task", "", time.Now(), "pending")
+	result, err := tu.CreateTask(context.Background(), owner, usecases.TaskInput{Title: "New Task", DueDate: time.Now(), Status: "pending"})
 	assert.NoError(t, err)
+	created.NextStatuses = []string{domain.StatusInProgress, domain.StatusCompleted}
 	assert.Equal(t, created, result)
 	mockRepo.AssertExpectations(t)
 }
 
 func TestCreateTask_InvalidStatus(t *testing.T) {
 	mockRepo := new(mocks.MockTaskRepository)
-	tu := usecases.NewTaskUsecases(mockRepo)
+	tu := usecases.NewTaskUsecases(mockRepo, new(mocks.MockUserRepository), repositories.NewMemoryAuditRepository(), domain.DefaultWorkflow())
 
-	_, err := tu.CreateTask("Task", "", time.Now(), "invalid")
+	_, err := tu.CreateTask(context.Background(), owner, usecases.TaskInput{Title: "Task", DueDate: time.Now(), Status: "invalid"})
 	assert.Error(t, err)
 	assert.Equal(t, "invalid status", err.Error())
+
+	var domainErr *domain.Error
+	assert.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, domain.CodeValidation, domainErr.Code)
+	assert.Equal(t, []domain.FieldError{{Field: "status", Message: "invalid status"}}, domainErr.Fields)
 }
 
 func TestUpdateTask(t *testing.T) {
 	mockRepo := new(mocks.MockTaskRepository)
-	tu := usecases.NewTaskUsecases(mockRepo)
+	tu := usecases.NewTaskUsecases(mockRepo, new(mocks.MockUserRepository), repositories.NewMemoryAuditRepository(), domain.DefaultWorkflow())
 
-	updated := domain.Task{ID: "1", Title: "Updated"}
-	mockRepo.On("Update", "1", mock.AnythingOfType("domain.Task")).Return(updated, nil)
+	updated := domain.Task{ID: "1", Title: "Updated", OwnerID: owner.UserID}
+	mockRepo.On("GetByID", "1").Return(domain.Task{ID: "1", OwnerID: owner.UserID}, nil)
+	mockRepo.On("GetChildren", "1").Return([]domain.Task{}, nil)
+	mockRepo.On("Update", "1", mock.MatchedBy(func(t domain.Task) bool {
+		return t.OwnerID == owner.UserID
+	})).Return(updated, nil)
 
-	result, err := tu.UpdateTask("1", "Updated", "", time.Now(), "pending")
+	result, err := tu.UpdateTask(context.Background(), owner, "1", usecases.TaskInput{Title: "Updated", DueDate: time.Now(), Status: "pending"})
 	assert.NoError(t, err)
 	assert.Equal(t, updated, result)
 	mockRepo.AssertExpectations(t)
 }
 
+func TestUpdateTask_OtherUsersTask(t *testing.T) {
+	mockRepo := new(mocks.MockTaskRepository)
+	tu := usecases.NewTaskUsecases(mockRepo, new(mocks.MockUserRepository), repositories.NewMemoryAuditRepository(), domain.DefaultWorkflow())
+
+	mockRepo.On("GetByID", "1").Return(domain.Task{ID: "1", OwnerID: owner.UserID}, nil)
+
+	_, err := tu.UpdateTask(context.Background(), other, "1", usecases.TaskInput{Title: "Updated", DueDate: time.Now(), Status: "pending"})
+	assert.Error(t, err)
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
 func TestDeleteTask(t *testing.T) {
 	mockRepo := new(mocks.MockTaskRepository)
-	tu := usecases.NewTaskUsecases(mockRepo)
+	tu := usecases.NewTaskUsecases(mockRepo, new(mocks.MockUserRepository), repositories.NewMemoryAuditRepository(), domain.DefaultWorkflow())
 
+	mockRepo.On("GetByID", "1").Return(domain.Task{ID: "1", OwnerID: owner.UserID}, nil)
+	mockRepo.On("GetChildren", "1").Return([]domain.Task{}, nil)
 	mockRepo.On("Delete", "1").Return(nil)
 
-	err := tu.DeleteTask("1")
+	err := tu.DeleteTask(context.Background(), admin, "1")
 	assert.NoError(t, err)
 	mockRepo.AssertExpectations(t)
 }
+
+func TestCreateTask_InvalidPriority(t *testing.T) {
+	mockRepo := new(mocks.MockTaskRepository)
+	tu := usecases.NewTaskUsecases(mockRepo, new(mocks.MockUserRepository), repositories.NewMemoryAuditRepository(), domain.DefaultWorkflow())
+
+	_, err := tu.CreateTask(context.Background(), owner, usecases.TaskInput{Title: "Task", DueDate: time.Now().Add(time.Hour), Priority: "someday", Status: domain.StatusPending})
+	var domainErr *domain.Error
+	assert.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, "priority", domainErr.Fields[0].Field)
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestCreateTask_NormalizesTags(t *testing.T) {
+	mockRepo := new(mocks.MockTaskRepository)
+	tu := usecases.NewTaskUsecases(mockRepo, new(mocks.MockUserRepository), repositories.NewMemoryAuditRepository(), domain.DefaultWorkflow())
+
+	mockRepo.On("Create", mock.MatchedBy(func(t domain.Task) bool {
+		return t.Priority == domain.PriorityMedium && len(t.Tags) == 1 && t.Tags[0] == "backend"
+	})).Return(domain.Task{ID: "1"}, nil)
+
+	_, err := tu.CreateTask(context.Background(), owner, usecases.TaskInput{Title: "Task", DueDate: time.Now().Add(time.Hour), Tags: []string{" Backend", "backend"}, Status: domain.StatusPending})
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCreateTask_UnknownAssignee(t *testing.T) {
+	mockRepo := new(mocks.MockTaskRepository)
+	mockUserRepo := new(mocks.MockUserRepository)
+	tu := usecases.NewTaskUsecases(mockRepo, mockUserRepo, repositories.NewMemoryAuditRepository(), domain.DefaultWorkflow())
+
+	mockUserRepo.On("GetByID", "u1").Return(domain.User{}, nil)
+	mockUserRepo.On("GetByID", "ghost").Return(domain.User{}, repositories.ErrUserNotFound)
+
+	_, err := tu.CreateTask(context.Background(), owner, usecases.TaskInput{Title: "Task", DueDate: time.Now().Add(time.Hour), AssigneeIDs: []string{"u1", "ghost"}, Status: domain.StatusPending})
+	var domainErr *domain.Error
+	assert.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, domain.CodeValidation, domainErr.Code)
+	assert.Equal(t, "assignee_ids", domainErr.Fields[0].Field)
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestCreateTask_MissingParent(t *testing.T) {
+	mockRepo := new(mocks.MockTaskRepository)
+	tu := usecases.NewTaskUsecases(mockRepo, new(mocks.MockUserRepository), repositories.NewMemoryAuditRepository(), domain.DefaultWorkflow())
+
+	mockRepo.On("GetByID", "p1").Return(domain.Task{}, repositories.ErrNotFound)
+
+	_, err := tu.CreateTask(context.Background(), owner, usecases.TaskInput{Title: "Task", DueDate: time.Now().Add(time.Hour), ParentID: "p1", Status: domain.StatusPending})
+	var domainErr *domain.Error
+	assert.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, domain.CodeValidation, domainErr.Code)
+	assert.Equal(t, "parent_id", domainErr.Fields[0].Field)
+}
+
+func TestUpdateTask_ParentCycle(t *testing.T) {
+	mockRepo := new(mocks.MockTaskRepository)
+	tu := usecases.NewTaskUsecases(mockRepo, new(mocks.MockUserRepository), repositories.NewMemoryAuditRepository(), domain.DefaultWorkflow())
+
+	mockRepo.On("GetByID", "1").Return(domain.Task{ID: "1", OwnerID: owner.UserID}, nil)
+	mockRepo.On("GetByID", "2").Return(domain.Task{ID: "2", OwnerID: owner.UserID, ParentID: "1"}, nil)
+
+	_, err := tu.UpdateTask(context.Background(), owner, "1", usecases.TaskInput{Title: "Task", DueDate: time.Now().Add(time.Hour), ParentID: "2", Status: domain.StatusPending})
+	var domainErr *domain.Error
+	assert.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, "parent_id", domainErr.Fields[0].Field)
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestUpdateTask_CompleteWithIncompleteSubtasks(t *testing.T) {
+	mockRepo := new(mocks.MockTaskRepository)
+	tu := usecases.NewTaskUsecases(mockRepo, new(mocks.MockUserRepository), repositories.NewMemoryAuditRepository(), domain.DefaultWorkflow())
+
+	mockRepo.On("GetByID", "1").Return(domain.Task{ID: "1", OwnerID: owner.UserID}, nil)
+	mockRepo.On("GetChildren", "1").Return([]domain.Task{{ID: "2", ParentID: "1", Status: domain.StatusPending}}, nil)
+
+	_, err := tu.UpdateTask(context.Background(), owner, "1", usecases.TaskInput{Title: "Task", DueDate: time.Now().Add(time.Hour), Status: domain.StatusCompleted})
+	var domainErr *domain.Error
+	assert.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, "status", domainErr.Fields[0].Field)
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestDeleteTask_WithSubtasks(t *testing.T) {
+	mockRepo := new(mocks.MockTaskRepository)
+	tu := usecases.NewTaskUsecases(mockRepo, new(mocks.MockUserRepository), repositories.NewMemoryAuditRepository(), domain.DefaultWorkflow())
+
+	mockRepo.On("GetByID", "1").Return(domain.Task{ID: "1", OwnerID: owner.UserID}, nil)
+	mockRepo.On("GetChildren", "1").Return([]domain.Task{{ID: "2", ParentID: "1"}}, nil)
+
+	err := tu.DeleteTask(context.Background(), owner, "1")
+	assert.ErrorIs(t, err, usecases.ErrTaskHasSubtasks)
+	mockRepo.AssertNotCalled(t, "Delete", mock.Anything)
+}
+
+func TestUpdateTask_IllegalTransition(t *testing.T) {
+	mockRepo := new(mocks.MockTaskRepository)
+	workflow := domain.Workflow{
+		Statuses:    []string{"todo", "review", "done"},
+		Initial:     "todo",
+		Completed:   "done",
+		Transitions: []domain.Transition{{From: "todo", To: "review"}, {From: "review", To: "done"}},
+	}
+	tu := usecases.NewTaskUsecases(mockRepo, new(mocks.MockUserRepository), repositories.NewMemoryAuditRepository(), workflow)
+
+	mockRepo.On("GetByID", "1").Return(domain.Task{ID: "1", OwnerID: owner.UserID, Status: "todo"}, nil)
+
+	_, err := tu.UpdateTask(context.Background(), owner, "1", usecases.TaskInput{Title: "Task", DueDate: time.Now().Add(time.Hour), Status: "done"})
+	var domainErr *domain.Error
+	assert.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, domain.CodeInvalidTransition, domainErr.Code)
+	assert.Equal(t, "cannot move a task from todo to done", domainErr.Message)
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestUpdateTask_ReopenRequiresAdmin(t *testing.T) {
+	mockRepo := new(mocks.MockTaskRepository)
+	tu := usecases.NewTaskUsecases(mockRepo, new(mocks.MockUserRepository), repositories.NewMemoryAuditRepository(), domain.DefaultWorkflow())
+
+	mockRepo.On("GetByID", "1").Return(domain.Task{ID: "1", OwnerID: owner.UserID, Status: domain.StatusCompleted}, nil)
+
+	_, err := tu.UpdateTask(context.Background(), owner, "1", usecases.TaskInput{Title: "Task", DueDate: time.Now().Add(time.Hour), Status: domain.StatusPending})
+	var domainErr *domain.Error
+	assert.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, domain.CodeForbidden, domainErr.Code)
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+
+	mockRepo.On("GetChildren", "1").Return([]domain.Task{}, nil)
+	mockRepo.On("Update", "1", mock.Anything).Return(domain.Task{ID: "1", Status: domain.StatusPending}, nil)
+
+	result, err := tu.UpdateTask(context.Background(), admin, "1", usecases.TaskInput{Title: "Task", DueDate: time.Now().Add(time.Hour), Status: domain.StatusPending})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{domain.StatusInProgress, domain.StatusCompleted}, result.NextStatuses)
+}
+
+func TestGetTaskByID_NextStatuses(t *testing.T) {
+	mockRepo := new(mocks.MockTaskRepository)
+	tu := usecases.NewTaskUsecases(mockRepo, new(mocks.MockUserRepository), repositories.NewMemoryAuditRepository(), domain.DefaultWorkflow())
+
+	mockRepo.On("GetByID", "1").Return(domain.Task{ID: "1", OwnerID: owner.UserID, Status: domain.StatusCompleted}, nil)
+
+	task, err := tu.GetTaskByID(context.Background(), owner, "1")
+	assert.NoError(t, err)
+	assert.Empty(t, task.NextStatuses)
+
+	task, err = tu.GetTaskByID(context.Background(), admin, "1")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{domain.StatusPending, domain.StatusInProgress}, task.NextStatuses)
+}
+
+func TestParseWorkflow_UnknownStatus(t *testing.T) {
+	_, err := domain.ParseWorkflow([]byte(`{"statuses":["todo","done"],"initial":"todo","completed":"done","transitions":[{"from":"todo","to":"archived"}]}`))
+	var domainErr *domain.Error
+	assert.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, "transitions", domainErr.Fields[0].Field)
+}
+
+func TestUpdateTask_StaleVersion(t *testing.T) {
+	mockRepo := new(mocks.MockTaskRepository)
+	tu := usecases.NewTaskUsecases(mockRepo, new(mocks.MockUserRepository), repositories.NewMemoryAuditRepository(), domain.DefaultWorkflow())
+
+	mockRepo.On("GetByID", "1").Return(domain.Task{ID: "1", OwnerID: owner.UserID, Status: domain.StatusPending, Version: 2}, nil)
+
+	_, err := tu.UpdateTask(context.Background(), owner, "1", usecases.TaskInput{Title: "Task", DueDate: time.Now().Add(time.Hour), Version: 1})
+	assert.ErrorIs(t, err, repositories.ErrVersionConflict)
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestUpdateTask_RecordsHistory(t *testing.T) {
+	mockRepo := new(mocks.MockTaskRepository)
+	auditRepo := repositories.NewMemoryAuditRepository()
+	tu := usecases.NewTaskUsecases(mockRepo, new(mocks.MockUserRepository), auditRepo, domain.DefaultWorkflow())
+
+	existing := domain.Task{ID: "1", Title: "Old", Status: domain.StatusPending, Priority: domain.PriorityMedium, OwnerID: owner.UserID, Version: 1}
+	updated := existing
+	updated.Title, updated.Status, updated.Version = "New", domain.StatusInProgress, 2
+	mockRepo.On("GetByID", "1").Return(existing, nil)
+	mockRepo.On("GetChildren", "1").Return([]domain.Task{}, nil)
+	mockRepo.On("Update", "1", mock.Anything).Return(updated, nil)
+
+	_, err := tu.UpdateTask(context.Background(), owner, "1", usecases.TaskInput{Title: "New", DueDate: time.Now().Add(time.Hour), Status: domain.StatusInProgress})
+	assert.NoError(t, err)
+
+	page, err := tu.TaskHistory(context.Background(), owner, "1", domain.AuditQuery{})
+	assert.NoError(t, err)
+	assert.Len(t, page.Entries, 1)
+	entry := page.Entries[0]
+	assert.Equal(t, domain.AuditTaskUpdate, entry.Action)
+	assert.Equal(t, owner.UserID, entry.ActorID)
+	assert.Equal(t, []domain.FieldChange{
+		{Field: "title", Old: json.RawMessage(`"Old"`), New: json.RawMessage(`"New"`)},
+		{Field: "status", Old: json.RawMessage(`"pending"`), New: json.RawMessage(`"in_progress"`)},
+	}, entry.Changes)
+
+	// Other users cannot read the history of tasks they cannot access
+	_, err = tu.TaskHistory(context.Background(), other, "1", domain.AuditQuery{})
+	assert.ErrorIs(t, err, repositories.ErrNotFound)
+}
+
+func TestListTrash_DefaultsToRecentlyDeleted(t *testing.T) {
+	mockRepo := new(mocks.MockTaskRepository)
+	tu := usecases.NewTaskUsecases(mockRepo, new(mocks.MockUserRepository), repositories.NewMemoryAuditRepository(), domain.DefaultWorkflow())
+
+	page := domain.TaskPage{Tasks: []domain.Task{}, Page: 1, PageSize: domain.DefaultPageSize}
+	mockRepo.On("List", domain.TaskQuery{
+		ProjectID: domain.DefaultProjectID,
+		Deleted:   true,
+		SortBy:    "deleted_at",
+		SortDesc:  true,
+		Page:      1,
+		PageSize:  domain.DefaultPageSize,
+	}).Return(page, nil)
+
+	result, err := tu.ListTrash(context.Background(), admin, domain.TaskQuery{})
+	assert.NoError(t, err)
+	assert.Equal(t, page, result)
+}
+
+func TestRestoreTask(t *testing.T) {
+	mockRepo := new(mocks.MockTaskRepository)
+	tu := usecases.NewTaskUsecases(mockRepo, new(mocks.MockUserRepository), repositories.NewMemoryAuditRepository(), domain.DefaultWorkflow())
+
+	deletedAt := time.Now()
+	mockRepo.On("GetDeleted", "1").Return(domain.Task{ID: "1", OwnerID: owner.UserID, Status: domain.StatusPending, DeletedAt: &deletedAt}, nil)
+	restored := domain.Task{ID: "1", OwnerID: owner.UserID, Status: domain.StatusPending, Version: 3}
+	mockRepo.On("Restore", "1").Return(restored, nil)
+
+	result, err := tu.RestoreTask(context.Background(), admin, "1")
+	assert.NoError(t, err)
+	assert.Nil(t, result.DeletedAt)
+	assert.Equal(t, int64(3), result.Version)
+}
+
+func TestRestoreTask_ParentInTrash(t *testing.T) {
+	mockRepo := new(mocks.MockTaskRepository)
+	tu := usecases.NewTaskUsecases(mockRepo, new(mocks.MockUserRepository), repositories.NewMemoryAuditRepository(), domain.DefaultWorkflow())
+
+	deletedAt := time.Now()
+	mockRepo.On("GetDeleted", "2").Return(domain.Task{ID: "2", ParentID: "1", OwnerID: owner.UserID, DeletedAt: &deletedAt}, nil)
+	mockRepo.On("GetByID", "1").Return(domain.Task{}, repositories.ErrNotFound)
+	mockRepo.On("GetDeleted", "1").Return(domain.Task{ID: "1", OwnerID: owner.UserID, DeletedAt: &deletedAt}, nil)
+
+	_, err := tu.RestoreTask(context.Background(), admin, "2")
+	assert.ErrorIs(t, err, usecases.ErrParentDeleted)
+	mockRepo.AssertNotCalled(t, "Restore", mock.Anything)
+}
+
+func TestRestoreTask_ParentPurged(t *testing.T) {
+	mockRepo := new(mocks.MockTaskRepository)
+	tu := usecases.NewTaskUsecases(mockRepo, new(mocks.MockUserRepository), repositories.NewMemoryAuditRepository(), domain.DefaultWorkflow())
+
+	deletedAt := time.Now()
+	mockRepo.On("GetDeleted", "2").Return(domain.Task{ID: "2", ParentID: "1", OwnerID: owner.UserID, DeletedAt: &deletedAt}, nil)
+	mockRepo.On("GetByID", "1").Return(domain.Task{}, repositories.ErrNotFound)
+	mockRepo.On("GetDeleted", "1").Return(domain.Task{}, repositories.ErrNotFound)
+	mockRepo.On("Restore", "2").Return(domain.Task{ID: "2", ParentID: "1", OwnerID: owner.UserID, Version: 3}, nil)
+	mockRepo.On("Update", "2", mock.MatchedBy(func(t domain.Task) bool {
+		return t.ParentID == "" && t.Version == 3
+	})).Return(domain.Task{ID: "2", OwnerID: owner.UserID, Version: 4}, nil)
+
+	result, err := tu.RestoreTask(context.Background(), admin, "2")
+	assert.NoError(t, err)
+	assert.Empty(t, result.ParentID)
+}
+
+func TestPurgeTask_OtherUsersTask(t *testing.T) {
+	mockRepo := new(mocks.MockTaskRepository)
+	tu := usecases.NewTaskUsecases(mockRepo, new(mocks.MockUserRepository), repositories.NewMemoryAuditRepository(), domain.DefaultWorkflow())
+
+	deletedAt := time.Now()
+	mockRepo.On("GetDeleted", "1").Return(domain.Task{ID: "1", OwnerID: owner.UserID, DeletedAt: &deletedAt}, nil)
+
+	err := tu.PurgeTask(context.Background(), other, "1")
+	assert.ErrorIs(t, err, repositories.ErrNotFound)
+	mockRepo.AssertNotCalled(t, "Purge", mock.Anything)
+}
+
+func TestCreateTask_DueDateRules(t *testing.T) {
+	tu := usecases.NewTaskUsecases(new(mocks.MockTaskRepository), new(mocks.MockUserRepository), repositories.NewMemoryAuditRepository(), domain.DefaultWorkflow())
+
+	for due, message := range map[time.Time]string{
+		{}:                             "due date is required",
+		time.Now().Add(-time.Hour):     "due date must not be in the past",
+		time.Now().Add(-2 * time.Hour): "due date must not be in the past",
+	} {
+		_, err := tu.CreateTask(context.Background(), owner, usecases.TaskInput{Title: "Task", DueDate: due})
+		var domainErr *domain.Error
+		assert.ErrorAs(t, err, &domainErr)
+		assert.Equal(t, []domain.FieldError{{Field: "due_date", Message: message}}, domainErr.Fields)
+	}
+}
+
+func TestUpdateTask_KeepsPastDueDate(t *testing.T) {
+	mockRepo := new(mocks.MockTaskRepository)
+	tu := usecases.NewTaskUsecases(mockRepo, new(mocks.MockUserRepository), repositories.NewMemoryAuditRepository(), domain.DefaultWorkflow())
+	due := time.Now().Add(-24 * time.Hour)
+	mockRepo.On("GetByID", "1").Return(domain.Task{ID: "1", OwnerID: owner.UserID, Status: domain.StatusPending, DueDate: due}, nil)
+	mockRepo.On("GetChildren", "1").Return([]domain.Task{}, nil)
+	mockRepo.On("Update", "1", mock.Anything).Return(domain.Task{ID: "1", Status: domain.StatusCompleted, DueDate: due}, nil)
+
+	_, err := tu.UpdateTask(context.Background(), owner, "1", usecases.TaskInput{Title: "Task", DueDate: due, Status: domain.StatusCompleted})
+	assert.NoError(t, err)
+}
@@ -1,22 +1,27 @@
 package usecases_test
 
 import (
+	"context"
+	"encoding/json"
 	domain "task_manager/Domain"
+	repositories "task_manager/Repositories"
 	"task_manager/Tests/mocks"
 	usecases "task_manager/Usecases"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 func TestRegisterUser_FirstUserAdmin(t *testing.T) {
 	mockRepo := new(mocks.MockUserRepository)
-	uu := usecases.NewUserUsecases(mockRepo)
+	uu := usecases.NewUserUsecases(mockRepo, repositories.NewMemoryAuditRepository())
 
 	mockRepo.On("CreateUser", "admin", "pass").Return(domain.User{ID: primitive.NewObjectID(), Username: "admin", Role: "admin"}, nil)
 
-	user, err := uu.RegisterUser("admin", "pass")
+	user, err := uu.RegisterUser(context.Background(), "admin", "pass")
 	assert.NoError(t, err)
 	assert.Equal(t, "admin", user.Role)
 	mockRepo.AssertExpectations(t)
@@ -24,11 +29,11 @@ func TestRegisterUser_FirstUserAdmin(t *testing.T) {
 
 func TestRegisterUser_SubsequentUser(t *testing.T) {
 	mockRepo := new(mocks.MockUserRepository)
-	uu := usecases.NewUserUsecases(mockRepo)
+	uu := usecases.NewUserUsecases(mockRepo, repositories.NewMemoryAuditRepository())
 
 	mockRepo.On("CreateUser", "user", "pass").Return(domain.User{ID: primitive.NewObjectID(), Username: "user", Role: "user"}, nil)
 
-	user, err := uu.RegisterUser("user", "pass")
+	user, err := uu.RegisterUser(context.Background(), "user", "pass")
 	assert.NoError(t, err)
 	assert.Equal(t, "user", user.Role)
 	mockRepo.AssertExpectations(t)
@@ -36,13 +41,13 @@ func TestRegisterUser_SubsequentUser(t *testing.T) {
 
 func TestLoginUser_Success(t *testing.T) {
 	mockRepo := new(mocks.MockUserRepository)
-	uu := usecases.NewUserUsecases(mockRepo)
+	uu := usecases.NewUserUsecases(mockRepo, repositories.NewMemoryAuditRepository())
 
 	user := domain.User{ID: primitive.NewObjectID(), Username: "user", PasswordHash: "hash", Role: "user"}
 	mockRepo.On("GetByUsername", "user").Return(user, nil)
 	mockRepo.On("VerifyPassword", user, "pass").Return(true)
 
-	loggedIn, err := uu.LoginUser("user", "pass")
+	loggedIn, err := uu.LoginUser(context.Background(), "user", "pass", "192.0.2.1")
 	assert.NoError(t, err)
 	assert.Equal(t, user.Username, loggedIn.Username)
 	mockRepo.AssertExpectations(t)
@@ -50,25 +55,130 @@ func TestLoginUser_Success(t *testing.T) {
 
 func TestLoginUser_InvalidCredentials(t *testing.T) {
 	mockRepo := new(mocks.MockUserRepository)
-	uu := usecases.NewUserUsecases(mockRepo)
+	uu := usecases.NewUserUsecases(mockRepo, repositories.NewMemoryAuditRepository())
 
 	user := domain.User{ID: primitive.NewObjectID(), Username: "user", PasswordHash: "hash", Role: "user"}
 	mockRepo.On("GetByUsername", "user").Return(user, nil)
 	mockRepo.On("VerifyPassword", user, "pass").Return(false)
 
-	_, err := uu.LoginUser("user", "pass")
+	_, err := uu.LoginUser(context.Background(), "user", "pass", "192.0.2.1")
 	assert.Error(t, err)
 	assert.Equal(t, "invalid credentials", err.Error())
 	mockRepo.AssertExpectations(t)
 }
 
+func TestLoginUser_UnknownUser(t *testing.T) {
+	mockRepo := new(mocks.MockUserRepository)
+	uu := usecases.NewUserUsecases(mockRepo, repositories.NewMemoryAuditRepository())
+
+	mockRepo.On("GetByUsername", "ghost").Return(domain.User{}, repositories.ErrUserNotFound)
+
+	_, err := uu.LoginUser(context.Background(), "ghost", "pass", "192.0.2.1")
+	assert.ErrorIs(t, err, usecases.ErrInvalidCredentials)
+}
+
+func TestLoginUser_Lockout(t *testing.T) {
+	userRepo := repositories.NewMemoryUserRepository()
+	_, err := userRepo.CreateUser(context.Background(), "alice", "password123")
+	assert.NoError(t, err)
+	uu := usecases.NewUserUsecases(userRepo, repositories.NewMemoryAuditRepository())
+	uu.EnableLockout(repositories.NewMemoryRateLimitStore(), domain.LockoutPolicy{Threshold: 2, Delay: time.Minute, MaxDelay: time.Hour, Window: time.Hour})
+	const attacker, victim = "198.51.100.7", "192.0.2.1"
+
+	_, err = uu.LoginUser(context.Background(), "alice", "wrong", victim)
+	assert.ErrorIs(t, err, usecases.ErrInvalidCredentials)
+	// A success forgets the failures before it
+	_, err = uu.LoginUser(context.Background(), "alice", "password123", victim)
+	assert.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		_, err = uu.LoginUser(context.Background(), "alice", "wrong", attacker)
+		assert.ErrorIs(t, err, usecases.ErrInvalidCredentials)
+	}
+	// Locked logins are refused even with the right password
+	_, err = uu.LoginUser(context.Background(), "alice", "password123", attacker)
+	var domainErr *domain.Error
+	if assert.ErrorAs(t, err, &domainErr) {
+		assert.Equal(t, domain.CodeRateLimited, domainErr.Code)
+		assert.InDelta(t, float64(time.Minute), float64(domainErr.RetryAfter), float64(time.Second))
+	}
+	// The lock only applies to the client that failed; guesses from many
+	// clients are left to the per-username rate limit
+	_, err = uu.LoginUser(context.Background(), "alice", "password123", victim)
+	assert.NoError(t, err)
+
+	// Unknown usernames are locked out alike
+	for i := 0; i < 2; i++ {
+		_, err = uu.LoginUser(context.Background(), "ghost", "wrong", attacker)
+		assert.ErrorIs(t, err, usecases.ErrInvalidCredentials)
+	}
+	_, err = uu.LoginUser(context.Background(), "ghost", "wrong", attacker)
+	assert.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, domain.CodeRateLimited, domainErr.Code)
+}
+
+func TestLockoutPolicy_Backoff(t *testing.T) {
+	policy := domain.LockoutPolicy{Threshold: 3, Delay: time.Minute, MaxDelay: 10 * time.Minute}
+	for failures, want := range []time.Duration{0, 0, 0, time.Minute, 2 * time.Minute, 4 * time.Minute, 8 * time.Minute, 10 * time.Minute, 10 * time.Minute} {
+		assert.Equal(t, want, policy.LockFor(failures), "%d failures", failures)
+	}
+	assert.Zero(t, domain.LockoutPolicy{}.LockFor(100))
+}
+
 func TestPromoteUser(t *testing.T) {
 	mockRepo := new(mocks.MockUserRepository)
-	uu := usecases.NewUserUsecases(mockRepo)
+	auditRepo := repositories.NewMemoryAuditRepository()
+	uu := usecases.NewUserUsecases(mockRepo, auditRepo)
 
+	mockRepo.On("GetByID", "id").Return(domain.User{Role: domain.RoleUser}, nil)
 	mockRepo.On("PromoteUser", "id").Return(nil)
 
-	err := uu.PromoteUser("id")
+	err := uu.PromoteUser(context.Background(), admin, "id")
 	assert.NoError(t, err)
 	mockRepo.AssertExpectations(t)
+
+	page, err := auditRepo.List(context.Background(), domain.AuditQuery{Page: 1, PageSize: 10})
+	assert.NoError(t, err)
+	assert.Len(t, page.Entries, 1)
+	entry := page.Entries[0]
+	assert.Equal(t, domain.AuditUserPromote, entry.Action)
+	assert.Equal(t, admin.UserID, entry.ActorID)
+	assert.Equal(t, "id", entry.TargetID)
+	assert.Equal(t, []domain.FieldChange{{Field: "role", Old: json.RawMessage(`"user"`), New: json.RawMessage(`"admin"`)}}, entry.Changes)
+}
+
+func TestPromoteUser_NotFound(t *testing.T) {
+	mockRepo := new(mocks.MockUserRepository)
+	auditRepo := repositories.NewMemoryAuditRepository()
+	uu := usecases.NewUserUsecases(mockRepo, auditRepo)
+
+	mockRepo.On("GetByID", "missing").Return(domain.User{}, repositories.ErrUserNotFound)
+
+	err := uu.PromoteUser(context.Background(), admin, "missing")
+	assert.ErrorIs(t, err, repositories.ErrUserNotFound)
+	mockRepo.AssertNotCalled(t, "PromoteUser", mock.Anything)
+
+	page, _ := auditRepo.List(context.Background(), domain.AuditQuery{Page: 1, PageSize: 10})
+	assert.Empty(t, page.Entries)
+}
+
+func TestDemoteUser(t *testing.T) {
+	mockRepo := new(mocks.MockUserRepository)
+	uu := usecases.NewUserUsecases(mockRepo, repositories.NewMemoryAuditRepository())
+
+	mockRepo.On("GetByID", "id").Return(domain.User{Role: domain.RoleAdmin}, nil)
+	mockRepo.On("SetRole", "id", domain.RoleUser).Return(nil)
+
+	err := uu.DemoteUser(context.Background(), admin, "id")
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestDemoteUser_Self(t *testing.T) {
+	mockRepo := new(mocks.MockUserRepository)
+	uu := usecases.NewUserUsecases(mockRepo, repositories.NewMemoryAuditRepository())
+
+	err := uu.DemoteUser(context.Background(), admin, admin.UserID)
+	assert.ErrorIs(t, err, usecases.ErrOwnRoleChange)
+	mockRepo.AssertNotCalled(t, "SetRole", mock.Anything, mock.Anything)
 }
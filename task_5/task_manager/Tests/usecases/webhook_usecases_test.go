@@ -0,0 +1,230 @@
+package usecases_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	domain "task_manager/Domain"
+	infrastructure "task_manager/Infrastructure"
+	repositories "task_manager/Repositories"
+	usecases "task_manager/Usecases"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// receivedHook is one request captured by a webhookReceiver.
+type receivedHook struct {
+	header http.Header
+	body   []byte
+}
+
+// webhookReceiver is an httptest endpoint answering with the queued statuses,
+// then 200.
+type webhookReceiver struct {
+	*httptest.Server
+	mu       sync.Mutex
+	statuses []int
+	received []receivedHook
+	got      chan struct{}
+}
+
+func newWebhookReceiver(t *testing.T, statuses ...int) *webhookReceiver {
+	rcv := &webhookReceiver{statuses: statuses, got: make(chan struct{}, 16)}
+	rcv.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		rcv.mu.Lock()
+		rcv.received = append(rcv.received, receivedHook{header: r.Header.Clone(), body: body})
+		status := http.StatusOK
+		if len(rcv.statuses) > 0 {
+			status, rcv.statuses = rcv.statuses[0], rcv.statuses[1:]
+		}
+		rcv.mu.Unlock()
+		w.WriteHeader(status)
+		rcv.got <- struct{}{}
+	}))
+	t.Cleanup(rcv.Close)
+	return rcv
+}
+
+func (rcv *webhookReceiver) requests() []receivedHook {
+	rcv.mu.Lock()
+	defer rcv.mu.Unlock()
+	return append([]receivedHook(nil), rcv.received...)
+}
+
+func newTestWebhookUsecases(maxAttempts int) (*usecases.WebhookUsecases, repositories.IWebhookRepository) {
+	repo := repositories.NewMemoryWebhookRepository()
+	config := usecases.WebhookConfig{MaxAttempts: maxAttempts, InitialBackoff: time.Millisecond, MaxBackoff: 4 * time.Millisecond, PollInterval: 10 * time.Millisecond}
+	return usecases.NewWebhookUsecases(repo, repositories.NewMemoryAuditRepository(), infrastructure.NewWebhookSender(time.Second), config), repo
+}
+
+func createdEvent() domain.TaskEvent {
+	return domain.TaskEvent{ID: 1, Type: domain.EventTaskCreated, Task: &domain.Task{ID: "t1", Title: "Task", NextStatuses: []string{domain.StatusCompleted}}}
+}
+
+func deliveries(t *testing.T, repo repositories.IWebhookRepository, webhookID string) []domain.WebhookDelivery {
+	page, err := repo.ListDeliveries(context.Background(), domain.DeliveryQuery{WebhookID: webhookID, Page: 1, PageSize: 10})
+	require.NoError(t, err)
+	return page.Deliveries
+}
+
+func TestCreateWebhook_Validation(t *testing.T) {
+	wu, _ := newTestWebhookUsecases(3)
+
+	_, err := wu.CreateWebhook(context.Background(), admin, "ftp://example.com", []string{domain.EventTaskCreated})
+	var domainErr *domain.Error
+	assert.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, []domain.FieldError{{Field: "url", Message: "url must be an absolute http or https URL"}}, domainErr.Fields)
+
+	_, err = wu.CreateWebhook(context.Background(), admin, "https://example.com", []string{"task.renamed"})
+	assert.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, []domain.FieldError{{Field: "events", Message: "unknown event: task.renamed"}}, domainErr.Fields)
+
+	hook, err := wu.CreateWebhook(context.Background(), admin, "https://example.com", []string{domain.EventTaskCreated})
+	assert.NoError(t, err)
+	assert.Len(t, hook.Secret, 64)
+	got, err := wu.GetWebhook(context.Background(), hook.ID)
+	assert.NoError(t, err)
+	assert.Empty(t, got.Secret)
+}
+
+func TestWebhook_SignedDelivery(t *testing.T) {
+	rcv := newWebhookReceiver(t)
+	wu, repo := newTestWebhookUsecases(3)
+	ctx := context.Background()
+	hook, err := wu.CreateWebhook(ctx, admin, rcv.URL, []string{domain.EventTaskCreated})
+	require.NoError(t, err)
+	_, err = wu.CreateWebhook(ctx, admin, rcv.URL, []string{domain.EventTaskDeleted})
+	require.NoError(t, err)
+
+	require.NoError(t, wu.Dispatch(ctx, createdEvent()))
+	require.NoError(t, wu.ProcessDue(ctx))
+
+	requests := rcv.requests()
+	require.Len(t, requests, 1)
+	req := requests[0]
+	timestamp := req.header.Get(infrastructure.WebhookTimestampHeader)
+	assert.Equal(t, infrastructure.SignWebhookPayload(hook.Secret, timestamp, req.body), req.header.Get(infrastructure.WebhookSignatureHeader))
+	assert.Equal(t, domain.EventTaskCreated, req.header.Get(infrastructure.WebhookEventHeader))
+
+	var event domain.TaskEvent
+	require.NoError(t, json.Unmarshal(req.body, &event))
+	assert.Equal(t, int64(1), event.ID)
+	assert.Equal(t, "t1", event.Task.ID)
+	assert.Empty(t, event.Task.NextStatuses)
+
+	sent := deliveries(t, repo, hook.ID)
+	require.Len(t, sent, 1)
+	assert.Equal(t, req.header.Get(infrastructure.WebhookDeliveryHeader), sent[0].ID)
+	assert.Equal(t, domain.DeliverySucceeded, sent[0].Status)
+	assert.Equal(t, 1, sent[0].Attempts)
+	assert.Equal(t, http.StatusOK, sent[0].ResponseStatus)
+}
+
+func TestWebhook_RetriesWithBackoff(t *testing.T) {
+	rcv := newWebhookReceiver(t, http.StatusInternalServerError)
+	wu, repo := newTestWebhookUsecases(3)
+	ctx := context.Background()
+	hook, err := wu.CreateWebhook(ctx, admin, rcv.URL, []string{domain.EventTaskCreated})
+	require.NoError(t, err)
+
+	require.NoError(t, wu.Dispatch(ctx, createdEvent()))
+	before := time.Now().UTC()
+	require.NoError(t, wu.ProcessDue(ctx))
+
+	failed := deliveries(t, repo, hook.ID)[0]
+	assert.Equal(t, domain.DeliveryPending, failed.Status)
+	assert.Equal(t, 1, failed.Attempts)
+	assert.Equal(t, http.StatusInternalServerError, failed.ResponseStatus)
+	assert.NotEmpty(t, failed.LastError)
+	assert.False(t, failed.NextAttemptAt.Before(before.Add(time.Millisecond)))
+
+	time.Sleep(5 * time.Millisecond)
+	require.NoError(t, wu.ProcessDue(ctx))
+	retried := deliveries(t, repo, hook.ID)[0]
+	assert.Equal(t, domain.DeliverySucceeded, retried.Status)
+	assert.Equal(t, 2, retried.Attempts)
+	assert.Empty(t, retried.LastError)
+	assert.Len(t, rcv.requests(), 2)
+}
+
+func TestWebhook_FailsAfterMaxAttempts(t *testing.T) {
+	rcv := newWebhookReceiver(t, http.StatusBadGateway, http.StatusBadGateway)
+	wu, repo := newTestWebhookUsecases(2)
+	ctx := context.Background()
+	hook, err := wu.CreateWebhook(ctx, admin, rcv.URL, []string{domain.EventTaskCreated})
+	require.NoError(t, err)
+
+	require.NoError(t, wu.Dispatch(ctx, createdEvent()))
+	require.NoError(t, wu.ProcessDue(ctx))
+	time.Sleep(5 * time.Millisecond)
+	require.NoError(t, wu.ProcessDue(ctx))
+	time.Sleep(5 * time.Millisecond)
+	require.NoError(t, wu.ProcessDue(ctx))
+
+	failed := deliveries(t, repo, hook.ID)[0]
+	assert.Equal(t, domain.DeliveryFailed, failed.Status)
+	assert.Equal(t, 2, failed.Attempts)
+	assert.Equal(t, http.StatusBadGateway, failed.ResponseStatus)
+	assert.Len(t, rcv.requests(), 2)
+}
+
+func TestWebhook_Redeliver(t *testing.T) {
+	rcv := newWebhookReceiver(t)
+	wu, repo := newTestWebhookUsecases(3)
+	ctx := context.Background()
+	hook, err := wu.CreateWebhook(ctx, admin, rcv.URL, []string{domain.EventTaskCreated})
+	require.NoError(t, err)
+	require.NoError(t, wu.Dispatch(ctx, createdEvent()))
+	require.NoError(t, wu.ProcessDue(ctx))
+	original := deliveries(t, repo, hook.ID)[0]
+
+	redelivery, err := wu.Redeliver(ctx, hook.ID, original.ID)
+	require.NoError(t, err)
+	assert.NotEqual(t, original.ID, redelivery.ID)
+	assert.Equal(t, domain.DeliveryPending, redelivery.Status)
+	require.NoError(t, wu.ProcessDue(ctx))
+
+	requests := rcv.requests()
+	require.Len(t, requests, 2)
+	assert.Equal(t, requests[0].body, requests[1].body)
+	assert.Equal(t, redelivery.ID, requests[1].header.Get(infrastructure.WebhookDeliveryHeader))
+
+	other, err := wu.CreateWebhook(ctx, admin, rcv.URL, []string{domain.EventTaskCreated})
+	require.NoError(t, err)
+	_, err = wu.Redeliver(ctx, other.ID, original.ID)
+	assert.ErrorIs(t, err, repositories.ErrDeliveryNotFound)
+}
+
+func TestWebhook_Dispatcher(t *testing.T) {
+	rcv := newWebhookReceiver(t)
+	wu, _ := newTestWebhookUsecases(3)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	_, err := wu.CreateWebhook(ctx, admin, rcv.URL, []string{domain.EventTaskStatusChanged})
+	require.NoError(t, err)
+
+	bus := usecases.NewEventBus(10)
+	wu.StartDispatcher(ctx, bus)
+	// Give the dispatcher time to subscribe before publishing
+	time.Sleep(20 * time.Millisecond)
+	bus.Publish(domain.TaskEvent{Type: domain.EventTaskUpdated, Task: &domain.Task{ID: "t1", Status: domain.StatusCompleted}})
+	bus.Publish(domain.TaskEvent{Type: domain.EventTaskStatusChanged, Task: &domain.Task{ID: "t1", Status: domain.StatusCompleted}, PreviousStatus: domain.StatusPending})
+
+	select {
+	case <-rcv.got:
+	case <-time.After(2 * time.Second):
+		t.Fatal("webhook was not delivered")
+	}
+	var event domain.TaskEvent
+	require.NoError(t, json.Unmarshal(rcv.requests()[0].body, &event))
+	assert.Equal(t, domain.EventTaskStatusChanged, event.Type)
+	assert.Equal(t, domain.StatusPending, event.PreviousStatus)
+}
@@ -0,0 +1,347 @@
+package usecases
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	domain "task_manager/Domain"
+	repositories "task_manager/Repositories"
+)
+
+// sniffLength is how many leading bytes content types are detected from.
+const sniffLength = 512
+
+// AttachmentConfig limits the files that can be attached to tasks.
+type AttachmentConfig struct {
+	// MaxSize is the largest accepted file in bytes.
+	MaxSize int64
+	// AllowedTypes are the accepted content types; "image/*" accepts every image type.
+	AllowedTypes []string
+}
+
+// DefaultAttachmentConfig accepts images, PDFs, text and office documents of up to 10 MiB.
+func DefaultAttachmentConfig() AttachmentConfig {
+	return AttachmentConfig{
+		MaxSize: 10 << 20,
+		AllowedTypes: []string{
+			"image/png", "image/jpeg", "image/gif", "image/webp",
+			"application/pdf", "text/plain", "text/csv", "text/markdown",
+			"application/vnd.openxmlformats-officedocument.wordprocessingml.document",
+			"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+			"application/vnd.openxmlformats-officedocument.presentationml.presentation",
+			"application/vnd.oasis.opendocument.text",
+			"application/vnd.oasis.opendocument.spreadsheet",
+		},
+	}
+}
+
+// Allows reports whether files of the content type are accepted.
+func (c AttachmentConfig) Allows(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	for _, allowed := range c.AllowedTypes {
+		if allowed == mediaType || strings.HasSuffix(allowed, "/*") && strings.HasPrefix(mediaType, strings.TrimSuffix(allowed, "*")) {
+			return true
+		}
+	}
+	return false
+}
+
+// refinedTypes are the content types a file extension may narrow a sniffed
+// type down to, for formats that cannot be told apart by their content.
+var refinedTypes = map[string]map[string]string{
+	"application/zip": {
+		".docx": "application/vnd.openxmlformats-officedocument.wordprocessingml.document",
+		".xlsx": "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+		".pptx": "application/vnd.openxmlformats-officedocument.presentationml.presentation",
+		".odt":  "application/vnd.oasis.opendocument.text",
+		".ods":  "application/vnd.oasis.opendocument.spreadsheet",
+	},
+	"text/plain": {
+		".csv": "text/csv",
+		".md":  "text/markdown",
+	},
+}
+
+// detectContentType determines the type of a file from its leading bytes.
+// The client's claims about the type are not trusted; the file name only
+// refines types that look alike.
+func detectContentType(filename string, head []byte) string {
+	sniffed := http.DetectContentType(head)
+	mediaType, params, err := mime.ParseMediaType(sniffed)
+	if err != nil {
+		return sniffed
+	}
+	if refined, ok := refinedTypes[mediaType][strings.ToLower(path.Ext(filename))]; ok {
+		return mime.FormatMediaType(refined, params)
+	}
+	return sniffed
+}
+
+// AttachmentUpload is a file to attach to a task.
+type AttachmentUpload struct {
+	Filename string
+	Content  io.Reader
+	// SHA256 is the hex-encoded checksum the client expects the content to have, if any.
+	SHA256 string
+}
+
+// AttachmentUsecases handles the files attached to tasks.
+type AttachmentUsecases struct {
+	attachmentRepo repositories.IAttachmentRepository
+	blobs          repositories.IBlobStore
+	taskRepo       repositories.ITaskRepository
+	config         AttachmentConfig
+}
+
+// NewAttachmentUsecases creates a new attachment usecases instance.
+// Metadata is kept in the attachment repository and content in the blob
+// store. Attachments are only reachable through tasks the actor can access.
+func NewAttachmentUsecases(attachmentRepo repositories.IAttachmentRepository, blobs repositories.IBlobStore, taskRepo repositories.ITaskRepository, config AttachmentConfig) *AttachmentUsecases {
+	return &AttachmentUsecases{attachmentRepo: attachmentRepo, blobs: blobs, taskRepo: taskRepo, config: config}
+}
+
+// Config returns the limits attachments must stay within.
+func (au *AttachmentUsecases) Config() AttachmentConfig {
+	return au.config
+}
+
+// UploadAttachment stores a file and attaches it to the task.
+// The content type is detected from the content and must be allowed, the
+// size must stay within the limit and, when the upload names a checksum,
+// the content must match it.
+func (au *AttachmentUsecases) UploadAttachment(ctx context.Context, actor domain.Actor, taskID string, upload AttachmentUpload) (domain.Attachment, error) {
+	task, err := visibleTask(ctx, au.taskRepo, actor, taskID)
+	if err != nil {
+		return domain.Attachment{}, err
+	}
+	if err := checkModify(actor, task); err != nil {
+		return domain.Attachment{}, err
+	}
+	var expected []byte
+	if upload.SHA256 != "" {
+		if expected, err = hex.DecodeString(upload.SHA256); err != nil || len(expected) != sha256.Size {
+			return domain.Attachment{}, domain.NewValidationError("invalid checksum",
+				domain.FieldError{Field: "sha256", Message: "must be a hex-encoded SHA-256 checksum"})
+		}
+	}
+	// Refuse early rather than store content in vain; Create enforces the
+	// limit for uploads racing this one
+	existing, err := au.attachmentRepo.ListByTask(ctx, taskID)
+	if err != nil {
+		return domain.Attachment{}, err
+	}
+	if len(existing) >= domain.MaxAttachmentsPerTask {
+		return domain.Attachment{}, repositories.ErrTooManyAttachments
+	}
+
+	filename := domain.CleanAttachmentName(upload.Filename)
+	content := bufio.NewReaderSize(upload.Content, sniffLength)
+	head, err := content.Peek(sniffLength)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return domain.Attachment{}, fmt.Errorf("failed to read attachment: %w", err)
+	}
+	if len(head) == 0 {
+		return domain.Attachment{}, domain.NewValidationError("file is empty", domain.FieldError{Field: "file", Message: "file is empty"})
+	}
+	contentType := detectContentType(filename, head)
+	if !au.config.Allows(contentType) {
+		return domain.Attachment{}, domain.NewUnsupportedMediaError(fmt.Sprintf("attachments of type %s are not accepted", contentType))
+	}
+
+	key, err := newBlobKey()
+	if err != nil {
+		return domain.Attachment{}, err
+	}
+	checked := &checkedReader{r: content, hash: sha256.New(), limit: au.config.MaxSize}
+	if err := au.blobs.Put(ctx, key, checked); err != nil {
+		if checked.tooLarge {
+			return domain.Attachment{}, domain.NewTooLargeError(fmt.Sprintf("attachments must be at most %d bytes", au.config.MaxSize))
+		}
+		return domain.Attachment{}, err
+	}
+	sum := checked.hash.Sum(nil)
+	if expected != nil && !bytes.Equal(sum, expected) {
+		au.deleteBlob(ctx, key)
+		return domain.Attachment{}, domain.NewValidationError("checksum mismatch",
+			domain.FieldError{Field: "sha256", Message: "does not match the uploaded content"})
+	}
+
+	created, err := au.attachmentRepo.Create(ctx, domain.Attachment{
+		TaskID:      taskID,
+		UploaderID:  actor.UserID,
+		Filename:    filename,
+		ContentType: contentType,
+		Size:        checked.n,
+		SHA256:      hex.EncodeToString(sum),
+		BlobKey:     key,
+		CreatedAt:   time.Now().UTC(),
+	})
+	if err != nil {
+		au.deleteBlob(context.WithoutCancel(ctx), key)
+		return domain.Attachment{}, err
+	}
+	return created, nil
+}
+
+// ListAttachments retrieves the attachments of a task, oldest first.
+func (au *AttachmentUsecases) ListAttachments(ctx context.Context, actor domain.Actor, taskID string) ([]domain.Attachment, error) {
+	if _, err := visibleTask(ctx, au.taskRepo, actor, taskID); err != nil {
+		return nil, err
+	}
+	return au.attachmentRepo.ListByTask(ctx, taskID)
+}
+
+// OpenAttachment retrieves an attachment of the task and a reader of its
+// content, which the caller must close.
+func (au *AttachmentUsecases) OpenAttachment(ctx context.Context, actor domain.Actor, taskID, id string) (domain.Attachment, io.ReadCloser, error) {
+	_, attachment, err := au.find(ctx, actor, taskID, id)
+	if err != nil {
+		return domain.Attachment{}, nil, err
+	}
+	content, err := au.blobs.Open(ctx, attachment.BlobKey)
+	if err != nil {
+		return domain.Attachment{}, nil, fmt.Errorf("failed to open attachment %s: %w", id, err)
+	}
+	return attachment, content, nil
+}
+
+// DeleteAttachment removes an attachment from the task.
+func (au *AttachmentUsecases) DeleteAttachment(ctx context.Context, actor domain.Actor, taskID, id string) error {
+	task, attachment, err := au.find(ctx, actor, taskID, id)
+	if err != nil {
+		return err
+	}
+	if err := checkModify(actor, task); err != nil {
+		return err
+	}
+	// Forget the attachment first; content left behind is only wasted space
+	if err := au.attachmentRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+	au.deleteBlob(ctx, attachment.BlobKey)
+	return nil
+}
+
+// TaskPurged removes the attachments of a task purged from the trash.
+func (au *AttachmentUsecases) TaskPurged(ctx context.Context, taskID string) {
+	if err := au.removeTaskAttachments(ctx, taskID); err != nil {
+		log.Printf("Failed to remove attachments of purged task %s: %v", taskID, err)
+	}
+}
+
+// TrashSwept removes the attachments of every task that no longer exists.
+func (au *AttachmentUsecases) TrashSwept(ctx context.Context) {
+	taskIDs, err := au.attachmentRepo.TaskIDs(ctx)
+	if err != nil {
+		log.Printf("Failed to find attachments of purged tasks: %v", err)
+		return
+	}
+	for _, taskID := range taskIDs {
+		exists, err := au.taskExists(ctx, taskID)
+		if err != nil {
+			log.Printf("Failed to check task %s for attachments: %v", taskID, err)
+			continue
+		}
+		if !exists {
+			au.TaskPurged(ctx, taskID)
+		}
+	}
+}
+
+// taskExists reports whether the task is stored, in the trash or not.
+func (au *AttachmentUsecases) taskExists(ctx context.Context, taskID string) (bool, error) {
+	_, err := au.taskRepo.GetByID(ctx, taskID)
+	if errors.Is(err, repositories.ErrNotFound) {
+		_, err = au.taskRepo.GetDeleted(ctx, taskID)
+	}
+	if errors.Is(err, repositories.ErrNotFound) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// removeTaskAttachments deletes the content and then the metadata of every
+// attachment of the task, so that a failed cleanup can be repeated.
+func (au *AttachmentUsecases) removeTaskAttachments(ctx context.Context, taskID string) error {
+	attachments, err := au.attachmentRepo.ListByTask(ctx, taskID)
+	if err != nil {
+		return err
+	}
+	for _, a := range attachments {
+		if err := au.blobs.Delete(ctx, a.BlobKey); err != nil && !errors.Is(err, repositories.ErrBlobNotFound) {
+			return err
+		}
+		if err := au.attachmentRepo.Delete(ctx, a.ID); err != nil && !errors.Is(err, repositories.ErrAttachmentNotFound) {
+			return err
+		}
+	}
+	return nil
+}
+
+// find returns an attachment of the task visible to the actor, and the task.
+func (au *AttachmentUsecases) find(ctx context.Context, actor domain.Actor, taskID, id string) (domain.Task, domain.Attachment, error) {
+	task, err := visibleTask(ctx, au.taskRepo, actor, taskID)
+	if err != nil {
+		return domain.Task{}, domain.Attachment{}, err
+	}
+	attachment, err := au.attachmentRepo.GetByID(ctx, id)
+	if err != nil {
+		return domain.Task{}, domain.Attachment{}, err
+	}
+	if attachment.TaskID != taskID {
+		return domain.Task{}, domain.Attachment{}, repositories.ErrAttachmentNotFound
+	}
+	return task, attachment, nil
+}
+
+func (au *AttachmentUsecases) deleteBlob(ctx context.Context, key string) {
+	if err := au.blobs.Delete(ctx, key); err != nil && !errors.Is(err, repositories.ErrBlobNotFound) {
+		log.Printf("Failed to delete attachment content %s: %v", key, err)
+	}
+}
+
+func newBlobKey() (string, error) {
+	key := make([]byte, 16)
+	if _, err := rand.Read(key); err != nil {
+		return "", fmt.Errorf("failed to generate attachment key: %w", err)
+	}
+	return hex.EncodeToString(key), nil
+}
+
+// checkedReader hashes and counts the bytes read through it, and fails
+// once more than limit bytes were read.
+type checkedReader struct {
+	r        io.Reader
+	hash     hash.Hash
+	n        int64
+	limit    int64
+	tooLarge bool
+}
+
+func (cr *checkedReader) Read(p []byte) (int, error) {
+	n, err := cr.r.Read(p)
+	cr.n += int64(n)
+	if cr.n > cr.limit {
+		cr.tooLarge = true
+		return 0, errors.New("attachment too large")
+	}
+	cr.hash.Write(p[:n])
+	return n, err
+}
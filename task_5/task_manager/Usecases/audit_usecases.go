@@ -0,0 +1,35 @@
+package usecases
+
+import (
+	"context"
+	"log"
+
+	domain "task_manager/Domain"
+	repositories "task_manager/Repositories"
+)
+
+// AuditUsecases handles reading the audit log.
+type AuditUsecases struct {
+	auditRepo repositories.IAuditRepository
+}
+
+// NewAuditUsecases creates a new audit usecases instance.
+func NewAuditUsecases(auditRepo repositories.IAuditRepository) *AuditUsecases {
+	return &AuditUsecases{auditRepo: auditRepo}
+}
+
+// ListAudit retrieves a filtered page of the audit log, newest first.
+func (au *AuditUsecases) ListAudit(ctx context.Context, q domain.AuditQuery) (domain.AuditPage, error) {
+	if err := q.Normalize(); err != nil {
+		return domain.AuditPage{}, err
+	}
+	return au.auditRepo.List(ctx, q)
+}
+
+// recordAudit appends an entry to the audit log. The change it describes has
+// already been made, so a failure is logged rather than returned.
+func recordAudit(ctx context.Context, auditRepo repositories.IAuditRepository, entry domain.AuditEntry) {
+	if err := auditRepo.Append(ctx, entry); err != nil {
+		log.Printf("failed to record %s of %s %s by %s: %v", entry.Action, entry.TargetType, entry.TargetID, entry.ActorID, err)
+	}
+}
@@ -0,0 +1,126 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	domain "task_manager/Domain"
+	repositories "task_manager/Repositories"
+)
+
+// CommentUsecases handles the discussion threads of tasks.
+type CommentUsecases struct {
+	commentRepo repositories.ICommentRepository
+	taskRepo    repositories.ITaskRepository
+	userRepo    repositories.IUserRepository
+}
+
+// NewCommentUsecases creates a new comment usecases instance.
+// Comments are only reachable through tasks the actor can access, and the
+// user repository resolves the users they mention.
+func NewCommentUsecases(commentRepo repositories.ICommentRepository, taskRepo repositories.ITaskRepository, userRepo repositories.IUserRepository) *CommentUsecases {
+	return &CommentUsecases{commentRepo: commentRepo, taskRepo: taskRepo, userRepo: userRepo}
+}
+
+// AddComment posts a comment by the actor on the task.
+func (cu *CommentUsecases) AddComment(ctx context.Context, actor domain.Actor, taskID, body string) (domain.Comment, error) {
+	if err := cu.checkTask(ctx, actor, taskID); err != nil {
+		return domain.Comment{}, err
+	}
+	comment := domain.Comment{TaskID: taskID, AuthorID: actor.UserID, Body: body, CreatedAt: time.Now().UTC()}
+	if err := cu.prepare(ctx, &comment); err != nil {
+		return domain.Comment{}, err
+	}
+	return cu.commentRepo.Create(ctx, comment)
+}
+
+// ListComments retrieves a page of a task's comments, oldest first.
+func (cu *CommentUsecases) ListComments(ctx context.Context, actor domain.Actor, q domain.CommentQuery) (domain.CommentPage, error) {
+	if err := q.Normalize(); err != nil {
+		return domain.CommentPage{}, err
+	}
+	if err := cu.checkTask(ctx, actor, q.TaskID); err != nil {
+		return domain.CommentPage{}, err
+	}
+	return cu.commentRepo.List(ctx, q)
+}
+
+// EditComment replaces the body of a comment. Only its author and actors
+// who manage comments may edit it.
+func (cu *CommentUsecases) EditComment(ctx context.Context, actor domain.Actor, taskID, id, body string) (domain.Comment, error) {
+	comment, err := cu.authored(ctx, actor, taskID, id)
+	if err != nil {
+		return domain.Comment{}, err
+	}
+	comment.Body = body
+	if err := cu.prepare(ctx, &comment); err != nil {
+		return domain.Comment{}, err
+	}
+	now := time.Now().UTC()
+	comment.EditedAt = &now
+	if err := cu.commentRepo.Update(ctx, comment); err != nil {
+		return domain.Comment{}, err
+	}
+	return comment, nil
+}
+
+// DeleteComment removes a comment. Only its author and actors who manage
+// comments may delete it.
+func (cu *CommentUsecases) DeleteComment(ctx context.Context, actor domain.Actor, taskID, id string) error {
+	if _, err := cu.authored(ctx, actor, taskID, id); err != nil {
+		return err
+	}
+	return cu.commentRepo.Delete(ctx, id)
+}
+
+// checkTask reports tasks the actor cannot access as not found.
+func (cu *CommentUsecases) checkTask(ctx context.Context, actor domain.Actor, taskID string) error {
+	_, err := visibleTask(ctx, cu.taskRepo, actor, taskID)
+	return err
+}
+
+// authored returns a comment on the task that the actor may change.
+func (cu *CommentUsecases) authored(ctx context.Context, actor domain.Actor, taskID, id string) (domain.Comment, error) {
+	if err := cu.checkTask(ctx, actor, taskID); err != nil {
+		return domain.Comment{}, err
+	}
+	comment, err := cu.commentRepo.GetByID(ctx, id)
+	if err != nil {
+		return domain.Comment{}, err
+	}
+	if comment.TaskID != taskID {
+		return domain.Comment{}, repositories.ErrCommentNotFound
+	}
+	if comment.AuthorID != actor.UserID && !actor.Can(domain.PermCommentsManage) {
+		return domain.Comment{}, domain.NewForbiddenError("only the author can change this comment")
+	}
+	return comment, nil
+}
+
+// prepare validates the comment and resolves its mentions. Mentions of
+// unknown usernames are left as plain text.
+func (cu *CommentUsecases) prepare(ctx context.Context, comment *domain.Comment) error {
+	comment.Normalize()
+	if err := comment.Validate(); err != nil {
+		return err
+	}
+	usernames := domain.ParseMentions(comment.Body)
+	if len(usernames) > domain.MaxMentions {
+		return domain.NewValidationError("too many mentions",
+			domain.FieldError{Field: "body", Message: fmt.Sprintf("at most %d users can be mentioned", domain.MaxMentions)})
+	}
+	comment.Mentions = []domain.Mention{}
+	for _, username := range usernames {
+		user, err := cu.userRepo.GetByUsername(ctx, username)
+		if errors.Is(err, repositories.ErrUserNotFound) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		comment.Mentions = append(comment.Mentions, domain.Mention{UserID: user.ID.Hex(), Username: user.Username})
+	}
+	return nil
+}
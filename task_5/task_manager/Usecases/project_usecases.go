@@ -0,0 +1,230 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	domain "task_manager/Domain"
+	repositories "task_manager/Repositories"
+)
+
+var (
+	// ErrLastManager is returned when a change would leave a project without a manager.
+	ErrLastManager error = domain.NewConflictError("a project needs at least one manager")
+	// ErrProjectArchived is returned when changing an archived project.
+	ErrProjectArchived error = domain.NewConflictError("project is archived")
+	// ErrMemberNotFound is returned when removing a user who is not a member.
+	ErrMemberNotFound error = domain.NewNotFoundError("member not found")
+)
+
+// ProjectUsecases handles projects and their members.
+type ProjectUsecases struct {
+	projectRepo repositories.IProjectRepository
+	userRepo    repositories.IUserRepository
+	auditRepo   repositories.IAuditRepository
+}
+
+// NewProjectUsecases creates a new project usecases instance.
+// The user repository is used to check that members exist, and changes are
+// recorded in the audit log.
+func NewProjectUsecases(projectRepo repositories.IProjectRepository, userRepo repositories.IUserRepository, auditRepo repositories.IAuditRepository) *ProjectUsecases {
+	return &ProjectUsecases{projectRepo: projectRepo, userRepo: userRepo, auditRepo: auditRepo}
+}
+
+// ProjectInput carries the client-editable fields of a new project.
+type ProjectInput struct {
+	Name        string
+	Description string
+	Members     []domain.ProjectMember
+}
+
+// CreateProject creates a project. The actor becomes one of its managers
+// unless the members already include them.
+func (pu *ProjectUsecases) CreateProject(ctx context.Context, actor domain.Actor, input ProjectInput) (domain.Project, error) {
+	project := domain.Project{
+		Name:        input.Name,
+		Description: input.Description,
+		Members:     slices.Clone(input.Members),
+		CreatedBy:   actor.UserID,
+		CreatedAt:   time.Now().UTC(),
+	}
+	if _, ok := project.MemberRole(actor.UserID); !ok {
+		project.SetMember(actor.UserID, domain.ProjectRoleManager)
+	}
+	project.Normalize()
+	if err := project.Validate(); err != nil {
+		return domain.Project{}, err
+	}
+	for _, m := range project.Members {
+		if err := pu.checkMember(ctx, m.UserID); err != nil {
+			return domain.Project{}, err
+		}
+	}
+
+	created, err := pu.projectRepo.Create(ctx, project)
+	if err != nil {
+		return domain.Project{}, err
+	}
+	recordAudit(ctx, pu.auditRepo, domain.NewAuditEntry(actor, domain.AuditProjectCreate, domain.AuditTargetProject, created.ID,
+		domain.DiffField("name", "", created.Name)))
+	return created, nil
+}
+
+// ListProjects retrieves the projects the actor is a member of, or every
+// project when the actor manages projects.
+func (pu *ProjectUsecases) ListProjects(ctx context.Context, actor domain.Actor) ([]domain.Project, error) {
+	if actor.Can(domain.PermProjectsManage) {
+		return pu.projectRepo.List(ctx, "")
+	}
+	return pu.projectRepo.List(ctx, actor.UserID)
+}
+
+// GetProject retrieves a project. Projects the actor is not a member of
+// are reported as not found unless the actor manages projects.
+func (pu *ProjectUsecases) GetProject(ctx context.Context, actor domain.Actor, id string) (domain.Project, error) {
+	project, err := pu.projectRepo.GetByID(ctx, id)
+	if err != nil {
+		return domain.Project{}, err
+	}
+	if _, member := project.MemberRole(actor.UserID); !member && !actor.Can(domain.PermProjectsManage) {
+		return domain.Project{}, repositories.ErrProjectNotFound
+	}
+	return project, nil
+}
+
+// ArchiveProject makes a project read-only. Archiving an archived project changes nothing.
+func (pu *ProjectUsecases) ArchiveProject(ctx context.Context, actor domain.Actor, id string) (domain.Project, error) {
+	return pu.setArchived(ctx, actor, id, true)
+}
+
+// UnarchiveProject makes an archived project writable again.
+func (pu *ProjectUsecases) UnarchiveProject(ctx context.Context, actor domain.Actor, id string) (domain.Project, error) {
+	return pu.setArchived(ctx, actor, id, false)
+}
+
+func (pu *ProjectUsecases) setArchived(ctx context.Context, actor domain.Actor, id string, archived bool) (domain.Project, error) {
+	project, err := pu.GetProject(ctx, actor, id)
+	if err != nil {
+		return domain.Project{}, err
+	}
+	if project.IsArchived() == archived {
+		return project, nil
+	}
+	action := domain.AuditProjectUnarchive
+	project.ArchivedAt = nil
+	if archived {
+		action = domain.AuditProjectArchive
+		now := time.Now().UTC()
+		project.ArchivedAt = &now
+	}
+	if err := pu.projectRepo.Update(ctx, project); err != nil {
+		return domain.Project{}, err
+	}
+	recordAudit(ctx, pu.auditRepo, domain.NewAuditEntry(actor, action, domain.AuditTargetProject, id, nil))
+	return project, nil
+}
+
+// SetMember adds a user to the project with the role, or changes the role
+// of a member. Only the project's managers and actors who manage projects
+// may change its members.
+func (pu *ProjectUsecases) SetMember(ctx context.Context, actor domain.Actor, id, userID, role string) (domain.Project, error) {
+	project, err := pu.managed(ctx, actor, id)
+	if err != nil {
+		return domain.Project{}, err
+	}
+	if !domain.IsValidProjectRole(role) {
+		return domain.Project{}, domain.NewValidationError("unknown project role: "+role,
+			domain.FieldError{Field: "role", Message: "unknown project role: " + role})
+	}
+	previous, member := project.MemberRole(userID)
+	if !member {
+		if len(project.Members) >= domain.MaxProjectMembers {
+			return domain.Project{}, domain.NewConflictError("project has too many members")
+		}
+		if _, err := pu.userRepo.GetByID(ctx, userID); err != nil {
+			return domain.Project{}, err
+		}
+	}
+	project.SetMember(userID, role)
+	if !project.HasManager() {
+		return domain.Project{}, ErrLastManager
+	}
+	if err := pu.projectRepo.Update(ctx, project); err != nil {
+		return domain.Project{}, err
+	}
+	recordAudit(ctx, pu.auditRepo, domain.NewAuditEntry(actor, domain.AuditProjectSetMember, domain.AuditTargetProject, id,
+		domain.DiffField("members."+userID, previous, role)))
+	return project, nil
+}
+
+// RemoveMember removes a user from the project. The last manager cannot be removed.
+func (pu *ProjectUsecases) RemoveMember(ctx context.Context, actor domain.Actor, id, userID string) error {
+	project, err := pu.managed(ctx, actor, id)
+	if err != nil {
+		return err
+	}
+	previous, _ := project.MemberRole(userID)
+	if !project.RemoveMember(userID) {
+		return ErrMemberNotFound
+	}
+	if !project.HasManager() {
+		return ErrLastManager
+	}
+	if err := pu.projectRepo.Update(ctx, project); err != nil {
+		return err
+	}
+	recordAudit(ctx, pu.auditRepo, domain.NewAuditEntry(actor, domain.AuditProjectRemoveMember, domain.AuditTargetProject, id,
+		domain.DiffField("members."+userID, previous, "")))
+	return nil
+}
+
+// managed returns a project whose members the actor may change.
+func (pu *ProjectUsecases) managed(ctx context.Context, actor domain.Actor, id string) (domain.Project, error) {
+	project, err := pu.GetProject(ctx, actor, id)
+	if err != nil {
+		return domain.Project{}, err
+	}
+	if role, _ := project.MemberRole(actor.UserID); role != domain.ProjectRoleManager && !actor.Can(domain.PermProjectsManage) {
+		return domain.Project{}, domain.NewForbiddenError("only project managers can change members")
+	}
+	if project.IsArchived() {
+		return domain.Project{}, ErrProjectArchived
+	}
+	return project, nil
+}
+
+// ProjectAccess resolves what the actor may do in the project: members
+// get the permissions of their project role, and actors who manage
+// projects those of a manager. In the default project actors keep the
+// permissions of their own role. Projects the actor cannot enter are
+// reported as not found.
+func (pu *ProjectUsecases) ProjectAccess(ctx context.Context, actor domain.Actor, id string) (domain.ProjectAccess, error) {
+	if id == domain.DefaultProjectID {
+		return domain.ProjectAccess{ProjectID: id, Permissions: actor.Permissions}, nil
+	}
+	project, err := pu.GetProject(ctx, actor, id)
+	if err != nil {
+		return domain.ProjectAccess{}, err
+	}
+	role, member := project.MemberRole(actor.UserID)
+	if !member {
+		role = domain.ProjectRoleManager
+	}
+	return domain.ProjectAccess{
+		ProjectID:   project.ID,
+		Permissions: domain.ProjectRolePermissions(role),
+		ReadOnly:    project.IsArchived(),
+	}, nil
+}
+
+// checkMember reports members who are not users as invalid.
+func (pu *ProjectUsecases) checkMember(ctx context.Context, userID string) error {
+	_, err := pu.userRepo.GetByID(ctx, userID)
+	if errors.Is(err, repositories.ErrUserNotFound) {
+		return domain.NewValidationError("unknown member: "+userID,
+			domain.FieldError{Field: "members", Message: "unknown member: " + userID})
+	}
+	return err
+}
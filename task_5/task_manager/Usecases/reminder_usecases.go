@@ -0,0 +1,154 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	domain "task_manager/Domain"
+	repositories "task_manager/Repositories"
+)
+
+// Notifier delivers task.due_soon and task.overdue reminder events.
+type Notifier interface {
+	Notify(ctx context.Context, event domain.TaskEvent) error
+}
+
+// Notifiers sends every event to each of its notifiers, even when some fail.
+type Notifiers []Notifier
+
+// Notify sends the event to every notifier and joins their errors.
+func (ns Notifiers) Notify(ctx context.Context, event domain.TaskEvent) error {
+	var errs []error
+	for _, n := range ns {
+		if err := n.Notify(ctx, event); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ReminderConfig controls which tasks reminders are sent for.
+type ReminderConfig struct {
+	// Lead is how long before its due date a task gets a task.due_soon
+	// reminder. Zero sends only overdue reminders.
+	Lead time.Duration
+	// Lookback is how long after its due date a task can still get a
+	// task.overdue reminder, e.g. after the server was down.
+	Lookback time.Duration
+}
+
+// DefaultReminderConfig returns the reminder settings used unless configured otherwise.
+func DefaultReminderConfig() ReminderConfig {
+	return ReminderConfig{Lead: 24 * time.Hour, Lookback: 24 * time.Hour}
+}
+
+// ReminderUsecases finds tasks that are about to become due or are overdue
+// and sends one reminder per due date.
+type ReminderUsecases struct {
+	taskRepo     repositories.ITaskRepository
+	reminderRepo repositories.IReminderRepository
+	workflow     domain.Workflow
+	notifier     Notifier
+	config       ReminderConfig
+}
+
+// NewReminderUsecases creates a new reminder usecases instance. Tasks in the
+// workflow's completed status get no reminders.
+func NewReminderUsecases(taskRepo repositories.ITaskRepository, reminderRepo repositories.IReminderRepository, workflow domain.Workflow, notifier Notifier, config ReminderConfig) *ReminderUsecases {
+	return &ReminderUsecases{
+		taskRepo:     taskRepo,
+		reminderRepo: reminderRepo,
+		workflow:     workflow,
+		notifier:     notifier,
+		config:       config,
+	}
+}
+
+// SendReminders sends the reminders that are due at now and returns how many were sent.
+// Each reminder is claimed before it is sent, so concurrent schedulers never
+// send the same one twice. Reminders that fail to send are released and tried
+// again on the next run.
+func (ru *ReminderUsecases) SendReminders(ctx context.Context, now time.Time) (int, error) {
+	since := now.Add(-ru.config.Lookback)
+	// Reminders for older due dates can no longer be claimed again
+	if _, err := ru.reminderRepo.DeleteDueBefore(ctx, since); err != nil {
+		return 0, err
+	}
+
+	q := domain.TaskQuery{
+		DueAfter:      since,
+		DueBefore:     now.Add(ru.config.Lead),
+		ExcludeStatus: ru.workflow.Completed,
+		PageSize:      domain.MaxPageSize,
+	}
+	if err := q.Normalize(); err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for {
+		page, err := ru.taskRepo.List(ctx, q)
+		if err != nil {
+			return sent, err
+		}
+		for _, task := range page.Tasks {
+			eventType := domain.EventTaskDueSoon
+			if !task.DueDate.After(now) {
+				eventType = domain.EventTaskOverdue
+			}
+			ok, err := ru.remind(ctx, task, eventType, now)
+			if err != nil {
+				return sent, err
+			}
+			if ok {
+				sent++
+			}
+		}
+		if page.NextPage == 0 {
+			return sent, nil
+		}
+		q.Page = page.NextPage
+	}
+}
+
+// remind sends the reminder unless it was already claimed, reporting whether it was sent.
+func (ru *ReminderUsecases) remind(ctx context.Context, task domain.Task, eventType string, now time.Time) (bool, error) {
+	reminder := domain.NewReminder(task, eventType, now)
+	claimed, err := ru.reminderRepo.Claim(ctx, reminder)
+	if err != nil || !claimed {
+		return false, err
+	}
+
+	event := domain.TaskEvent{Type: eventType, Task: &task, CreatedAt: now}
+	if err := ru.notifier.Notify(ctx, event); err != nil {
+		log.Printf("Failed to send %s reminder for task %s: %v", eventType, task.ID, err)
+		if err := ru.reminderRepo.Release(ctx, reminder.ID); err != nil {
+			return false, err
+		}
+		return false, nil
+	}
+	return true, nil
+}
+
+// StartReminderScheduler sends due reminders every interval until ctx is done.
+func (ru *ReminderUsecases) StartReminderScheduler(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				n, err := ru.SendReminders(ctx, time.Now().UTC())
+				if err != nil && ctx.Err() == nil {
+					log.Printf("Reminder scheduler failed: %v", err)
+				} else if n > 0 {
+					log.Printf("Sent %d due date reminders", n)
+				}
+			}
+		}
+	}()
+}
@@ -0,0 +1,113 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+
+	domain "task_manager/Domain"
+	repositories "task_manager/Repositories"
+)
+
+var (
+	ErrBuiltInRole   error = domain.NewForbiddenError("built-in roles cannot be changed")
+	ErrOwnRoleChange error = domain.NewForbiddenError("cannot change your own role")
+)
+
+// RoleUsecases handles role and permission business logic.
+type RoleUsecases struct {
+	roleRepo  repositories.IRoleRepository
+	userRepo  repositories.IUserRepository
+	auditRepo repositories.IAuditRepository
+}
+
+// NewRoleUsecases creates a new role usecases instance.
+// Role assignments are recorded in the audit log.
+func NewRoleUsecases(roleRepo repositories.IRoleRepository, userRepo repositories.IUserRepository, auditRepo repositories.IAuditRepository) *RoleUsecases {
+	return &RoleUsecases{roleRepo: roleRepo, userRepo: userRepo, auditRepo: auditRepo}
+}
+
+// ListRoles retrieves the built-in roles followed by the custom roles.
+func (ru *RoleUsecases) ListRoles(ctx context.Context) ([]domain.Role, error) {
+	custom, err := ru.roleRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return append(domain.BuiltInRoles(), custom...), nil
+}
+
+// GetRole retrieves a built-in or custom role by name.
+func (ru *RoleUsecases) GetRole(ctx context.Context, name string) (domain.Role, error) {
+	if role, ok := domain.BuiltInRole(name); ok {
+		return role, nil
+	}
+	return ru.roleRepo.GetByName(ctx, name)
+}
+
+// UserRole returns the role a user currently has.
+func (ru *RoleUsecases) UserRole(ctx context.Context, userID string) (string, error) {
+	user, err := ru.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+	return user.Role, nil
+}
+
+// PermissionsForRole returns the permissions granted by a role.
+// Unknown roles grant no permissions.
+func (ru *RoleUsecases) PermissionsForRole(ctx context.Context, name string) ([]string, error) {
+	role, err := ru.GetRole(ctx, name)
+	if err != nil {
+		if errors.Is(err, repositories.ErrRoleNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return role.Permissions, nil
+}
+
+// SaveRole creates or replaces a custom role after validation.
+func (ru *RoleUsecases) SaveRole(ctx context.Context, role domain.Role) (domain.Role, error) {
+	if _, ok := domain.BuiltInRole(role.Name); ok {
+		return domain.Role{}, ErrBuiltInRole
+	}
+	role.BuiltIn = false
+	if role.Permissions == nil {
+		role.Permissions = []string{}
+	}
+	if err := role.Validate(); err != nil {
+		return domain.Role{}, err
+	}
+	if err := ru.roleRepo.Save(ctx, role); err != nil {
+		return domain.Role{}, err
+	}
+	return role, nil
+}
+
+// DeleteRole deletes a custom role.
+// Users still assigned to it are left without permissions.
+func (ru *RoleUsecases) DeleteRole(ctx context.Context, name string) error {
+	if _, ok := domain.BuiltInRole(name); ok {
+		return ErrBuiltInRole
+	}
+	return ru.roleRepo.Delete(ctx, name)
+}
+
+// AssignRole assigns an existing role to a user.
+func (ru *RoleUsecases) AssignRole(ctx context.Context, actor domain.Actor, userID, roleName string) error {
+	if actor.UserID == userID {
+		return ErrOwnRoleChange
+	}
+	if _, err := ru.GetRole(ctx, roleName); err != nil {
+		return err
+	}
+	user, err := ru.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if err := ru.userRepo.SetRole(ctx, userID, roleName); err != nil {
+		return err
+	}
+	recordAudit(ctx, ru.auditRepo, domain.NewAuditEntry(actor, domain.AuditUserSetRole, domain.AuditTargetUser, userID,
+		domain.DiffField("role", user.Role, roleName)))
+	return nil
+}
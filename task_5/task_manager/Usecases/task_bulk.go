@@ -0,0 +1,194 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	domain "task_manager/Domain"
+	repositories "task_manager/Repositories"
+)
+
+// Kinds of bulk operations.
+const (
+	BulkCreate = repositories.WriteCreate
+	BulkUpdate = repositories.WriteUpdate
+	BulkDelete = repositories.WriteDelete
+)
+
+// MaxBulkOperations bounds the operations of one bulk request.
+const MaxBulkOperations = 100
+
+// BulkOperation is one create, update or delete of a bulk request.
+type BulkOperation struct {
+	Op string
+	ID string // the task to update or delete
+	// Input is the task to create or the update to apply. Input.Version,
+	// when set, must match the task's current version for updates and deletes.
+	Input TaskInput
+	// Err fails the operation before it runs, e.g. when the delivery layer
+	// could not decode it.
+	Err error
+}
+
+// BulkResult is the outcome of one bulk operation: the written task, or why it failed.
+type BulkResult struct {
+	Task domain.Task
+	Err  error
+}
+
+// bulkPlan is a checked operation and the writes that carry it out.
+type bulkPlan struct {
+	existing domain.Task // the task before an update or delete
+	write    int         // index of the operation's write
+	next     int         // index of the next occurrence's write, or -1
+}
+
+// BulkTasks runs a batch of operations and reports the result of each, in
+// order. Every operation is authorized and validated like its single-task
+// counterpart, against the tasks as they were before the batch, and the
+// writes are then sent to the repository together.
+// Operations succeed or fail on their own unless atomic is set, in which
+// case either all of them are applied or none is, and the operations that
+// did not fail report repositories.ErrBulkAborted.
+func (tu *TaskUsecases) BulkTasks(ctx context.Context, actor domain.Actor, ops []BulkOperation, atomic bool) ([]BulkResult, error) {
+	if len(ops) == 0 || len(ops) > MaxBulkOperations {
+		message := fmt.Sprintf("between 1 and %d operations are required", MaxBulkOperations)
+		return nil, domain.NewValidationError(message, domain.FieldError{Field: "operations", Message: message})
+	}
+
+	results := make([]BulkResult, len(ops))
+	plans := make([]bulkPlan, len(ops))
+	var writes []repositories.TaskWrite
+	failed := false
+	for i, op := range ops {
+		plan, opWrites, err := tu.planBulkOperation(ctx, actor, op)
+		if err != nil {
+			results[i].Err = err
+			failed = true
+			continue
+		}
+		// Writes are numbered from the end of the batch so far
+		plan.write += len(writes)
+		if plan.next >= 0 {
+			plan.next += len(writes)
+		}
+		plans[i] = plan
+		writes = append(writes, opWrites...)
+	}
+	if atomic && failed {
+		for i := range results {
+			if results[i].Err == nil {
+				results[i].Err = repositories.ErrBulkAborted
+			}
+		}
+		return results, nil
+	}
+	if len(writes) == 0 {
+		return results, nil
+	}
+
+	written, err := tu.taskRepo.BulkWrite(ctx, writes, atomic)
+	if err != nil {
+		return nil, err
+	}
+	for i, op := range ops {
+		if results[i].Err != nil {
+			continue
+		}
+		plan := plans[i]
+		result := written[plan.write]
+		var next *repositories.TaskWriteResult
+		if plan.next >= 0 {
+			next = &written[plan.next]
+		}
+		if result.Err != nil {
+			results[i].Err = result.Err
+			if next != nil && next.Err == nil && !atomic {
+				tu.discard(ctx, next.Task.ID)
+			}
+			continue
+		}
+
+		switch op.Op {
+		case BulkCreate:
+			tu.recordCreate(ctx, actor, result.Task)
+		case BulkUpdate:
+			tu.recordUpdate(ctx, actor, plan.existing, result.Task)
+			if next != nil {
+				if next.Err != nil {
+					log.Printf("Failed to create the next occurrence of task %s: %v", result.Task.ID, next.Err)
+				} else {
+					tu.recordCreate(ctx, actor, next.Task)
+				}
+			}
+		case BulkDelete:
+			tu.recordDelete(ctx, actor, plan.existing)
+			deleted := plan.existing
+			deleted.Version, deleted.DeletedAt = result.Task.Version, result.Task.DeletedAt
+			deleted.NextStatuses = nil
+			results[i].Task = deleted
+			continue
+		}
+		result.Task.NextStatuses = tu.workflow.NextStatuses(result.Task.Status, actor.Role)
+		results[i].Task = result.Task
+	}
+	return results, nil
+}
+
+// planBulkOperation checks one operation and returns the writes that carry
+// it out, with the plan's indexes relative to those writes.
+func (tu *TaskUsecases) planBulkOperation(ctx context.Context, actor domain.Actor, op BulkOperation) (bulkPlan, []repositories.TaskWrite, error) {
+	if op.Err != nil {
+		return bulkPlan{}, nil, op.Err
+	}
+	plan := bulkPlan{next: -1}
+
+	switch op.Op {
+	case BulkCreate:
+		if !actor.Can(domain.PermTasksCreate) {
+			return bulkPlan{}, nil, domain.NewForbiddenError("permission required: " + domain.PermTasksCreate)
+		}
+		task, err := tu.prepareCreate(ctx, actor, op.Input, false)
+		if err != nil {
+			return bulkPlan{}, nil, err
+		}
+		return plan, []repositories.TaskWrite{{Op: repositories.WriteCreate, Task: task}}, nil
+
+	case BulkUpdate:
+		if !actor.Can(domain.PermTasksUpdate) {
+			return bulkPlan{}, nil, domain.NewForbiddenError("permission required: " + domain.PermTasksUpdate)
+		}
+		existing, task, err := tu.prepareUpdate(ctx, actor, op.ID, op.Input)
+		if err != nil {
+			return bulkPlan{}, nil, err
+		}
+		plan.existing = existing
+		// As in UpdateTask, the next occurrence is written before the update
+		if occurrence, ok := tu.takeNextOccurrence(existing, &task); ok {
+			plan.next, plan.write = 0, 1
+			return plan, []repositories.TaskWrite{
+				{Op: repositories.WriteCreate, Task: occurrence},
+				{Op: repositories.WriteUpdate, Task: task},
+			}, nil
+		}
+		return plan, []repositories.TaskWrite{{Op: repositories.WriteUpdate, Task: task}}, nil
+
+	case BulkDelete:
+		if !actor.Can(domain.PermTasksDelete) {
+			return bulkPlan{}, nil, domain.NewForbiddenError("permission required: " + domain.PermTasksDelete)
+		}
+		existing, err := tu.prepareDelete(ctx, actor, op.ID)
+		if err != nil {
+			return bulkPlan{}, nil, err
+		}
+		if op.Input.Version != 0 && op.Input.Version != existing.Version {
+			return bulkPlan{}, nil, repositories.ErrVersionConflict
+		}
+		plan.existing = existing
+		task := domain.Task{ID: existing.ID, Version: existing.Version}
+		return plan, []repositories.TaskWrite{{Op: repositories.WriteDelete, Task: task}}, nil
+	}
+	return bulkPlan{}, nil, domain.NewValidationError("unknown operation: "+op.Op,
+		domain.FieldError{Field: "op", Message: "op must be create, update or delete"})
+}
@@ -0,0 +1,208 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	domain "task_manager/Domain"
+)
+
+// ErrStreamClosed is returned by a stream that was closed by the server,
+// either on shutdown or because its subscriber fell too far behind.
+var ErrStreamClosed = errors.New("task stream closed")
+
+// DefaultEventHistory is how many recent events the bus keeps so that
+// reconnecting subscribers can resume.
+const DefaultEventHistory = 1000
+
+// subscriberBuffer is how many events a subscriber may fall behind before it is dropped.
+const subscriberBuffer = 64
+
+// EventBus fans task events out to subscribers and keeps the most recent
+// ones for subscribers that resume from an earlier event. It is safe for
+// concurrent use.
+type EventBus struct {
+	mu          sync.Mutex
+	lastID      int64
+	history     []domain.TaskEvent
+	historySize int
+	subscribers map[*Subscription]struct{}
+	closed      bool
+}
+
+// NewEventBus creates a bus that keeps the last historySize events.
+func NewEventBus(historySize int) *EventBus {
+	return &EventBus{historySize: historySize, subscribers: make(map[*Subscription]struct{})}
+}
+
+// Publish assigns the event the next ID and delivers it to every subscriber.
+// Subscribers whose buffer is full are dropped rather than blocking the publisher.
+func (b *EventBus) Publish(event domain.TaskEvent) domain.TaskEvent {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.lastID++
+	event.ID = b.lastID
+	event.CreatedAt = time.Now().UTC()
+	b.history = append(b.history, event)
+	if len(b.history) > b.historySize {
+		b.history = b.history[len(b.history)-b.historySize:]
+	}
+
+	for sub := range b.subscribers {
+		select {
+		case sub.events <- event:
+		default:
+			b.drop(sub)
+		}
+	}
+	return event
+}
+
+// Subscribe starts delivering events to a new subscription. When
+// lastEventID is set, the events published after it are replayed first.
+func (b *EventBus) Subscribe(lastEventID int64) *Subscription {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	sub := &Subscription{bus: b, events: make(chan domain.TaskEvent, subscriberBuffer)}
+	if lastEventID > 0 {
+		sub.replay, sub.reset = b.since(lastEventID)
+	}
+	if b.closed {
+		close(sub.events)
+	} else {
+		b.subscribers[sub] = struct{}{}
+	}
+	return sub
+}
+
+// since returns the kept events published after id, and whether some of
+// them are no longer kept. IDs this bus never issued count as missed.
+func (b *EventBus) since(id int64) ([]domain.TaskEvent, bool) {
+	if id > b.lastID {
+		return nil, true
+	}
+	// History holds consecutive IDs ending at lastID
+	first := b.lastID - int64(len(b.history)) + 1
+	if id < first-1 {
+		return append([]domain.TaskEvent(nil), b.history...), true
+	}
+	return append([]domain.TaskEvent(nil), b.history[id-first+1:]...), false
+}
+
+// Close ends every subscription. Later subscriptions end immediately.
+func (b *EventBus) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.closed = true
+	for sub := range b.subscribers {
+		b.drop(sub)
+	}
+}
+
+// Closed reports whether Close was called.
+func (b *EventBus) Closed() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.closed
+}
+
+func (b *EventBus) drop(sub *Subscription) {
+	if _, ok := b.subscribers[sub]; ok {
+		delete(b.subscribers, sub)
+		close(sub.events)
+	}
+}
+
+// Subscription receives the events published on a bus.
+type Subscription struct {
+	bus    *EventBus
+	events chan domain.TaskEvent
+	replay []domain.TaskEvent
+	reset  bool
+}
+
+// Reset reports whether events after the requested ID were missed.
+func (s *Subscription) Reset() bool {
+	return s.reset
+}
+
+// Next waits for the next event, replayed ones first.
+func (s *Subscription) Next(ctx context.Context) (domain.TaskEvent, error) {
+	if len(s.replay) > 0 {
+		event := s.replay[0]
+		s.replay = s.replay[1:]
+		return event, nil
+	}
+	select {
+	case <-ctx.Done():
+		return domain.TaskEvent{}, ctx.Err()
+	case event, ok := <-s.events:
+		if !ok {
+			return domain.TaskEvent{}, ErrStreamClosed
+		}
+		return event, nil
+	}
+}
+
+// Close stops delivery to the subscription.
+func (s *Subscription) Close() {
+	s.bus.mu.Lock()
+	defer s.bus.mu.Unlock()
+	s.bus.drop(s)
+}
+
+// TaskStream delivers the task events one actor is allowed to see.
+type TaskStream struct {
+	sub      *Subscription
+	actor    domain.Actor
+	workflow domain.Workflow
+}
+
+// StreamTasks subscribes the actor to task events, resuming after
+// lastEventID when it is set.
+func (tu *TaskUsecases) StreamTasks(actor domain.Actor, lastEventID int64) *TaskStream {
+	return &TaskStream{sub: tu.events.Subscribe(lastEventID), actor: actor, workflow: tu.workflow}
+}
+
+// Events returns the bus task changes are published on.
+func (tu *TaskUsecases) Events() *EventBus {
+	return tu.events
+}
+
+// CloseStreams ends every task stream, for use on shutdown.
+func (tu *TaskUsecases) CloseStreams() {
+	tu.events.Close()
+}
+
+// Reset reports whether events after the requested ID were missed.
+func (s *TaskStream) Reset() bool {
+	return s.sub.Reset()
+}
+
+// Next waits for the next event about a task the actor can access.
+func (s *TaskStream) Next(ctx context.Context) (domain.TaskEvent, error) {
+	for {
+		event, err := s.sub.Next(ctx)
+		if err != nil {
+			return domain.TaskEvent{}, err
+		}
+		if !s.actor.CanView(*event.Task) {
+			continue
+		}
+		if event.Type != domain.EventTaskDeleted {
+			task := *event.Task
+			task.NextStatuses = s.workflow.NextStatuses(task.Status, s.actor.Role)
+			event.Task = &task
+		}
+		return event, nil
+	}
+}
+
+// Close stops the stream.
+func (s *TaskStream) Close() {
+	s.sub.Close()
+}
@@ -0,0 +1,98 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	domain "task_manager/Domain"
+	repositories "task_manager/Repositories"
+)
+
+// MaxImportTasks bounds the tasks of one import.
+const MaxImportTasks = 10000
+
+// ExportTasks calls fn with every task visible to the actor that matches
+// the filters of q, in q's sort order. The tasks are read in batches, so
+// exports of any size use bounded memory. It stops at fn's first error.
+func (tu *TaskUsecases) ExportTasks(ctx context.Context, actor domain.Actor, q domain.TaskQuery, fn func(domain.Task) error) error {
+	scopeTasks(actor, &q)
+	if q.Overdue {
+		q.RestrictToOverdue(time.Now().UTC(), tu.workflow.Completed)
+	}
+	if err := q.Normalize(); err != nil {
+		return err
+	}
+	return tu.taskRepo.Each(ctx, q, fn)
+}
+
+// ImportRecord is one task of an import and the line it starts on.
+type ImportRecord struct {
+	Line  int
+	Input TaskInput
+	// Err reports a line the delivery layer could not read.
+	Err error
+}
+
+// ImportError is why the task on a line was not imported.
+type ImportError struct {
+	Line int
+	Err  error
+}
+
+// ImportReport is the outcome of an import.
+type ImportReport struct {
+	DryRun   bool
+	Valid    int // tasks that passed validation
+	Imported int // tasks created
+	Errors   []ImportError
+}
+
+// ImportTasks creates the tasks of an import, owned by the actor. Every task
+// is validated like one created through CreateTask, except that it may be
+// due in the past, and nothing is created unless all of them are valid. On
+// a dry run the tasks are only validated. Tasks that fail to be written are
+// reported alongside the ones that were.
+func (tu *TaskUsecases) ImportTasks(ctx context.Context, actor domain.Actor, records []ImportRecord, dryRun bool) (ImportReport, error) {
+	if len(records) > MaxImportTasks {
+		message := fmt.Sprintf("at most %d tasks can be imported at once", MaxImportTasks)
+		return ImportReport{}, domain.NewValidationError(message, domain.FieldError{Field: "file", Message: message})
+	}
+
+	report := ImportReport{DryRun: dryRun}
+	writes := make([]repositories.TaskWrite, 0, len(records))
+	lines := make([]int, 0, len(records))
+	for _, record := range records {
+		err := record.Err
+		if err == nil {
+			var task domain.Task
+			if task, err = tu.prepareCreate(ctx, actor, record.Input, true); err == nil {
+				writes = append(writes, repositories.TaskWrite{Op: repositories.WriteCreate, Task: task})
+				lines = append(lines, record.Line)
+				continue
+			}
+		}
+		report.Errors = append(report.Errors, ImportError{Line: record.Line, Err: err})
+	}
+	report.Valid = len(writes)
+	if dryRun || len(report.Errors) > 0 {
+		return report, nil
+	}
+
+	for start := 0; start < len(writes); start += MaxBulkOperations {
+		end := min(start+MaxBulkOperations, len(writes))
+		results, err := tu.taskRepo.BulkWrite(ctx, writes[start:end], false)
+		if err != nil {
+			return report, err
+		}
+		for i, result := range results {
+			if result.Err != nil {
+				report.Errors = append(report.Errors, ImportError{Line: lines[start+i], Err: result.Err})
+				continue
+			}
+			tu.recordCreate(ctx, actor, result.Task)
+			report.Imported++
+		}
+	}
+	return report, nil
+}
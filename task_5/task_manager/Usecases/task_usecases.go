@@ -1,65 +1,614 @@
 package usecases
 
 import (
+	"context"
+	"errors"
+	"log"
+	"slices"
 	"time"
 
 	domain "task_manager/Domain"
 	repositories "task_manager/Repositories"
 )
 
+var (
+	// ErrTaskHasSubtasks is returned when deleting a task that still has subtasks.
+	ErrTaskHasSubtasks error = domain.NewConflictError("task has subtasks")
+	// ErrParentDeleted is returned when restoring a subtask whose parent is in the trash.
+	ErrParentDeleted error = domain.NewConflictError("restore the parent task first")
+)
+
 // TaskUsecases handles task-related business logic.
 type TaskUsecases struct {
-	taskRepo repositories.ITaskRepository
+	taskRepo  repositories.ITaskRepository
+	userRepo  repositories.IUserRepository
+	auditRepo repositories.IAuditRepository
+	workflow  domain.Workflow
+	events    *EventBus
+	// purgeListeners clean up what belongs to purged tasks
+	purgeListeners []PurgeListener
+}
+
+// PurgeListener cleans up data kept alongside tasks, such as their
+// attachments, once the tasks are permanently removed.
+type PurgeListener interface {
+	// TaskPurged is called after the task was purged from the trash.
+	TaskPurged(ctx context.Context, taskID string)
+	// TrashSwept is called after expired tasks were purged, whose IDs are not known.
+	TrashSwept(ctx context.Context)
 }
 
 // NewTaskUsecases creates a new task usecases instance.
-func NewTaskUsecases(taskRepo repositories.ITaskRepository) *TaskUsecases {
-	return &TaskUsecases{taskRepo: taskRepo}
+// The user repository is used to check that assignees exist, changes are
+// recorded in the audit log, and the workflow decides which statuses tasks
+// may move between. Every change is also published on the usecases' event bus.
+func NewTaskUsecases(taskRepo repositories.ITaskRepository, userRepo repositories.IUserRepository, auditRepo repositories.IAuditRepository, workflow domain.Workflow) *TaskUsecases {
+	return &TaskUsecases{
+		taskRepo:  taskRepo,
+		userRepo:  userRepo,
+		auditRepo: auditRepo,
+		workflow:  workflow,
+		events:    NewEventBus(DefaultEventHistory),
+	}
+}
+
+// AddPurgeListener registers a listener for purged tasks.
+// It must be called before the usecases are used.
+func (tu *TaskUsecases) AddPurgeListener(l PurgeListener) {
+	tu.purgeListeners = append(tu.purgeListeners, l)
+}
+
+// Workflow returns the workflow tasks follow.
+func (tu *TaskUsecases) Workflow() domain.Workflow {
+	return tu.workflow
+}
+
+// GetAllTasks retrieves the tasks visible to the actor, by due date.
+// Actors with the tasks:manage permission see every task of their project;
+// others see only the tasks they own.
+func (tu *TaskUsecases) GetAllTasks(ctx context.Context, actor domain.Actor) ([]domain.Task, error) {
+	var q domain.TaskQuery
+	scopeTasks(actor, &q)
+	if err := q.Normalize(); err != nil {
+		return nil, err
+	}
+	tasks := []domain.Task{}
+	err := tu.taskRepo.Each(ctx, q, func(t domain.Task) error {
+		tasks = append(tasks, t)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	tu.setNextStatuses(actor, tasks)
+	return tasks, nil
+}
+
+// scopeTasks limits the query to the tasks the actor can view: those of
+// the actor's project and, unless the actor sees all of them, only the
+// actor's own.
+func scopeTasks(actor domain.Actor, q *domain.TaskQuery) {
+	q.ProjectID = actor.Project()
+	if !actor.SeesAllTasks() {
+		q.OwnerID = actor.UserID
+	}
+}
+
+// ListTasks retrieves a filtered, sorted page of the tasks visible to the actor.
+func (tu *TaskUsecases) ListTasks(ctx context.Context, actor domain.Actor, q domain.TaskQuery) (domain.TaskPage, error) {
+	scopeTasks(actor, &q)
+	if q.Overdue {
+		q.RestrictToOverdue(time.Now().UTC(), tu.workflow.Completed)
+	}
+	if err := q.Normalize(); err != nil {
+		return domain.TaskPage{}, err
+	}
+	page, err := tu.taskRepo.List(ctx, q)
+	if err != nil {
+		return domain.TaskPage{}, err
+	}
+	tu.setNextStatuses(actor, page.Tasks)
+	return page, nil
 }
 
-// GetAllTasks retrieves all tasks.
-func (tu *TaskUsecases) GetAllTasks() ([]domain.Task, error) {
-	return tu.taskRepo.GetAll()
+// SearchTasks finds the tasks matching the search text, most relevant
+// first. Like ListTasks, it only searches the actor's project, and only the
+// actor's own tasks unless the actor sees all of them.
+func (tu *TaskUsecases) SearchTasks(ctx context.Context, actor domain.Actor, s domain.TaskSearch) (domain.TaskSearchPage, error) {
+	s.ProjectID = actor.Project()
+	if !actor.SeesAllTasks() {
+		s.OwnerID = actor.UserID
+	}
+	if err := s.Normalize(); err != nil {
+		return domain.TaskSearchPage{}, err
+	}
+	page, err := tu.taskRepo.Search(ctx, s)
+	if err != nil {
+		return domain.TaskSearchPage{}, err
+	}
+	for i := range page.Hits {
+		page.Hits[i].Task.NextStatuses = tu.workflow.NextStatuses(page.Hits[i].Task.Status, actor.Role)
+	}
+	return page, nil
 }
 
 // GetTaskByID retrieves a task by ID.
-func (tu *TaskUsecases) GetTaskByID(id string) (domain.Task, error) {
-	return tu.taskRepo.GetByID(id)
+// Tasks the actor cannot access are reported as not found.
+func (tu *TaskUsecases) GetTaskByID(ctx context.Context, actor domain.Actor, id string) (domain.Task, error) {
+	task, err := visibleTask(ctx, tu.taskRepo, actor, id)
+	if err != nil {
+		return domain.Task{}, err
+	}
+	task.NextStatuses = tu.workflow.NextStatuses(task.Status, actor.Role)
+	return task, nil
+}
+
+// visibleTask retrieves a task, reporting tasks the actor cannot view as not found.
+// Everything that belongs to a task is only reachable through this check.
+func visibleTask(ctx context.Context, taskRepo repositories.ITaskRepository, actor domain.Actor, id string) (domain.Task, error) {
+	task, err := taskRepo.GetByID(ctx, id)
+	if err != nil {
+		return domain.Task{}, err
+	}
+	if !actor.CanView(task) {
+		return domain.Task{}, repositories.ErrNotFound
+	}
+	return task, nil
+}
+
+// checkModify refuses changes to a task the actor can view but not modify.
+func checkModify(actor domain.Actor, task domain.Task) error {
+	if !actor.CanModify(task) {
+		return domain.NewForbiddenError("permission required: " + domain.PermTasksManage)
+	}
+	return nil
+}
+
+// setNextStatuses fills in the statuses the actor may move each task to.
+func (tu *TaskUsecases) setNextStatuses(actor domain.Actor, tasks []domain.Task) {
+	for i := range tasks {
+		tasks[i].NextStatuses = tu.workflow.NextStatuses(tasks[i].Status, actor.Role)
+	}
+}
+
+// TaskInput carries the client-editable fields of a task.
+type TaskInput struct {
+	Title       string
+	Description string
+	DueDate     time.Time
+	Status      string
+	Priority    string
+	Tags        []string
+	AssigneeIDs []string
+	ParentID    string
+	Recurrence  *domain.Recurrence
+
+	// Version is the version the client last read, checked by UpdateTask.
+	// Zero accepts whatever version is current.
+	Version int64
+}
+
+// maxTaskDepth bounds the walk up a subtask's ancestors.
+const maxTaskDepth = 100
+
+// CreateTask creates a new task owned by the actor in the actor's project after validation.
+// New tasks start in the workflow's initial status or one the actor may move to from it.
+func (tu *TaskUsecases) CreateTask(ctx context.Context, actor domain.Actor, input TaskInput) (domain.Task, error) {
+	task, err := tu.prepareCreate(ctx, actor, input, false)
+	if err != nil {
+		return domain.Task{}, err
+	}
+	created, err := tu.taskRepo.Create(ctx, task)
+	if err != nil {
+		return domain.Task{}, err
+	}
+	tu.recordCreate(ctx, actor, created)
+	created.NextStatuses = tu.workflow.NextStatuses(created.Status, actor.Role)
+	return created, nil
 }
 
-// CreateTask creates a new task after validation.
-func (tu *TaskUsecases) CreateTask(title, description string, dueDate time.Time, status string) (domain.Task, error) {
-	task := domain.Task{
-		Title:       title,
-		Description: description,
-		DueDate:     dueDate,
-		Status:      status,
+// prepareCreate builds and checks the task the actor asks to create.
+// Imported tasks may be due in the past.
+func (tu *TaskUsecases) prepareCreate(ctx context.Context, actor domain.Actor, input TaskInput, imported bool) (domain.Task, error) {
+	task := newTask(input)
+	task.OwnerID = actor.UserID
+	task.ProjectID = actor.Project()
+	if task.Status == "" {
+		task.Status = tu.workflow.Initial
 	}
 
-	if err := task.Validate(); err != nil {
+	if err := tu.validate(ctx, actor, &task, imported); err != nil {
+		return domain.Task{}, err
+	}
+	if err := tu.workflow.CheckTransition(tu.workflow.Initial, task.Status, actor.Role); err != nil {
 		return domain.Task{}, err
 	}
+	return task, nil
+}
 
-	return tu.taskRepo.Create(task)
+// recordCreate audits and publishes a created task.
+func (tu *TaskUsecases) recordCreate(ctx context.Context, actor domain.Actor, created domain.Task) {
+	recordAudit(ctx, tu.auditRepo, domain.NewAuditEntry(actor, domain.AuditTaskCreate, domain.AuditTargetTask, created.ID,
+		domain.DiffTasks(domain.Task{}, created)))
+	tu.events.Publish(domain.TaskEvent{Type: domain.EventTaskCreated, Task: &created})
 }
 
 // UpdateTask updates an existing task after validation.
-func (tu *TaskUsecases) UpdateTask(id, title, description string, dueDate time.Time, status string) (domain.Task, error) {
-	task := domain.Task{
-		Title:       title,
-		Description: description,
-		DueDate:     dueDate,
-		Status:      status,
+// The task keeps its original owner and project and, when no status is given, its status.
+// The write fails with ErrVersionConflict if the task changed since input.Version
+// or while the update was being checked.
+// Status changes must follow the workflow, and a task can only be completed
+// once its subtasks are. Completing a recurring task creates its next
+// occurrence, which takes over the recurrence.
+func (tu *TaskUsecases) UpdateTask(ctx context.Context, actor domain.Actor, id string, input TaskInput) (domain.Task, error) {
+	existing, task, err := tu.prepareUpdate(ctx, actor, id, input)
+	if err != nil {
+		return domain.Task{}, err
+	}
+
+	// The next occurrence is created first so that a failure leaves the task open
+	var next *domain.Task
+	if occurrence, ok := tu.takeNextOccurrence(existing, &task); ok {
+		created, err := tu.taskRepo.Create(ctx, occurrence)
+		if err != nil {
+			return domain.Task{}, err
+		}
+		next = &created
 	}
 
-	if err := task.Validate(); err != nil {
+	updated, err := tu.taskRepo.Update(ctx, id, task)
+	if err != nil {
+		if next != nil {
+			tu.discard(ctx, next.ID)
+		}
 		return domain.Task{}, err
 	}
+	tu.recordUpdate(ctx, actor, existing, updated)
+	if next != nil {
+		tu.recordCreate(ctx, actor, *next)
+	}
+	updated.NextStatuses = tu.workflow.NextStatuses(updated.Status, actor.Role)
+	return updated, nil
+}
+
+// prepareUpdate builds and checks the update the actor asks for, returning
+// the task as it is and as it will be written.
+func (tu *TaskUsecases) prepareUpdate(ctx context.Context, actor domain.Actor, id string, input TaskInput) (existing, task domain.Task, err error) {
+	task = newTask(input)
+	task.ID = id
+
+	existing, err = tu.GetTaskByID(ctx, actor, id)
+	if err != nil {
+		return domain.Task{}, domain.Task{}, err
+	}
+	if err := checkModify(actor, existing); err != nil {
+		return domain.Task{}, domain.Task{}, err
+	}
+	if input.Version != 0 && input.Version != existing.Version {
+		return domain.Task{}, domain.Task{}, repositories.ErrVersionConflict
+	}
+	task.OwnerID = existing.OwnerID
+	task.ProjectID = existing.ProjectID
+	task.Version = existing.Version
+	if task.Status == "" {
+		task.Status = existing.Status
+	}
+
+	if err := tu.validate(ctx, actor, &task, false); err != nil {
+		return domain.Task{}, domain.Task{}, err
+	}
+	// Tasks left in a status the workflow no longer defines may move anywhere
+	if tu.workflow.HasStatus(existing.Status) {
+		if err := tu.workflow.CheckTransition(existing.Status, task.Status, actor.Role); err != nil {
+			return domain.Task{}, domain.Task{}, err
+		}
+	}
+	subtasks, err := tu.taskRepo.GetChildren(ctx, id)
+	if err != nil {
+		return domain.Task{}, domain.Task{}, err
+	}
+	if err := tu.workflow.ValidateSubtasks(task, subtasks); err != nil {
+		return domain.Task{}, domain.Task{}, err
+	}
+	return existing, task, nil
+}
 
-	return tu.taskRepo.Update(id, task)
+// recordUpdate audits and publishes an updated task.
+func (tu *TaskUsecases) recordUpdate(ctx context.Context, actor domain.Actor, existing, updated domain.Task) {
+	recordAudit(ctx, tu.auditRepo, domain.NewAuditEntry(actor, domain.AuditTaskUpdate, domain.AuditTargetTask, updated.ID,
+		domain.DiffTasks(existing, updated)))
+	tu.events.Publish(domain.TaskEvent{Type: domain.EventTaskUpdated, Task: &updated})
+	if updated.Status != existing.Status {
+		tu.events.Publish(domain.TaskEvent{Type: domain.EventTaskStatusChanged, Task: &updated, PreviousStatus: existing.Status})
+	}
+}
+
+// takeNextOccurrence builds the next occurrence when the update completes a
+// recurring task, moving the recurrence from the task to the occurrence.
+// It is false when there is no occurrence to create.
+func (tu *TaskUsecases) takeNextOccurrence(existing domain.Task, task *domain.Task) (domain.Task, bool) {
+	if task.Recurrence == nil || !tu.workflow.IsCompleted(task.Status) || tu.workflow.IsCompleted(existing.Status) {
+		return domain.Task{}, false
+	}
+	recurrence, due, ok := task.Recurrence.Next(task.DueDate, time.Now())
+	task.Recurrence = nil
+	if !ok {
+		return domain.Task{}, false
+	}
+	return domain.Task{
+		Title:       task.Title,
+		Description: task.Description,
+		DueDate:     due,
+		Status:      tu.workflow.Initial,
+		Priority:    task.Priority,
+		Tags:        slices.Clone(task.Tags),
+		AssigneeIDs: slices.Clone(task.AssigneeIDs),
+		OwnerID:     task.OwnerID,
+		ProjectID:   task.ProjectID,
+		Recurrence:  &recurrence,
+	}, true
+}
+
+// discard removes a task that was created for a write that then failed.
+func (tu *TaskUsecases) discard(ctx context.Context, id string) {
+	err := tu.taskRepo.Delete(ctx, id)
+	if err == nil {
+		err = tu.taskRepo.Purge(ctx, id)
+	}
+	if err != nil {
+		log.Printf("Failed to discard task %s: %v", id, err)
+	}
+}
+
+// DeleteTask moves a task to the trash.
+// Tasks with subtasks cannot be deleted until their subtasks are.
+func (tu *TaskUsecases) DeleteTask(ctx context.Context, actor domain.Actor, id string) error {
+	existing, err := tu.prepareDelete(ctx, actor, id)
+	if err != nil {
+		return err
+	}
+	if err := tu.taskRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+	tu.recordDelete(ctx, actor, existing)
+	return nil
+}
+
+// prepareDelete checks that the actor may delete the task and returns it.
+func (tu *TaskUsecases) prepareDelete(ctx context.Context, actor domain.Actor, id string) (domain.Task, error) {
+	existing, err := tu.GetTaskByID(ctx, actor, id)
+	if err != nil {
+		return domain.Task{}, err
+	}
+	if err := checkModify(actor, existing); err != nil {
+		return domain.Task{}, err
+	}
+	subtasks, err := tu.taskRepo.GetChildren(ctx, id)
+	if err != nil {
+		return domain.Task{}, err
+	}
+	if len(subtasks) > 0 {
+		return domain.Task{}, ErrTaskHasSubtasks
+	}
+	return existing, nil
+}
+
+// recordDelete audits and publishes a task moved to the trash.
+func (tu *TaskUsecases) recordDelete(ctx context.Context, actor domain.Actor, existing domain.Task) {
+	recordAudit(ctx, tu.auditRepo, domain.NewAuditEntry(actor, domain.AuditTaskDelete, domain.AuditTargetTask, existing.ID,
+		domain.DiffTasks(existing, domain.Task{})))
+	existing.NextStatuses = nil
+	tu.events.Publish(domain.TaskEvent{Type: domain.EventTaskDeleted, Task: &existing})
+}
+
+// ListTrash retrieves a page of the deleted tasks visible to the actor,
+// most recently deleted first unless the query asks for another order.
+func (tu *TaskUsecases) ListTrash(ctx context.Context, actor domain.Actor, q domain.TaskQuery) (domain.TaskPage, error) {
+	q.Deleted = true
+	if q.SortBy == "" {
+		q.SortBy = "deleted_at"
+		q.SortDesc = true
+	}
+	scopeTasks(actor, &q)
+	if err := q.Normalize(); err != nil {
+		return domain.TaskPage{}, err
+	}
+	return tu.taskRepo.List(ctx, q)
+}
+
+// RestoreTask moves a task out of the trash.
+// A subtask can only be restored after its parent; if the parent has been
+// purged, the task is restored as a top-level task instead.
+func (tu *TaskUsecases) RestoreTask(ctx context.Context, actor domain.Actor, id string) (domain.Task, error) {
+	deleted, err := tu.getDeleted(ctx, actor, id)
+	if err != nil {
+		return domain.Task{}, err
+	}
+
+	detach := false
+	if deleted.ParentID != "" {
+		parent, err := tu.taskRepo.GetByID(ctx, deleted.ParentID)
+		switch {
+		case err == nil:
+			if tu.workflow.IsCompleted(parent.Status) && !tu.workflow.IsCompleted(deleted.Status) {
+				return domain.Task{}, invalidParent("a completed task cannot have incomplete subtasks")
+			}
+		case !errors.Is(err, repositories.ErrNotFound):
+			return domain.Task{}, err
+		default:
+			if _, err := tu.taskRepo.GetDeleted(ctx, deleted.ParentID); err == nil {
+				return domain.Task{}, ErrParentDeleted
+			} else if !errors.Is(err, repositories.ErrNotFound) {
+				return domain.Task{}, err
+			}
+			detach = true
+		}
+	}
+
+	restored, err := tu.taskRepo.Restore(ctx, id)
+	if err != nil {
+		return domain.Task{}, err
+	}
+	if detach {
+		restored.ParentID = ""
+		if restored, err = tu.taskRepo.Update(ctx, id, restored); err != nil {
+			return domain.Task{}, err
+		}
+	}
+
+	changes := domain.DiffField("deleted_at", *deleted.DeletedAt, time.Time{})
+	changes = append(changes, domain.DiffField("parent_id", deleted.ParentID, restored.ParentID)...)
+	recordAudit(ctx, tu.auditRepo, domain.NewAuditEntry(actor, domain.AuditTaskRestore, domain.AuditTargetTask, id, changes))
+	// Restored tasks reappear to stream subscribers
+	tu.events.Publish(domain.TaskEvent{Type: domain.EventTaskCreated, Task: &restored})
+	restored.NextStatuses = tu.workflow.NextStatuses(restored.Status, actor.Role)
+	return restored, nil
+}
+
+// PurgeTask permanently removes a task from the trash.
+func (tu *TaskUsecases) PurgeTask(ctx context.Context, actor domain.Actor, id string) error {
+	if _, err := tu.getDeleted(ctx, actor, id); err != nil {
+		return err
+	}
+	if err := tu.taskRepo.Purge(ctx, id); err != nil {
+		return err
+	}
+	recordAudit(ctx, tu.auditRepo, domain.NewAuditEntry(actor, domain.AuditTaskPurge, domain.AuditTargetTask, id, nil))
+	for _, l := range tu.purgeListeners {
+		l.TaskPurged(ctx, id)
+	}
+	return nil
+}
+
+// PurgeExpired permanently removes the tasks that have been in the trash
+// for longer than retention and returns how many were removed.
+func (tu *TaskUsecases) PurgeExpired(ctx context.Context, retention time.Duration) (int64, error) {
+	n, err := tu.taskRepo.PurgeDeletedBefore(ctx, time.Now().Add(-retention))
+	if err != nil {
+		return 0, err
+	}
+	if n > 0 {
+		for _, l := range tu.purgeListeners {
+			l.TrashSwept(ctx)
+		}
+	}
+	return n, nil
+}
+
+// StartTrashSweeper purges expired tasks from the trash every interval until ctx is done.
+func (tu *TaskUsecases) StartTrashSweeper(ctx context.Context, retention, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				n, err := tu.PurgeExpired(ctx, retention)
+				if err != nil {
+					log.Printf("Trash sweep failed: %v", err)
+				} else if n > 0 {
+					log.Printf("Purged %d expired tasks from the trash", n)
+				}
+			}
+		}
+	}()
+}
+
+// getDeleted retrieves a task in the trash.
+// Tasks the actor cannot modify are reported as not found.
+func (tu *TaskUsecases) getDeleted(ctx context.Context, actor domain.Actor, id string) (domain.Task, error) {
+	task, err := tu.taskRepo.GetDeleted(ctx, id)
+	if err != nil {
+		return domain.Task{}, err
+	}
+	if !actor.CanModify(task) {
+		return domain.Task{}, repositories.ErrNotFound
+	}
+	return task, nil
+}
+
+// TaskHistory retrieves a page of the audit entries of a task the actor can access, newest first.
+func (tu *TaskUsecases) TaskHistory(ctx context.Context, actor domain.Actor, id string, q domain.AuditQuery) (domain.AuditPage, error) {
+	if _, err := tu.GetTaskByID(ctx, actor, id); err != nil {
+		return domain.AuditPage{}, err
+	}
+	q.TargetType = domain.AuditTargetTask
+	q.TargetID = id
+	if err := q.Normalize(); err != nil {
+		return domain.AuditPage{}, err
+	}
+	return tu.auditRepo.List(ctx, q)
+}
+
+func newTask(input TaskInput) domain.Task {
+	return domain.Task{
+		Title:       input.Title,
+		Description: input.Description,
+		DueDate:     input.DueDate,
+		Status:      input.Status,
+		Priority:    input.Priority,
+		Tags:        input.Tags,
+		AssigneeIDs: input.AssigneeIDs,
+		ParentID:    input.ParentID,
+		Recurrence:  input.Recurrence,
+	}
+}
+
+// validate normalizes the task and checks it together with its assignees and parent.
+func (tu *TaskUsecases) validate(ctx context.Context, actor domain.Actor, task *domain.Task, imported bool) error {
+	task.Normalize()
+	validate := task.Validate
+	if imported {
+		validate = task.ValidateImported
+	}
+	if err := validate(); err != nil {
+		return err
+	}
+	if err := tu.workflow.CheckStatus(task.Status); err != nil {
+		return err
+	}
+
+	for _, id := range task.AssigneeIDs {
+		if _, err := tu.userRepo.GetByID(ctx, id); err != nil {
+			if errors.Is(err, repositories.ErrUserNotFound) {
+				return domain.NewValidationError("unknown assignee: "+id,
+					domain.FieldError{Field: "assignee_ids", Message: "unknown assignee: " + id})
+			}
+			return err
+		}
+	}
+
+	if task.ParentID == "" {
+		return nil
+	}
+	parent, err := tu.GetTaskByID(ctx, actor, task.ParentID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return invalidParent("parent task not found")
+		}
+		return err
+	}
+	if tu.workflow.IsCompleted(parent.Status) && !tu.workflow.IsCompleted(task.Status) {
+		return invalidParent("a completed task cannot have incomplete subtasks")
+	}
+
+	// Refuse to make the task a descendant of itself
+	for depth := 0; parent.ParentID != ""; depth++ {
+		if parent.ParentID == task.ID || depth == maxTaskDepth {
+			return invalidParent("subtasks cannot form a cycle")
+		}
+		if parent, err = tu.taskRepo.GetByID(ctx, parent.ParentID); err != nil {
+			if errors.Is(err, repositories.ErrNotFound) {
+				return nil
+			}
+			return err
+		}
+	}
+	return nil
 }
 
-// DeleteTask deletes a task by ID.
-func (tu *TaskUsecases) DeleteTask(id string) error {
-	return tu.taskRepo.Delete(id)
+func invalidParent(message string) error {
+	return domain.NewValidationError(message, domain.FieldError{Field: "parent_id", Message: message})
 }
@@ -1,42 +1,113 @@
 package usecases
 
 import (
+	"context"
 	"errors"
+	"time"
 
 	domain "task_manager/Domain"
 	repositories "task_manager/Repositories"
 )
 
+// ErrInvalidCredentials is returned for an unknown username or a wrong password alike.
+var ErrInvalidCredentials error = domain.NewUnauthorizedError("invalid credentials")
+
 // UserUsecases handles user-related business logic.
 type UserUsecases struct {
-	userRepo repositories.IUserRepository
+	userRepo  repositories.IUserRepository
+	auditRepo repositories.IAuditRepository
+	// lockouts records failed logins when lockouts are enabled
+	lockouts repositories.IRateLimitStore
+	lockout  domain.LockoutPolicy
 }
 
 // NewUserUsecases creates a new user usecases instance.
-func NewUserUsecases(userRepo repositories.IUserRepository) *UserUsecases {
-	return &UserUsecases{userRepo: userRepo}
+// Role changes are recorded in the audit log.
+func NewUserUsecases(userRepo repositories.IUserRepository, auditRepo repositories.IAuditRepository) *UserUsecases {
+	return &UserUsecases{userRepo: userRepo, auditRepo: auditRepo}
+}
+
+// EnableLockout locks accounts out of logging in after repeated failed
+// logins, as the policy demands. Failures are recorded in the store.
+func (uu *UserUsecases) EnableLockout(store repositories.IRateLimitStore, policy domain.LockoutPolicy) {
+	uu.lockouts = store
+	uu.lockout = policy
 }
 
 // RegisterUser registers a new user.
-func (uu *UserUsecases) RegisterUser(username, password string) (domain.User, error) {
-	return uu.userRepo.CreateUser(username, password)
+func (uu *UserUsecases) RegisterUser(ctx context.Context, username, password string) (domain.User, error) {
+	return uu.userRepo.CreateUser(ctx, username, password)
 }
 
-// LoginUser authenticates a user and returns the user if successful.
-func (uu *UserUsecases) LoginUser(username, password string) (domain.User, error) {
-	user, err := uu.userRepo.GetByUsername(username)
+// LoginUser authenticates a user logging in from clientIP and returns the
+// user if successful. While lockouts are enabled, failed logins lock the
+// username for that client only, so that nobody can lock other clients out
+// of an account; the per-username rate limit throttles guesses at an
+// account from many clients. A locked login is refused without checking its password.
+// Unknown usernames are locked out like accounts, so that a lockout does not
+// reveal whether an account exists.
+func (uu *UserUsecases) LoginUser(ctx context.Context, username, password, clientIP string) (domain.User, error) {
+	// Client IPs contain no spaces, so the key cannot be confused with another client's
+	key := "login:" + clientIP + " " + username
+	if uu.lockouts != nil {
+		// The attempt counts as a failure until the password has been verified
+		wait, err := uu.lockouts.Attempt(ctx, key, uu.lockout, time.Now())
+		if err != nil {
+			return domain.User{}, err
+		}
+		if wait > 0 {
+			return domain.User{}, domain.NewRateLimitedError("too many failed logins", wait)
+		}
+	}
+
+	user, err := uu.userRepo.GetByUsername(ctx, username)
 	if err != nil {
+		if errors.Is(err, repositories.ErrUserNotFound) {
+			return domain.User{}, ErrInvalidCredentials
+		}
 		return domain.User{}, err
 	}
 
 	if !uu.userRepo.VerifyPassword(user, password) {
-		return domain.User{}, errors.New("invalid credentials")
+		return domain.User{}, ErrInvalidCredentials
 	}
 
+	if uu.lockouts != nil {
+		if err := uu.lockouts.ResetFailures(ctx, key); err != nil {
+			return domain.User{}, err
+		}
+	}
 	return user, nil
 }
 
 // PromoteUser promotes a user to admin.
-func (uu *UserUsecases) PromoteUser(idHex string) error {
-	return uu.userRepo.PromoteUser(idHex)
+func (uu *UserUsecases) PromoteUser(ctx context.Context, actor domain.Actor, idHex string) error {
+	user, err := uu.userRepo.GetByID(ctx, idHex)
+	if err != nil {
+		return err
+	}
+	if err := uu.userRepo.PromoteUser(ctx, idHex); err != nil {
+		return err
+	}
+	recordAudit(ctx, uu.auditRepo, domain.NewAuditEntry(actor, domain.AuditUserPromote, domain.AuditTargetUser, idHex,
+		domain.DiffField("role", user.Role, domain.RoleAdmin)))
+	return nil
+}
+
+// DemoteUser demotes a user to the regular user role.
+// Actors cannot demote themselves.
+func (uu *UserUsecases) DemoteUser(ctx context.Context, actor domain.Actor, idHex string) error {
+	if actor.UserID == idHex {
+		return ErrOwnRoleChange
+	}
+	user, err := uu.userRepo.GetByID(ctx, idHex)
+	if err != nil {
+		return err
+	}
+	if err := uu.userRepo.SetRole(ctx, idHex, domain.RoleUser); err != nil {
+		return err
+	}
+	recordAudit(ctx, uu.auditRepo, domain.NewAuditEntry(actor, domain.AuditUserDemote, domain.AuditTargetUser, idHex,
+		domain.DiffField("role", user.Role, domain.RoleUser)))
+	return nil
 }
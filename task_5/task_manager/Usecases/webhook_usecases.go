@@ -0,0 +1,338 @@
+package usecases
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"time"
+
+	domain "task_manager/Domain"
+	repositories "task_manager/Repositories"
+)
+
+// dueBatchSize is how many due deliveries one ProcessDue call attempts.
+const dueBatchSize = 100
+
+// WebhookSender sends one delivery to its webhook. It returns the HTTP status
+// of the response, if one was received, and an error unless it was a 2xx.
+type WebhookSender interface {
+	Send(ctx context.Context, hook domain.Webhook, delivery domain.WebhookDelivery) (int, error)
+}
+
+// WebhookConfig controls how deliveries are retried.
+type WebhookConfig struct {
+	// MaxAttempts is how many times a delivery is tried before it is marked failed.
+	MaxAttempts int
+	// InitialBackoff is the wait after the first failed attempt; it doubles
+	// after every further failure, up to MaxBackoff.
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	// PollInterval is how often the dispatcher looks for deliveries that are due.
+	PollInterval time.Duration
+}
+
+// DefaultWebhookConfig returns the retry settings used unless configured otherwise.
+func DefaultWebhookConfig() WebhookConfig {
+	return WebhookConfig{
+		MaxAttempts:    8,
+		InitialBackoff: 10 * time.Second,
+		MaxBackoff:     time.Hour,
+		PollInterval:   time.Second,
+	}
+}
+
+// Backoff returns the wait before the attempt following the given number of failed ones.
+func (c WebhookConfig) Backoff(failures int) time.Duration {
+	wait := c.InitialBackoff
+	for i := 1; i < failures && wait < c.MaxBackoff; i++ {
+		wait *= 2
+	}
+	return min(wait, c.MaxBackoff)
+}
+
+// WebhookUsecases handles webhook registration and the delivery of task events to them.
+type WebhookUsecases struct {
+	webhookRepo repositories.IWebhookRepository
+	auditRepo   repositories.IAuditRepository
+	sender      WebhookSender
+	config      WebhookConfig
+	// wake prompts the dispatcher to look for due deliveries before its next poll
+	wake chan struct{}
+}
+
+// NewWebhookUsecases creates a new webhook usecases instance.
+// Registrations and removals are recorded in the audit log.
+func NewWebhookUsecases(webhookRepo repositories.IWebhookRepository, auditRepo repositories.IAuditRepository, sender WebhookSender, config WebhookConfig) *WebhookUsecases {
+	return &WebhookUsecases{
+		webhookRepo: webhookRepo,
+		auditRepo:   auditRepo,
+		sender:      sender,
+		config:      config,
+		wake:        make(chan struct{}, 1),
+	}
+}
+
+// CreateWebhook registers an endpoint for the events. The returned webhook
+// carries the generated signing secret, which is not shown again.
+func (wu *WebhookUsecases) CreateWebhook(ctx context.Context, actor domain.Actor, url string, events []string) (domain.Webhook, error) {
+	hook := domain.Webhook{URL: url, Events: events}
+	hook.Normalize()
+	if err := hook.Validate(); err != nil {
+		return domain.Webhook{}, err
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return domain.Webhook{}, fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+	hook.Secret = hex.EncodeToString(secret)
+	hook.CreatedBy = actor.UserID
+	hook.CreatedAt = time.Now().UTC()
+
+	created, err := wu.webhookRepo.Create(ctx, hook)
+	if err != nil {
+		return domain.Webhook{}, err
+	}
+	recordAudit(ctx, wu.auditRepo, domain.NewAuditEntry(actor, domain.AuditWebhookCreate, domain.AuditTargetWebhook, created.ID,
+		domain.DiffWebhooks(domain.Webhook{}, created)))
+	return created, nil
+}
+
+// ListWebhooks retrieves every webhook, without their secrets.
+func (wu *WebhookUsecases) ListWebhooks(ctx context.Context) ([]domain.Webhook, error) {
+	webhooks, err := wu.webhookRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range webhooks {
+		webhooks[i].Secret = ""
+	}
+	return webhooks, nil
+}
+
+// GetWebhook retrieves a webhook by ID, without its secret.
+func (wu *WebhookUsecases) GetWebhook(ctx context.Context, id string) (domain.Webhook, error) {
+	hook, err := wu.webhookRepo.GetByID(ctx, id)
+	if err != nil {
+		return domain.Webhook{}, err
+	}
+	hook.Secret = ""
+	return hook, nil
+}
+
+// DeleteWebhook removes a webhook and its delivery log.
+func (wu *WebhookUsecases) DeleteWebhook(ctx context.Context, actor domain.Actor, id string) error {
+	hook, err := wu.webhookRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := wu.webhookRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+	recordAudit(ctx, wu.auditRepo, domain.NewAuditEntry(actor, domain.AuditWebhookDelete, domain.AuditTargetWebhook, id,
+		domain.DiffWebhooks(hook, domain.Webhook{})))
+	return nil
+}
+
+// ListDeliveries retrieves a page of a webhook's deliveries, newest first.
+func (wu *WebhookUsecases) ListDeliveries(ctx context.Context, q domain.DeliveryQuery) (domain.DeliveryPage, error) {
+	if err := q.Normalize(); err != nil {
+		return domain.DeliveryPage{}, err
+	}
+	if _, err := wu.webhookRepo.GetByID(ctx, q.WebhookID); err != nil {
+		return domain.DeliveryPage{}, err
+	}
+	return wu.webhookRepo.ListDeliveries(ctx, q)
+}
+
+// Redeliver queues a new delivery of the same payload as an earlier one,
+// whatever the earlier one's outcome.
+func (wu *WebhookUsecases) Redeliver(ctx context.Context, webhookID, deliveryID string) (domain.WebhookDelivery, error) {
+	if _, err := wu.webhookRepo.GetByID(ctx, webhookID); err != nil {
+		return domain.WebhookDelivery{}, err
+	}
+	original, err := wu.webhookRepo.GetDelivery(ctx, deliveryID)
+	if err != nil {
+		return domain.WebhookDelivery{}, err
+	}
+	if original.WebhookID != webhookID {
+		return domain.WebhookDelivery{}, repositories.ErrDeliveryNotFound
+	}
+
+	delivery, err := wu.webhookRepo.CreateDelivery(ctx, newDelivery(webhookID, original.EventID, original.EventType, original.Payload))
+	if err != nil {
+		return domain.WebhookDelivery{}, err
+	}
+	wu.notify()
+	return delivery, nil
+}
+
+// Dispatch queues a delivery of the event to every webhook subscribed to its type.
+func (wu *WebhookUsecases) Dispatch(ctx context.Context, event domain.TaskEvent) error {
+	if !slices.Contains(domain.WebhookEvents, event.Type) {
+		return nil
+	}
+	webhooks, err := wu.webhookRepo.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	if event.Task != nil {
+		// Allowed statuses depend on the viewer, so they are left out
+		task := *event.Task
+		task.NextStatuses = nil
+		event.Task = &task
+	}
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook payload: %w", err)
+	}
+
+	queued := false
+	for _, hook := range webhooks {
+		if !hook.Subscribes(event.Type) {
+			continue
+		}
+		if _, err := wu.webhookRepo.CreateDelivery(ctx, newDelivery(hook.ID, event.ID, event.Type, payload)); err != nil {
+			return err
+		}
+		queued = true
+	}
+	if queued {
+		wu.notify()
+	}
+	return nil
+}
+
+// Notify queues a reminder event for the subscribed webhooks, so that
+// webhooks can serve as a reminder Notifier.
+func (wu *WebhookUsecases) Notify(ctx context.Context, event domain.TaskEvent) error {
+	return wu.Dispatch(ctx, event)
+}
+
+// ProcessDue attempts the deliveries that are due. Failed attempts are
+// rescheduled with exponential backoff until MaxAttempts is reached.
+func (wu *WebhookUsecases) ProcessDue(ctx context.Context) error {
+	due, err := wu.webhookRepo.DueDeliveries(ctx, time.Now().UTC(), dueBatchSize)
+	if err != nil {
+		return err
+	}
+	for _, delivery := range due {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := wu.attempt(ctx, delivery); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (wu *WebhookUsecases) attempt(ctx context.Context, delivery domain.WebhookDelivery) error {
+	hook, err := wu.webhookRepo.GetByID(ctx, delivery.WebhookID)
+	switch {
+	case errors.Is(err, repositories.ErrWebhookNotFound):
+		delivery.Status = domain.DeliveryFailed
+		delivery.LastError = "webhook was deleted"
+	case err != nil:
+		return err
+	default:
+		status, err := wu.sender.Send(ctx, hook, delivery)
+		if ctx.Err() != nil {
+			// Interrupted by shutdown; the attempt is repeated after a restart
+			return ctx.Err()
+		}
+		delivery.Attempts++
+		delivery.ResponseStatus = status
+		delivery.LastError = ""
+		switch {
+		case err == nil:
+			delivery.Status = domain.DeliverySucceeded
+		case delivery.Attempts >= wu.config.MaxAttempts:
+			delivery.Status = domain.DeliveryFailed
+			delivery.LastError = err.Error()
+		default:
+			delivery.LastError = err.Error()
+			delivery.NextAttemptAt = time.Now().UTC().Add(wu.config.Backoff(delivery.Attempts))
+		}
+	}
+
+	delivery.UpdatedAt = time.Now().UTC()
+	return wu.webhookRepo.UpdateDelivery(ctx, delivery)
+}
+
+// StartDispatcher queues deliveries for the events published on the bus and
+// sends due deliveries until ctx is done.
+func (wu *WebhookUsecases) StartDispatcher(ctx context.Context, bus *EventBus) {
+	go wu.listen(ctx, bus)
+	go func() {
+		ticker := time.NewTicker(wu.config.PollInterval)
+		defer ticker.Stop()
+		for {
+			if err := wu.ProcessDue(ctx); err != nil && ctx.Err() == nil {
+				log.Printf("Webhook delivery failed: %v", err)
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			case <-wu.wake:
+			}
+		}
+	}()
+}
+
+// listen dispatches the bus's events until ctx is done or the bus is closed.
+func (wu *WebhookUsecases) listen(ctx context.Context, bus *EventBus) {
+	var lastID int64
+	sub := bus.Subscribe(0)
+	defer func() { sub.Close() }()
+	for {
+		event, err := sub.Next(ctx)
+		if errors.Is(err, ErrStreamClosed) {
+			if bus.Closed() {
+				return
+			}
+			// Dropped for falling behind; resume after the last dispatched event
+			sub = bus.Subscribe(lastID)
+			if sub.Reset() {
+				log.Printf("Webhook dispatcher missed task events after event %d", lastID)
+			}
+			continue
+		}
+		if err != nil {
+			return
+		}
+		lastID = event.ID
+		if err := wu.Dispatch(ctx, event); err != nil {
+			log.Printf("Failed to queue webhook deliveries for event %d: %v", event.ID, err)
+		}
+	}
+}
+
+// notify wakes the dispatcher without waiting for it.
+func (wu *WebhookUsecases) notify() {
+	select {
+	case wu.wake <- struct{}{}:
+	default:
+	}
+}
+
+func newDelivery(webhookID string, eventID int64, eventType string, payload json.RawMessage) domain.WebhookDelivery {
+	now := time.Now().UTC()
+	return domain.WebhookDelivery{
+		WebhookID:     webhookID,
+		EventID:       eventID,
+		EventType:     eventType,
+		Payload:       payload,
+		Status:        domain.DeliveryPending,
+		NextAttemptAt: now,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+}
@@ -1,48 +1,323 @@
 package main
 
 import (
+	"context"
+	"fmt"
 	"log"
+	"net/http"
 	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
 
 	"task_manager/Delivery/routers"
+	"task_manager/Domain"
 	"task_manager/Infrastructure"
-	"task_manager/Repositories"
 	"task_manager/Usecases"
 )
 
 func main() {
-	// MongoDB configuration
-	mongoURI := getEnv("MONGODB_URI", "mongodb://localhost:27017")
-	dbName := getEnv("DB_NAME", "taskmanager")
-	jwtSecret := getEnv("JWT_SECRET", "your-secret-key")
+	// Cancelled on SIGINT or SIGTERM to start a graceful shutdown
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
-	// Initialize repositories
-	taskRepo, err := repositories.NewMongoTaskRepository(mongoURI, dbName, "tasks")
+	// JWT configuration
+	jwtConfig, err := loadJWTConfig()
+	if err != nil {
+		log.Fatalf("Invalid JWT configuration: %v", err)
+	}
+	jwtService, err := infrastructure.NewJWTServiceFromConfig(jwtConfig)
 	if err != nil {
-		log.Fatalf("Failed to connect to task repository: %v", err)
+		log.Fatalf("Failed to initialize JWT service: %v", err)
+	}
+	if interval := getEnv("JWT_KEY_ROTATION_INTERVAL", ""); interval != "" {
+		d, err := time.ParseDuration(interval)
+		if err != nil || d <= 0 {
+			log.Fatalf("Invalid JWT_KEY_ROTATION_INTERVAL %q", interval)
+		}
+		if jwtConfig.Algorithm == infrastructure.AlgorithmHS256 {
+			log.Fatalf("JWT key rotation requires RS256 or EdDSA")
+		}
+		jwtService.StartKeyRotation(ctx, d)
 	}
-	defer taskRepo.Close()
 
-	userRepo, err := repositories.NewMongoUserRepository(mongoURI, dbName, "users")
+	// Initialize repositories
+	store, err := openStorage()
 	if err != nil {
-		log.Fatalf("Failed to connect to user repository: %v", err)
+		log.Fatalf("Failed to open storage: %v", err)
 	}
-	defer userRepo.Close()
 
 	// Initialize infrastructure services
-	jwtService := infrastructure.NewJWTService(jwtSecret)
-	authMiddleware := infrastructure.NewAuthMiddleware(jwtService)
+	tokenService := infrastructure.NewTokenService(jwtService, store.tokens, store.users)
+
+	workflow, err := loadWorkflow()
+	if err != nil {
+		log.Fatalf("Invalid workflow: %v", err)
+	}
 
 	// Initialize usecases
-	taskUsecases := usecases.NewTaskUsecases(taskRepo)
-	userUsecases := usecases.NewUserUsecases(userRepo)
+	taskUsecases := usecases.NewTaskUsecases(store.tasks, store.users, store.audit, workflow)
+	userUsecases := usecases.NewUserUsecases(store.users, store.audit)
+	roleUsecases := usecases.NewRoleUsecases(store.roles, store.users, store.audit)
+	auditUsecases := usecases.NewAuditUsecases(store.audit)
+	commentUsecases := usecases.NewCommentUsecases(store.comments, store.tasks, store.users)
+	projectUsecases := usecases.NewProjectUsecases(store.projects, store.users, store.audit)
+	attachmentUsecases, err := newAttachmentUsecases(store)
+	if err != nil {
+		log.Fatalf("Invalid attachment configuration: %v", err)
+	}
+	taskUsecases.AddPurgeListener(attachmentUsecases)
+	webhookUsecases, err := newWebhookUsecases(store)
+	if err != nil {
+		log.Fatalf("Invalid webhook configuration: %v", err)
+	}
+	webhookUsecases.StartDispatcher(ctx, taskUsecases.Events())
+
+	if err := startReminderScheduler(ctx, store, workflow, webhookUsecases); err != nil {
+		log.Fatalf("Invalid reminder configuration: %v", err)
+	}
+
+	if err := startTrashSweeper(ctx, taskUsecases); err != nil {
+		log.Fatalf("Invalid trash configuration: %v", err)
+	}
+
+	authMiddleware := infrastructure.NewAuthMiddleware(jwtService, store.tokens, roleUsecases)
+	rateLimiter, err := newRateLimiter(store, userUsecases)
+	if err != nil {
+		log.Fatalf("Invalid rate limit configuration: %v", err)
+	}
 
 	// Setup router
-	r := routers.SetupRouter(taskUsecases, userUsecases, jwtService, authMiddleware)
+	r := routers.SetupRouter(taskUsecases, userUsecases, roleUsecases, auditUsecases, webhookUsecases, commentUsecases, attachmentUsecases, projectUsecases, tokenService, authMiddleware, rateLimiter)
+	// Client IPs, which rate limits are counted by, are only taken from
+	// forwarding headers set by TRUSTED_PROXIES
+	var proxies []string
+	for _, proxy := range strings.Split(getEnv("TRUSTED_PROXIES", ""), ",") {
+		if proxy = strings.TrimSpace(proxy); proxy != "" {
+			proxies = append(proxies, proxy)
+		}
+	}
+	if err := r.SetTrustedProxies(proxies); err != nil {
+		log.Fatalf("Invalid TRUSTED_PROXIES: %v", err)
+	}
+
+	shutdownTimeout, err := time.ParseDuration(getEnv("SHUTDOWN_TIMEOUT", "15s"))
+	if err != nil || shutdownTimeout <= 0 {
+		log.Fatalf("Invalid SHUTDOWN_TIMEOUT %q", getEnv("SHUTDOWN_TIMEOUT", ""))
+	}
 
 	// Run server
-	log.Println("Server starting on :8080")
-	r.Run(":8080")
+	srv := &http.Server{Addr: ":8080", Handler: r}
+	// Streams never finish on their own, so end them when shutdown begins
+	srv.RegisterOnShutdown(taskUsecases.CloseStreams)
+	serverErr := make(chan error, 1)
+	go func() {
+		log.Println("Server starting on :8080")
+		serverErr <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-serverErr:
+		store.Close(context.Background())
+		log.Fatalf("Server failed: %v", err)
+	case <-ctx.Done():
+	}
+	stop()
+
+	// Stop accepting requests, drain in-flight ones, then release storage
+	log.Println("Shutting down server")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		log.Printf("Server shutdown incomplete: %v", err)
+	}
+	store.Close(shutdownCtx)
+	log.Println("Server stopped")
+}
+
+// defaultJWTSecret is the placeholder secret the server refuses to run with.
+const defaultJWTSecret = "your-secret-key"
+
+// loadJWTConfig reads the JWT signing configuration from the environment.
+func loadJWTConfig() (infrastructure.JWTConfig, error) {
+	cfg := infrastructure.JWTConfig{
+		Algorithm: getEnv("JWT_ALGORITHM", infrastructure.AlgorithmHS256),
+		Secret:    os.Getenv("JWT_SECRET"),
+		Issuer:    getEnv("JWT_ISSUER", infrastructure.DefaultIssuer),
+		Audience:  getEnv("JWT_AUDIENCE", infrastructure.DefaultAudience),
+	}
+
+	if cfg.Algorithm == infrastructure.AlgorithmHS256 {
+		if cfg.Secret == "" || cfg.Secret == defaultJWTSecret {
+			return cfg, fmt.Errorf("JWT_SECRET must be set to a non-default value when using HS256")
+		}
+		return cfg, nil
+	}
+
+	// Comma-separated PEM files; the last one signs new tokens
+	for _, path := range strings.Split(os.Getenv("JWT_PRIVATE_KEY_FILES"), ",") {
+		path = strings.TrimSpace(path)
+		if path == "" {
+			continue
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return cfg, fmt.Errorf("failed to read key file %s: %v", path, err)
+		}
+		key, err := infrastructure.ParsePrivateKeyPEM(data)
+		if err != nil {
+			return cfg, fmt.Errorf("invalid key file %s: %v", path, err)
+		}
+		cfg.Keys = append(cfg.Keys, key)
+	}
+	return cfg, nil
+}
+
+// loadWorkflow reads the task workflow from WORKFLOW_FILE, falling back
+// to the default pending, in_progress and completed workflow.
+func loadWorkflow() (domain.Workflow, error) {
+	path := os.Getenv("WORKFLOW_FILE")
+	if path == "" {
+		return domain.DefaultWorkflow(), nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return domain.Workflow{}, fmt.Errorf("failed to read %s: %v", path, err)
+	}
+	return domain.ParseWorkflow(data)
+}
+
+// startTrashSweeper purges tasks that have been in the trash for longer than
+// TRASH_RETENTION, checking every TRASH_SWEEP_INTERVAL. A retention of 0 keeps them forever.
+func startTrashSweeper(ctx context.Context, taskUsecases *usecases.TaskUsecases) error {
+	retention, err := time.ParseDuration(getEnv("TRASH_RETENTION", "720h"))
+	if err != nil || retention < 0 {
+		return fmt.Errorf("invalid TRASH_RETENTION %q", getEnv("TRASH_RETENTION", ""))
+	}
+	if retention == 0 {
+		return nil
+	}
+	interval, err := time.ParseDuration(getEnv("TRASH_SWEEP_INTERVAL", "1h"))
+	if err != nil || interval <= 0 {
+		return fmt.Errorf("invalid TRASH_SWEEP_INTERVAL %q", getEnv("TRASH_SWEEP_INTERVAL", ""))
+	}
+	taskUsecases.StartTrashSweeper(ctx, retention, interval)
+	return nil
+}
+
+// newWebhookUsecases configures webhook delivery from WEBHOOK_TIMEOUT and WEBHOOK_MAX_ATTEMPTS.
+func newWebhookUsecases(store *storage) (*usecases.WebhookUsecases, error) {
+	timeout, err := time.ParseDuration(getEnv("WEBHOOK_TIMEOUT", "10s"))
+	if err != nil || timeout <= 0 {
+		return nil, fmt.Errorf("invalid WEBHOOK_TIMEOUT %q", getEnv("WEBHOOK_TIMEOUT", ""))
+	}
+	config := usecases.DefaultWebhookConfig()
+	if value := getEnv("WEBHOOK_MAX_ATTEMPTS", ""); value != "" {
+		if config.MaxAttempts, err = strconv.Atoi(value); err != nil || config.MaxAttempts <= 0 {
+			return nil, fmt.Errorf("invalid WEBHOOK_MAX_ATTEMPTS %q", value)
+		}
+	}
+	return usecases.NewWebhookUsecases(store.webhooks, store.audit, infrastructure.NewWebhookSender(timeout), config), nil
+}
+
+// newAttachmentUsecases limits attachments to ATTACHMENT_MAX_SIZE bytes
+// and the content types listed in ATTACHMENT_TYPES.
+func newAttachmentUsecases(store *storage) (*usecases.AttachmentUsecases, error) {
+	config := usecases.DefaultAttachmentConfig()
+	if value := getEnv("ATTACHMENT_MAX_SIZE", ""); value != "" {
+		var err error
+		if config.MaxSize, err = strconv.ParseInt(value, 10, 64); err != nil || config.MaxSize <= 0 {
+			return nil, fmt.Errorf("invalid ATTACHMENT_MAX_SIZE %q", value)
+		}
+	}
+	if value := getEnv("ATTACHMENT_TYPES", ""); value != "" {
+		config.AllowedTypes = nil
+		for _, t := range strings.Split(value, ",") {
+			if t = strings.TrimSpace(t); t != "" {
+				config.AllowedTypes = append(config.AllowedTypes, strings.ToLower(t))
+			}
+		}
+	}
+	return usecases.NewAttachmentUsecases(store.attachments, store.blobs, store.tasks, config), nil
+}
+
+// newRateLimiter throttles the credential routes as RATE_LIMITS configures
+// and locks a client out of an account after LOGIN_LOCKOUT_THRESHOLD failed logins in a
+// row, for LOGIN_LOCKOUT_DELAY doubling up to LOGIN_LOCKOUT_MAX_DELAY.
+// A threshold of 0 disables lockouts.
+func newRateLimiter(store *storage, userUsecases *usecases.UserUsecases) (*infrastructure.RateLimiter, error) {
+	limits, err := infrastructure.ParseRateLimits(infrastructure.DefaultRateLimits(), getEnv("RATE_LIMITS", ""))
+	if err != nil {
+		return nil, fmt.Errorf("invalid RATE_LIMITS: %w", err)
+	}
+
+	policy := domain.DefaultLockoutPolicy()
+	if value := getEnv("LOGIN_LOCKOUT_THRESHOLD", ""); value != "" {
+		if policy.Threshold, err = strconv.Atoi(value); err != nil || policy.Threshold < 0 {
+			return nil, fmt.Errorf("invalid LOGIN_LOCKOUT_THRESHOLD %q", value)
+		}
+	}
+	if policy.Delay, err = time.ParseDuration(getEnv("LOGIN_LOCKOUT_DELAY", policy.Delay.String())); err != nil || policy.Delay <= 0 {
+		return nil, fmt.Errorf("invalid LOGIN_LOCKOUT_DELAY %q", getEnv("LOGIN_LOCKOUT_DELAY", ""))
+	}
+	if policy.MaxDelay, err = time.ParseDuration(getEnv("LOGIN_LOCKOUT_MAX_DELAY", policy.MaxDelay.String())); err != nil || policy.MaxDelay < policy.Delay {
+		return nil, fmt.Errorf("invalid LOGIN_LOCKOUT_MAX_DELAY %q", getEnv("LOGIN_LOCKOUT_MAX_DELAY", ""))
+	}
+	if policy.Enabled() {
+		userUsecases.EnableLockout(store.limits, policy)
+	}
+	return infrastructure.NewRateLimiter(store.limits, limits), nil
+}
+
+// startReminderScheduler sends due date reminders every REMINDER_INTERVAL
+// through the notifiers listed in REMINDER_NOTIFIERS. An interval of 0
+// disables reminders.
+func startReminderScheduler(ctx context.Context, store *storage, workflow domain.Workflow, webhookUsecases *usecases.WebhookUsecases) error {
+	interval, err := time.ParseDuration(getEnv("REMINDER_INTERVAL", "1m"))
+	if err != nil || interval < 0 {
+		return fmt.Errorf("invalid REMINDER_INTERVAL %q", getEnv("REMINDER_INTERVAL", ""))
+	}
+	if interval == 0 {
+		return nil
+	}
+	config := usecases.DefaultReminderConfig()
+	if config.Lead, err = time.ParseDuration(getEnv("REMINDER_LEAD", config.Lead.String())); err != nil || config.Lead < 0 {
+		return fmt.Errorf("invalid REMINDER_LEAD %q", getEnv("REMINDER_LEAD", ""))
+	}
+	if config.Lookback, err = time.ParseDuration(getEnv("REMINDER_LOOKBACK", config.Lookback.String())); err != nil || config.Lookback < 0 {
+		return fmt.Errorf("invalid REMINDER_LOOKBACK %q", getEnv("REMINDER_LOOKBACK", ""))
+	}
+
+	var notifiers usecases.Notifiers
+	for _, name := range strings.Split(getEnv("REMINDER_NOTIFIERS", "log"), ",") {
+		switch strings.TrimSpace(name) {
+		case "log":
+			notifiers = append(notifiers, infrastructure.NewLogNotifier(nil))
+		case "webhook":
+			notifiers = append(notifiers, webhookUsecases)
+		case "smtp":
+			smtpNotifier, err := infrastructure.NewSMTPNotifier(infrastructure.SMTPConfig{
+				Addr:     getEnv("SMTP_ADDR", ""),
+				Username: getEnv("SMTP_USERNAME", ""),
+				Password: getEnv("SMTP_PASSWORD", ""),
+				From:     getEnv("SMTP_FROM", ""),
+				To:       strings.Split(getEnv("SMTP_TO", ""), ","),
+			})
+			if err != nil {
+				return err
+			}
+			notifiers = append(notifiers, smtpNotifier)
+		case "":
+		default:
+			return fmt.Errorf("unknown reminder notifier %q", name)
+		}
+	}
+
+	reminderUsecases := usecases.NewReminderUsecases(store.tasks, store.reminders, workflow, notifiers, config)
+	reminderUsecases.StartReminderScheduler(ctx, interval)
+	return nil
 }
 
 func getEnv(key, defaultValue string) string {
@@ -0,0 +1,249 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"task_manager/Repositories"
+)
+
+// Storage backends selectable with STORAGE_BACKEND.
+const (
+	backendMongo  = "mongo"
+	backendMemory = "memory"
+	backendSQLite = "sqlite"
+)
+
+// Attachment content stores selectable with ATTACHMENT_STORAGE.
+const (
+	blobsFilesystem = "filesystem"
+	blobsGridFS     = "gridfs"
+	blobsMemory     = "memory"
+)
+
+// storage holds the repositories of one backend.
+type storage struct {
+	tasks     repositories.ITaskRepository
+	users     repositories.IUserRepository
+	roles     repositories.IRoleRepository
+	tokens    repositories.ITokenStore
+	audit     repositories.IAuditRepository
+	webhooks  repositories.IWebhookRepository
+	reminders repositories.IReminderRepository
+	comments  repositories.ICommentRepository
+	projects  repositories.IProjectRepository
+	// limits holds rate limit buckets and failed logins
+	limits repositories.IRateLimitStore
+	// attachments holds attachment metadata and blobs their content
+	attachments repositories.IAttachmentRepository
+	blobs       repositories.IBlobStore
+	// disconnect releases the connection shared by the repositories, if any
+	disconnect func(ctx context.Context) error
+}
+
+// openStorage opens the repositories of the backend named by STORAGE_BACKEND.
+func openStorage() (*storage, error) {
+	backend := getEnv("STORAGE_BACKEND", backendMongo)
+	log.Printf("Using %s storage backend", backend)
+
+	timeouts, err := loadTimeouts()
+	if err != nil {
+		return nil, err
+	}
+
+	switch backend {
+	case backendMemory:
+		blobs, err := openBlobStore(blobsMemory, nil)
+		if err != nil {
+			return nil, err
+		}
+		return &storage{
+			tasks:       repositories.NewMemoryTaskRepository(),
+			users:       repositories.NewMemoryUserRepository(),
+			roles:       repositories.NewMemoryRoleRepository(),
+			tokens:      repositories.NewMemoryTokenStore(),
+			audit:       repositories.NewMemoryAuditRepository(),
+			webhooks:    repositories.NewMemoryWebhookRepository(),
+			reminders:   repositories.NewMemoryReminderRepository(),
+			comments:    repositories.NewMemoryCommentRepository(),
+			projects:    repositories.NewMemoryProjectRepository(),
+			limits:      repositories.NewMemoryRateLimitStore(),
+			attachments: repositories.NewMemoryAttachmentRepository(),
+			blobs:       blobs,
+		}, nil
+
+	case backendSQLite:
+		blobs, err := openBlobStore(blobsFilesystem, nil)
+		if err != nil {
+			return nil, err
+		}
+		db, err := repositories.OpenSQLite(getEnv("SQLITE_PATH", "taskmanager.db"))
+		if err != nil {
+			blobs.Close()
+			return nil, err
+		}
+		return &storage{
+			tasks:       repositories.NewSQLiteTaskRepository(db, timeouts),
+			users:       repositories.NewSQLiteUserRepository(db, timeouts),
+			roles:       repositories.NewSQLiteRoleRepository(db, timeouts),
+			tokens:      repositories.NewSQLiteTokenStore(db, timeouts),
+			audit:       repositories.NewSQLiteAuditRepository(db, timeouts),
+			webhooks:    repositories.NewSQLiteWebhookRepository(db, timeouts),
+			reminders:   repositories.NewSQLiteReminderRepository(db, timeouts),
+			comments:    repositories.NewSQLiteCommentRepository(db, timeouts),
+			projects:    repositories.NewSQLiteProjectRepository(db, timeouts),
+			limits:      repositories.NewSQLiteRateLimitStore(db, timeouts),
+			attachments: repositories.NewSQLiteAttachmentRepository(db, timeouts),
+			blobs:       blobs,
+		}, nil
+
+	case backendMongo:
+		return openMongoStorage(timeouts)
+	}
+	return nil, fmt.Errorf("unknown STORAGE_BACKEND %q", backend)
+}
+
+func openMongoStorage(timeouts repositories.Timeouts) (*storage, error) {
+	cfg, err := loadMongoConfig()
+	if err != nil {
+		return nil, err
+	}
+	client, err := repositories.OpenMongo(cfg)
+	if err != nil {
+		return nil, err
+	}
+	db := client.Database(cfg.Database)
+
+	s := &storage{
+		users:      repositories.NewMongoUserRepository(db, "users", timeouts),
+		roles:      repositories.NewMongoRoleRepository(db, "roles", timeouts),
+		disconnect: client.Disconnect,
+	}
+	if s.tasks, err = repositories.NewMongoTaskRepository(db, "tasks", timeouts); err != nil {
+		s.Close(context.Background())
+		return nil, fmt.Errorf("task repository: %w", err)
+	}
+	if s.tokens, err = repositories.NewMongoTokenStore(db, timeouts); err != nil {
+		s.Close(context.Background())
+		return nil, fmt.Errorf("token store: %w", err)
+	}
+	if s.audit, err = repositories.NewMongoAuditRepository(db, "audit_log", timeouts); err != nil {
+		s.Close(context.Background())
+		return nil, fmt.Errorf("audit repository: %w", err)
+	}
+	if s.webhooks, err = repositories.NewMongoWebhookRepository(db, timeouts); err != nil {
+		s.Close(context.Background())
+		return nil, fmt.Errorf("webhook repository: %w", err)
+	}
+	if s.reminders, err = repositories.NewMongoReminderRepository(db, "reminders", timeouts); err != nil {
+		s.Close(context.Background())
+		return nil, fmt.Errorf("reminder repository: %w", err)
+	}
+	if s.comments, err = repositories.NewMongoCommentRepository(db, "comments", timeouts); err != nil {
+		s.Close(context.Background())
+		return nil, fmt.Errorf("comment repository: %w", err)
+	}
+	if s.projects, err = repositories.NewMongoProjectRepository(db, "projects", timeouts); err != nil {
+		s.Close(context.Background())
+		return nil, fmt.Errorf("project repository: %w", err)
+	}
+	if s.limits, err = repositories.NewMongoRateLimitStore(db, timeouts); err != nil {
+		s.Close(context.Background())
+		return nil, fmt.Errorf("rate limit store: %w", err)
+	}
+	if s.attachments, err = repositories.NewMongoAttachmentRepository(db, "attachments", timeouts); err != nil {
+		s.Close(context.Background())
+		return nil, fmt.Errorf("attachment repository: %w", err)
+	}
+	s.blobs, err = openBlobStore(blobsGridFS, func() (repositories.IBlobStore, error) {
+		return repositories.NewGridFSBlobStore(db, "blobs")
+	})
+	if err != nil {
+		s.Close(context.Background())
+		return nil, err
+	}
+	return s, nil
+}
+
+// openBlobStore opens the attachment content store named by
+// ATTACHMENT_STORAGE, or by fallback when it is unset. Files are kept in
+// ATTACHMENT_DIR; GridFS is only available with the mongo backend, which
+// passes gridFS to open it.
+func openBlobStore(fallback string, gridFS func() (repositories.IBlobStore, error)) (repositories.IBlobStore, error) {
+	kind := getEnv("ATTACHMENT_STORAGE", fallback)
+	switch kind {
+	case blobsFilesystem:
+		return repositories.NewFileBlobStore(getEnv("ATTACHMENT_DIR", "attachments"))
+	case blobsMemory:
+		return repositories.NewMemoryBlobStore(), nil
+	case blobsGridFS:
+		if gridFS == nil {
+			return nil, fmt.Errorf("ATTACHMENT_STORAGE %q requires the mongo storage backend", kind)
+		}
+		return gridFS()
+	}
+	return nil, fmt.Errorf("unknown ATTACHMENT_STORAGE %q", kind)
+}
+
+// loadMongoConfig reads the MongoDB client settings from the environment.
+func loadMongoConfig() (repositories.MongoConfig, error) {
+	cfg := repositories.MongoConfig{
+		URI:            getEnv("MONGODB_URI", "mongodb://localhost:27017"),
+		Database:       getEnv("DB_NAME", "taskmanager"),
+		ReadPreference: getEnv("MONGODB_READ_PREFERENCE", ""),
+		WriteConcern:   getEnv("MONGODB_WRITE_CONCERN", ""),
+	}
+	var err error
+	if cfg.MaxPoolSize, err = getEnvUint("MONGODB_MAX_POOL_SIZE"); err != nil {
+		return cfg, err
+	}
+	if cfg.MinPoolSize, err = getEnvUint("MONGODB_MIN_POOL_SIZE"); err != nil {
+		return cfg, err
+	}
+	return cfg, nil
+}
+
+func getEnvUint(key string) (uint64, error) {
+	value := getEnv(key, "")
+	if value == "" {
+		return 0, nil
+	}
+	n, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s %q", key, value)
+	}
+	return n, nil
+}
+
+// loadTimeouts reads the repository operation timeouts from DB_TIMEOUT and DB_TIMEOUTS.
+func loadTimeouts() (repositories.Timeouts, error) {
+	defaultTimeout, err := time.ParseDuration(getEnv("DB_TIMEOUT", repositories.DefaultOperationTimeout.String()))
+	if err != nil || defaultTimeout <= 0 {
+		return repositories.Timeouts{}, fmt.Errorf("invalid DB_TIMEOUT %q", getEnv("DB_TIMEOUT", ""))
+	}
+	timeouts, err := repositories.ParseTimeouts(defaultTimeout, getEnv("DB_TIMEOUTS", ""))
+	if err != nil {
+		return repositories.Timeouts{}, fmt.Errorf("invalid DB_TIMEOUTS: %w", err)
+	}
+	return timeouts, nil
+}
+
+// Close closes every opened repository and then their shared connection.
+func (s *storage) Close(ctx context.Context) {
+	for _, c := range []interface{ Close() error }{s.tasks, s.users, s.roles, s.tokens, s.audit, s.webhooks, s.reminders, s.comments, s.projects, s.limits, s.attachments, s.blobs} {
+		if c == nil {
+			continue
+		}
+		if err := c.Close(); err != nil {
+			log.Printf("Failed to close repository: %v", err)
+		}
+	}
+	if s.disconnect != nil {
+		if err := s.disconnect(ctx); err != nil {
+			log.Printf("Failed to disconnect from database: %v", err)
+		}
+	}
+}